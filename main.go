@@ -5,19 +5,26 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 
 	"github.com/MCPRUNNER/gossisMCP/pkg/config"
+	"github.com/MCPRUNNER/gossisMCP/pkg/formatter"
 	"github.com/MCPRUNNER/gossisMCP/pkg/handlers/analysis"
+	"github.com/MCPRUNNER/gossisMCP/pkg/handlers/database"
+	"github.com/MCPRUNNER/gossisMCP/pkg/handlers/diagram"
+	"github.com/MCPRUNNER/gossisMCP/pkg/handlers/environment"
 	"github.com/MCPRUNNER/gossisMCP/pkg/handlers/extraction"
+	"github.com/MCPRUNNER/gossisMCP/pkg/handlers/files"
 	"github.com/MCPRUNNER/gossisMCP/pkg/handlers/optimization"
 	packagehandlers "github.com/MCPRUNNER/gossisMCP/pkg/handlers/packages"
+	"github.com/MCPRUNNER/gossisMCP/pkg/handlers/summary"
 	templatehandlers "github.com/MCPRUNNER/gossisMCP/pkg/handlers/templates"
 	serverutil "github.com/MCPRUNNER/gossisMCP/pkg/util/server"
 	workflowutil "github.com/MCPRUNNER/gossisMCP/pkg/util/workflow"
@@ -28,12 +35,8 @@ import (
 // loadEnvironmentConfig loads configuration overrides from environment variables
 // validateConfig validates the configuration
 // configureLogging configures the logging based on the configuration
-func configureLogging(config config.LoggingConfig) {
-	// Set log level (simplified - in a real implementation you'd use a proper logging library)
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
-	if strings.ToLower(config.Level) == "debug" {
-		log.SetFlags(log.LstdFlags | log.Lshortfile | log.Lmicroseconds)
-	}
+func configureLogging(cfg config.LoggingConfig) {
+	config.ConfigureLogging(cfg)
 }
 
 func main() {
@@ -42,28 +45,41 @@ func main() {
 	httpPort := flag.String("port", "8086", "HTTP server port")
 	pkgDir := flag.String("pkg-dir", "", "Root directory for SSIS packages (can also be set via GOSSIS_PKG_DIRECTORY env var, defaults to current working directory)")
 	configPath := flag.String("config", "", "Path to configuration file (JSON or YAML)")
+	htmlTemplatePath := flag.String("html-template", "", "Path to a Go html/template file that overrides the built-in HTML report layout")
 	flag.Parse()
 
 	// Load configuration
 	config, err := config.LoadConfig(*configPath)
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		slog.Error("failed to load configuration", "error", err)
+		os.Exit(1)
 	}
 
-	// Override config with command line flags if provided
-	if *httpMode {
-		config.Server.HTTPMode = true
+	// Override config file and environment values with explicitly set command line flags
+	explicitFlags := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { explicitFlags[f.Name] = true })
+	if explicitFlags["http"] {
+		config.Server.HTTPMode = *httpMode
 	}
-	if *httpPort != "8086" {
+	if explicitFlags["port"] {
 		config.Server.Port = *httpPort
 	}
-	if *pkgDir != "" {
+	if explicitFlags["pkg-dir"] {
 		config.Packages.Directory = *pkgDir
 	}
 
 	// Configure logging
 	configureLogging(config.Logging)
 
+	packagehandlers.SetServerNamePatterns(config.Analysis.ServerNamePatterns)
+
+	if *htmlTemplatePath != "" {
+		if err := formatter.SetHTMLTemplate(*htmlTemplatePath); err != nil {
+			slog.Error("failed to load HTML template", "file", *htmlTemplatePath, "error", err)
+			os.Exit(1)
+		}
+	}
+
 	// Determine package directory from config, environment variable, or default
 	packageDirectory := config.Packages.Directory
 	excludeFile := config.Packages.ExcludeFile
@@ -82,7 +98,7 @@ func main() {
 		if err == nil {
 			packageDirectory = absPath
 		}
-		log.Printf("Using SSIS package directory: %s", packageDirectory)
+		slog.Info("using SSIS package directory", "directory", packageDirectory)
 	}
 
 	s := server.NewMCPServer(
@@ -90,10 +106,12 @@ func main() {
 		"1.0.0",
 		server.WithToolCapabilities(true),
 		server.WithResourceCapabilities(true, true),
+		server.WithToolHandlerMiddleware(serverutil.TruncateOutputMiddleware),
+		server.WithToolHandlerMiddleware(serverutil.LoggingMiddleware),
 	)
 
 	// Initialize plugin system
-	pluginSystem := NewPluginSystem(config.Plugins)
+	pluginSystem := NewPluginSystem(config.Plugins, packageDirectory)
 
 	// Register plugin management tools
 	pluginSystem.createPluginManagementTools(s)
@@ -124,6 +142,12 @@ func main() {
 			mcp.Required(),
 			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
 		),
+		mcp.WithString("task_type",
+			mcp.Description("Comma-separated task types to include, e.g. execute_sql,script_task,data_flow (also: execute_package, execute_process, file_system, ftp, send_mail, bulk_insert, expression, cdc_control, web_service, xml, message_queue, sequence, for_loop, foreach_loop)"),
+		),
+		mcp.WithBoolean("negate",
+			mcp.Description("Return every task except the types listed in task_type (default: false)"),
+		),
 		mcp.WithString("format",
 			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
 		),
@@ -145,6 +169,12 @@ func main() {
 			mcp.Required(),
 			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
 		),
+		mcp.WithString("connection_type",
+			mcp.Description("Comma-separated connection manager types to include, e.g. oledb, flatfile, smtp, adonet, file, excel, msmq, ftp, http"),
+		),
+		mcp.WithBoolean("mask_passwords",
+			mcp.Description("Mask password values in connection strings (default: true)"),
+		),
 		mcp.WithString("format",
 			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
 		),
@@ -167,7 +197,7 @@ func main() {
 			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
 		),
 		mcp.WithString("format",
-			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
+			mcp.Description("Output format: text, json, csv, html, markdown, or graph for a JSON adjacency list of nodes and edges (default: text)"),
 		),
 		mcp.WithString("output_file_path",
 			mcp.Description("Destination path to write the tool result (relative to package directory if set)"),
@@ -213,9 +243,27 @@ func main() {
 		return extraction.HandleExtractParameters(ctx, request, packageDirectory)
 	})
 
+	// Tool to extract checkpoint configuration
+	extractCheckpointConfigTool := mcp.NewTool("extract_checkpoint_config",
+		mcp.WithDescription("Extract the checkpoint configuration of a DTSX file (SaveCheckpoints, CheckpointFileName and CheckpointUsage restart behavior) and flag absolute, environment-specific checkpoint file paths"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
+		),
+		mcp.WithString("output_file_path",
+			mcp.Description("Destination path to write the tool result (relative to package directory if set)"),
+		),
+	)
+	s.AddTool(extractCheckpointConfigTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return extraction.HandleExtractCheckpointConfig(ctx, request, packageDirectory)
+	})
+
 	// Tool to extract script code from Script Tasks
 	extractScriptTool := mcp.NewTool("extract_script_code",
-		mcp.WithDescription("Extract script code from Script Tasks in a DTSX file"),
+		mcp.WithDescription("Extract script code from Script Tasks and data flow Script Components in a DTSX file, including each Script Component's parent Data Flow Task"),
 		mcp.WithString("file_path",
 			mcp.Required(),
 			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
@@ -238,8 +286,17 @@ func main() {
 			mcp.Required(),
 			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
 		),
+		mcp.WithString("min_severity",
+			mcp.Description("Only report findings at or above this severity: error, warning, info (default: info)"),
+		),
+		mcp.WithString("rules_file",
+			mcp.Description("Path to a JSON file of custom XPath rules to evaluate alongside the built-in checks (relative to package directory if set)"),
+		),
+		mcp.WithNumber("min_description_length",
+			mcp.Description("Package and task descriptions shorter than this many characters are flagged (default: 10)"),
+		),
 		mcp.WithString("format",
-			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
+			mcp.Description("Output format: text, json, csv, html, markdown, sarif, junit, table (default: text)"),
 		),
 		mcp.WithString("output_file_path",
 			mcp.Description("Destination path to write the tool result (relative to package directory if set)"),
@@ -260,6 +317,17 @@ func main() {
 			mcp.Required(),
 			mcp.Description("Question about the DTSX file"),
 		),
+		mcp.WithArray("conversation",
+			mcp.Description("Prior turns as {role, content} objects (role: user or assistant) so follow-up questions such as \"what about its error handling?\" can refer to earlier answers"),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"role":    map[string]any{"type": "string", "enum": []string{"user", "assistant"}},
+					"content": map[string]any{"type": "string"},
+				},
+				"required": []string{"role", "content"},
+			}),
+		),
 		mcp.WithString("format",
 			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
 		),
@@ -273,7 +341,7 @@ func main() {
 
 	// Tool to analyze Message Queue Tasks
 	analyzeMessageQueueTool := mcp.NewTool("analyze_message_queue_tasks",
-		mcp.WithDescription("Analyze Message Queue Tasks in a DTSX file, including send/receive operations and message content"),
+		mcp.WithDescription("Analyze Message Queue Tasks in a DTSX file, including send/receive operations, queue paths, message types, filters, encryption settings and message content"),
 		mcp.WithString("file_path",
 			mcp.Required(),
 			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
@@ -289,6 +357,42 @@ func main() {
 		return packagehandlers.HandleAnalyzeMessageQueueTasks(ctx, request, packageDirectory)
 	})
 
+	// Tool to analyze CDC Control Tasks
+	analyzeCdcControlTaskTool := mcp.NewTool("analyze_cdc_control_task",
+		mcp.WithDescription("Analyze CDC Control Tasks in a DTSX file, including the CDC operation, connection and state settings"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
+		),
+		mcp.WithString("output_file_path",
+			mcp.Description("Destination path to write the tool result (relative to package directory if set)"),
+		),
+	)
+	s.AddTool(analyzeCdcControlTaskTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return packagehandlers.HandleAnalyzeCdcControlTask(ctx, request, packageDirectory)
+	})
+
+	// Tool to analyze WMI Data Reader and WMI Event Watcher tasks
+	analyzeWmiTaskTool := mcp.NewTool("analyze_wmi_task",
+		mcp.WithDescription("Analyze WMI Data Reader and WMI Event Watcher tasks in a DTSX file, including the WMI connection, WQL query, output type and event/timeout actions"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
+		),
+		mcp.WithString("output_file_path",
+			mcp.Description("Destination path to write the tool result (relative to package directory if set)"),
+		),
+	)
+	s.AddTool(analyzeWmiTaskTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return packagehandlers.HandleAnalyzeWmiTask(ctx, request, packageDirectory)
+	})
+
 	// Tool to analyze Script Tasks
 	analyzeScriptTaskTool := mcp.NewTool("analyze_script_task",
 		mcp.WithDescription("Analyze Script Tasks in a DTSX file, including script code, variables, and task configuration"),
@@ -309,13 +413,16 @@ func main() {
 
 	// Tool to detect hard-coded values
 	detectHardcodedValuesTool := mcp.NewTool("detect_hardcoded_values",
-		mcp.WithDescription("Detect hard-coded values in a DTSX file, such as embedded literals in connection strings, messages, or expressions"),
+		mcp.WithDescription("Detect hard-coded values in a DTSX file, such as embedded literals in connection strings, messages, or expressions, including IPv4/IPv6 addresses in connection strings and SQL commands and environment-specific server names"),
 		mcp.WithString("file_path",
 			mcp.Required(),
 			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
 		),
+		mcp.WithString("environment_names",
+			mcp.Description("Comma-separated hostname substrings that mark a server as environment-specific, overriding analysis.server_name_patterns (default: PROD,DEV,UAT,QA)"),
+		),
 		mcp.WithString("format",
-			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
+			mcp.Description("Output format: text, json, csv, html, markdown, sarif (default: text)"),
 		),
 		mcp.WithString("output_file_path",
 			mcp.Description("Destination path to write the tool result (relative to package directory if set)"),
@@ -327,11 +434,14 @@ func main() {
 
 	// Tool to analyze logging configuration
 	analyzeLoggingTool := mcp.NewTool("analyze_logging_configuration",
-		mcp.WithDescription("Analyze detailed logging configuration in a DTSX file, including log providers, events, and destinations"),
+		mcp.WithDescription("Analyze detailed logging configuration in a DTSX file: log providers grouped by type with the events enabled for each, package- vs task-level logging conflicts, and destinations"),
 		mcp.WithString("file_path",
 			mcp.Required(),
 			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
 		),
+		mcp.WithString("provider_type",
+			mcp.Description("Only report log providers of this type: sql_server, windows_event_log, text_file, xml_file, ssis_log_provider"),
+		),
 		mcp.WithString("format",
 			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
 		),
@@ -349,6 +459,9 @@ func main() {
 		mcp.WithString("format",
 			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
 		),
+		mcp.WithString("file_types",
+			mcp.Description("Comma-separated file types to include: dtsx, params, dtsConfig (default: dtsx)"),
+		),
 		mcp.WithString("output_file_path",
 			mcp.Description("Destination path to write the tool result (relative to package directory if set)"),
 		),
@@ -373,9 +486,16 @@ func main() {
 		mcp.WithString("json_file_path",
 			mcp.Description("Path to a JSON file containing the template data (relative to package directory if set)"),
 		),
+		mcp.WithString("base_template_path",
+			mcp.Description("Path to a base layout template (relative to package directory if set). The base is executed and {{define}} blocks in template_file_path override its {{block}} defaults"),
+		),
+		mcp.WithString("functions_plugin",
+			mcp.Description("Path to a compiled Go plugin exporting FuncMap() template.FuncMap whose functions are added to the template (relative to package directory if set; requires server.enable_template_plugins)"),
+		),
 	)
+	templateOptions := templatehandlers.Options{EnablePlugins: config.Server.EnableTemplatePlugins}
 	s.AddTool(renderTemplateTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		return templatehandlers.HandleRenderTemplate(ctx, request, packageDirectory)
+		return templatehandlers.HandleRenderTemplate(ctx, request, packageDirectory, templateOptions)
 	})
 
 	// Tool to analyze data flow components
@@ -385,6 +505,9 @@ func main() {
 			mcp.Required(),
 			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
 		),
+		mcp.WithString("task_filter",
+			mcp.Description("Only analyze the Data Flow Task with this name; an exact match (ignoring case) wins, otherwise every task whose name contains the filter is analyzed"),
+		),
 		mcp.WithString("format",
 			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
 		),
@@ -398,11 +521,14 @@ func main() {
 
 	// Tool to analyze data flow components with detailed configurations
 	analyzeDataFlowDetailedTool := mcp.NewTool("analyze_data_flow_detailed",
-		mcp.WithDescription("Provide detailed analysis of Data Flow components including configurations, properties, inputs/outputs, and data mappings"),
+		mcp.WithDescription("Provide detailed analysis of Data Flow components including configurations, properties, inputs/outputs, data mappings, and source-to-destination column lineage"),
 		mcp.WithString("file_path",
 			mcp.Required(),
 			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
 		),
+		mcp.WithString("task_filter",
+			mcp.Description("Only analyze the Data Flow Task with this name; an exact match (ignoring case) wins, otherwise every task whose name contains the filter is analyzed"),
+		),
 		mcp.WithString("format",
 			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
 		),
@@ -423,7 +549,10 @@ func main() {
 		),
 		mcp.WithString("source_type",
 			mcp.Required(),
-			mcp.Description("Type of source to analyze: ole_db, ado_net, odbc, flat_file, excel, access, xml, raw_file, cdc, sap_bw"),
+			mcp.Description("Type of source to analyze: ole_db, ado_net, odbc, flat_file, excel, access, xml, raw_file, cdc, sap_bw, azure_blob, azure_dls, json_source, odata, generic_classid, or all to report every supported source type"),
+		),
+		mcp.WithString("class_id",
+			mcp.Description("Component class ID to match when source_type is generic_classid"),
 		),
 		mcp.WithString("format",
 			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
@@ -445,7 +574,10 @@ func main() {
 		),
 		mcp.WithString("destination_type",
 			mcp.Required(),
-			mcp.Description("Type of destination to analyze: ole_db, flat_file, sql_server, excel, raw_file"),
+			mcp.Description("Type of destination to analyze: ole_db, flat_file, sql_server, excel, raw_file, azure_blob, azure_sql, odbc, ado_net, generic_classid, or all to report every supported destination type"),
+		),
+		mcp.WithString("class_id",
+			mcp.Description("Component class ID to match when destination_type is generic_classid"),
 		),
 		mcp.WithString("format",
 			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
@@ -766,7 +898,7 @@ func main() {
 
 	// Tool to analyze Derived Column transformations
 	analyzeDerivedColumnTool := mcp.NewTool("analyze_derived_column",
-		mcp.WithDescription("Analyze Derived Column transformations in a DTSX file, extracting expression logic and output mappings"),
+		mcp.WithDescription("Analyze Derived Column transformations in a DTSX file, extracting expression logic and output mappings. Expressions are parsed to report syntax errors, unknown functions, wrong argument counts, comparisons with NULL and casts longer than the output column"),
 		mcp.WithString("file_path",
 			mcp.Required(),
 			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
@@ -928,7 +1060,7 @@ func main() {
 
 	// Tool to analyze containers
 	analyzeContainersTool := mcp.NewTool("analyze_containers",
-		mcp.WithDescription("Analyze containers in a DTSX file, including Sequence, For Loop, and Foreach Loop containers with their properties and nested executables"),
+		mcp.WithDescription("Analyze containers in a DTSX file, including Sequence, For Loop, and Foreach Loop containers with their properties, Foreach enumerator settings, nested executables and their estimated execution order from precedence constraints. For Loops are checked for an AssignExpression that moves the tested variable toward ending the loop"),
 		mcp.WithString("file_path",
 			mcp.Required(),
 			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
@@ -944,6 +1076,24 @@ func main() {
 		return analysis.HandleAnalyzeContainers(ctx, request, packageDirectory)
 	})
 
+	// Tool to check For Loop containers for loops that cannot end
+	analyzeForLoopContainerTool := mcp.NewTool("analyze_for_loop_container",
+		mcp.WithDescription("Check every For Loop container in a DTSX file, including nested ones, for potential infinite loops: parses the EvalExpression and AssignExpression, reports an error when the AssignExpression does not modify a variable tested by the EvalExpression or steps it away from the bound, and a warning when the direction of change is ambiguous"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
+		),
+		mcp.WithString("output_file_path",
+			mcp.Description("Destination path to write the tool result (relative to package directory if set)"),
+		),
+	)
+	s.AddTool(analyzeForLoopContainerTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return analysis.HandleAnalyzeForLoopContainer(ctx, request, packageDirectory)
+	})
+
 	// Tool to analyze custom and third-party components
 	analyzeCustomComponentsTool := mcp.NewTool("analyze_custom_components",
 		mcp.WithDescription("Analyze custom and third-party components in a DTSX file, identifying non-standard components and their configurations"),
@@ -951,6 +1101,9 @@ func main() {
 			mcp.Required(),
 			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
 		),
+		mcp.WithString("registry_file",
+			mcp.Description("Optional JSON component registry mapping class IDs or class ID prefixes to vendor, version, docs_url and known_issues (relative to package directory if set)"),
+		),
 		mcp.WithString("format",
 			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
 		),
@@ -963,7 +1116,7 @@ func main() {
 	})
 
 	comparePackagesTool := mcp.NewTool("compare_packages",
-		mcp.WithDescription("Compare two DTSX files and highlight differences"),
+		mcp.WithDescription("Compare two DTSX files and highlight differences, or perform a three-way comparison against a common base package"),
 		mcp.WithString("file_path1",
 			mcp.Required(),
 			mcp.Description("Path to the first DTSX file (relative to package directory if set)"),
@@ -972,6 +1125,9 @@ func main() {
 			mcp.Required(),
 			mcp.Description("Path to the second DTSX file (relative to package directory if set)"),
 		),
+		mcp.WithString("file_path_base",
+			mcp.Description("Optional common ancestor DTSX file; when set, changes from base to each file are reported separately along with conflicting changes (relative to package directory if set)"),
+		),
 		mcp.WithString("format",
 			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
 		),
@@ -1013,6 +1169,18 @@ func main() {
 			mcp.DefaultBool(true),
 			mcp.Description("Include enable line numbers in the content (true or false, default: true)"),
 		),
+		mcp.WithBoolean("force",
+			mcp.Description("Read the file as text even when it is detected as binary (default: false)"),
+		),
+		mcp.WithString("encoding",
+			mcp.Description("Text encoding: auto, utf-8, utf-16le, utf-16be, windows-1252 (default: auto, which detects BOMs and falls back to windows-1252 for invalid UTF-8)"),
+		),
+		mcp.WithNumber("start_line",
+			mcp.Description("First line to return, 1-based (default: 1)"),
+		),
+		mcp.WithNumber("end_line",
+			mcp.Description("Last line to return, inclusive; values past the end of the file stop at EOF (default: last line)"),
+		),
 		mcp.WithString("output_file_path",
 			mcp.Description("Destination path to write the tool result (relative to package directory if set)"),
 		),
@@ -1052,13 +1220,16 @@ func main() {
 
 	// Tool for comprehensive credential scanning with pattern matching
 	scanCredentialsTool := mcp.NewTool("scan_credentials",
-		mcp.WithDescription("Perform comprehensive credential scanning with advanced pattern matching to detect hardcoded credentials, API keys, tokens, and sensitive data patterns"),
+		mcp.WithDescription("Perform comprehensive credential scanning with advanced pattern matching to detect hardcoded credentials, API keys, tokens, and sensitive data patterns in DTSX packages or .dtsConfig configuration files"),
 		mcp.WithString("file_path",
 			mcp.Required(),
-			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
+			mcp.Description("Path to the DTSX or .dtsConfig file (relative to package directory if set)"),
+		),
+		mcp.WithString("file_type",
+			mcp.Description("File type to scan: dtsx or dtsConfig (default: detected from the file extension)"),
 		),
 		mcp.WithString("format",
-			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
+			mcp.Description("Output format: text, json, csv, html, markdown, sarif (default: text)"),
 		),
 		mcp.WithString("output_file_path",
 			mcp.Description("Destination path to write the tool result (relative to package directory if set)"),
@@ -1097,7 +1268,7 @@ func main() {
 			mcp.Description("Compliance standard to check (gdpr, hipaa, pci, or 'all' for comprehensive check)"),
 		),
 		mcp.WithString("format",
-			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
+			mcp.Description("Output format: text, json, csv, html, markdown, junit (default: text)"),
 		),
 		mcp.WithString("output_file_path",
 			mcp.Description("Destination path to write the tool result (relative to package directory if set)"),
@@ -1116,6 +1287,9 @@ func main() {
 			mcp.Required(),
 			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
 		),
+		mcp.WithNumber("assumed_varchar_length",
+			mcp.Description("Width assumed for variable-length columns without a length when estimating row size (default: 50)"),
+		),
 		mcp.WithString("format",
 			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
 		),
@@ -1152,6 +1326,15 @@ func main() {
 			mcp.Required(),
 			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
 		),
+		mcp.WithNumber("memory_threshold_mb",
+			mcp.Description("Flag Sort and Aggregate transformations estimated to exceed this many MB (default: 512)"),
+		),
+		mcp.WithNumber("row_estimate",
+			mcp.Description("Row count assumed for Sort and Aggregate transformations without a per-component estimate (default: 1000000)"),
+		),
+		mcp.WithObject("row_estimates",
+			mcp.Description("Per-component row counts keyed by component name, e.g. {\"Sort Customers\": 250000}"),
+		),
 		mcp.WithString("format",
 			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
 		),
@@ -1178,31 +1361,241 @@ func main() {
 	s.AddTool(mergeJSONFilesTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		return packagehandlers.MergeJSONFilesHandler(ctx, request, packageDirectory)
 	})
+
+	fileOptions := files.Options{AllowAbsolutePaths: config.Packages.AllowAbsolutePaths}
+
+	// Tool to write text content back to DTSX, SQL or config files
+	writeTextFileTool := mcp.NewTool("write_text_file",
+		mcp.WithDescription("Write, append or prepend text content to a file within the package directory"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the file to write (relative to package directory if set)"),
+		),
+		mcp.WithString("content",
+			mcp.Description("Inline content to write"),
+		),
+		mcp.WithString("content_file_path",
+			mcp.Description("Path to a file whose content is written instead of inline content (relative to package directory if set; must be inside it unless absolute paths are allowed)"),
+		),
+		mcp.WithString("mode",
+			mcp.Description("Write mode: overwrite, append, prepend (default: overwrite)"),
+		),
+		mcp.WithBoolean("create_backup",
+			mcp.Description("Copy the existing file to <file>.bak before writing (default: false)"),
+		),
+	)
+	s.AddTool(writeTextFileTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return files.HandleWriteTextFile(ctx, request, packageDirectory, fileOptions)
+	})
+
+	// Tool to delete intermediate files produced by workflows
+	deleteFileTool := mcp.NewTool("delete_file",
+		mcp.WithDescription("Delete one or more files within the package directory"),
+		mcp.WithString("file_path",
+			mcp.Description("Path to the file to delete (relative to package directory if set)"),
+		),
+		mcp.WithArray("file_paths",
+			mcp.Description("Array of file paths to delete (relative to package directory if set)"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+	)
+	s.AddTool(deleteFileTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return files.HandleDeleteFile(ctx, request, packageDirectory, fileOptions)
+	})
+
+	// Tool to move or rename files within the package directory
+	moveFileTool := mcp.NewTool("move_file",
+		mcp.WithDescription("Move or rename a file within the package directory"),
+		mcp.WithString("source_path",
+			mcp.Required(),
+			mcp.Description("Path to the file to move (relative to package directory if set)"),
+		),
+		mcp.WithString("destination_path",
+			mcp.Required(),
+			mcp.Description("Destination path for the file (relative to package directory if set)"),
+		),
+		mcp.WithBoolean("overwrite",
+			mcp.Description("Replace the destination if it already exists (default: false)"),
+		),
+	)
+	s.AddTool(moveFileTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return files.HandleMoveFile(ctx, request, packageDirectory, fileOptions)
+	})
+
+	environmentOptions := environment.Options{AllowWrite: config.Server.AllowEnvWrite}
+	databaseOptions := database.Options{Enabled: config.Server.EnableSQLExecution}
+
+	// Tool to read environment-specific values for workflow parameter injection
+	getEnvironmentVariableTool := mcp.NewTool("get_environment_variable",
+		mcp.WithDescription("Read the value of an environment variable; fails when the variable is not set"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the environment variable"),
+		),
+	)
+	s.AddTool(getEnvironmentVariableTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return environment.HandleGetEnvironmentVariable(ctx, request)
+	})
+
+	// Tool to set environment variables (disabled unless server.allow_env_write is true)
+	setEnvironmentVariableTool := mcp.NewTool("set_environment_variable",
+		mcp.WithDescription("Set an environment variable for the server process (requires server.allow_env_write)"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the environment variable"),
+		),
+		mcp.WithString("value",
+			mcp.Required(),
+			mcp.Description("Value to assign"),
+		),
+	)
+	s.AddTool(setEnvironmentVariableTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return environment.HandleSetEnvironmentVariable(ctx, request, environmentOptions)
+	})
+
+	// Tool to run validation queries against a connection manager's target (requires server.enable_sql_execution)
+	runSQLQueryTool := mcp.NewTool("run_sql_query",
+		mcp.WithDescription("Execute a SQL query against a connection string or a DTSX connection manager and return the rows (requires server.enable_sql_execution)"),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("SQL query to execute"),
+		),
+		mcp.WithString("connection_string",
+			mcp.Description("Connection string for the target database"),
+		),
+		mcp.WithString("connection_name",
+			mcp.Description("Name of a connection manager in file_path whose connection string is used"),
+		),
+		mcp.WithString("file_path",
+			mcp.Description("Path to the DTSX file used to look up connection_name (relative to package directory if set)"),
+		),
+		mcp.WithString("driver",
+			mcp.Description("database/sql driver name (default: sqlserver)"),
+		),
+		mcp.WithNumber("max_rows",
+			mcp.Description("Maximum number of rows to return (default: 100)"),
+		),
+		mcp.WithNumber("timeout_seconds",
+			mcp.Description("Query timeout in seconds (default: 30)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format: text, json, csv, html, markdown, table (default: text)"),
+		),
+		mcp.WithString("output_file_path",
+			mcp.Description("Destination path to write the tool result (relative to package directory if set)"),
+		),
+	)
+	s.AddTool(runSQLQueryTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return database.HandleRunSQLQuery(ctx, request, packageDirectory, databaseOptions)
+	})
 	// Batch Processing Tools
 
 	// Tool for batch analysis of multiple DTSX files
 	batchAnalyzeTool := mcp.NewTool("batch_analyze",
 		mcp.WithDescription("Analyze multiple DTSX files in parallel and provide aggregated results"),
 		mcp.WithArray("file_paths",
-			mcp.Required(),
-			mcp.Description("Array of DTSX file paths to analyze (relative to package directory if set)"),
+			mcp.Description("Array of DTSX file paths to analyze (relative to package directory if set); required unless glob_pattern is set"),
 			mcp.Items(map[string]any{"type": "string"}),
 		),
+		mcp.WithString("glob_pattern",
+			mcp.Description("filepath.Glob pattern of files to analyze, e.g. \"ETL/*.dtsx\" (relative to package directory if set). Combined with file_paths when both are set, without duplicates"),
+		),
 		mcp.WithString("format",
 			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
 		),
 		mcp.WithNumber("max_concurrent",
 			mcp.Description("Maximum number of concurrent analyses (default: 4)"),
 		),
+		mcp.WithBoolean("benchmark",
+			mcp.Description("Report parse, analysis and total wall-clock time per file with p50/p90/p99 percentiles (default: false)"),
+		),
+		mcp.WithBoolean("disable_progress",
+			mcp.Description("Suppress per-file progress events, sent as SSE notifications over HTTP streaming or written to stderr in stdio mode (default: false)"),
+		),
 		mcp.WithString("output_file_path",
-			mcp.Description("Destination path to write the tool result (relative to package directory if set)"),
+			mcp.Description("Destination path to write the tool result (relative to package directory if set); each package's result is also written to <name>.json in the same directory as soon as it completes"),
 		),
 	)
 	s.AddTool(batchAnalyzeTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		return packagehandlers.HandleBatchAnalyze(ctx, request, packageDirectory)
 	})
 
-	registerWorkflowRunnerTool(s, packageDirectory, excludeFile)
+	// Tool to run every curated analysis on a single DTSX file
+	summarizeAllTool := mcp.NewTool("summarize_all",
+		mcp.WithDescription("Run the core analysis tools on a DTSX file concurrently and combine their results into a single report"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the DTSX file (relative to package directory if set, or absolute path)"),
+		),
+		mcp.WithArray("sections",
+			mcp.Description("Sections to include by tool name (e.g. scan_credentials); prefix a name with '-' to exclude it (default: all sections)"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format: text, json, csv, html, markdown (default: text)"),
+		),
+		mcp.WithString("output_file_path",
+			mcp.Description("Destination path to write the tool result (relative to package directory if set)"),
+		),
+	)
+	s.AddTool(summarizeAllTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return summary.HandleSummarizeAll(ctx, request, packageDirectory)
+	})
+
+	// Tool to render package control flow as a Mermaid flowchart
+	generateMermaidDiagramTool := mcp.NewTool("generate_mermaid_diagram",
+		mcp.WithDescription("Generate a Mermaid flowchart of a DTSX file's control flow, with containers as subgraphs and precedence constraints as labeled edges"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the DTSX file (relative to package directory if set, or absolute path)"),
+		),
+		mcp.WithString("output_file_path",
+			mcp.Description("Destination .mmd file for the diagram (relative to package directory if set)"),
+		),
+	)
+	s.AddTool(generateMermaidDiagramTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return diagram.HandleGenerateMermaidDiagram(ctx, request, packageDirectory)
+	})
+
+	// Tool to export package-level dependencies as a GraphViz graph
+	generateDependencyGraphTool := mcp.NewTool("generate_dependency_graph",
+		mcp.WithDescription("Generate a GraphViz DOT graph of package dependencies: shared connections and Execute Package Task references"),
+		mcp.WithString("directory",
+			mcp.Description("Directory to scan for DTSX files (relative to package directory if set; default: package directory)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format: dot, svg (default: dot). svg requires the GraphViz dot command and falls back to DOT when unavailable"),
+		),
+		mcp.WithString("output_file_path",
+			mcp.Description("Destination path to write the graph (relative to package directory if set)"),
+		),
+	)
+	s.AddTool(generateDependencyGraphTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return diagram.HandleGenerateDependencyGraph(ctx, request, packageDirectory, excludeFile)
+	})
+
+	// Tool to count hard-coded values for CI checks and dashboards
+	countHardcodedValuesTool := mcp.NewTool("count_hardcoded_values",
+		mcp.WithDescription("Count hard-coded values in a DTSX file by category and report whether the total exceeds a threshold, returning JSON only"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the DTSX file (relative to package directory if set)"),
+		),
+		mcp.WithNumber("threshold",
+			mcp.Description("Maximum number of hard-coded values allowed before exceeds_threshold is true (default: 0)"),
+		),
+		mcp.WithString("output_file_path",
+			mcp.Description("Destination path to write the JSON result (relative to package directory if set)"),
+		),
+	)
+	s.AddTool(countHardcodedValuesTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return packagehandlers.HandleCountHardcodedValues(ctx, request, packageDirectory)
+	})
+
+	registerWorkflowRunnerTool(s, packageDirectory, excludeFile, config)
+
+	// Every tool accepts truncate_output, applied by TruncateOutputMiddleware
+	serverutil.AddTruncateOutputParameter(s)
 
 	if config.Server.HTTPMode {
 		// Run in HTTP streaming mode
@@ -1215,7 +1608,7 @@ func main() {
 	}
 }
 
-func registerWorkflowRunnerTool(s *server.MCPServer, packageDirectory, excludeFile string) {
+func registerWorkflowRunnerTool(s *server.MCPServer, packageDirectory, excludeFile string, cfg config.Config) {
 	workflowRunnerTool := mcp.NewTool("workflow_runner",
 		mcp.WithDescription("Execute a workflow definition file and run each referenced MCP tool step sequentially"),
 		mcp.WithString("file_path",
@@ -1228,14 +1621,82 @@ func registerWorkflowRunnerTool(s *server.MCPServer, packageDirectory, excludeFi
 		mcp.WithString("output_file_path",
 			mcp.Description("Destination path to write the workflow summary (relative to package directory if set)"),
 		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Validate step tools, required parameters and step output references without executing any step (default: false)"),
+		),
 	)
 
+	lookup := workflowToolLookup(s)
+	arrayParams := workflowArrayParameters(s)
 	s.AddTool(workflowRunnerTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		return handleWorkflowRunner(ctx, request, packageDirectory, excludeFile)
+		return handleWorkflowRunner(ctx, request, packageDirectory, excludeFile, cfg, lookup, arrayParams)
 	})
 }
 
-func handleWorkflowRunner(ctx context.Context, request mcp.CallToolRequest, packageDirectory, excludeFile string) (*mcp.CallToolResult, error) {
+// workflowToolLookup resolves workflow step tools against the tools registered on the server
+func workflowToolLookup(s *server.MCPServer) workflow.ToolLookup {
+	return func(tool string, _ map[string]interface{}) ([]string, bool) {
+		registered := s.GetTool(tool)
+		if registered == nil || tool == "workflow_runner" {
+			return nil, false
+		}
+		return registered.Tool.InputSchema.Required, true
+	}
+}
+
+// workflowArrayParameters reports which parameters of a registered tool its input schema declares as arrays
+func workflowArrayParameters(s *server.MCPServer) func(tool string) map[string]bool {
+	return func(tool string) map[string]bool {
+		registered := s.GetTool(tool)
+		if registered == nil {
+			return nil
+		}
+		arrays := make(map[string]bool)
+		for name, property := range registered.Tool.InputSchema.Properties {
+			if schema, ok := property.(map[string]interface{}); ok && schema["type"] == "array" {
+				arrays[name] = true
+			}
+		}
+		return arrays
+	}
+}
+
+// pipeStructuredOutput passes the previous step's result as an array to the array parameters in arrayParams
+// that received its text output, so a JSON array piped into a parameter such as file_paths arrives as a list of
+// items. String parameters keep the text even when it is a JSON array.
+func pipeStructuredOutput(params map[string]interface{}, arrayParams map[string]bool, previousText string, previousStructured interface{}) {
+	items, ok := structuredItems(previousStructured)
+	if !ok || previousText == "" {
+		return
+	}
+	for key, value := range params {
+		if text, ok := value.(string); ok && arrayParams[key] && text == previousText {
+			params[key] = append([]interface{}{}, items...)
+		}
+	}
+}
+
+// structuredItems returns the elements of a structured result that is a slice or array
+func structuredItems(value interface{}) ([]interface{}, bool) {
+	if items, ok := value.([]interface{}); ok {
+		return items, true
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, false
+	}
+	items := make([]interface{}, v.Len())
+	for i := range items {
+		items[i] = v.Index(i).Interface()
+	}
+	return items, true
+}
+
+func handleWorkflowRunner(ctx context.Context, request mcp.CallToolRequest, packageDirectory, excludeFile string, cfg config.Config, lookup workflow.ToolLookup, arrayParams func(tool string) map[string]bool) (*mcp.CallToolResult, error) {
+	fileOptions := files.Options{AllowAbsolutePaths: cfg.Packages.AllowAbsolutePaths}
+	environmentOptions := environment.Options{AllowWrite: cfg.Server.AllowEnvWrite}
+	databaseOptions := database.Options{Enabled: cfg.Server.EnableSQLExecution}
+	templateOptions := templatehandlers.Options{EnablePlugins: cfg.Server.EnableTemplatePlugins}
 	args, _ := request.Params.Arguments.(map[string]interface{})
 
 	workflowPath := workflowutil.ExtractStringArg(args, "file_path")
@@ -1262,19 +1723,46 @@ func handleWorkflowRunner(ctx context.Context, request mcp.CallToolRequest, pack
 		return mcp.NewToolResultError("workflow_runner expects a file, not a directory"), nil
 	}
 
+	if request.GetBool("dry_run", false) {
+		report := workflow.DryRunFile(workflowPath, lookup)
+		if strings.EqualFold(workflowutil.ExtractStringArg(args, "format"), "json") {
+			data, marshalErr := json.MarshalIndent(report, "", "  ")
+			if marshalErr != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal dry run report: %v", marshalErr)), nil
+			}
+			return mcp.NewToolResultText(string(data)), nil
+		}
+		return mcp.NewToolResultText(workflowutil.FormatDryRunReportMarkdown(report)), nil
+	}
+
 	workflowDir := filepath.Dir(workflowPath)
 	var writtenOutputs []string
+	// previousText and previousStructured hold the last step's output so piped values can be passed structured
+	var previousText string
+	var previousStructured interface{}
 
 	runner := func(stepCtx context.Context, tool string, params map[string]interface{}) (string, error) {
 		normalized := workflowutil.CloneArguments(params)
+		if arrayParams != nil {
+			pipeStructuredOutput(normalized, arrayParams(tool), previousText, previousStructured)
+		}
 
 		workflowutil.NormalizeWorkflowPathArg(normalized, workflowPath, "file_path")
+		workflowutil.NormalizeWorkflowPathArg(normalized, workflowPath, "file_path1")
+		workflowutil.NormalizeWorkflowPathArg(normalized, workflowPath, "file_path2")
+		workflowutil.NormalizeWorkflowPathArg(normalized, workflowPath, "file_path_base")
 		workflowutil.NormalizeWorkflowPathArg(normalized, workflowPath, "outputFilePath")
 		workflowutil.NormalizeWorkflowPathArg(normalized, workflowPath, "templateFilePath")
 		workflowutil.NormalizeWorkflowPathArg(normalized, workflowPath, "output_file_path")
 		workflowutil.NormalizeWorkflowPathArg(normalized, workflowPath, "template_file_path")
 		workflowutil.NormalizeWorkflowPathArg(normalized, workflowPath, "json_file_path")
 		workflowutil.NormalizeWorkflowPathArg(normalized, workflowPath, "jsonFilePath")
+		workflowutil.NormalizeWorkflowPathArg(normalized, workflowPath, "content_file_path")
+		workflowutil.NormalizeWorkflowPathArg(normalized, workflowPath, "source_path")
+		workflowutil.NormalizeWorkflowPathArg(normalized, workflowPath, "destination_path")
+		workflowutil.NormalizeWorkflowPathArg(normalized, workflowPath, "rules_file")
+		workflowutil.NormalizeWorkflowPathArg(normalized, workflowPath, "registry_file")
+		workflowutil.NormalizeWorkflowPathArg(normalized, workflowPath, "glob_pattern")
 		workflowutil.NormalizeWorkflowPathArrayArg(normalized, workflowPath, "file_paths")
 
 		if tool == "list_packages" {
@@ -1292,20 +1780,23 @@ func handleWorkflowRunner(ctx context.Context, request mcp.CallToolRequest, pack
 				if !ok {
 					return "", fmt.Errorf("batch_analyze: jsonData must be a string value")
 				}
-				files, err := workflowutil.ExtractFilePathsFromJSON(jsonText)
+				files, err := workflowutil.ExtractFilePathsFromJSON(jsonText, workflowutil.StringFromAny(normalized["json_path"]))
 				if err != nil {
-					return "", err
+					return "", fmt.Errorf("batch_analyze: %w", err)
 				}
 				normalized["file_paths"] = workflowutil.ToInterfaceSlice(files)
 				delete(normalized, "jsonData")
 			}
+			delete(normalized, "json_path")
 		}
 
 		req := mcp.CallToolRequest{
 			Params: mcp.CallToolParams{
+				Name:      tool,
 				Arguments: normalized,
 			},
 		}
+		serverutil.ToolLogger(req).Debug("workflow step invoked", "workflow", workflowPath)
 
 		var result *mcp.CallToolResult
 		switch tool {
@@ -1327,6 +1818,24 @@ func handleWorkflowRunner(ctx context.Context, request mcp.CallToolRequest, pack
 				return "", err
 			}
 			result = res
+		case "summarize_all":
+			res, err := summary.HandleSummarizeAll(stepCtx, req, packageDirectory)
+			if err != nil {
+				return "", err
+			}
+			result = res
+		case "generate_mermaid_diagram":
+			res, err := diagram.HandleGenerateMermaidDiagram(stepCtx, req, packageDirectory)
+			if err != nil {
+				return "", err
+			}
+			result = res
+		case "generate_dependency_graph":
+			res, err := diagram.HandleGenerateDependencyGraph(stepCtx, req, packageDirectory, excludeFile)
+			if err != nil {
+				return "", err
+			}
+			result = res
 		case "analyze_logging_configuration":
 			res, err := packagehandlers.HandleAnalyzeLoggingConfiguration(stepCtx, req, packageDirectory)
 			if err != nil {
@@ -1340,7 +1849,7 @@ func handleWorkflowRunner(ctx context.Context, request mcp.CallToolRequest, pack
 			}
 			result = res
 		case "render_template":
-			res, err := templatehandlers.HandleRenderTemplate(stepCtx, req, packageDirectory)
+			res, err := templatehandlers.HandleRenderTemplate(stepCtx, req, packageDirectory, templateOptions)
 			if err != nil {
 				return "", err
 			}
@@ -1351,6 +1860,42 @@ func handleWorkflowRunner(ctx context.Context, request mcp.CallToolRequest, pack
 				return "", err
 			}
 			result = res
+		case "write_text_file":
+			res, err := files.HandleWriteTextFile(stepCtx, req, packageDirectory, fileOptions)
+			if err != nil {
+				return "", err
+			}
+			result = res
+		case "delete_file":
+			res, err := files.HandleDeleteFile(stepCtx, req, packageDirectory, fileOptions)
+			if err != nil {
+				return "", err
+			}
+			result = res
+		case "move_file":
+			res, err := files.HandleMoveFile(stepCtx, req, packageDirectory, fileOptions)
+			if err != nil {
+				return "", err
+			}
+			result = res
+		case "get_environment_variable":
+			res, err := environment.HandleGetEnvironmentVariable(stepCtx, req)
+			if err != nil {
+				return "", err
+			}
+			result = res
+		case "set_environment_variable":
+			res, err := environment.HandleSetEnvironmentVariable(stepCtx, req, environmentOptions)
+			if err != nil {
+				return "", err
+			}
+			result = res
+		case "run_sql_query":
+			res, err := database.HandleRunSQLQuery(stepCtx, req, packageDirectory, databaseOptions)
+			if err != nil {
+				return "", err
+			}
+			result = res
 		case "analyze_data_flow_detailed":
 			res, err := analysis.HandleAnalyzeDataFlowDetailed(stepCtx, req, packageDirectory)
 			if err != nil {
@@ -1399,6 +1944,12 @@ func handleWorkflowRunner(ctx context.Context, request mcp.CallToolRequest, pack
 				return "", err
 			}
 			result = res
+		case "extract_checkpoint_config":
+			res, err := extraction.HandleExtractCheckpointConfig(stepCtx, req, packageDirectory)
+			if err != nil {
+				return "", err
+			}
+			result = res
 		case "extract_script_code":
 			res, err := extraction.HandleExtractScriptCode(stepCtx, req, packageDirectory)
 			if err != nil {
@@ -1417,6 +1968,18 @@ func handleWorkflowRunner(ctx context.Context, request mcp.CallToolRequest, pack
 				return "", err
 			}
 			result = res
+		case "analyze_cdc_control_task":
+			res, err := packagehandlers.HandleAnalyzeCdcControlTask(stepCtx, req, packageDirectory)
+			if err != nil {
+				return "", err
+			}
+			result = res
+		case "analyze_wmi_task":
+			res, err := packagehandlers.HandleAnalyzeWmiTask(stepCtx, req, packageDirectory)
+			if err != nil {
+				return "", err
+			}
+			result = res
 		case "analyze_script_task":
 			res, err := packagehandlers.HandleAnalyzeScriptTask(stepCtx, req, packageDirectory)
 			if err != nil {
@@ -1429,6 +1992,12 @@ func handleWorkflowRunner(ctx context.Context, request mcp.CallToolRequest, pack
 				return "", err
 			}
 			result = res
+		case "count_hardcoded_values":
+			res, err := packagehandlers.HandleCountHardcodedValues(stepCtx, req, packageDirectory)
+			if err != nil {
+				return "", err
+			}
+			result = res
 		case "analyze_source":
 			res, err := analysis.HandleAnalyzeSource(stepCtx, req, packageDirectory)
 			if err != nil {
@@ -1603,6 +2172,12 @@ func handleWorkflowRunner(ctx context.Context, request mcp.CallToolRequest, pack
 				return "", err
 			}
 			result = res
+		case "analyze_for_loop_container":
+			res, err := analysis.HandleAnalyzeForLoopContainer(stepCtx, req, packageDirectory)
+			if err != nil {
+				return "", err
+			}
+			result = res
 		case "analyze_custom_components":
 			res, err := analysis.HandleAnalyzeCustomComponents(stepCtx, req, packageDirectory)
 			if err != nil {
@@ -1667,10 +2242,16 @@ func handleWorkflowRunner(ctx context.Context, request mcp.CallToolRequest, pack
 			return "", fmt.Errorf("workflow runner: tool %q is not supported", tool)
 		}
 
+		result = serverutil.TruncateResult(result, req.GetInt(serverutil.TruncateOutputParameter, 0))
 		text, err := workflow.ToolResultToString(result)
 		if err != nil {
 			return "", err
 		}
+		previousText = text
+		previousStructured, err = workflow.ToolResultToStructured(result)
+		if err != nil {
+			slog.Debug("workflow step result has no structured value", "tool", tool, "error", err)
+		}
 
 		renderedPath := ""
 		if tool == "render_template" {
@@ -1694,11 +2275,8 @@ func handleWorkflowRunner(ctx context.Context, request mcp.CallToolRequest, pack
 				if marshalErr != nil {
 					return "", fmt.Errorf("failed to marshal structured tool result: %w", marshalErr)
 				}
-				if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
-					return "", fmt.Errorf("failed to create workflow output directory %s: %w", filepath.Dir(outputPath), err)
-				}
-				if err := os.WriteFile(outputPath, append(data, '\n'), 0o644); err != nil {
-					return "", fmt.Errorf("failed to write workflow output %s: %w", outputPath, err)
+				if err := workflowutil.WriteWorkflowOutput(outputPath, string(data)+"\n"); err != nil {
+					return "", err
 				}
 			} else {
 				// If no structured content is available, only write text when the
@@ -1735,15 +2313,15 @@ func handleWorkflowRunner(ctx context.Context, request mcp.CallToolRequest, pack
 	}
 
 	// Write any combined step outputs declared by the workflow steps.
-	if combined, cerr := workflow.WriteCombinedStepOutputs(workflowPath, wf, results); cerr == nil {
-		writtenOutputs = append(writtenOutputs, combined...)
-	} else {
-		// helper already prints errors to stderr; still append any files that were written
-		writtenOutputs = append(writtenOutputs, combined...)
+	combined, err := workflow.WriteCombinedStepOutputs(workflowPath, wf, results)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Workflow execution failed: %v", err)), nil
 	}
+	writtenOutputs = append(writtenOutputs, combined...)
 
 	summary := workflowutil.CreateWorkflowExecutionSummary(workflowPath, wf, results, writtenOutputs)
 
+	var text string
 	format := strings.ToLower(workflowutil.ExtractStringArg(args, "format"))
 	switch format {
 	case "json":
@@ -1751,9 +2329,14 @@ func handleWorkflowRunner(ctx context.Context, request mcp.CallToolRequest, pack
 		if marshalErr != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal workflow summary: %v", marshalErr)), nil
 		}
-		return mcp.NewToolResultText(string(data)), nil
+		text = string(data)
 	default:
-		markdown := workflowutil.FormatWorkflowSummaryMarkdown(summary)
-		return mcp.NewToolResultText(markdown), nil
+		text = workflowutil.FormatWorkflowSummaryMarkdown(summary)
+	}
+
+	// Steps whose assertions failed under fail_fast: false still fail the workflow as a whole
+	if summary.HasAssertionFailures() {
+		return mcp.NewToolResultError(text), nil
 	}
+	return mcp.NewToolResultText(text), nil
 }
@@ -151,7 +151,7 @@ func TestLoadConfigInvalidFile(t *testing.T) {
 	assert.Error(t, err)
 }
 
-// TestLoadConfigInvalidJSON tests loading invalid JSON configuration (should fall back to defaults)
+// TestLoadConfigInvalidJSON tests loading invalid JSON configuration (parsed as YAML, rejected for its unknown key)
 func TestLoadConfigInvalidJSON(t *testing.T) {
 	tmpDir := t.TempDir()
 	configFile := filepath.Join(tmpDir, "invalid.json")
@@ -160,10 +160,9 @@ func TestLoadConfigInvalidJSON(t *testing.T) {
 	err := os.WriteFile(configFile, []byte(`{"invalid": json}`), 0644)
 	require.NoError(t, err)
 
-	loadedConfig, err := config.LoadConfig(configFile)
-	// Should succeed with default values
-	assert.NoError(t, err)
-	assert.Equal(t, config.DefaultConfig(), loadedConfig)
+	_, err = config.LoadConfig(configFile)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown config keys: invalid")
 }
 
 // TestLoadConfigInvalidYAML tests loading invalid YAML configuration
@@ -10,9 +10,11 @@ import (
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/MCPRUNNER/gossisMCP/pkg/config"
 	"github.com/MCPRUNNER/gossisMCP/pkg/handlers/analysis"
 	"github.com/MCPRUNNER/gossisMCP/pkg/handlers/extraction"
 	packagehandlers "github.com/MCPRUNNER/gossisMCP/pkg/handlers/packages"
@@ -746,3 +748,96 @@ func TestMCPToolHandlerWithPackageDirectory(t *testing.T) {
 	assert.True(t, ok, "Expected TextContent")
 	assert.Contains(t, textContent.Text, "parse_dtsx")
 }
+
+// TestWorkflowRunnerComparePackagesRelativePaths tests that compare_packages steps resolve file_path1 and file_path2 against the workflow file
+func TestWorkflowRunnerComparePackagesRelativePaths(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "packages"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "workflows"), 0o755))
+	for _, name := range []string{"Checkpoint.dtsx", "MessageQueue.dtsx"} {
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "packages", name), data, 0o644))
+	}
+	workflowPath := filepath.Join(dir, "workflows", "compare.json")
+	definition := `{"Steps":[{"Name":"Compare","Type":"#compare_packages","Parameters":{"file_path1":"../packages/Checkpoint.dtsx","file_path2":"../packages/MessageQueue.dtsx"},"Enabled":true,"Output":{"Name":"Report","Format":"text"}}]}`
+	require.NoError(t, os.WriteFile(workflowPath, []byte(definition), 0o644))
+
+	request := createTestCallToolRequest("workflow_runner", map[string]interface{}{
+		"file_path": workflowPath,
+		"format":    "json",
+	})
+	result, err := handleWorkflowRunner(context.Background(), request, "", "", config.DefaultConfig(), nil, nil)
+	require.NoError(t, err)
+	require.False(t, result.IsError, "workflow failed: %v", result.Content)
+
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "Package Comparison Report")
+	assert.NotContains(t, text, "failed to read")
+}
+
+func TestPipeStructuredOutputPassesArrays(t *testing.T) {
+	previousText := "[\n  \"a.dtsx\",\n  \"b.dtsx\"\n]"
+	params := map[string]interface{}{"file_paths": previousText, "format": "json"}
+	pipeStructuredOutput(params, map[string]bool{"file_paths": true}, previousText, []interface{}{"a.dtsx", "b.dtsx"})
+	assert.Equal(t, []interface{}{"a.dtsx", "b.dtsx"}, params["file_paths"])
+	assert.Equal(t, "json", params["format"])
+
+	// Typed structured content is passed as items too
+	params = map[string]interface{}{"file_paths": previousText}
+	pipeStructuredOutput(params, map[string]bool{"file_paths": true}, previousText, []string{"a.dtsx", "b.dtsx"})
+	assert.Equal(t, []interface{}{"a.dtsx", "b.dtsx"}, params["file_paths"])
+
+	// Objects and plain text keep being piped as text
+	params = map[string]interface{}{"json_data": `{"count":1}`}
+	pipeStructuredOutput(params, map[string]bool{"json_data": true}, `{"count":1}`, map[string]interface{}{"count": float64(1)})
+	assert.Equal(t, `{"count":1}`, params["json_data"])
+}
+
+func TestPipeStructuredOutputKeepsStringParams(t *testing.T) {
+	s := server.NewMCPServer("test", "1.0.0")
+	s.AddTool(mcp.NewTool("batch_analyze",
+		mcp.WithArray("file_paths", mcp.WithStringItems()),
+		mcp.WithString("jsonData"),
+	), func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) { return nil, nil })
+	arrayParams := workflowArrayParameters(s)
+	require.Equal(t, map[string]bool{"file_paths": true}, arrayParams("batch_analyze"))
+	require.Nil(t, arrayParams("missing_tool"))
+
+	// A string parameter that receives array JSON text, e.g. "jsonData": "{Prev.Content}", keeps the text
+	previousText := `["a.dtsx","b.dtsx"]`
+	params := map[string]interface{}{"jsonData": previousText, "file_paths": previousText}
+	pipeStructuredOutput(params, arrayParams("batch_analyze"), previousText, []interface{}{"a.dtsx", "b.dtsx"})
+	assert.Equal(t, previousText, params["jsonData"])
+	assert.Equal(t, []interface{}{"a.dtsx", "b.dtsx"}, params["file_paths"])
+}
+
+func TestWorkflowRunnerLineageReportFixture(t *testing.T) {
+	dir := t.TempDir()
+	copies := map[string]string{
+		".gossismcp/workflows/workflow_lineage_report.json": ".gossismcp/workflows/workflow_lineage_report.json",
+		".gossismcp/templates/lineage_report.html":          ".gossismcp/templates/lineage_report.html",
+		"testdata/ColumnLineage.dtsx":                       "testdata/ColumnLineage.dtsx",
+	}
+	for source, target := range copies {
+		data, err := os.ReadFile(source)
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, target)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, target), data, 0o644))
+	}
+
+	request := createTestCallToolRequest("workflow_runner", map[string]interface{}{
+		"file_path": filepath.Join(dir, ".gossismcp", "workflows", "workflow_lineage_report.json"),
+	})
+	result, err := handleWorkflowRunner(context.Background(), request, "", "", config.DefaultConfig(), nil, nil)
+	require.NoError(t, err)
+	require.False(t, result.IsError, "workflow failed: %v", result.Content)
+
+	report, err := os.ReadFile(filepath.Join(dir, ".gossismcp", "Output", "lineage_report.html"))
+	require.NoError(t, err)
+	html := string(report)
+	assert.Contains(t, html, `<h2 class="package-name mono">ColumnLineage.dtsx</h2>`)
+	assert.Contains(t, html, "Build Full Name &rarr; Sort By Name")
+	assert.Contains(t, html, `<td class="mono">CustomerKey</td>`)
+	assert.NotContains(t, html, "No lineage data available")
+}
@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -12,22 +15,27 @@ import (
 
 // Config represents the application configuration
 type Config struct {
-	Server   ServerConfig  `json:"server" yaml:"server"`
-	Packages PackageConfig `json:"packages" yaml:"packages"`
-	Logging  LoggingConfig `json:"logging" yaml:"logging"`
-	Plugins  PluginConfig  `json:"plugins" yaml:"plugins"`
+	Server   ServerConfig   `json:"server" yaml:"server"`
+	Packages PackageConfig  `json:"packages" yaml:"packages"`
+	Logging  LoggingConfig  `json:"logging" yaml:"logging"`
+	Plugins  PluginConfig   `json:"plugins" yaml:"plugins"`
+	Analysis AnalysisConfig `json:"analysis" yaml:"analysis"`
 }
 
 // ServerConfig holds server-related configuration
 type ServerConfig struct {
-	HTTPMode bool   `json:"http_mode" yaml:"http_mode"`
-	Port     string `json:"port" yaml:"port"`
+	HTTPMode              bool   `json:"http_mode" yaml:"http_mode"`
+	Port                  string `json:"port" yaml:"port"`
+	AllowEnvWrite         bool   `json:"allow_env_write" yaml:"allow_env_write"`
+	EnableSQLExecution    bool   `json:"enable_sql_execution" yaml:"enable_sql_execution"`
+	EnableTemplatePlugins bool   `json:"enable_template_plugins" yaml:"enable_template_plugins"`
 }
 
 // PackageConfig holds package directory configuration
 type PackageConfig struct {
-	Directory   string `json:"directory" yaml:"directory"`
-	ExcludeFile string `json:"exclude_file" yaml:"exclude_file"`
+	Directory          string `json:"directory" yaml:"directory"`
+	ExcludeFile        string `json:"exclude_file" yaml:"exclude_file"`
+	AllowAbsolutePaths bool   `json:"allow_absolute_paths" yaml:"allow_absolute_paths"`
 }
 
 // LoggingConfig holds logging configuration
@@ -36,6 +44,12 @@ type LoggingConfig struct {
 	Format string `json:"format" yaml:"format"`
 }
 
+// AnalysisConfig holds settings for the package analysis tools
+type AnalysisConfig struct {
+	// ServerNamePatterns are hostname substrings that mark a server as environment-specific
+	ServerNamePatterns []string `json:"server_name_patterns" yaml:"server_name_patterns"`
+}
+
 // PluginConfig holds plugin system configuration
 type PluginConfig struct {
 	PluginDir         string         `json:"plugin_dir" yaml:"plugin_dir"`
@@ -49,8 +63,10 @@ type PluginConfig struct {
 type PluginSecurity struct {
 	AllowNetworkAccess bool     `json:"allow_network_access" yaml:"allow_network_access"`
 	AllowedDomains     []string `json:"allowed_domains" yaml:"allowed_domains"`
-	SignatureRequired  bool     `json:"signature_required" yaml:"signature_required"`
-	TrustedPublishers  []string `json:"trusted_publishers" yaml:"trusted_publishers"`
+	// AllowedDirectories maps a plugin ID to a directory its tools may access in addition to the package directory
+	AllowedDirectories map[string]string `json:"allowed_directories" yaml:"allowed_directories"`
+	SignatureRequired  bool              `json:"signature_required" yaml:"signature_required"`
+	TrustedPublishers  []string          `json:"trusted_publishers" yaml:"trusted_publishers"`
 }
 
 // DefaultConfig returns a default configuration
@@ -68,6 +84,9 @@ func DefaultConfig() Config {
 			Format: "text",
 		},
 		Plugins: DefaultPluginConfig(),
+		Analysis: AnalysisConfig{
+			ServerNamePatterns: []string{"PROD", "DEV", "UAT", "QA"},
+		},
 	}
 }
 
@@ -95,47 +114,157 @@ func LoadConfig(configPath string) (Config, error) {
 	return config, nil
 }
 
-// loadConfigFile loads configuration from JSON or YAML file
+// loadConfigFile loads configuration from a JSON or YAML file. Files with a .yml or .yaml
+// extension are parsed as YAML; anything else is tried as JSON first and then YAML.
+// Keys that do not correspond to a config field are rejected.
 func loadConfigFile(configPath string, config *Config) error {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return err
 	}
 
-	// Try JSON first
-	if err := json.Unmarshal(data, config); err != nil {
+	unmarshal := json.Unmarshal
+	if ext := strings.ToLower(filepath.Ext(configPath)); ext == ".yml" || ext == ".yaml" {
+		unmarshal = yaml.Unmarshal
+		if err := yaml.Unmarshal(data, config); err != nil {
+			return fmt.Errorf("failed to parse YAML config: %w", err)
+		}
+	} else if err := json.Unmarshal(data, config); err != nil {
 		// Try YAML
 		if yamlErr := yaml.Unmarshal(data, config); yamlErr != nil {
 			return fmt.Errorf("failed to parse config as JSON or YAML: %v, %v", err, yamlErr)
 		}
+		unmarshal = yaml.Unmarshal
+	}
+
+	var raw map[string]interface{}
+	if err := unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to parse config keys: %w", err)
+	}
+	if unknown := unknownKeys(raw, reflect.TypeOf(Config{}), ""); len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown config keys: %s", strings.Join(unknown, ", "))
 	}
 
 	return nil
 }
 
-// loadEnvironmentConfig loads configuration overrides from environment variables
-func loadEnvironmentConfig(config *Config) error {
-	// Server configuration
-	if port := os.Getenv("GOSSIS_HTTP_PORT"); port != "" {
-		config.Server.Port = port
+// unknownKeys returns the dotted paths of keys in raw that have no matching JSON tag in t
+func unknownKeys(raw map[string]interface{}, t reflect.Type, prefix string) []string {
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonFieldName(t.Field(i)); name != "" {
+			fields[name] = t.Field(i)
+		}
 	}
 
-	// Package directory
-	if pkgDir := os.Getenv("GOSSIS_PKG_DIRECTORY"); pkgDir != "" {
-		config.Packages.Directory = pkgDir
+	var unknown []string
+	for key, value := range raw {
+		path := prefix + key
+		field, ok := fields[key]
+		if !ok {
+			unknown = append(unknown, path)
+			continue
+		}
+		if nested, isMap := value.(map[string]interface{}); isMap && field.Type.Kind() == reflect.Struct {
+			unknown = append(unknown, unknownKeys(nested, field.Type, path+".")...)
+		}
 	}
+	return unknown
+}
 
-	// Logging configuration
-	if logLevel := os.Getenv("GOSSIS_LOG_LEVEL"); logLevel != "" {
-		config.Logging.Level = logLevel
-	}
-	if logFormat := os.Getenv("GOSSIS_LOG_FORMAT"); logFormat != "" {
-		config.Logging.Format = logFormat
+// environmentPrefix is prepended to every configuration environment variable
+const environmentPrefix = "GOSSIS"
+
+// legacyEnvironmentVariables maps the original environment variable names to their config fields
+var legacyEnvironmentVariables = []struct {
+	name  string
+	field func(*Config) *string
+}{
+	{"GOSSIS_HTTP_PORT", func(c *Config) *string { return &c.Server.Port }},
+	{"GOSSIS_PKG_DIRECTORY", func(c *Config) *string { return &c.Packages.Directory }},
+	{"GOSSIS_LOG_LEVEL", func(c *Config) *string { return &c.Logging.Level }},
+	{"GOSSIS_LOG_FORMAT", func(c *Config) *string { return &c.Logging.Format }},
+}
+
+// loadEnvironmentConfig loads configuration overrides from environment variables. Every
+// field is mapped to GOSSIS_<SECTION>_<FIELD> using its JSON tag (for example
+// GOSSIS_SERVER_PORT or GOSSIS_PLUGINS_SECURITY_ALLOWED_DOMAINS); the older short names
+// such as GOSSIS_HTTP_PORT are still honored but the full names take precedence.
+func loadEnvironmentConfig(config *Config) error {
+	for _, legacy := range legacyEnvironmentVariables {
+		if value := os.Getenv(legacy.name); value != "" {
+			*legacy.field(config) = value
+		}
 	}
 
+	return applyEnvironment(reflect.ValueOf(config).Elem(), environmentPrefix)
+}
+
+// applyEnvironment sets struct fields from their GOSSIS_ environment variables
+func applyEnvironment(value reflect.Value, prefix string) error {
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := jsonFieldName(field)
+		if name == "" {
+			continue
+		}
+		envName := prefix + "_" + strings.ToUpper(name)
+		target := value.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			if err := applyEnvironment(target, envName); err != nil {
+				return err
+			}
+			continue
+		}
+
+		raw, ok := os.LookupEnv(envName)
+		if !ok || raw == "" {
+			continue
+		}
+		switch field.Type.Kind() {
+		case reflect.String:
+			target.SetString(raw)
+		case reflect.Bool:
+			parsed, err := strconv.ParseBool(raw)
+			if err != nil {
+				return fmt.Errorf("invalid boolean for %s: %s", envName, raw)
+			}
+			target.SetBool(parsed)
+		case reflect.Slice:
+			if field.Type.Elem().Kind() != reflect.String {
+				return fmt.Errorf("unsupported type for %s: %s", envName, field.Type)
+			}
+			var items []string
+			for _, item := range strings.Split(raw, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			target.Set(reflect.ValueOf(items))
+		default:
+			return fmt.Errorf("unsupported type for %s: %s", envName, field.Type)
+		}
+	}
 	return nil
 }
 
+// jsonFieldName returns the JSON key for a struct field, or an empty string if it is skipped
+func jsonFieldName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name
+}
+
 // ValidateConfig validates the configuration
 func ValidateConfig(config Config) error {
 	// Validate port
@@ -178,6 +307,15 @@ func mergeConfigs(base, override Config) Config {
 	if override.Server.HTTPMode {
 		result.Server.HTTPMode = override.Server.HTTPMode
 	}
+	if override.Server.AllowEnvWrite {
+		result.Server.AllowEnvWrite = override.Server.AllowEnvWrite
+	}
+	if override.Server.EnableSQLExecution {
+		result.Server.EnableSQLExecution = override.Server.EnableSQLExecution
+	}
+	if override.Server.EnableTemplatePlugins {
+		result.Server.EnableTemplatePlugins = override.Server.EnableTemplatePlugins
+	}
 
 	// Merge package config
 	if override.Packages.Directory != "" {
@@ -186,6 +324,9 @@ func mergeConfigs(base, override Config) Config {
 	if override.Packages.ExcludeFile != "" {
 		result.Packages.ExcludeFile = override.Packages.ExcludeFile
 	}
+	if override.Packages.AllowAbsolutePaths {
+		result.Packages.AllowAbsolutePaths = override.Packages.AllowAbsolutePaths
+	}
 
 	// Merge logging config
 	if override.Logging.Level != "" {
@@ -195,6 +336,11 @@ func mergeConfigs(base, override Config) Config {
 		result.Logging.Format = override.Logging.Format
 	}
 
+	// Merge analysis config
+	if len(override.Analysis.ServerNamePatterns) > 0 {
+		result.Analysis.ServerNamePatterns = override.Analysis.ServerNamePatterns
+	}
+
 	return result
 }
 
@@ -208,6 +354,7 @@ func DefaultPluginConfig() PluginConfig {
 		Security: PluginSecurity{
 			AllowNetworkAccess: false,
 			AllowedDomains:     []string{},
+			AllowedDirectories: map[string]string{},
 			SignatureRequired:  false,
 			TrustedPublishers:  []string{"gossisMCP"},
 		},
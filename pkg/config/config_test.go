@@ -1,9 +1,13 @@
 package config
 
 import (
-	"log"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -106,18 +110,191 @@ func TestLoadConfigFromJSON(t *testing.T) {
 	}
 }
 
-func TestConfigureLogging(t *testing.T) {
-	originalFlags := log.Flags()
-	t.Cleanup(func() { log.SetFlags(originalFlags) })
+func TestLoadConfigYAMLMatchesJSON(t *testing.T) {
+	jsonConfig, err := LoadConfig(filepath.Join("testdata", "config.json"))
+	if err != nil {
+		t.Fatalf("expected JSON config to load, got error %v", err)
+	}
+	yamlConfig, err := LoadConfig(filepath.Join("testdata", "config.yaml"))
+	if err != nil {
+		t.Fatalf("expected YAML config to load, got error %v", err)
+	}
+	if !reflect.DeepEqual(jsonConfig, yamlConfig) {
+		t.Fatalf("expected identical configs\njson: %+v\nyaml: %+v", jsonConfig, yamlConfig)
+	}
+	if yamlConfig.Server.Port != "9191" || !yamlConfig.Packages.AllowAbsolutePaths {
+		t.Fatalf("expected values from YAML fixture, got %+v", yamlConfig)
+	}
+	if len(yamlConfig.Plugins.Security.TrustedPublishers) != 2 {
+		t.Fatalf("expected nested plugin settings from YAML, got %+v", yamlConfig.Plugins.Security)
+	}
+}
+
+func TestLoadConfigYAMLEnvironmentOverride(t *testing.T) {
+	t.Setenv("GOSSIS_HTTP_PORT", "7070")
+	cfg, err := LoadConfig(filepath.Join("testdata", "config.yaml"))
+	if err != nil {
+		t.Fatalf("expected YAML config to load, got error %v", err)
+	}
+	if cfg.Server.Port != "7070" {
+		t.Fatalf("expected env override for port, got %s", cfg.Server.Port)
+	}
+}
+
+func TestLoadConfigRejectsInvalidYAML(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(filePath, []byte("server: [unterminated"), 0o644); err != nil {
+		t.Fatalf("failed to write temp config: %v", err)
+	}
+	if _, err := LoadConfig(filePath); err == nil || !strings.Contains(err.Error(), "YAML") {
+		t.Fatalf("expected YAML parse error, got %v", err)
+	}
+}
+
+func TestLoadConfigEnvironmentMapping(t *testing.T) {
+	packageDir := t.TempDir()
+	t.Setenv("GOSSIS_SERVER_PORT", "6060")
+	t.Setenv("GOSSIS_SERVER_HTTP_MODE", "true")
+	t.Setenv("GOSSIS_SERVER_ENABLE_SQL_EXECUTION", "1")
+	t.Setenv("GOSSIS_PACKAGES_DIRECTORY", packageDir)
+	t.Setenv("GOSSIS_LOGGING_LEVEL", "error")
+	t.Setenv("GOSSIS_PLUGINS_PLUGIN_DIR", "/opt/plugins")
+	t.Setenv("GOSSIS_PLUGINS_ENABLED_PLUGINS", "first, second")
+	t.Setenv("GOSSIS_PLUGINS_SECURITY_SIGNATURE_REQUIRED", "true")
+
+	cfg, err := LoadConfig(filepath.Join("testdata", "config.json"))
+	if err != nil {
+		t.Fatalf("expected config to load, got error %v", err)
+	}
+	if cfg.Server.Port != "6060" || !cfg.Server.HTTPMode || !cfg.Server.EnableSQLExecution {
+		t.Fatalf("expected server env overrides, got %+v", cfg.Server)
+	}
+	if cfg.Packages.Directory != packageDir {
+		t.Fatalf("expected package directory %s, got %s", packageDir, cfg.Packages.Directory)
+	}
+	if cfg.Logging.Level != "error" {
+		t.Fatalf("expected log level error, got %s", cfg.Logging.Level)
+	}
+	if cfg.Plugins.PluginDir != "/opt/plugins" {
+		t.Fatalf("expected plugin dir override, got %s", cfg.Plugins.PluginDir)
+	}
+	if !reflect.DeepEqual(cfg.Plugins.EnabledPlugins, []string{"first", "second"}) {
+		t.Fatalf("expected enabled plugins from env, got %v", cfg.Plugins.EnabledPlugins)
+	}
+	if !cfg.Plugins.Security.SignatureRequired {
+		t.Fatal("expected nested security override to apply")
+	}
+	if cfg.Packages.ExcludeFile != ".gossisignore" {
+		t.Fatalf("expected unset fields to keep file values, got %s", cfg.Packages.ExcludeFile)
+	}
+}
+
+func TestLoadConfigServerNamePatterns(t *testing.T) {
+	cfg, err := LoadConfig("")
+	if err != nil {
+		t.Fatalf("expected config to load, got error %v", err)
+	}
+	if !reflect.DeepEqual(cfg.Analysis.ServerNamePatterns, []string{"PROD", "DEV", "UAT", "QA"}) {
+		t.Fatalf("expected default server name patterns, got %v", cfg.Analysis.ServerNamePatterns)
+	}
+
+	t.Setenv("GOSSIS_ANALYSIS_SERVER_NAME_PATTERNS", "PRD, STG")
+	cfg, err = LoadConfig("")
+	if err != nil {
+		t.Fatalf("expected config to load, got error %v", err)
+	}
+	if !reflect.DeepEqual(cfg.Analysis.ServerNamePatterns, []string{"PRD", "STG"}) {
+		t.Fatalf("expected server name patterns from env, got %v", cfg.Analysis.ServerNamePatterns)
+	}
+}
+
+func TestLoadConfigEnvironmentPrecedence(t *testing.T) {
+	t.Setenv("GOSSIS_HTTP_PORT", "5050")
+	t.Setenv("GOSSIS_SERVER_PORT", "6060")
+	cfg, err := LoadConfig("")
+	if err != nil {
+		t.Fatalf("expected config to load, got error %v", err)
+	}
+	if cfg.Server.Port != "6060" {
+		t.Fatalf("expected GOSSIS_SERVER_PORT to win over legacy name, got %s", cfg.Server.Port)
+	}
+}
+
+func TestLoadConfigEnvironmentInvalidBool(t *testing.T) {
+	t.Setenv("GOSSIS_SERVER_HTTP_MODE", "maybe")
+	if _, err := LoadConfig(""); err == nil || !strings.Contains(err.Error(), "GOSSIS_SERVER_HTTP_MODE") {
+		t.Fatalf("expected invalid boolean error naming the variable, got %v", err)
+	}
+}
+
+func TestLoadConfigRejectsUnknownKeys(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.json")
+	contents := `{
+        "server": {"port": "9090"},
+        "pakages": {"directory": ""},
+        "logging": {"level": "info", "formt": "text"}
+    }`
+	if err := os.WriteFile(filePath, []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write temp config: %v", err)
+	}
+	_, err := LoadConfig(filePath)
+	if err == nil {
+		t.Fatal("expected unknown keys to be rejected")
+	}
+	if !strings.Contains(err.Error(), "logging.formt, pakages") {
+		t.Fatalf("expected error to name the offending keys, got %v", err)
+	}
+}
+
+func TestLoadConfigRejectsUnknownYAMLKeys(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.yaml")
+	contents := "plugins:\n  security:\n    allow_network: true\n"
+	if err := os.WriteFile(filePath, []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write temp config: %v", err)
+	}
+	if _, err := LoadConfig(filePath); err == nil || !strings.Contains(err.Error(), "plugins.security.allow_network") {
+		t.Fatalf("expected error naming plugins.security.allow_network, got %v", err)
+	}
+}
 
-	ConfigureLogging(LoggingConfig{Level: "debug"})
-	if log.Flags()&log.Lmicroseconds == 0 {
-		t.Fatalf("expected microseconds flag when log level is debug")
+func TestNewLogHandlerFormats(t *testing.T) {
+	var buf bytes.Buffer
+	slog.New(NewLogHandler(&buf, LoggingConfig{Level: "info", Format: "json"})).Info("started", "tool", "parse_dtsx")
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected JSON log output, got %q: %v", buf.String(), err)
+	}
+	if entry["msg"] != "started" || entry["tool"] != "parse_dtsx" {
+		t.Fatalf("unexpected JSON log entry: %v", entry)
 	}
 
-	ConfigureLogging(LoggingConfig{Level: "info"})
-	expected := log.LstdFlags | log.Lshortfile
-	if log.Flags() != expected {
-		t.Fatalf("expected flags %d, got %d", expected, log.Flags())
+	buf.Reset()
+	slog.New(NewLogHandler(&buf, LoggingConfig{Level: "info", Format: "text"})).Info("started", "tool", "parse_dtsx")
+	if !strings.Contains(buf.String(), "msg=started tool=parse_dtsx") {
+		t.Fatalf("expected text log output, got %q", buf.String())
+	}
+}
+
+func TestNewLogHandlerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(&buf, LoggingConfig{Level: "warn", Format: "text"}))
+	logger.Info("hidden")
+	logger.Warn("shown")
+	if strings.Contains(buf.String(), "hidden") || !strings.Contains(buf.String(), "shown") {
+		t.Fatalf("expected only warn-level output, got %q", buf.String())
+	}
+
+	if !NewLogHandler(&buf, LoggingConfig{Level: "debug"}).Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatalf("expected debug level to be enabled")
+	}
+}
+
+func TestConfigureLoggingSetsDefaultLogger(t *testing.T) {
+	original := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(original) })
+
+	ConfigureLogging(LoggingConfig{Level: "debug", Format: "json"})
+	if !slog.Default().Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatalf("expected the default logger to log at debug level")
 	}
 }
@@ -1,15 +1,36 @@
 package config
 
 import (
-	"log"
+	"io"
+	"log/slog"
+	"os"
 	"strings"
 )
 
-// ConfigureLogging configures the logging based on the configuration
+// ConfigureLogging installs the default slog logger, writing to stderr so stdio transport output stays clean
 func ConfigureLogging(config LoggingConfig) {
-	// Set log level (simplified - in a real implementation you'd use a proper logging library)
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
-	if strings.ToLower(config.Level) == "debug" {
-		log.SetFlags(log.LstdFlags | log.Lshortfile | log.Lmicroseconds)
+	slog.SetDefault(slog.New(NewLogHandler(os.Stderr, config)))
+}
+
+// NewLogHandler creates a slog handler for the configured level and format (text or json)
+func NewLogHandler(w io.Writer, config LoggingConfig) slog.Handler {
+	options := &slog.HandlerOptions{Level: parseLogLevel(config.Level)}
+	if strings.EqualFold(config.Format, "json") {
+		return slog.NewJSONHandler(w, options)
+	}
+	return slog.NewTextHandler(w, options)
+}
+
+// parseLogLevel maps a configured level name to a slog level, defaulting to info
+func parseLogLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
 	}
 }
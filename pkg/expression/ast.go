@@ -0,0 +1,230 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Node is an element of a parsed SSIS expression
+type Node interface {
+	// Pos is the byte offset of the node in the parsed text
+	Pos() int
+	// String renders the node as SSIS expression syntax, parenthesizing nested operators
+	String() string
+}
+
+type position int
+
+func (p position) Pos() int { return int(p) }
+
+// VariableRef is a variable or parameter reference such as @[User::Counter], @[$Project::Server] or @Counter
+type VariableRef struct {
+	position
+	Namespace string
+	Name      string
+}
+
+func (v *VariableRef) String() string {
+	if v.Namespace == "" {
+		return "@[" + v.Name + "]"
+	}
+	return "@[" + v.Namespace + "::" + v.Name + "]"
+}
+
+// ColumnRef is a data flow column reference: [FirstName], a bare FirstName as written in a
+// FriendlyExpression, or a #{...} lineage reference, which keeps its full text in Lineage
+type ColumnRef struct {
+	position
+	Name    string
+	Lineage string
+}
+
+func (c *ColumnRef) String() string {
+	if c.Lineage != "" {
+		return "#{" + c.Lineage + "}"
+	}
+	return "[" + c.Name + "]"
+}
+
+// StringLiteral is a double-quoted string with its escape sequences decoded
+type StringLiteral struct {
+	position
+	Value string
+}
+
+func (s *StringLiteral) String() string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + replacer.Replace(s.Value) + `"`
+}
+
+// NumberLiteral is a numeric literal as written, including any type suffix such as 10L
+type NumberLiteral struct {
+	position
+	Text string
+}
+
+func (n *NumberLiteral) String() string { return n.Text }
+
+// BoolLiteral is TRUE or FALSE
+type BoolLiteral struct {
+	position
+	Value bool
+}
+
+func (b *BoolLiteral) String() string {
+	if b.Value {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+// TypeName is a data type passed as a function argument, as in NULL(DT_WSTR, 50)
+type TypeName struct {
+	position
+	Name string
+}
+
+func (t *TypeName) String() string { return t.Name }
+
+// FunctionCall is a call such as ISNULL([x]); Name keeps the case it was written in
+type FunctionCall struct {
+	position
+	Name string
+	Args []Node
+}
+
+func (f *FunctionCall) String() string {
+	args := make([]string, len(f.Args))
+	for i, arg := range f.Args {
+		args[i] = arg.String()
+	}
+	return f.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// Cast is a type cast such as (DT_WSTR, 50) [Code]; Params holds the length, precision, scale or code page
+type Cast struct {
+	position
+	Type    string
+	Params  []string
+	Operand Node
+}
+
+func (c *Cast) String() string {
+	parts := append([]string{c.Type}, c.Params...)
+	return "(" + strings.Join(parts, ", ") + ") " + nested(c.Operand)
+}
+
+// UnaryExpr is a negation (-), logical not (!) or bitwise not (~)
+type UnaryExpr struct {
+	position
+	Op      string
+	Operand Node
+}
+
+func (u *UnaryExpr) String() string { return u.Op + nested(u.Operand) }
+
+// BinaryExpr is an arithmetic, comparison, bitwise or logical operation
+type BinaryExpr struct {
+	position
+	Op    string
+	Left  Node
+	Right Node
+}
+
+func (b *BinaryExpr) String() string {
+	return nested(b.Left) + " " + b.Op + " " + nested(b.Right)
+}
+
+// Conditional is the condition ? then : else operator
+type Conditional struct {
+	position
+	Condition Node
+	Then      Node
+	Else      Node
+}
+
+func (c *Conditional) String() string {
+	return nested(c.Condition) + " ? " + nested(c.Then) + " : " + nested(c.Else)
+}
+
+// Assignment sets a variable, as For Loop InitExpression and AssignExpression do
+type Assignment struct {
+	position
+	Variable *VariableRef
+	Value    Node
+}
+
+func (a *Assignment) String() string { return a.Variable.String() + " = " + a.Value.String() }
+
+// nested renders an operand, wrapping operators in parentheses so the result reads unambiguously
+func nested(node Node) string {
+	switch node.(type) {
+	case *BinaryExpr, *Conditional, *Assignment, *Cast:
+		return "(" + node.String() + ")"
+	default:
+		return node.String()
+	}
+}
+
+// Walk visits node and its descendants depth-first in source order, skipping the children of any node for
+// which fn returns false
+func Walk(node Node, fn func(Node) bool) {
+	if node == nil || !fn(node) {
+		return
+	}
+	switch n := node.(type) {
+	case *FunctionCall:
+		for _, arg := range n.Args {
+			Walk(arg, fn)
+		}
+	case *Cast:
+		Walk(n.Operand, fn)
+	case *UnaryExpr:
+		Walk(n.Operand, fn)
+	case *BinaryExpr:
+		Walk(n.Left, fn)
+		Walk(n.Right, fn)
+	case *Conditional:
+		Walk(n.Condition, fn)
+		Walk(n.Then, fn)
+		Walk(n.Else, fn)
+	case *Assignment:
+		Walk(n.Variable, fn)
+		Walk(n.Value, fn)
+	}
+}
+
+// Variables returns every variable reference in node, in source order
+func Variables(node Node) []*VariableRef {
+	var variables []*VariableRef
+	Walk(node, func(n Node) bool {
+		if v, ok := n.(*VariableRef); ok {
+			variables = append(variables, v)
+		}
+		return true
+	})
+	return variables
+}
+
+// Columns returns every column reference in node, in source order
+func Columns(node Node) []*ColumnRef {
+	var columns []*ColumnRef
+	Walk(node, func(n Node) bool {
+		if c, ok := n.(*ColumnRef); ok {
+			columns = append(columns, c)
+		}
+		return true
+	})
+	return columns
+}
+
+// SyntaxError reports where and why an expression failed to parse
+type SyntaxError struct {
+	// Offset is the byte offset of the problem in the expression
+	Offset  int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Offset+1)
+}
@@ -0,0 +1,73 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Finding is a problem found in a parsed expression
+type Finding struct {
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	// Offset is the byte offset of the offending node in the expression
+	Offset int `json:"offset"`
+}
+
+// functionArity maps each SSIS expression function to its minimum and maximum argument counts
+var functionArity = map[string][2]int{
+	"ABS": {1, 1}, "CEILING": {1, 1}, "CODEPOINT": {1, 1}, "DATEADD": {3, 3}, "DATEDIFF": {3, 3},
+	"DATEPART": {2, 2}, "DAY": {1, 1}, "EXP": {1, 1}, "FINDSTRING": {3, 3}, "FLOOR": {1, 1},
+	"GETDATE": {0, 0}, "GETUTCDATE": {0, 0}, "HEX": {1, 1}, "ISNULL": {1, 1}, "LEFT": {2, 2},
+	"LEN": {1, 1}, "LN": {1, 1}, "LOG": {1, 1}, "LOWER": {1, 1}, "LTRIM": {1, 1}, "MONTH": {1, 1},
+	"NULL": {1, 4}, "POWER": {2, 2}, "REPLACE": {3, 3}, "REPLACENULL": {2, 2}, "REPLICATE": {2, 2},
+	"REVERSE": {1, 1}, "RIGHT": {2, 2}, "ROUND": {2, 2}, "RTRIM": {1, 1}, "SIGN": {1, 1},
+	"SQRT": {1, 1}, "SQUARE": {1, 1}, "SUBSTRING": {3, 3}, "TOKEN": {3, 3}, "TOKENCOUNT": {2, 2},
+	"TRIM": {1, 1}, "UPPER": {1, 1}, "YEAR": {1, 1},
+}
+
+// Lint checks a parsed expression for calls to unknown functions, calls with the wrong number of arguments
+// and comparisons with NULL, which are always NULL rather than true or false
+func Lint(node Node) []Finding {
+	var findings []Finding
+	Walk(node, func(n Node) bool {
+		switch n := n.(type) {
+		case *FunctionCall:
+			arity, ok := functionArity[strings.ToUpper(n.Name)]
+			switch {
+			case !ok:
+				findings = append(findings, Finding{Severity: SeverityError, Offset: n.Pos(), Message: fmt.Sprintf("%s is not an SSIS expression function", n.Name)})
+			case len(n.Args) < arity[0] || len(n.Args) > arity[1]:
+				findings = append(findings, Finding{Severity: SeverityError, Offset: n.Pos(), Message: fmt.Sprintf("%s takes %s, not %d", strings.ToUpper(n.Name), describeArity(arity), len(n.Args))})
+			}
+		case *BinaryExpr:
+			if (n.Op == "==" || n.Op == "!=") && (isNullCall(n.Left) || isNullCall(n.Right)) {
+				findings = append(findings, Finding{Severity: SeverityWarning, Offset: n.Pos(), Message: fmt.Sprintf("%s compares with NULL, which never evaluates to true; use ISNULL() instead", n)})
+			}
+		}
+		return true
+	})
+	return findings
+}
+
+func describeArity(arity [2]int) string {
+	plural := func(n int) string {
+		if n == 1 {
+			return "1 argument"
+		}
+		return fmt.Sprintf("%d arguments", n)
+	}
+	if arity[0] == arity[1] {
+		return plural(arity[0])
+	}
+	return fmt.Sprintf("%d to %s", arity[0], plural(arity[1]))
+}
+
+func isNullCall(node Node) bool {
+	call, ok := node.(*FunctionCall)
+	return ok && strings.EqualFold(call.Name, "NULL")
+}
@@ -0,0 +1,462 @@
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenVariable
+	tokenColumn
+	tokenLineage
+	tokenIdent
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	// text is the token as written, except for strings, columns and lineage references, where it is the
+	// decoded value without its delimiters
+	text   string
+	offset int
+}
+
+// operators lists the operators and punctuation, two-character forms first so they match greedily
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "+", "-", "*", "/", "%", "<", ">", "!", "~", "&", "|", "^", "(", ")", ",", "?", ":", "="}
+
+// binaryPrecedence ranks the binary operators from loosest (1) to tightest binding
+var binaryPrecedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"|":  3,
+	"^":  4,
+	"&":  5,
+	"==": 6, "!=": 6,
+	"<": 7, ">": 7, "<=": 7, ">=": 7,
+	"+": 8, "-": 8,
+	"*": 9, "/": 9, "%": 9,
+}
+
+// stringEscapes decodes the single-character escape sequences allowed in string literals
+var stringEscapes = map[byte]string{
+	'a': "\a", 'b': "\b", 'f': "\f", 'n': "\n", 'r': "\r", 't': "\t", 'v': "\v", '0': "\x00", '"': `"`, '\\': `\`,
+}
+
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '"':
+			value, end, err := scanString(text, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value, offset: i})
+			i = end
+		case c == '@':
+			end, err := scanVariable(text, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenVariable, text: text[i:end], offset: i})
+			i = end
+		case c == '[':
+			end := strings.IndexByte(text[i:], ']')
+			if end < 0 {
+				return nil, &SyntaxError{Offset: i, Message: "unterminated column reference"}
+			}
+			tokens = append(tokens, token{kind: tokenColumn, text: text[i+1 : i+end], offset: i})
+			i += end + 1
+		case c == '#' && strings.HasPrefix(text[i:], "#{"):
+			end := strings.IndexByte(text[i:], '}')
+			if end < 0 {
+				return nil, &SyntaxError{Offset: i, Message: "unterminated lineage reference"}
+			}
+			tokens = append(tokens, token{kind: tokenLineage, text: text[i+2 : i+end], offset: i})
+			i += end + 1
+		case isDigit(c):
+			end := scanNumber(text, i)
+			tokens = append(tokens, token{kind: tokenNumber, text: text[i:end], offset: i})
+			i = end
+		case isIdentStart(c):
+			end := i + 1
+			for end < len(text) && isIdentPart(text[end]) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: text[i:end], offset: i})
+			i = end
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(text[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, &SyntaxError{Offset: i, Message: fmt.Sprintf("unexpected character %q", c)}
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, offset: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, offset: len(text)}), nil
+}
+
+// scanString decodes the string literal starting at the quote at start and returns it with the offset
+// just past its closing quote
+func scanString(text string, start int) (string, int, error) {
+	var value strings.Builder
+	for i := start + 1; i < len(text); i++ {
+		switch text[i] {
+		case '"':
+			return value.String(), i + 1, nil
+		case '\\':
+			if i+1 >= len(text) {
+				return "", 0, &SyntaxError{Offset: i, Message: "unterminated escape sequence"}
+			}
+			if decoded, ok := stringEscapes[text[i+1]]; ok {
+				value.WriteString(decoded)
+				i++
+				continue
+			}
+			// \xhhhh is a Unicode character in hexadecimal
+			if text[i+1] == 'x' && i+6 <= len(text) {
+				if code, err := strconv.ParseUint(text[i+2:i+6], 16, 32); err == nil {
+					value.WriteRune(rune(code))
+					i += 5
+					continue
+				}
+			}
+			return "", 0, &SyntaxError{Offset: i, Message: fmt.Sprintf("invalid escape sequence \\%c", text[i+1])}
+		default:
+			value.WriteByte(text[i])
+		}
+	}
+	return "", 0, &SyntaxError{Offset: start, Message: "unterminated string literal"}
+}
+
+// scanVariable returns the offset just past the variable reference starting at the @ at start
+func scanVariable(text string, start int) (int, error) {
+	i := start + 1
+	if i < len(text) && text[i] == '[' {
+		end := strings.IndexByte(text[i:], ']')
+		if end < 0 {
+			return 0, &SyntaxError{Offset: start, Message: "unterminated variable reference"}
+		}
+		return i + end + 1, nil
+	}
+	// Unbracketed references may be qualified as @Namespace::Name
+	for i < len(text) && (isIdentPart(text[i]) || text[i] == '$' || strings.HasPrefix(text[i:], "::")) {
+		if text[i] == ':' {
+			i++
+		}
+		i++
+	}
+	if i == start+1 {
+		return 0, &SyntaxError{Offset: start, Message: "expected a variable name after @"}
+	}
+	return i, nil
+}
+
+// scanNumber returns the offset just past the numeric literal starting at start, including a fraction,
+// exponent and type suffix such as L, U or E
+func scanNumber(text string, start int) int {
+	i := start
+	for i < len(text) && isDigit(text[i]) {
+		i++
+	}
+	if i+1 < len(text) && text[i] == '.' && isDigit(text[i+1]) {
+		i++
+		for i < len(text) && isDigit(text[i]) {
+			i++
+		}
+	}
+	if i < len(text) && (text[i] == 'e' || text[i] == 'E') {
+		j := i + 1
+		if j < len(text) && (text[j] == '+' || text[j] == '-') {
+			j++
+		}
+		if j < len(text) && isDigit(text[j]) {
+			i = j
+			for i < len(text) && isDigit(text[i]) {
+				i++
+			}
+		}
+	}
+	for i < len(text) && unicode.IsLetter(rune(text[i])) {
+		i++
+	}
+	return i
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isIdentStart(c byte) bool { return c == '_' || unicode.IsLetter(rune(c)) }
+
+func isIdentPart(c byte) bool { return isIdentStart(c) || isDigit(c) }
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Parse parses an SSIS expression. Assignments such as a For Loop's "@[User::i] = @[User::i] + 1" are
+// accepted at the top level; any syntax problem is returned as a *SyntaxError.
+func Parse(text string) (Node, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return nil, &SyntaxError{Offset: 0, Message: "empty expression"}
+	}
+	p := &parser{tokens: tokens}
+	node, err := p.parseAssignment()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, p.unexpected(next)
+	}
+	return node, nil
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// isOperator reports whether the next token is the operator op
+func (p *parser) isOperator(op string) bool {
+	tok := p.peek()
+	return tok.kind == tokenOperator && tok.text == op
+}
+
+func (p *parser) expect(op string) error {
+	if !p.isOperator(op) {
+		return &SyntaxError{Offset: p.peek().offset, Message: fmt.Sprintf("expected %q but found %s", op, describeToken(p.peek()))}
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) unexpected(tok token) error {
+	return &SyntaxError{Offset: tok.offset, Message: "unexpected " + describeToken(tok)}
+}
+
+func describeToken(tok token) string {
+	switch tok.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return "string literal"
+	default:
+		return fmt.Sprintf("%q", tok.text)
+	}
+}
+
+func (p *parser) parseAssignment() (Node, error) {
+	left, err := p.parseConditional()
+	if err != nil || !p.isOperator("=") {
+		return left, err
+	}
+	variable, ok := left.(*VariableRef)
+	if !ok {
+		return nil, &SyntaxError{Offset: p.peek().offset, Message: "only a variable can be assigned"}
+	}
+	p.next()
+	value, err := p.parseAssignment()
+	if err != nil {
+		return nil, err
+	}
+	return &Assignment{position: variable.position, Variable: variable, Value: value}, nil
+}
+
+func (p *parser) parseConditional() (Node, error) {
+	condition, err := p.parseBinary(1)
+	if err != nil || !p.isOperator("?") {
+		return condition, err
+	}
+	p.next()
+	then, err := p.parseConditional()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.parseConditional()
+	if err != nil {
+		return nil, err
+	}
+	return &Conditional{position: position(condition.Pos()), Condition: condition, Then: then, Else: otherwise}, nil
+}
+
+// parseBinary parses operators binding at least as tightly as minPrecedence; all binary operators are left
+// associative
+func (p *parser) parseBinary(minPrecedence int) (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		precedence, ok := binaryPrecedence[tok.text]
+		if tok.kind != tokenOperator || !ok || precedence < minPrecedence {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseBinary(precedence + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{position: position(left.Pos()), Op: tok.text, Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	tok := p.peek()
+	if tok.kind == tokenOperator && (tok.text == "-" || tok.text == "!" || tok.text == "~") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &UnaryExpr{position: position(tok.offset), Op: tok.text, Operand: operand}, nil
+	}
+	if p.isOperator("(") && isTypeName(p.tokens[p.pos+1]) {
+		return p.parseCast()
+	}
+	return p.parsePrimary()
+}
+
+// parseCast parses "(DT_TYPE[, param...]) operand"; the operand binds as tightly as a unary operator
+func (p *parser) parseCast() (Node, error) {
+	open := p.next()
+	cast := &Cast{position: position(open.offset), Type: strings.ToUpper(p.next().text)}
+	for p.isOperator(",") {
+		p.next()
+		param := p.next()
+		if param.kind != tokenNumber && param.kind != tokenIdent {
+			return nil, p.unexpected(param)
+		}
+		cast.Params = append(cast.Params, param.text)
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	cast.Operand = operand
+	return cast, nil
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.next()
+	at := position(tok.offset)
+	switch tok.kind {
+	case tokenNumber:
+		return &NumberLiteral{position: at, Text: tok.text}, nil
+	case tokenString:
+		return &StringLiteral{position: at, Value: tok.text}, nil
+	case tokenVariable:
+		return newVariableRef(at, tok.text), nil
+	case tokenColumn:
+		return &ColumnRef{position: at, Name: tok.text}, nil
+	case tokenLineage:
+		return &ColumnRef{position: at, Name: lineageColumnName(tok.text), Lineage: tok.text}, nil
+	case tokenIdent:
+		if p.isOperator("(") {
+			return p.parseCall(tok)
+		}
+		switch upper := strings.ToUpper(tok.text); {
+		case upper == "TRUE" || upper == "FALSE":
+			return &BoolLiteral{position: at, Value: upper == "TRUE"}, nil
+		case isTypeName(tok):
+			return &TypeName{position: at, Name: upper}, nil
+		default:
+			return &ColumnRef{position: at, Name: tok.text}, nil
+		}
+	case tokenOperator:
+		if tok.text == "(" {
+			inner, err := p.parseConditional()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return inner, nil
+		}
+	}
+	return nil, p.unexpected(tok)
+}
+
+func (p *parser) parseCall(name token) (Node, error) {
+	call := &FunctionCall{position: position(name.offset), Name: name.text}
+	p.next()
+	if p.isOperator(")") {
+		p.next()
+		return call, nil
+	}
+	for {
+		arg, err := p.parseConditional()
+		if err != nil {
+			return nil, err
+		}
+		call.Args = append(call.Args, arg)
+		if !p.isOperator(",") {
+			break
+		}
+		p.next()
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return call, nil
+}
+
+// newVariableRef splits "@[Namespace::Name]" or "@Name" into its parts
+func newVariableRef(at position, text string) *VariableRef {
+	name := strings.TrimPrefix(text, "@")
+	if strings.HasPrefix(name, "[") {
+		name = name[1 : len(name)-1]
+	}
+	ref := &VariableRef{position: at, Name: name}
+	if idx := strings.Index(name, "::"); idx >= 0 {
+		ref.Namespace, ref.Name = name[:idx], name[idx+2:]
+	}
+	return ref
+}
+
+// lineageColumnName returns the column name at the end of a lineage reference such as
+// "Package\Flow\Source.Outputs[Output].Columns[FirstName]"
+func lineageColumnName(lineage string) string {
+	idx := strings.LastIndex(lineage, ".Columns[")
+	if idx < 0 || !strings.HasSuffix(lineage, "]") {
+		return lineage
+	}
+	return lineage[idx+len(".Columns[") : len(lineage)-1]
+}
+
+func isTypeName(tok token) bool {
+	return tok.kind == tokenIdent && strings.HasPrefix(strings.ToUpper(tok.text), "DT_")
+}
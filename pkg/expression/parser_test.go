@@ -0,0 +1,130 @@
+package expression
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRendersPrecedence(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`[FirstName] + " " + [LastName]`, `([FirstName] + " ") + [LastName]`},
+		{`1 + 2 * 3`, `1 + (2 * 3)`},
+		{`@[User::Counter] < 10 && !@[User::Done] || @Retry`, `((@[User::Counter] < 10) && !@[User::Done]) || @[Retry]`},
+		{`ISNULL([Code]) ? "N/A" : UPPER(TRIM([Code]))`, `ISNULL([Code]) ? "N/A" : UPPER(TRIM([Code]))`},
+		{`(DT_WSTR, 50) [CustomerID] + "-" + (DT_WSTR,10)(-@[$Project::Batch])`, `(((DT_WSTR, 50) [CustomerID]) + "-") + ((DT_WSTR, 10) -@[$Project::Batch])`},
+		{`(DT_NUMERIC, 18, 2) ([Amount] * 1.5E2)`, `(DT_NUMERIC, 18, 2) ([Amount] * 1.5E2)`},
+		{`[a] == NULL(DT_WSTR, 20) ? TRUE : false`, `([a] == NULL(DT_WSTR, 20)) ? TRUE : FALSE`},
+		{`@[User::i] = @[User::i] + 1`, `@[User::i] = @[User::i] + 1`},
+		{`"say \"hi\"\n" + GETDATE()`, `"say \"hi\"\n" + GETDATE()`},
+		{`10L % 3 & 7 | 1 ^ ~0`, `((10L % 3) & 7) | (1 ^ ~0)`},
+	}
+
+	for _, tt := range tests {
+		node, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", tt.input, err)
+		}
+		if got := node.String(); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseBuildsNodes(t *testing.T) {
+	node, err := Parse(`(DT_WSTR, 50) #{Package\Flow\Source.Outputs[Output].Columns[FirstName]}`)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	cast, ok := node.(*Cast)
+	if !ok || cast.Type != "DT_WSTR" || !reflect.DeepEqual(cast.Params, []string{"50"}) {
+		t.Fatalf("expected a DT_WSTR cast with length 50, got %#v", node)
+	}
+	column, ok := cast.Operand.(*ColumnRef)
+	if !ok || column.Name != "FirstName" || column.Lineage != `Package\Flow\Source.Outputs[Output].Columns[FirstName]` {
+		t.Fatalf("expected a lineage column reference, got %#v", cast.Operand)
+	}
+
+	node, err = Parse(`@[User::Counter] <= @[$Package::Limit] - @Step`)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	var names []string
+	for _, v := range Variables(node) {
+		names = append(names, v.Namespace+"::"+v.Name)
+	}
+	if want := []string{"User::Counter", "$Package::Limit", "::Step"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("Variables() = %v, want %v", names, want)
+	}
+	if pos := Variables(node)[1].Pos(); pos != 20 {
+		t.Fatalf("expected @[$Package::Limit] at offset 20, got %d", pos)
+	}
+}
+
+func TestParseReportsSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		message string
+		offset  int
+	}{
+		{``, "empty expression", 0},
+		{`[a] +`, "unexpected end of expression", 5},
+		{`UPPER([a]`, `expected ")" but found end of expression`, 9},
+		{`"open`, "unterminated string literal", 0},
+		{`"bad \q"`, `invalid escape sequence \q`, 5},
+		{`[a] = 1`, "only a variable can be assigned", 4},
+		{`@[User::x] ? 1`, `expected ":" but found end of expression`, 14},
+		{`[a] $ 1`, `unexpected character '$'`, 4},
+		{`1 2`, `unexpected "2"`, 2},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.input)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("Parse(%q) error = %v, want a *SyntaxError", tt.input, err)
+		}
+		if syntaxErr.Message != tt.message || syntaxErr.Offset != tt.offset {
+			t.Errorf("Parse(%q) error = %q at %d, want %q at %d", tt.input, syntaxErr.Message, syntaxErr.Offset, tt.message, tt.offset)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{`ISNULL([a]) ? "" : LEFT([a], 3)`, nil},
+		{`Left([a], 3) + NULL(DT_WSTR, 10)`, nil},
+		{`LEFTT([a], 3)`, []string{"error: LEFTT is not an SSIS expression function"}},
+		{`SUBSTRING([a], 1)`, []string{"error: SUBSTRING takes 3 arguments, not 2"}},
+		{`GETDATE(1) > [d]`, []string{"error: GETDATE takes 0 arguments, not 1"}},
+		{`[a] != NULL(DT_I4)`, []string{"warning: [a] != NULL(DT_I4) compares with NULL, which never evaluates to true; use ISNULL() instead"}},
+	}
+
+	for _, tt := range tests {
+		node, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", tt.input, err)
+		}
+		var got []string
+		for _, finding := range Lint(node) {
+			got = append(got, finding.Severity+": "+finding.Message)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Lint(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSyntaxErrorMessageIsOneBased(t *testing.T) {
+	_, err := Parse(`[a] +`)
+	if err == nil || !strings.HasSuffix(err.Error(), "at position 6") {
+		t.Fatalf("expected a one-based position in %v", err)
+	}
+}
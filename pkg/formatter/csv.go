@@ -1,7 +1,9 @@
 package formatter
 
 import (
+	"encoding/csv"
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -10,11 +12,9 @@ type CSVFormatter struct{}
 
 func (f *CSVFormatter) Format(result *AnalysisResult) string {
 	if result.Error != "" {
-		return fmt.Sprintf("Error,%s\n", f.escapeCSV(result.Error))
+		return f.writeRecords([][]string{{"Error", result.Error}})
 	}
 
-	var output strings.Builder
-
 	// Handle different data types
 	switch v := result.Data.(type) {
 	case *TableData:
@@ -23,56 +23,29 @@ func (f *CSVFormatter) Format(result *AnalysisResult) string {
 		return f.formatSectionsCSV(v)
 	default:
 		// Fallback to simple format
-		output.WriteString("Tool,File,Timestamp,Status,Data,Error\n")
-		output.WriteString(fmt.Sprintf("%s,%s,%s,%s,%s,%s\n",
-			f.escapeCSV(result.ToolName),
-			f.escapeCSV(result.FilePath),
-			f.escapeCSV(result.Timestamp),
-			f.escapeCSV(result.Status),
-			f.escapeCSV(fmt.Sprintf("%v", result.Data)),
-			f.escapeCSV(result.Error)))
-		return output.String()
+		return f.writeRecords([][]string{
+			{"Tool", "File", "Timestamp", "Status", "Data", "Error"},
+			{result.ToolName, result.FilePath, result.Timestamp, result.Status, fmt.Sprintf("%v", result.Data), result.Error},
+		})
 	}
 }
 
 func (f *CSVFormatter) formatTableCSV(table *TableData) string {
-	var output strings.Builder
-
-	// Headers
-	for i, header := range table.Headers {
-		output.WriteString(f.escapeCSV(header))
-		if i < len(table.Headers)-1 {
-			output.WriteString(",")
-		}
-	}
-	output.WriteString("\n")
-
-	// Rows
-	for _, row := range table.Rows {
-		for i, cell := range row {
-			output.WriteString(f.escapeCSV(cell))
-			if i < len(row)-1 {
-				output.WriteString(",")
-			}
-		}
-		output.WriteString("\n")
-	}
-
-	return output.String()
+	records := make([][]string, 0, len(table.Rows)+1)
+	records = append(records, table.Headers)
+	records = append(records, table.Rows...)
+	return f.writeRecords(records)
 }
 
 func (f *CSVFormatter) formatSectionsCSV(sections []SectionData) string {
-	var output strings.Builder
-	output.WriteString("Section,Content\n")
-
+	records := [][]string{{"Section", "Content"}}
 	for _, section := range sections {
-		f.formatSectionCSV(&output, section, "")
+		records = f.appendSectionCSV(records, section, "")
 	}
-
-	return output.String()
+	return f.writeRecords(records)
 }
 
-func (f *CSVFormatter) formatSectionCSV(output *strings.Builder, section SectionData, prefix string) {
+func (f *CSVFormatter) appendSectionCSV(records [][]string, section SectionData, prefix string) [][]string {
 	sectionPath := section.Title
 	if prefix != "" {
 		sectionPath = prefix + " > " + section.Title
@@ -80,28 +53,36 @@ func (f *CSVFormatter) formatSectionCSV(output *strings.Builder, section Section
 
 	switch v := section.Content.(type) {
 	case string:
-		output.WriteString(fmt.Sprintf("%s,%s\n", f.escapeCSV(sectionPath), f.escapeCSV(v)))
+		records = append(records, []string{sectionPath, v})
 	case []string:
 		for _, s := range v {
-			output.WriteString(fmt.Sprintf("%s,%s\n", f.escapeCSV(sectionPath), f.escapeCSV(s)))
+			records = append(records, []string{sectionPath, s})
 		}
 	case map[string]interface{}:
-		for key, value := range v {
-			output.WriteString(fmt.Sprintf("%s,%s: %v\n", f.escapeCSV(sectionPath), f.escapeCSV(key), value))
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			records = append(records, []string{sectionPath, fmt.Sprintf("%s: %v", key, v[key])})
 		}
 	}
 
 	for _, subsection := range section.Subsections {
-		f.formatSectionCSV(output, subsection, sectionPath)
+		records = f.appendSectionCSV(records, subsection, sectionPath)
 	}
+	return records
 }
 
-func (f *CSVFormatter) escapeCSV(s string) string {
-	if strings.ContainsAny(s, ",\"\n") {
-		s = strings.ReplaceAll(s, "\"", "\"\"")
-		s = "\"" + s + "\""
+// writeRecords encodes records with encoding/csv so embedded commas, quotes and newlines are quoted
+func (f *CSVFormatter) writeRecords(records [][]string) string {
+	var output strings.Builder
+	writer := csv.NewWriter(&output)
+	if err := writer.WriteAll(records); err != nil {
+		return fmt.Sprintf("Error,%v\n", err)
 	}
-	return s
+	return output.String()
 }
 
 func (f *CSVFormatter) GetContentType() string {
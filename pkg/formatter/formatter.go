@@ -12,6 +12,9 @@ var formatters = map[OutputFormat]OutputFormatter{
 	FormatCSV:      &CSVFormatter{},
 	FormatHTML:     &HTMLFormatter{},
 	FormatMarkdown: &MarkdownFormatter{},
+	FormatSARIF:    &SARIFFormatter{},
+	FormatJUnit:    &JUnitFormatter{},
+	FormatTable:    &TableFormatter{},
 }
 
 // GetFormatter returns the formatter for the specified format
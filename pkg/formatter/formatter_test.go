@@ -1,9 +1,13 @@
 package formatter
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 func TestGetFormatterDefault(t *testing.T) {
@@ -49,6 +53,32 @@ func TestCSVFormatterWithTable(t *testing.T) {
 	}
 }
 
+func TestCSVFormatterRoundTripsSpecialCharacters(t *testing.T) {
+	value := "SELECT a, b FROM t WHERE name = \"x\"\nORDER BY a"
+	table := &TableData{
+		Headers: []string{"Finding", "Value"},
+		Rows:    [][]string{{"SQL Command", value}},
+	}
+	sections := []SectionData{{
+		Title:   "Findings",
+		Content: map[string]interface{}{"SqlCommand": value},
+	}}
+
+	for name, data := range map[string]interface{}{"table": table, "sections": sections} {
+		output := (&CSVFormatter{}).Format(&AnalysisResult{Data: data})
+		records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+		if err != nil {
+			t.Fatalf("%s: failed to parse CSV output %q: %v", name, output, err)
+		}
+		if len(records) != 2 {
+			t.Fatalf("%s: expected header and one row, got %d records", name, len(records))
+		}
+		if got := records[1][1]; !strings.HasSuffix(got, value) {
+			t.Fatalf("%s: expected value to round-trip, got %q", name, got)
+		}
+	}
+}
+
 func TestHTMLFormatterError(t *testing.T) {
 	result := &AnalysisResult{Error: "boom"}
 	output := (&HTMLFormatter{}).Format(result)
@@ -57,6 +87,69 @@ func TestHTMLFormatterError(t *testing.T) {
 	}
 }
 
+func TestHTMLFormatterSectionsAndSQL(t *testing.T) {
+	result := &AnalysisResult{
+		ToolName:  "Source",
+		FilePath:  "pkg.dtsx",
+		Timestamp: "now",
+		Data: []SectionData{
+			{Title: "OLE DB Source", Content: "SELECT Name, 'a<b' FROM dbo.Customers -- all rows"},
+			{Title: "Details", Content: map[string]interface{}{"columns": []interface{}{"Name"}}},
+		},
+	}
+	output := (&HTMLFormatter{}).Format(result)
+	for _, want := range []string{
+		`<nav class="sidebar">`,
+		`<a href="#section-ole-db-source">OLE DB Source</a>`,
+		`<section id="section-ole-db-source">`,
+		`<span class="kw">SELECT</span>`,
+		`<span class="str">&#39;a&lt;b&#39;</span>`,
+		`<span class="cmt">-- all rows</span>`,
+		`<details><summary>columns</summary>`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in HTML output, got %q", want, output)
+		}
+	}
+}
+
+func TestHTMLFormatterTableSummaryKeepsRunes(t *testing.T) {
+	cell := strings.Repeat("é", 150)
+	result := &AnalysisResult{
+		ToolName:  "Table",
+		FilePath:  "pkg.dtsx",
+		Timestamp: "now",
+		Data:      &TableData{Headers: []string{"Value"}, Rows: [][]string{{cell}}},
+	}
+	output := (&HTMLFormatter{}).Format(result)
+	if !utf8.ValidString(output) {
+		t.Fatal("expected the collapsed summary to stay valid UTF-8")
+	}
+	if want := "<summary>" + strings.Repeat("é", 80) + "…</summary>"; !strings.Contains(output, want) {
+		t.Fatalf("expected an 80-character summary, got %q", output)
+	}
+}
+
+func TestSetHTMLTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "custom.tmpl")
+	if err := os.WriteFile(path, []byte(`<custom>{{.ToolName}}</custom>`), 0o644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	original := htmlTemplate
+	defer func() { htmlTemplate = original }()
+
+	if err := SetHTMLTemplate(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	output := (&HTMLFormatter{}).Format(&AnalysisResult{ToolName: "Custom"})
+	if output != "<custom>Custom</custom>" {
+		t.Fatalf("expected custom template output, got %q", output)
+	}
+	if err := SetHTMLTemplate(filepath.Join(t.TempDir(), "missing.tmpl")); err == nil {
+		t.Fatal("expected error for missing template")
+	}
+}
+
 func TestMarkdownFormatterList(t *testing.T) {
 	result := &AnalysisResult{ToolName: "Lister", FilePath: "file", Timestamp: "now", Data: []string{"one", "two"}}
 	output := (&MarkdownFormatter{}).Format(result)
@@ -1,50 +1,140 @@
 package formatter
 
 import (
+	"bytes"
+	"embed"
 	"fmt"
 	"html"
+	"html/template"
+	"os"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
 )
 
+//go:embed templates/report.html.tmpl
+var templateFS embed.FS
+
+var (
+	htmlTemplateMu sync.RWMutex
+	htmlTemplate   = template.Must(template.ParseFS(templateFS, "templates/report.html.tmpl"))
+)
+
+// SetHTMLTemplate replaces the embedded HTML report template with the template file at path
+func SetHTMLTemplate(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read HTML template: %w", err)
+	}
+	tmpl, err := template.New("report").Parse(string(data))
+	if err != nil {
+		return fmt.Errorf("failed to parse HTML template: %w", err)
+	}
+
+	htmlTemplateMu.Lock()
+	htmlTemplate = tmpl
+	htmlTemplateMu.Unlock()
+	return nil
+}
+
+// htmlReport is the data passed to the HTML report template
+type htmlReport struct {
+	ToolName  string
+	FilePath  string
+	Timestamp string
+	Status    string
+	Error     string
+	Content   template.HTML
+	Sections  []htmlSection
+}
+
+// htmlSection is a rendered report section with an anchor for the navigation sidebar
+type htmlSection struct {
+	ID          string
+	Title       string
+	Level       int
+	Heading     template.HTML
+	Body        template.HTML
+	Subsections []htmlSection
+}
+
 // HTMLFormatter formats analysis results as HTML
 type HTMLFormatter struct{}
 
 func (f *HTMLFormatter) Format(result *AnalysisResult) string {
-	var output strings.Builder
+	report := htmlReport{
+		ToolName:  result.ToolName,
+		FilePath:  result.FilePath,
+		Timestamp: result.Timestamp,
+		Status:    result.Status,
+		Error:     result.Error,
+	}
+
+	if result.Error == "" {
+		if sections, ok := result.Data.([]SectionData); ok {
+			ids := make(map[string]int)
+			for _, section := range sections {
+				report.Sections = append(report.Sections, f.buildSection(section, 0, ids))
+			}
+		} else {
+			var content strings.Builder
+			f.formatDataHTML(&content, result.Data, 0)
+			report.Content = template.HTML(content.String())
+		}
+	}
+
+	htmlTemplateMu.RLock()
+	tmpl := htmlTemplate
+	htmlTemplateMu.RUnlock()
 
-	output.WriteString("<!DOCTYPE html>\n<html>\n<head>\n")
-	output.WriteString("<title>SSIS Analysis Report</title>\n")
-	output.WriteString("<style>\n")
-	output.WriteString("body { font-family: Arial, sans-serif; margin: 20px; }\n")
-	output.WriteString("h1 { color: #333; }\n")
-	output.WriteString("h2 { color: #666; margin-top: 30px; }\n")
-	output.WriteString("table { border-collapse: collapse; width: 100%; margin: 10px 0; }\n")
-	output.WriteString("th, td { border: 1px solid #ddd; padding: 8px; text-align: left; }\n")
-	output.WriteString("th { background-color: #f2f2f2; }\n")
-	output.WriteString("tr:nth-child(even) { background-color: #f9f9f9; }\n")
-	output.WriteString(".error { color: red; }\n")
-	output.WriteString(".success { color: green; }\n")
-	output.WriteString("</style>\n")
-	output.WriteString("</head>\n<body>\n")
-
-	if result.Error != "" {
-		output.WriteString(fmt.Sprintf("<h1 class=\"error\">Error</h1>\n<p>%s</p>\n", html.EscapeString(result.Error)))
-	} else {
-		output.WriteString(fmt.Sprintf("<h1>%s Analysis Report</h1>\n", html.EscapeString(result.ToolName)))
-		output.WriteString(fmt.Sprintf("<p><strong>File:</strong> %s</p>\n", html.EscapeString(result.FilePath)))
-		output.WriteString(fmt.Sprintf("<p><strong>Generated:</strong> %s</p>\n", html.EscapeString(result.Timestamp)))
-
-		f.formatDataHTML(&output, result.Data, 0)
-	}
-
-	output.WriteString("</body>\n</html>\n")
+	var output bytes.Buffer
+	if err := tmpl.Execute(&output, report); err != nil {
+		return fmt.Sprintf("<!DOCTYPE html>\n<html>\n<body>\n<h1 class=\"error\">Error</h1>\n<p>%s</p>\n</body>\n</html>\n",
+			html.EscapeString(fmt.Sprintf("failed to render HTML template: %v", err)))
+	}
 	return output.String()
 }
 
+func (f *HTMLFormatter) buildSection(section SectionData, level int, ids map[string]int) htmlSection {
+	id := sectionAnchor(section.Title)
+	ids[id]++
+	if ids[id] > 1 {
+		id = fmt.Sprintf("%s-%d", id, ids[id])
+	}
+
+	tag := fmt.Sprintf("h%d", min(level+2, 6))
+	var body strings.Builder
+	f.formatDataHTML(&body, section.Content, level)
+
+	built := htmlSection{
+		ID:      id,
+		Title:   section.Title,
+		Level:   level,
+		Heading: template.HTML(fmt.Sprintf("<%s>%s</%s>", tag, html.EscapeString(section.Title), tag)),
+		Body:    template.HTML(body.String()),
+	}
+	for _, subsection := range section.Subsections {
+		built.Subsections = append(built.Subsections, f.buildSection(subsection, level+1, ids))
+	}
+	return built
+}
+
+var anchorPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// sectionAnchor converts a section title into an HTML id
+func sectionAnchor(title string) string {
+	anchor := strings.Trim(anchorPattern.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if anchor == "" {
+		anchor = "section"
+	}
+	return "section-" + anchor
+}
+
 func (f *HTMLFormatter) formatDataHTML(output *strings.Builder, data interface{}, level int) {
 	switch v := data.(type) {
 	case string:
-		output.WriteString(fmt.Sprintf("<p>%s</p>\n", html.EscapeString(v)))
+		output.WriteString(formatTextHTML(v))
 	case []string:
 		output.WriteString("<ul>\n")
 		for _, s := range v {
@@ -52,8 +142,20 @@ func (f *HTMLFormatter) formatDataHTML(output *strings.Builder, data interface{}
 		}
 		output.WriteString("</ul>\n")
 	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
 		output.WriteString("<dl>\n")
-		for key, value := range v {
+		for _, key := range keys {
+			value := v[key]
+			if isCollapsible(value) {
+				output.WriteString(fmt.Sprintf("<dd><details><summary>%s</summary>\n", html.EscapeString(key)))
+				f.formatDataHTML(output, value, level)
+				output.WriteString("</details></dd>\n")
+				continue
+			}
 			output.WriteString(fmt.Sprintf("<dt>%s</dt>\n", html.EscapeString(key)))
 			output.WriteString("<dd>")
 			f.formatDataHTML(output, value, level)
@@ -67,12 +169,24 @@ func (f *HTMLFormatter) formatDataHTML(output *strings.Builder, data interface{}
 	case *TableData:
 		f.formatTableHTML(output, v)
 	case []SectionData:
-		f.formatSectionsHTML(output, v, level)
+		ids := make(map[string]int)
+		for _, section := range v {
+			built := f.buildSection(section, level, ids)
+			f.writeSectionHTML(output, built)
+		}
+	case nil:
 	default:
 		output.WriteString(fmt.Sprintf("<p>%s</p>\n", html.EscapeString(fmt.Sprintf("%v", v))))
 	}
 }
 
+func (f *HTMLFormatter) writeSectionHTML(output *strings.Builder, section htmlSection) {
+	output.WriteString(fmt.Sprintf("%s\n%s", section.Heading, section.Body))
+	for _, subsection := range section.Subsections {
+		f.writeSectionHTML(output, subsection)
+	}
+}
+
 func (f *HTMLFormatter) formatTableHTML(output *strings.Builder, table *TableData) {
 	output.WriteString("<table>\n<thead>\n<tr>\n")
 	for _, header := range table.Headers {
@@ -83,7 +197,18 @@ func (f *HTMLFormatter) formatTableHTML(output *strings.Builder, table *TableDat
 	for _, row := range table.Rows {
 		output.WriteString("<tr>\n")
 		for _, cell := range row {
-			output.WriteString(fmt.Sprintf("<td>%s</td>\n", html.EscapeString(cell)))
+			output.WriteString("<td>")
+			if isCollapsible(cell) {
+				summary := strings.SplitN(strings.TrimSpace(cell), "\n", 2)[0]
+				// Cut on a rune boundary so multi-byte characters are not split
+				if runes := []rune(summary); len(runes) > 80 {
+					summary = string(runes[:80])
+				}
+				output.WriteString(fmt.Sprintf("<details><summary>%s…</summary>\n%s</details>", html.EscapeString(summary), formatTextHTML(cell)))
+			} else {
+				output.WriteString(html.EscapeString(cell))
+			}
+			output.WriteString("</td>\n")
 		}
 		output.WriteString("</tr>\n")
 	}
@@ -91,21 +216,73 @@ func (f *HTMLFormatter) formatTableHTML(output *strings.Builder, table *TableDat
 	output.WriteString("</tbody>\n</table>\n")
 }
 
-func (f *HTMLFormatter) formatSectionsHTML(output *strings.Builder, sections []SectionData, level int) {
-	for _, section := range sections {
-		f.formatSectionHTML(output, &section, level)
+// isCollapsible reports whether a value is long or nested enough to render inside a details element
+func isCollapsible(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return len(v) > 120 || strings.Count(v, "\n") > 2
+	case map[string]interface{}, []interface{}, *TableData:
+		return true
+	default:
+		return false
+	}
+}
+
+// formatTextHTML renders a text value, keeping multi-line output preformatted and highlighting SQL
+func formatTextHTML(s string) string {
+	if looksLikeSQL(s) {
+		return fmt.Sprintf("<pre class=\"sql\">%s</pre>\n", highlightSQL(s))
+	}
+	if strings.Contains(s, "\n") {
+		return fmt.Sprintf("<pre>%s</pre>\n", html.EscapeString(s))
 	}
+	return fmt.Sprintf("<p>%s</p>\n", html.EscapeString(s))
 }
 
-func (f *HTMLFormatter) formatSectionHTML(output *strings.Builder, section *SectionData, level int) {
-	tag := fmt.Sprintf("h%d", level+2)
-	output.WriteString(fmt.Sprintf("<%s>%s</%s>\n", tag, html.EscapeString(section.Title), tag))
+var (
+	sqlStatementPattern = regexp.MustCompile(`(?i)^\s*(select|insert|update|delete|merge|exec|execute|with|create|alter|drop|truncate|declare)\b`)
+	sqlTokenPattern     = regexp.MustCompile(`--[^\n]*|/\*[\s\S]*?\*/|'(?:[^']|'')*'|\b\d+(?:\.\d+)?\b|\b[A-Za-z_]+\b`)
+	sqlKeywords         = map[string]bool{
+		"ADD": true, "ALL": true, "ALTER": true, "AND": true, "AS": true, "ASC": true, "BEGIN": true, "BETWEEN": true,
+		"BY": true, "CASE": true, "CREATE": true, "DECLARE": true, "DELETE": true, "DESC": true, "DISTINCT": true,
+		"DROP": true, "ELSE": true, "END": true, "EXEC": true, "EXECUTE": true, "EXISTS": true, "FROM": true,
+		"FULL": true, "GROUP": true, "HAVING": true, "IF": true, "IN": true, "INNER": true, "INSERT": true,
+		"INTO": true, "IS": true, "JOIN": true, "LEFT": true, "LIKE": true, "MERGE": true, "NOT": true,
+		"NULL": true, "ON": true, "OR": true, "ORDER": true, "OUTER": true, "RIGHT": true, "SELECT": true,
+		"SET": true, "TABLE": true, "THEN": true, "TOP": true, "TRUNCATE": true, "UNION": true, "UPDATE": true,
+		"USING": true, "VALUES": true, "WHEN": true, "WHERE": true, "WITH": true,
+	}
+)
 
-	f.formatDataHTML(output, section.Content, level)
+// looksLikeSQL reports whether a string starts with a SQL statement keyword
+func looksLikeSQL(s string) bool {
+	return sqlStatementPattern.MatchString(s)
+}
 
-	for _, subsection := range section.Subsections {
-		f.formatSectionHTML(output, &subsection, level+1)
+// highlightSQL escapes SQL text and wraps keywords, literals and comments in styled spans
+func highlightSQL(sql string) string {
+	var output strings.Builder
+	last := 0
+	for _, loc := range sqlTokenPattern.FindAllStringIndex(sql, -1) {
+		output.WriteString(html.EscapeString(sql[last:loc[0]]))
+		token := sql[loc[0]:loc[1]]
+		escaped := html.EscapeString(token)
+		switch {
+		case strings.HasPrefix(token, "--"), strings.HasPrefix(token, "/*"):
+			output.WriteString(`<span class="cmt">` + escaped + `</span>`)
+		case strings.HasPrefix(token, "'"):
+			output.WriteString(`<span class="str">` + escaped + `</span>`)
+		case token[0] >= '0' && token[0] <= '9':
+			output.WriteString(`<span class="num">` + escaped + `</span>`)
+		case sqlKeywords[strings.ToUpper(token)]:
+			output.WriteString(`<span class="kw">` + escaped + `</span>`)
+		default:
+			output.WriteString(escaped)
+		}
+		last = loc[1]
 	}
+	output.WriteString(html.EscapeString(sql[last:]))
+	return output.String()
 }
 
 func (f *HTMLFormatter) GetContentType() string {
@@ -0,0 +1,97 @@
+package formatter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// TestCase is a single check reported as a JUnit test case; a non-empty Failure marks the check as failed
+type TestCase struct {
+	Name    string `json:"name"`
+	Failure string `json:"failure,omitempty"`
+	Details string `json:"details,omitempty"`
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	File      string          `xml:"file,attr,omitempty"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnitFormatter formats analysis results as JUnit XML; results whose Data is not a []TestCase produce an empty test suite
+type JUnitFormatter struct{}
+
+func (f *JUnitFormatter) Format(result *AnalysisResult) string {
+	if result.Error != "" {
+		suite := newJUnitSuite(result.ToolName, result.FilePath, nil)
+		suite.Tests, suite.Errors = 1, 1
+		suite.Cases = []junitTestCase{{
+			ClassName: result.ToolName,
+			Name:      "analysis",
+			File:      result.FilePath,
+			Error:     &junitProblem{Message: result.Error},
+		}}
+		return marshalJUnit(suite)
+	}
+	cases, _ := result.Data.([]TestCase)
+	return marshalJUnit(newJUnitSuite(result.ToolName, result.FilePath, cases))
+}
+
+func (f *JUnitFormatter) GetContentType() string {
+	return "application/xml"
+}
+
+// FormatAsJUnit renders checks as a JUnit XML test suite named after the tool, with one test case per check
+func FormatAsJUnit(toolName, filePath string, cases []TestCase) string {
+	return marshalJUnit(newJUnitSuite(toolName, filePath, cases))
+}
+
+// newJUnitSuite maps checks to test cases with classname set to the tool name
+func newJUnitSuite(toolName, filePath string, cases []TestCase) junitTestSuite {
+	suite := junitTestSuite{
+		Name:      toolName,
+		File:      filePath,
+		Tests:     len(cases),
+		Timestamp: time.Now().UTC().Format("2006-01-02T15:04:05"),
+	}
+	for _, c := range cases {
+		testCase := junitTestCase{ClassName: toolName, Name: c.Name, File: filePath}
+		if c.Failure != "" {
+			suite.Failures++
+			testCase.Failure = &junitProblem{Message: c.Failure, Text: c.Details}
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	return suite
+}
+
+func marshalJUnit(suite junitTestSuite) string {
+	xmlBytes, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("<error>Failed to format result: %s</error>", err.Error())
+	}
+	return xml.Header + string(xmlBytes)
+}
@@ -0,0 +1,54 @@
+package formatter
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestFormatAsJUnit(t *testing.T) {
+	cases := []TestCase{
+		{Name: "no_tasks"},
+		{Name: "missing_error_handling", Failure: "No OnError event handler found", Details: "Severity: error"},
+		{Name: "no_logging", Failure: "No logging configuration found", Details: "Severity: warning"},
+	}
+	output := FormatAsJUnit("validate_best_practices", "Load.dtsx", cases)
+	if !strings.HasPrefix(output, xml.Header) {
+		t.Fatalf("expected an XML declaration, got %q", output)
+	}
+
+	var suites junitTestSuites
+	if err := xml.Unmarshal([]byte(output), &suites); err != nil {
+		t.Fatalf("failed to parse JUnit output: %v\n%s", err, output)
+	}
+	if len(suites.Suites) != 1 {
+		t.Fatalf("expected one test suite, got %d", len(suites.Suites))
+	}
+	suite := suites.Suites[0]
+	if suite.Name != "validate_best_practices" || suite.File != "Load.dtsx" || suite.Timestamp == "" {
+		t.Fatalf("unexpected test suite attributes: %+v", suite)
+	}
+	if suite.Tests != 3 || suite.Failures != 2 || len(suite.Cases) != 3 {
+		t.Fatalf("expected 3 tests with 2 failures, got %+v", suite)
+	}
+	failed := suite.Cases[1]
+	if failed.ClassName != "validate_best_practices" || failed.Name != "missing_error_handling" ||
+		failed.Failure == nil || failed.Failure.Message != "No OnError event handler found" || failed.Failure.Text != "Severity: error" {
+		t.Fatalf("unexpected failed test case: %+v", failed)
+	}
+	if suite.Cases[0].Failure != nil {
+		t.Fatalf("expected passing check without failure, got %+v", suite.Cases[0])
+	}
+}
+
+func TestJUnitFormatterReportsErrors(t *testing.T) {
+	result := CreateAnalysisResult("check_compliance", "missing.dtsx", nil, assertError{})
+	var suites junitTestSuites
+	if err := xml.Unmarshal([]byte(FormatAnalysisResult(result, FormatJUnit)), &suites); err != nil {
+		t.Fatalf("failed to parse JUnit output: %v", err)
+	}
+	suite := suites.Suites[0]
+	if suite.Errors != 1 || len(suite.Cases) != 1 || suite.Cases[0].Error == nil || suite.Cases[0].Error.Message != "failed" {
+		t.Fatalf("expected a single errored test case, got %+v", suite)
+	}
+}
@@ -0,0 +1,170 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// SARIF result levels
+const (
+	SARIFLevelError   = "error"
+	SARIFLevelWarning = "warning"
+	SARIFLevelNote    = "note"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://docs.oasis-open.org/sarif/sarif/v2.1.0/errata01/os/schemas/sarif-schema-2.1.0.json"
+)
+
+// Finding is a single issue reported by a security or best-practices tool
+type Finding struct {
+	RuleID  string `json:"rule_id"`
+	Message string `json:"message"`
+	Level   string `json:"level"`
+	URI     string `json:"uri"`
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations,omitempty"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFFormatter formats analysis results as a SARIF 2.1.0 log; results whose Data is not a []Finding produce a run without results
+type SARIFFormatter struct{}
+
+func (f *SARIFFormatter) Format(result *AnalysisResult) string {
+	run := newSARIFRun(result.ToolName, nil)
+	if result.Error != "" {
+		run.Invocations = []sarifInvocation{{
+			ExecutionSuccessful:        false,
+			ToolExecutionNotifications: []sarifNotification{{Level: SARIFLevelError, Message: sarifMessage{Text: result.Error}}},
+		}}
+	} else if findings, ok := result.Data.([]Finding); ok {
+		run = newSARIFRun(result.ToolName, findings)
+	}
+	return marshalSARIF(run)
+}
+
+func (f *SARIFFormatter) GetContentType() string {
+	return "application/sarif+json"
+}
+
+// FormatAsSARIF renders findings as a SARIF 2.1.0 log with a single run for the given tool
+func FormatAsSARIF(toolName string, findings []Finding) string {
+	return marshalSARIF(newSARIFRun(toolName, findings))
+}
+
+// newSARIFRun maps findings to SARIF results and lists the rules they reference
+func newSARIFRun(toolName string, findings []Finding) sarifRun {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: toolName}},
+		Results: []sarifResult{},
+	}
+
+	ruleIDs := make(map[string]bool)
+	for _, finding := range findings {
+		ruleIDs[finding.RuleID] = true
+		result := sarifResult{
+			RuleID:  finding.RuleID,
+			Level:   sarifLevel(finding.Level),
+			Message: sarifMessage{Text: finding.Message},
+		}
+		if finding.URI != "" {
+			result.Locations = []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: sarifURI(finding.URI)}},
+			}}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	ids := make([]string, 0, len(ruleIDs))
+	for id := range ruleIDs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: id})
+	}
+	return run
+}
+
+// sarifLevel maps a finding level to a SARIF level, treating "info" and unknown levels as notes
+func sarifLevel(level string) string {
+	switch level {
+	case SARIFLevelError, SARIFLevelWarning:
+		return level
+	default:
+		return SARIFLevelNote
+	}
+}
+
+// sarifURI converts a file path, including a Windows path, to a URI reference with forward slashes and escaped characters
+func sarifURI(path string) string {
+	return (&url.URL{Path: strings.ReplaceAll(path, `\`, "/")}).String()
+}
+
+func marshalSARIF(run sarifRun) string {
+	log := sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}}
+	jsonBytes, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return fmt.Sprintf(`{"error": "Failed to format result: %s"}`, err.Error())
+	}
+	return string(jsonBytes)
+}
@@ -0,0 +1,82 @@
+package formatter
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+//go:embed testdata/sarif-schema-2.1.0.json
+var sarifSchemaJSON []byte
+
+// validateSARIF checks output against the official SARIF 2.1.0 JSON schema
+func validateSARIF(t *testing.T, output string) {
+	t.Helper()
+	schemaDoc, err := jsonschema.UnmarshalJSON(bytes.NewReader(sarifSchemaJSON))
+	if err != nil {
+		t.Fatalf("failed to load SARIF schema: %v", err)
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(sarifSchema, schemaDoc); err != nil {
+		t.Fatalf("failed to add SARIF schema: %v", err)
+	}
+	schema, err := compiler.Compile(sarifSchema)
+	if err != nil {
+		t.Fatalf("failed to compile SARIF schema: %v", err)
+	}
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(output))
+	if err != nil {
+		t.Fatalf("SARIF output is not valid JSON: %v\n%s", err, output)
+	}
+	if err := schema.Validate(doc); err != nil {
+		t.Fatalf("SARIF output does not match the schema: %v\n%s", err, output)
+	}
+}
+
+func TestFormatAsSARIFRoundTrip(t *testing.T) {
+	findings := []Finding{
+		{RuleID: "missing_error_handling", Message: "No OnError event handler found", Level: SARIFLevelError, URI: `packages\Load Customers.dtsx`},
+		{RuleID: "no_logging", Message: "No logging configuration found", Level: SARIFLevelWarning, URI: `packages\Load Customers.dtsx`},
+		{RuleID: "connection_description", Message: "Connection manager 'Warehouse' has no description", Level: "info", URI: "Load.dtsx"},
+	}
+	output := FormatAsSARIF("validate_best_practices", findings)
+	validateSARIF(t, output)
+
+	var log sarifLog
+	if err := json.Unmarshal([]byte(output), &log); err != nil {
+		t.Fatalf("failed to parse SARIF output: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected SARIF log: %+v", log)
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "validate_best_practices" || len(run.Tool.Driver.Rules) != 3 {
+		t.Fatalf("unexpected tool driver: %+v", run.Tool.Driver)
+	}
+	if len(run.Results) != 3 {
+		t.Fatalf("expected three results, got %+v", run.Results)
+	}
+	first := run.Results[0]
+	if first.RuleID != "missing_error_handling" || first.Level != SARIFLevelError || first.Message.Text != "No OnError event handler found" {
+		t.Fatalf("unexpected first result: %+v", first)
+	}
+	if uri := first.Locations[0].PhysicalLocation.ArtifactLocation.URI; uri != "packages/Load%20Customers.dtsx" {
+		t.Fatalf("unexpected artifact URI: %s", uri)
+	}
+	if run.Results[2].Level != SARIFLevelNote {
+		t.Fatalf("expected info findings to map to note, got %s", run.Results[2].Level)
+	}
+}
+
+func TestSARIFFormatterReportsErrors(t *testing.T) {
+	result := CreateAnalysisResult("scan_credentials", "missing.dtsx", nil, assertError{})
+	output := FormatAnalysisResult(result, FormatSARIF)
+	validateSARIF(t, output)
+	if !strings.Contains(output, `"executionSuccessful": false`) || !strings.Contains(output, "failed") {
+		t.Fatalf("expected a failed invocation, got %s", output)
+	}
+}
@@ -0,0 +1,134 @@
+package formatter
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+// TableFormatter formats analysis results as tab-aligned columns for terminal display. A *TableData or a
+// slice of structs such as []Finding is written one row per item, with struct field names as column headers.
+type TableFormatter struct{}
+
+func (f *TableFormatter) Format(result *AnalysisResult) string {
+	if result.Error != "" {
+		return f.writeRows([][]string{{"ERROR", result.Error}})
+	}
+
+	switch v := result.Data.(type) {
+	case *TableData:
+		return f.formatTable(v)
+	case []SectionData:
+		rows := [][]string{{"SECTION", "CONTENT"}}
+		for _, section := range v {
+			rows = f.appendSectionRows(rows, section, "")
+		}
+		return f.writeRows(rows)
+	case string:
+		return v
+	}
+
+	if table, ok := structSliceTable(result.Data); ok {
+		return f.formatTable(table)
+	}
+	return f.writeRows([][]string{
+		{"TOOL", "FILE", "TIMESTAMP", "STATUS", "DATA"},
+		{result.ToolName, result.FilePath, result.Timestamp, result.Status, fmt.Sprintf("%v", result.Data)},
+	})
+}
+
+func (f *TableFormatter) formatTable(table *TableData) string {
+	if len(table.Rows) == 0 {
+		return "No data available.\n"
+	}
+	rows := make([][]string, 0, len(table.Rows)+1)
+	headers := make([]string, len(table.Headers))
+	for i, header := range table.Headers {
+		headers[i] = strings.ToUpper(header)
+	}
+	rows = append(rows, headers)
+	rows = append(rows, table.Rows...)
+	return f.writeRows(rows)
+}
+
+func (f *TableFormatter) appendSectionRows(rows [][]string, section SectionData, prefix string) [][]string {
+	sectionPath := section.Title
+	if prefix != "" {
+		sectionPath = prefix + " > " + section.Title
+	}
+
+	switch v := section.Content.(type) {
+	case string:
+		rows = append(rows, []string{sectionPath, v})
+	case []string:
+		for _, s := range v {
+			rows = append(rows, []string{sectionPath, s})
+		}
+	}
+	for _, subsection := range section.Subsections {
+		rows = f.appendSectionRows(rows, subsection, sectionPath)
+	}
+	return rows
+}
+
+// writeRows aligns rows into columns separated by at least two spaces; tabs and newlines inside a cell are
+// replaced with spaces so they cannot break the alignment
+func (f *TableFormatter) writeRows(rows [][]string) string {
+	var output strings.Builder
+	writer := tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = strings.Join(strings.Fields(cell), " ")
+		}
+		fmt.Fprintln(writer, strings.Join(cells, "\t"))
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Sprintf("Error: %v\n", err)
+	}
+	return output.String()
+}
+
+func (f *TableFormatter) GetContentType() string {
+	return "text/plain"
+}
+
+// structSliceTable converts a slice of structs to a table with one column per exported field
+func structSliceTable(data interface{}) (*TableData, bool) {
+	value := reflect.ValueOf(data)
+	if value.Kind() != reflect.Slice {
+		return nil, false
+	}
+	elemType := value.Type().Elem()
+	if elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	var fields []int
+	table := &TableData{Rows: [][]string{}}
+	for i := 0; i < elemType.NumField(); i++ {
+		if elemType.Field(i).IsExported() {
+			fields = append(fields, i)
+			table.Headers = append(table.Headers, elemType.Field(i).Name)
+		}
+	}
+	for i := 0; i < value.Len(); i++ {
+		item := value.Index(i)
+		if item.Kind() == reflect.Pointer {
+			if item.IsNil() {
+				continue
+			}
+			item = item.Elem()
+		}
+		row := make([]string, len(fields))
+		for j, field := range fields {
+			row[j] = fmt.Sprintf("%v", item.Field(field).Interface())
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, true
+}
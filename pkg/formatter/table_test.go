@@ -0,0 +1,52 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTableFormatterAlignsFindingColumns(t *testing.T) {
+	findings := []Finding{
+		{RuleID: "password_in_connection", Message: "Connection 'Source' stores a password", Level: "error", URI: "Load.dtsx"},
+		{RuleID: "no_logging", Message: "No logging configured", Level: "warning", URI: "Load.dtsx"},
+	}
+	output := FormatAnalysisResult(CreateAnalysisResult("scan_credentials", "Load.dtsx", findings, nil), FormatTable)
+
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and two rows, got %q", output)
+	}
+	if !strings.HasPrefix(lines[0], "RULEID") || !strings.Contains(lines[0], "MESSAGE") || !strings.Contains(lines[0], "LEVEL") {
+		t.Fatalf("expected headers derived from field names, got %q", lines[0])
+	}
+	for _, header := range []string{"MESSAGE", "LEVEL", "URI"} {
+		column := strings.Index(lines[0], header)
+		for _, line := range lines[1:] {
+			if column >= len(line) || line[column-1] != ' ' || line[column] == ' ' {
+				t.Fatalf("expected column %s to start at offset %d in every row:\n%s", header, column, output)
+			}
+		}
+	}
+	if !strings.HasPrefix(lines[2][strings.Index(lines[0], "LEVEL"):], "warning") {
+		t.Fatalf("expected the level column to line up, got %q", lines[2])
+	}
+}
+
+func TestTableFormatterTableData(t *testing.T) {
+	table := &TableData{Headers: []string{"Name", "Type"}, Rows: [][]string{{"Load Customers", "Data Flow"}, {"Log", "Execute\tSQL"}}}
+	output := FormatAnalysisResult(CreateAnalysisResult("run_sql_query", "", table, nil), FormatTable)
+
+	expected := "NAME            TYPE\nLoad Customers  Data Flow\nLog             Execute SQL\n"
+	if output != expected {
+		t.Fatalf("unexpected table output:\n%q\nwant:\n%q", output, expected)
+	}
+}
+
+func TestTableFormatterErrorsAndEmptyResults(t *testing.T) {
+	if output := FormatAnalysisResult(CreateAnalysisResult("scan_credentials", "missing.dtsx", nil, assertError{}), FormatTable); output != "ERROR  failed\n" {
+		t.Fatalf("unexpected error output: %q", output)
+	}
+	if output := FormatAnalysisResult(CreateAnalysisResult("scan_credentials", "Load.dtsx", []Finding{}, nil), FormatTable); output != "No data available.\n" {
+		t.Fatalf("unexpected empty output: %q", output)
+	}
+}
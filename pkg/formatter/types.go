@@ -9,6 +9,9 @@ const (
 	FormatCSV      OutputFormat = "csv"
 	FormatHTML     OutputFormat = "html"
 	FormatMarkdown OutputFormat = "markdown"
+	FormatSARIF    OutputFormat = "sarif"
+	FormatJUnit    OutputFormat = "junit"
+	FormatTable    OutputFormat = "table"
 )
 
 // AnalysisResult represents the result of an analysis operation
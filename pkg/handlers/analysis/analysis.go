@@ -13,6 +13,7 @@ import (
 
 	"github.com/MCPRUNNER/gossisMCP/pkg/formatter"
 	"github.com/MCPRUNNER/gossisMCP/pkg/types"
+	analysisutil "github.com/MCPRUNNER/gossisMCP/pkg/util/analysis"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -24,6 +25,9 @@ func ResolveFilePath(filePath, packageDirectory string) string {
 	return filepath.Join(packageDirectory, filePath)
 }
 
+// pipelineEngineProperties are the Data Flow Task performance settings stored on the pipeline element
+var pipelineEngineProperties = []string{"DefaultBufferSize", "DefaultBufferMaxRows", "EngineThreads", "BLOBTempStoragePath"}
+
 // HandleAnalyzeDataFlow handles data flow analysis from DTSX files
 func HandleAnalyzeDataFlow(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
 	filePath, err := request.RequireString("file_path")
@@ -49,6 +53,18 @@ func HandleAnalyzeDataFlow(_ context.Context, request mcp.CallToolRequest, packa
 	var result strings.Builder
 	result.WriteString("Data Flow Analysis:\n\n")
 
+	// Limit the analysis to the Data Flow Tasks named by task_filter
+	if taskFilter := strings.TrimSpace(request.GetString("task_filter", "")); taskFilter != "" {
+		filtered, names := filterDataFlowTasks(xmlContent, taskFilter)
+		if len(names) == 0 {
+			result.WriteString(fmt.Sprintf("No matching Data Flow Task found for filter %q.\n", taskFilter))
+			analysisResult := formatter.CreateAnalysisResult("analyze_data_flow", filePath, result.String(), nil)
+			return mcp.NewToolResultText(formatter.FormatAnalysisResult(analysisResult, format)), nil
+		}
+		xmlContent = filtered
+		result.WriteString(fmt.Sprintf("Task Filter: %s (matched: %s)\n\n", taskFilter, strings.Join(names, ", ")))
+	}
+
 	// Check if this package contains data flow tasks
 	if !strings.Contains(xmlContent, "Microsoft.Pipeline") {
 		result.WriteString("No Data Flow Tasks found in this package.\n")
@@ -58,6 +74,22 @@ func HandleAnalyzeDataFlow(_ context.Context, request mcp.CallToolRequest, packa
 
 	result.WriteString("Data Flow Task found in package.\n\n")
 
+	// Report each pipeline's engine settings before its components
+	for _, pipelineTag := range regexp.MustCompile(`<pipeline\b[^>]*>`).FindAllString(xmlContent, -1) {
+		result.WriteString("Engine Settings:\n")
+		for _, prop := range pipelineEngineProperties {
+			value := ""
+			if match := regexp.MustCompile(`(?i)\s` + prop + `="([^"]*)"`).FindStringSubmatch(pipelineTag); len(match) > 1 {
+				value = strings.TrimSpace(match[1])
+			}
+			if value == "" {
+				value = "not configured (using defaults)"
+			}
+			result.WriteString(fmt.Sprintf("  %s: %s\n", prop, value))
+		}
+		result.WriteString("\n")
+	}
+
 	// Extract component information using regex
 	// Find all component definitions
 	componentRegex := regexp.MustCompile(`(?s)<component[^>]*>`)
@@ -205,6 +237,50 @@ func getComponentType(classID string) string {
 	}
 }
 
+var (
+	dataFlowTaskPattern = regexp.MustCompile(`<DTS:Executable\b[^>]*"Microsoft\.Pipeline"[^>]*>`)
+	objectNamePattern   = regexp.MustCompile(`DTS:ObjectName="([^"]*)"`)
+)
+
+// filterDataFlowTasks returns the XML and names of the Data Flow Tasks whose name equals the filter, ignoring
+// case, or contains it when no name matches exactly
+func filterDataFlowTasks(xmlContent, filter string) (string, []string) {
+	type dataFlowTask struct {
+		name    string
+		section string
+	}
+	var exact, partial []dataFlowTask
+	filterLower := strings.ToLower(filter)
+	for _, loc := range dataFlowTaskPattern.FindAllStringIndex(xmlContent, -1) {
+		end := strings.Index(xmlContent[loc[1]:], "</DTS:Executable>")
+		if end == -1 {
+			continue
+		}
+		task := dataFlowTask{section: xmlContent[loc[0] : loc[1]+end+len("</DTS:Executable>")]}
+		if match := objectNamePattern.FindStringSubmatch(xmlContent[loc[0]:loc[1]]); len(match) > 1 {
+			task.name = match[1]
+		}
+		nameLower := strings.ToLower(task.name)
+		switch {
+		case nameLower == filterLower:
+			exact = append(exact, task)
+		case strings.Contains(nameLower, filterLower):
+			partial = append(partial, task)
+		}
+	}
+	if len(exact) == 0 {
+		exact = partial
+	}
+
+	var sections strings.Builder
+	names := make([]string, 0, len(exact))
+	for _, task := range exact {
+		sections.WriteString(task.section)
+		names = append(names, task.name)
+	}
+	return sections.String(), names
+}
+
 // HandleAnalyzeDataFlowDetailed handles detailed data flow analysis from DTSX files
 func HandleAnalyzeDataFlowDetailed(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
 	filePath, err := request.RequireString("file_path")
@@ -240,6 +316,18 @@ func HandleAnalyzeDataFlowDetailed(_ context.Context, request mcp.CallToolReques
 	var result strings.Builder
 	result.WriteString("Detailed Data Flow Analysis:\n\n")
 
+	// Limit the analysis to the Data Flow Tasks named by task_filter
+	if taskFilter := strings.TrimSpace(request.GetString("task_filter", "")); taskFilter != "" {
+		filtered, names := filterDataFlowTasks(xmlContent, taskFilter)
+		if len(names) == 0 {
+			result.WriteString(fmt.Sprintf("No matching Data Flow Task found for filter %q.\n", taskFilter))
+			analysisResult := formatter.CreateAnalysisResult("analyze_data_flow_detailed", filePath, result.String(), nil)
+			return mcp.NewToolResultText(formatter.FormatAnalysisResult(analysisResult, format)), nil
+		}
+		xmlContent = filtered
+		result.WriteString(fmt.Sprintf("Task Filter: %s (matched: %s)\n\n", taskFilter, strings.Join(names, ", ")))
+	}
+
 	// Check if this package contains data flow tasks
 	if !strings.Contains(xmlContent, "Microsoft.Pipeline") {
 		result.WriteString("No Data Flow Tasks found in this package.\n")
@@ -386,17 +474,29 @@ func HandleAnalyzeDataFlowDetailed(_ context.Context, request mcp.CallToolReques
 		}
 	}
 
+	lineages := extractColumnLineage(xmlContent)
+	if len(lineages) > 0 {
+		result.WriteString("\nColumn Lineage:\n")
+		for _, lineage := range lineages {
+			steps := append([]string{fmt.Sprintf("%s.%s", lineage.SourceComponent, lineage.SourceColumn)}, lineage.Transformations...)
+			steps = append(steps, fmt.Sprintf("%s.%s", lineage.DestinationComponent, lineage.DestinationColumn))
+			result.WriteString(fmt.Sprintf("  %s\n", strings.Join(steps, " → ")))
+		}
+	}
+
 	analysisResult := formatter.CreateAnalysisResult("analyze_data_flow_detailed", filePath, result.String(), nil)
 
 	// For JSON format, return structured data for consistency with other analysis tools
 	if format == formatter.FormatJSON {
 		jsonResult := map[string]interface{}{
-			"tool_name": analysisResult.ToolName,
-			"file_path": analysisResult.FilePath,
-			"package":   filepath.Base(analysisResult.FilePath),
-			"timestamp": analysisResult.Timestamp,
-			"status":    analysisResult.Status,
-			"analysis":  analysisResult.Data,
+			"tool_name":      analysisResult.ToolName,
+			"file_path":      analysisResult.FilePath,
+			"package":        filepath.Base(analysisResult.FilePath),
+			"timestamp":      analysisResult.Timestamp,
+			"status":         analysisResult.Status,
+			"analysis":       analysisResult.Data,
+			"column_lineage": lineages,
+			"lineage":        newDataFlowLineage(filepath.Base(analysisResult.FilePath), lineages),
 		}
 		if analysisResult.Error != "" {
 			jsonResult["error"] = analysisResult.Error
@@ -1349,7 +1449,35 @@ func HandleAnalyzeSAPBWSource(_ context.Context, request mcp.CallToolRequest, pa
 	return mcp.NewToolResultText(formatter.FormatAnalysisResult(analysisResult, format)), nil
 }
 
-// HandleAnalyzeOLEDBDestination handles OLE DB destination analysis from DTSX files
+// destinationTypes lists the destination_type values in the order they are reported by destination_type=all
+var destinationTypes = []string{"ole_db", "flat_file", "sql_server", "excel", "raw_file", "azure_blob", "azure_sql", "odbc", "ado_net"}
+
+// destinationTypeMap maps destination types to ComponentClassIDs
+var destinationTypeMap = map[string]string{
+	"ole_db":     "Microsoft.SqlServer.Dts.Pipeline.OLEDBDestinationAdapter",
+	"flat_file":  "Microsoft.SqlServer.Dts.Pipeline.FlatFileDestinationAdapter",
+	"sql_server": "Microsoft.SqlServer.Dts.Pipeline.SqlServerDestinationAdapter",
+	"excel":      "Microsoft.SqlServer.Dts.Pipeline.ExcelDestinationAdapter",
+	"raw_file":   "Microsoft.SqlServer.Dts.Pipeline.RawFileDestinationAdapter",
+	"azure_blob": "Microsoft.Azure.BlobDestination",
+	"azure_sql":  "Microsoft.Azure.SqlDatabaseDestination",
+	"odbc":       "Microsoft.SqlServer.Dts.Pipeline.OdbcDestinationAdapter",
+	"ado_net":    "Microsoft.SqlServer.Dts.Pipeline.DataReaderDestinationAdapter",
+}
+
+// destinationNameMap maps destination types to display names
+var destinationNameMap = map[string]string{
+	"ole_db":     "OLE DB Destination",
+	"flat_file":  "Flat File Destination",
+	"sql_server": "SQL Server Destination",
+	"excel":      "Excel Destination",
+	"raw_file":   "Raw File Destination",
+	"azure_blob": "Azure Blob Destination",
+	"azure_sql":  "Azure SQL Database Destination",
+	"odbc":       "ODBC Destination",
+	"ado_net":    "ADO.NET Destination",
+}
+
 // HandleAnalyzeDestination provides unified analysis for various SSIS destination components
 func HandleAnalyzeDestination(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
 	filePath, err := request.RequireString("file_path")
@@ -1362,30 +1490,22 @@ func HandleAnalyzeDestination(_ context.Context, request mcp.CallToolRequest, pa
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	// Map destination types to ComponentClassIDs
-	destinationTypeMap := map[string]string{
-		"ole_db":     "Microsoft.SqlServer.Dts.Pipeline.OLEDBDestinationAdapter",
-		"flat_file":  "Microsoft.SqlServer.Dts.Pipeline.FlatFileDestinationAdapter",
-		"sql_server": "Microsoft.SqlServer.Dts.Pipeline.SqlServerDestinationAdapter",
-		"excel":      "Microsoft.SqlServer.Dts.Pipeline.ExcelDestinationAdapter",
-		"raw_file":   "Microsoft.SqlServer.Dts.Pipeline.RawFileDestinationAdapter",
-	}
-
 	componentClassID, exists := destinationTypeMap[destinationType]
-	if !exists {
-		return mcp.NewToolResultError(fmt.Sprintf("Unknown destination type: %s. Supported types: ole_db, flat_file, sql_server, excel, raw_file", destinationType)), nil
-	}
-
-	// Map destination types to display names
-	destinationNameMap := map[string]string{
-		"ole_db":     "OLE DB Destination",
-		"flat_file":  "Flat File Destination",
-		"sql_server": "SQL Server Destination",
-		"excel":      "Excel Destination",
-		"raw_file":   "Raw File Destination",
+	if destinationType == genericClassIDType {
+		if componentClassID, err = requireClassID(request); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	} else if !exists && destinationType != allComponentTypes {
+		return mcp.NewToolResultError(fmt.Sprintf("Unknown destination type: %s. Supported types: %s, %s, %s", destinationType, strings.Join(destinationTypes, ", "), genericClassIDType, allComponentTypes)), nil
 	}
 
 	displayName := destinationNameMap[destinationType]
+	switch destinationType {
+	case genericClassIDType:
+		displayName = fmt.Sprintf("Destination Component (%s)", componentClassID)
+	case allComponentTypes:
+		displayName = "All Destinations"
+	}
 	analysisTitle := fmt.Sprintf("%s Analysis", displayName)
 
 	// Get format parameter (default to "text")
@@ -1412,6 +1532,30 @@ func HandleAnalyzeDestination(_ context.Context, request mcp.CallToolRequest, pa
 	var result strings.Builder
 	result.WriteString(fmt.Sprintf("%s:\n\n", analysisTitle))
 
+	if destinationType == allComponentTypes {
+		found := false
+		for _, destType := range destinationTypes {
+			var section strings.Builder
+			if writeDestinationComponents(&section, pkg, destType, destinationTypeMap[destType]) {
+				found = true
+				result.WriteString(fmt.Sprintf("=== %s ===\n\n", destinationNameMap[destType]))
+				result.WriteString(section.String())
+			}
+		}
+		if !found {
+			result.WriteString("No destination components found in this package.\n")
+		}
+	} else if !writeDestinationComponents(&result, pkg, destinationType, componentClassID) {
+		result.WriteString(fmt.Sprintf("No %s components found in this package.\n", displayName))
+	}
+
+	analysisResult := formatter.CreateAnalysisResult(analysisTitle, filePath, result.String(), nil)
+	return mcp.NewToolResultText(formatter.FormatAnalysisResult(analysisResult, format)), nil
+}
+
+// writeDestinationComponents writes the analysis of every data flow component with the given class ID and
+// reports whether any was found
+func writeDestinationComponents(result *strings.Builder, pkg types.SSISPackage, destinationType, componentClassID string) bool {
 	found := false
 	for _, task := range pkg.Executables.Tasks {
 		if strings.Contains(task.CreationName, "Pipeline") {
@@ -1421,9 +1565,13 @@ func HandleAnalyzeDestination(_ context.Context, request mcp.CallToolRequest, pa
 					result.WriteString(fmt.Sprintf("Component: %s\n", comp.Name))
 					result.WriteString(fmt.Sprintf("Description: %s\n", comp.Description))
 
+					if keys, ok := destinationKeyProperties[destinationType]; ok {
+						writeKeyProperties(result, comp, keys)
+					}
+
 					// Properties
 					result.WriteString("Properties:\n")
-					for _, prop := range comp.ObjectData.PipelineComponent.Properties.Properties {
+					for _, prop := range componentProperties(comp) {
 						result.WriteString(fmt.Sprintf("  %s: %s\n", prop.Name, prop.Value))
 					}
 
@@ -1431,25 +1579,34 @@ func HandleAnalyzeDestination(_ context.Context, request mcp.CallToolRequest, pa
 					result.WriteString("Input Columns:\n")
 					for _, input := range comp.Inputs.Inputs {
 						for _, col := range input.InputColumns.Columns {
-							result.WriteString(fmt.Sprintf("  %s (%s", col.Name, col.DataType))
+							name := col.Name
+							if name == "" {
+								name = col.CachedName
+							}
+							result.WriteString(fmt.Sprintf("  %s (%s", name, col.DataType))
 							if col.Length > 0 {
 								result.WriteString(fmt.Sprintf(", length=%d", col.Length))
 							}
 							result.WriteString(")\n")
 						}
 					}
+
+					var mappings []string
+					for _, input := range comp.Inputs.Inputs {
+						mappings = append(mappings, inputColumnMappings(input)...)
+					}
+					if len(mappings) > 0 {
+						result.WriteString("Column Mappings:\n")
+						for _, mapping := range mappings {
+							result.WriteString(fmt.Sprintf("  %s\n", mapping))
+						}
+					}
 					result.WriteString("\n")
 				}
 			}
 		}
 	}
-
-	if !found {
-		result.WriteString(fmt.Sprintf("No %s components found in this package.\n", displayName))
-	}
-
-	analysisResult := formatter.CreateAnalysisResult(analysisTitle, filePath, result.String(), nil)
-	return mcp.NewToolResultText(formatter.FormatAnalysisResult(analysisResult, format)), nil
+	return found
 }
 
 // HandleAnalyzeOLEDBDestination handles OLE DB destination analysis from DTSX files
@@ -1730,6 +1887,12 @@ func HandleAnalyzeDerivedColumn(_ context.Context, request mcp.CallToolRequest,
 									result.WriteString(fmt.Sprintf(", length=%d", col.Length))
 								}
 								result.WriteString(")\n")
+								if expr := derivedColumnExpression(col); expr != "" {
+									result.WriteString(fmt.Sprintf("    Expression: %s\n", expr))
+									for _, finding := range derivedColumnFindings(col, expr) {
+										result.WriteString(fmt.Sprintf("    - %s: %s\n", strings.ToUpper(finding.Severity), finding.Message))
+									}
+								}
 							}
 						}
 					}
@@ -2992,6 +3155,37 @@ func HandleAnalyzeContainers(_ context.Context, request mcp.CallToolRequest, pac
 				}
 			}
 
+			if enumerator := task.ForEachEnumerator; enumerator != nil {
+				result.WriteString(fmt.Sprintf("  Enumerator: %s\n", enumerator.Type()))
+				for _, setting := range enumerator.Settings() {
+					value := setting.Value
+					if setting.Name == "FileNameRetrievalType" {
+						value = describeFileNameRetrieval(value)
+					}
+					result.WriteString(fmt.Sprintf("    %s: %s\n", setting.Name, value))
+				}
+			}
+
+			if task.CreationName == "Microsoft.ForLoop" {
+				initExpr, evalExpr, assignExpr := forLoopExpressions(task)
+				result.WriteString(fmt.Sprintf("  Init Expression: %s\n", initExpr))
+				result.WriteString(fmt.Sprintf("  Eval Expression: %s\n", evalExpr))
+				result.WriteString(fmt.Sprintf("  Assign Expression: %s\n", assignExpr))
+				if findings := checkForLoopTermination(task.Name, evalExpr, assignExpr); len(findings) > 0 {
+					result.WriteString("  Loop Termination:\n")
+					for _, finding := range findings {
+						result.WriteString(fmt.Sprintf("    - %s: %s\n", strings.ToUpper(finding.Severity), finding.Message))
+					}
+				} else {
+					result.WriteString("  Loop Termination: OK\n")
+				}
+			}
+
+			if task.Executables != nil && len(task.Executables.Tasks) > 0 {
+				result.WriteString("  Execution Order:\n")
+				result.WriteString(formatExecutionOrder(containerExecutionOrder(task.Executables.Tasks, task.PrecedenceConstraints.Constraints), "    "))
+			}
+
 			if task.ObjectData.ScriptTask.ScriptTaskData.ScriptProject.ScriptCode != "" {
 				result.WriteString("  Contains Script Task Content\n\n")
 				continue
@@ -3021,6 +3215,20 @@ func HandleAnalyzeContainers(_ context.Context, request mcp.CallToolRequest, pac
 	return mcp.NewToolResultText(formatter.FormatAnalysisResult(analysisResult, format)), nil
 }
 
+// describeFileNameRetrieval renders the FileNameRetrievalType of a Foreach File Enumerator
+func describeFileNameRetrieval(value string) string {
+	switch value {
+	case "0":
+		return "Fully qualified (0)"
+	case "1":
+		return "Name and extension (1)"
+	case "2":
+		return "Name only (2)"
+	default:
+		return value
+	}
+}
+
 // HandleAnalyzeCustomComponents handles custom component analysis from DTSX files
 func HandleAnalyzeCustomComponents(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
 	filePath, err := request.RequireString("file_path")
@@ -3048,6 +3256,14 @@ func HandleAnalyzeCustomComponents(_ context.Context, request mcp.CallToolReques
 		return mcp.NewToolResultText(formatter.FormatAnalysisResult(result, format)), nil
 	}
 
+	var registry *ComponentRegistry
+	if registryFile := request.GetString("registry_file", ""); registryFile != "" {
+		registry, err = loadComponentRegistry(ResolveFilePath(registryFile, packageDirectory))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
 	var result strings.Builder
 	result.WriteString("Custom and Third-Party Components Analysis:\n\n")
 	customCount := 0
@@ -3081,12 +3297,32 @@ func HandleAnalyzeCustomComponents(_ context.Context, request mcp.CallToolReques
 					vendor = "Unknown/Custom"
 				}
 
+				entry, registered := registry.Lookup(comp.ComponentClassID)
+				if registered {
+					isCustom = true
+					vendor = entry.Vendor
+				}
+
 				if isCustom {
 					customCount++
 					result.WriteString(fmt.Sprintf("Component %d: %s\n", customCount, comp.Name))
 					result.WriteString(fmt.Sprintf("  Vendor: %s\n", vendor))
 					result.WriteString(fmt.Sprintf("  Class ID: %s\n", comp.ComponentClassID))
 					result.WriteString(fmt.Sprintf("  Description: %s\n", comp.Description))
+					if registered {
+						if entry.Version != "" {
+							result.WriteString(fmt.Sprintf("  Version: %s\n", entry.Version))
+						}
+						if entry.DocsURL != "" {
+							result.WriteString(fmt.Sprintf("  Documentation: %s\n", entry.DocsURL))
+						}
+						if len(entry.KnownIssues) > 0 {
+							result.WriteString("  Known Issues:\n")
+							for _, issue := range entry.KnownIssues {
+								result.WriteString(fmt.Sprintf("    - %s\n", issue))
+							}
+						}
+					}
 
 					for _, prop := range comp.ObjectData.PipelineComponent.Properties.Properties {
 						if isKeyProperty(prop.Name) {
@@ -3368,7 +3604,7 @@ func HandleAnalyzeEventHandlers(_ context.Context, request mcp.CallToolRequest,
 }
 
 // HandleAnalyzePackageDependencies handles package dependency analysis across multiple DTSX files
-func HandleAnalyzePackageDependencies(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
+func HandleAnalyzePackageDependencies(ctx context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
 	// Get format parameter (default to "text")
 	formatStr := request.GetString("format", "text")
 	format := formatter.OutputFormat(formatStr)
@@ -3379,6 +3615,9 @@ func HandleAnalyzePackageDependencies(_ context.Context, request mcp.CallToolReq
 		if err != nil {
 			return err
 		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		if !info.IsDir() && strings.HasSuffix(strings.ToLower(info.Name()), ".dtsx") {
 			dtsxFiles = append(dtsxFiles, path)
 		}
@@ -3413,6 +3652,10 @@ func HandleAnalyzePackageDependencies(_ context.Context, request mcp.CallToolReq
 
 	// Process each DTSX file
 	for _, filePath := range dtsxFiles {
+		if err := ctx.Err(); err != nil {
+			result := formatter.CreateAnalysisResult("Package Dependency Analysis", "", nil, err)
+			return mcp.NewToolResultText(formatter.FormatAnalysisResult(result, format)), nil
+		}
 		data, err := os.ReadFile(filePath)
 		if err != nil {
 			continue // Skip files that can't be read
@@ -3601,6 +3844,20 @@ func HandleAnalyzeConfigurations(_ context.Context, request mcp.CallToolRequest,
 		if config.ConfigurationString != "" {
 			result.WriteString(fmt.Sprintf("   Configuration String: %s\n", config.ConfigurationString))
 		}
+		if config.ConfigurationVariable != "" {
+			result.WriteString(fmt.Sprintf("   Configuration Variable: %s\n", config.ConfigurationVariable))
+		}
+		if filter := config.Filter(); filter != "" {
+			result.WriteString(fmt.Sprintf("   Configuration Filter: %s\n", filter))
+		}
+
+		// Target of the configuration
+		if config.ConfiguredObjectPath != "" {
+			result.WriteString(fmt.Sprintf("   Configured Object Path: %s\n", config.ConfiguredObjectPath))
+		}
+		if config.ConfiguredPropertyPath != "" {
+			result.WriteString(fmt.Sprintf("   Configured Property Path: %s\n", config.ConfiguredPropertyPath))
+		}
 
 		// Configured type and value
 		if config.ConfiguredType != "" {
@@ -3815,6 +4072,20 @@ func HandleAnalyzeCodeQuality(_ context.Context, request mcp.CallToolRequest, pa
 		structuralScore, len(pkg.Executables.Tasks), len(pkg.ConnectionMgr.Connections), len(pkg.Variables.Vars)))
 	result.WriteString(fmt.Sprintf("• Control Flow Complexity: %d/10 (Precedence Constraints: %d)\n",
 		calculateControlFlowComplexity(pkg), len(pkg.PrecedenceConstraints.Constraints)))
+	cyclomaticMetrics := calculateCyclomaticComplexity(pkg)
+	result.WriteString(fmt.Sprintf("• Cyclomatic Complexity: %d (Score: %d/10; Expression Constraints: %d, Conditional Split Conditions: %d, For Loops: %d)\n",
+		cyclomaticMetrics.Complexity, cyclomaticMetrics.Score, cyclomaticMetrics.ExpressionConstraints,
+		cyclomaticMetrics.ConditionalSplitConditions, cyclomaticMetrics.ForLoops))
+
+	// Naming Issues
+	result.WriteString("\n🏷️ Naming Issues:\n")
+	duplicateNames := findDuplicateTaskNames(pkg)
+	for _, duplicate := range duplicateNames {
+		result.WriteString(fmt.Sprintf("- ERROR: Task name '%s' is used by %d tasks in container '%s'\n", duplicate.Name, duplicate.Count, duplicate.Container))
+	}
+	if len(duplicateNames) == 0 {
+		result.WriteString("• No duplicate task names found\n")
+	}
 
 	// Script Complexity Metrics
 	result.WriteString("\n📜 Script Complexity:\n")
@@ -3842,13 +4113,13 @@ func HandleAnalyzeCodeQuality(_ context.Context, request mcp.CallToolRequest, pa
 
 	// Overall Maintainability Score
 	result.WriteString("\n🎯 Overall Maintainability Score:\n")
-	overallScore := calculateOverallScore(structuralScore, scriptMetrics.QualityScore, expressionMetrics.ComplexityScore, variableMetrics.UsageScore)
+	overallScore := calculateOverallScore(structuralScore, scriptMetrics.QualityScore, expressionMetrics.ComplexityScore, variableMetrics.UsageScore, cyclomaticMetrics.Score)
 	result.WriteString(fmt.Sprintf("• Composite Score: %d/10\n", overallScore))
 	result.WriteString(fmt.Sprintf("• Rating: %s\n", getMaintainabilityRating(overallScore)))
 
 	// Recommendations
 	result.WriteString("\n💡 Recommendations:\n")
-	addQualityRecommendations(&result, overallScore, structuralScore, scriptMetrics, expressionMetrics, variableMetrics)
+	addQualityRecommendations(&result, overallScore, structuralScore, scriptMetrics, expressionMetrics, variableMetrics, cyclomaticMetrics)
 
 	analysisResult := formatter.CreateAnalysisResult("analyze_code_quality", filePath, result.String(), nil)
 
@@ -4086,6 +4357,129 @@ func calculateStructuralComplexity(pkg types.SSISPackage) int {
 	return sizeScore
 }
 
+// CyclomaticComplexityMetrics represents the cyclomatic complexity of a package's control and data flow
+type CyclomaticComplexityMetrics struct {
+	ExpressionConstraints      int
+	ConditionalSplitConditions int
+	ForLoops                   int
+	Complexity                 int
+	Score                      int
+}
+
+// calculateCyclomaticComplexity estimates cyclomatic complexity as 1 plus one decision point for each precedence
+// constraint that evaluates an expression, each Conditional Split condition and each For Loop container
+func calculateCyclomaticComplexity(pkg types.SSISPackage) CyclomaticComplexityMetrics {
+	metrics := CyclomaticComplexityMetrics{}
+
+	countConstraints := func(constraints []types.PrecedenceConstraint) {
+		for _, constraint := range constraints {
+			// EvalOp 1, 3 and 4 are Expression, ExpressionAndConstraint and ExpressionOrConstraint
+			switch constraint.EvalOp {
+			case "1", "3", "4":
+				if strings.TrimSpace(constraint.Expression) != "" {
+					metrics.ExpressionConstraints++
+				}
+			}
+		}
+	}
+
+	var walk func(tasks []types.Task)
+	walkEventHandlers := func(handlers []types.EventHandler) {
+		for _, handler := range handlers {
+			countConstraints(handler.PrecedenceConstraints.Constraints)
+			walk(handler.Executables.Tasks)
+		}
+	}
+	walk = func(tasks []types.Task) {
+		for _, task := range tasks {
+			if strings.Contains(strings.ToLower(task.CreationName), "forloop") {
+				metrics.ForLoops++
+			}
+			for _, comp := range task.ObjectData.DataFlow.Components.Components {
+				if !strings.Contains(comp.ComponentClassID, "ConditionalSplit") {
+					continue
+				}
+				for _, output := range comp.Outputs.Outputs {
+					if !output.IsErrorOut && !output.IsDefaultOut {
+						metrics.ConditionalSplitConditions++
+					}
+				}
+			}
+			countConstraints(task.PrecedenceConstraints.Constraints)
+			if task.Executables != nil {
+				walk(task.Executables.Tasks)
+			}
+			walkEventHandlers(task.EventHandlers.EventHandlers)
+		}
+	}
+
+	countConstraints(pkg.PrecedenceConstraints.Constraints)
+	walk(pkg.Executables.Tasks)
+	walkEventHandlers(pkg.EventHandlers.EventHandlers)
+
+	metrics.Complexity = 1 + metrics.ExpressionConstraints + metrics.ConditionalSplitConditions + metrics.ForLoops
+	switch {
+	case metrics.Complexity <= 5:
+		metrics.Score = 10
+	case metrics.Complexity <= 10:
+		metrics.Score = 7
+	case metrics.Complexity <= 20:
+		metrics.Score = 4
+	default:
+		metrics.Score = 1
+	}
+	return metrics
+}
+
+// DuplicateTaskName is a task name used more than once within the same container
+type DuplicateTaskName struct {
+	Name      string
+	Container string
+	Count     int
+}
+
+// findDuplicateTaskNames reports task names that appear more than once among the direct children of the package,
+// a container or an event handler
+func findDuplicateTaskNames(pkg types.SSISPackage) []DuplicateTaskName {
+	var duplicates []DuplicateTaskName
+
+	var walk func(tasks []types.Task, container string)
+	walkEventHandlers := func(handlers []types.EventHandler, owner string) {
+		for _, handler := range handlers {
+			walk(handler.Executables.Tasks, fmt.Sprintf("%s %s event handler", owner, handler.EventName))
+		}
+	}
+	walk = func(tasks []types.Task, container string) {
+		counts := make(map[string]int)
+		var order []string
+		for _, task := range tasks {
+			if counts[task.Name] == 0 {
+				order = append(order, task.Name)
+			}
+			counts[task.Name]++
+		}
+		for _, name := range order {
+			if counts[name] > 1 {
+				duplicates = append(duplicates, DuplicateTaskName{Name: name, Container: container, Count: counts[name]})
+			}
+		}
+		for _, task := range tasks {
+			if task.Executables != nil {
+				walk(task.Executables.Tasks, task.Name)
+			}
+			walkEventHandlers(task.EventHandlers.EventHandlers, task.Name)
+		}
+	}
+
+	packageName := pkg.ObjectName
+	if packageName == "" {
+		packageName = "Package"
+	}
+	walk(pkg.Executables.Tasks, packageName)
+	walkEventHandlers(pkg.EventHandlers.EventHandlers, packageName)
+	return duplicates
+}
+
 // calculateControlFlowComplexity calculates control flow complexity score
 func calculateControlFlowComplexity(pkg types.SSISPackage) int {
 	constraintCount := len(pkg.PrecedenceConstraints.Constraints)
@@ -4211,8 +4605,8 @@ func analyzeVariableUsage(pkg types.SSISPackage) VariableUsageMetrics {
 }
 
 // calculateOverallScore calculates overall maintainability score
-func calculateOverallScore(structural, script, expression, variable int) int {
-	return (structural + script + expression + variable) / 4
+func calculateOverallScore(structural, script, expression, variable, cyclomatic int) int {
+	return (structural + script + expression + variable + cyclomatic) / 5
 }
 
 // getMaintainabilityRating returns a rating string based on score
@@ -4232,7 +4626,7 @@ func getMaintainabilityRating(score int) string {
 }
 
 // addQualityRecommendations adds quality recommendations to the result
-func addQualityRecommendations(result *strings.Builder, overallScore int, structuralScore int, scriptMetrics ScriptComplexityMetrics, expressionMetrics ExpressionComplexityMetrics, variableMetrics VariableUsageMetrics) {
+func addQualityRecommendations(result *strings.Builder, overallScore int, structuralScore int, scriptMetrics ScriptComplexityMetrics, expressionMetrics ExpressionComplexityMetrics, variableMetrics VariableUsageMetrics, cyclomaticMetrics CyclomaticComplexityMetrics) {
 	if overallScore >= 7 {
 		result.WriteString("âœ… Package quality is good. Continue following best practices.\n")
 	} else {
@@ -4256,6 +4650,10 @@ func addQualityRecommendations(result *strings.Builder, overallScore int, struct
 			result.WriteString("â€¢ Increase use of expressions in variables for dynamic behavior\n")
 			result.WriteString("â€¢ Review variable naming and organization\n")
 		}
+
+		if cyclomaticMetrics.Score < 5 {
+			result.WriteString("â€¢ Reduce branching by splitting conditional paths into separate packages or containers\n")
+		}
 	}
 
 	result.WriteString("\nðŸ”¨ General Recommendations:\n")
@@ -4266,14 +4664,6 @@ func addQualityRecommendations(result *strings.Builder, overallScore int, struct
 	result.WriteString("â€¢ Regularly review and refactor complex packages\n")
 }
 
-// maskSensitiveValue masks sensitive values for display
-func maskSensitiveValue(value string) string {
-	if len(value) <= 4 {
-		return strings.Repeat("*", len(value))
-	}
-	return value[:2] + strings.Repeat("*", len(value)-4) + value[len(value)-2:]
-}
-
 // analyzeConnectionSecurity analyzes connection managers for security issues
 func analyzeConnectionSecurity(connections []types.Connection) []string {
 	var issues []string
@@ -4311,7 +4701,7 @@ func analyzeVariableSecurity(variables []types.Variable) []string {
 			if strings.Contains(varNameLower, pattern) {
 				// Check if the value looks like a real credential (not empty, not expression)
 				if variable.Value != "" && !strings.HasPrefix(variable.Value, "@") {
-					issues = append(issues, fmt.Sprintf("Variable '%s' contains sensitive data: %s", variable.Name, maskSensitiveValue(variable.Value)))
+					issues = append(issues, fmt.Sprintf("Variable '%s' contains sensitive data: %s", variable.Name, analysisutil.MaskSensitiveValue(variable.Value)))
 				}
 			}
 		}
@@ -4460,6 +4850,42 @@ func HandleDetectSecurityIssues(_ context.Context, request mcp.CallToolRequest,
 	return mcp.NewToolResultText(result.String()), nil
 }
 
+// credentialPatternGroup is a named set of substrings that indicate a credential of one category
+type credentialPatternGroup struct {
+	name     string
+	patterns []string
+	category string
+}
+
+// credentialPatterns are matched by scan_credentials against package and configuration content
+var credentialPatterns = []credentialPatternGroup{
+	{
+		name:     "Database Credentials",
+		patterns: []string{"password=", "pwd=", "user id=", "uid=", "userid=", "trusted_connection=false", "integrated security=false"},
+		category: "Database",
+	},
+	{
+		name:     "API Keys & Tokens",
+		patterns: []string{"apikey=", "api_key=", "token=", "bearer=", "authorization=", "x-api-key="},
+		category: "API",
+	},
+	{
+		name:     "Cloud Credentials",
+		patterns: []string{"accesskey=", "secretkey=", "accountkey=", "sharedaccesskey=", "sas=", "connectionstring="},
+		category: "Cloud",
+	},
+	{
+		name:     "Encryption Keys",
+		patterns: []string{"encryptionkey=", "key=", "certificate=", "privatekey=", "publickey="},
+		category: "Encryption",
+	},
+	{
+		name:     "Personal Data",
+		patterns: []string{"ssn=", "socialsecurity=", "creditcard=", "cardnumber=", "email=", "phone="},
+		category: "PII",
+	},
+}
+
 // HandleScanCredentials handles advanced credential scanning from DTSX files
 func HandleScanCredentials(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
 	filePath, err := request.RequireString("file_path")
@@ -4471,10 +4897,25 @@ func HandleScanCredentials(_ context.Context, request mcp.CallToolRequest, packa
 	formatStr := request.GetString("format", "text")
 	format := formatter.OutputFormat(formatStr)
 
+	// Configuration files are detected by extension unless file_type is given
+	fileType := strings.ToLower(request.GetString("file_type", ""))
+	if fileType == "" {
+		fileType = "dtsx"
+		if strings.EqualFold(filepath.Ext(filePath), ".dtsconfig") {
+			fileType = "dtsconfig"
+		}
+	}
+	if fileType != "dtsx" && fileType != "dtsconfig" {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid file_type %q: must be dtsx or dtsConfig", fileType)), nil
+	}
+
 	// Resolve the file path against the package directory
 	resolvedPath := ResolveFilePath(filePath, packageDirectory)
 
 	data, err := os.ReadFile(resolvedPath)
+	if err == nil && fileType == "dtsconfig" {
+		return scanConfigurationCredentials(data, filePath, format), nil
+	}
 	if err != nil {
 		result := formatter.CreateAnalysisResult("scan_credentials", filePath, nil, err)
 		if format == formatter.FormatJSON {
@@ -4516,45 +4957,8 @@ func HandleScanCredentials(_ context.Context, request mcp.CallToolRequest, packa
 
 	issuesFound := false
 
-	// Comprehensive credential pattern matching
-	credentialPatterns := []struct {
-		name     string
-		patterns []string
-		category string
-	}{
-		{
-			name:     "Database Credentials",
-			patterns: []string{"password=", "pwd=", "user id=", "uid=", "userid=", "trusted_connection=false", "integrated security=false"},
-			category: "Database",
-		},
-		{
-			name:     "API Keys & Tokens",
-			patterns: []string{"apikey=", "api_key=", "token=", "bearer=", "authorization=", "x-api-key="},
-			category: "API",
-		},
-		{
-			name:     "Cloud Credentials",
-			patterns: []string{"accesskey=", "secretkey=", "accountkey=", "sharedaccesskey=", "sas=", "connectionstring="},
-			category: "Cloud",
-		},
-		{
-			name:     "Encryption Keys",
-			patterns: []string{"encryptionkey=", "key=", "certificate=", "privatekey=", "publickey="},
-			category: "Encryption",
-		},
-		{
-			name:     "Personal Data",
-			patterns: []string{"ssn=", "socialsecurity=", "creditcard=", "cardnumber=", "email=", "phone="},
-			category: "PII",
-		},
-	}
-
 	// scanConnectionCredentials performs advanced credential scanning on connection strings
-	scanConnectionCredentials := func(connections []types.Connection, patterns []struct {
-		name     string
-		patterns []string
-		category string
-	}) []string {
+	scanConnectionCredentials := func(connections []types.Connection, patterns []credentialPatternGroup) []string {
 		var issues []string
 
 		for _, conn := range connections {
@@ -4578,11 +4982,7 @@ func HandleScanCredentials(_ context.Context, request mcp.CallToolRequest, packa
 	}
 
 	// scanVariableCredentials performs advanced credential scanning on variables
-	scanVariableCredentials := func(variables []types.Variable, patterns []struct {
-		name     string
-		patterns []string
-		category string
-	}) []string {
+	scanVariableCredentials := func(variables []types.Variable, patterns []credentialPatternGroup) []string {
 		var issues []string
 
 		for _, variable := range variables {
@@ -4606,11 +5006,7 @@ func HandleScanCredentials(_ context.Context, request mcp.CallToolRequest, packa
 	}
 
 	// scanScriptCredentials performs advanced credential scanning on script tasks
-	scanScriptCredentials := func(tasks []types.Task, patterns []struct {
-		name     string
-		patterns []string
-		category string
-	}) []string {
+	scanScriptCredentials := func(tasks []types.Task, patterns []credentialPatternGroup) []string {
 		var issues []string
 
 		for _, task := range tasks {
@@ -4635,11 +5031,7 @@ func HandleScanCredentials(_ context.Context, request mcp.CallToolRequest, packa
 	}
 
 	// scanExpressionCredentials performs advanced credential scanning on expressions
-	scanExpressionCredentials := func(tasks []types.Task, patterns []struct {
-		name     string
-		patterns []string
-		category string
-	}) []string {
+	scanExpressionCredentials := func(tasks []types.Task, patterns []credentialPatternGroup) []string {
 		var issues []string
 
 		for _, task := range tasks {
@@ -4663,11 +5055,7 @@ func HandleScanCredentials(_ context.Context, request mcp.CallToolRequest, packa
 	}
 
 	// scanRawContentCredentials performs advanced credential scanning on raw XML content
-	scanRawContentCredentials := func(content string, patterns []struct {
-		name     string
-		patterns []string
-		category string
-	}) []string {
+	scanRawContentCredentials := func(content string, patterns []credentialPatternGroup) []string {
 		var issues []string
 		contentLower := strings.ToLower(content)
 
@@ -4748,6 +5136,19 @@ func HandleScanCredentials(_ context.Context, request mcp.CallToolRequest, packa
 	}
 	result.WriteString("\n")
 
+	// Check raw XML content for Azure credentials
+	result.WriteString("☁️ Azure Credential Analysis:\n")
+	azureIssues := scanAzureCredentials(string(data))
+	if len(azureIssues) > 0 {
+		issuesFound = true
+		for _, issue := range azureIssues {
+			result.WriteString(fmt.Sprintf("⚠️  %s\n", issue))
+		}
+	} else {
+		result.WriteString("No Azure credential patterns detected.\n")
+	}
+	result.WriteString("\n")
+
 	if !issuesFound {
 		result.WriteString("✅ No credential patterns detected in this package.\n\n")
 		result.WriteString("💡 Credential Security Best Practices:\n")
@@ -4765,6 +5166,17 @@ func HandleScanCredentials(_ context.Context, request mcp.CallToolRequest, packa
 		result.WriteString("• Audit and monitor access to sensitive data\n")
 	}
 
+	if format == formatter.FormatSARIF {
+		var sarifFindings []formatter.Finding
+		for _, issues := range [][]string{connIssues, varIssues, scriptIssues, exprIssues, rawIssues} {
+			sarifFindings = append(sarifFindings, credentialFindings(issues, filePath)...)
+		}
+		for _, issue := range azureIssues {
+			sarifFindings = append(sarifFindings, formatter.Finding{RuleID: issue.RuleID, Message: issue.Message(), Level: issue.Severity, URI: filePath})
+		}
+		return mcp.NewToolResultText(formatter.FormatAsSARIF("scan_credentials", sarifFindings)), nil
+	}
+
 	analysisResult := formatter.CreateAnalysisResult("scan_credentials", filePath, result.String(), nil)
 
 	// For JSON format, return structured data
@@ -4786,6 +5198,100 @@ func HandleScanCredentials(_ context.Context, request mcp.CallToolRequest, packa
 	return mcp.NewToolResultText(formatter.FormatAnalysisResult(analysisResult, format)), nil
 }
 
+// credentialFindings converts "[Category] message" credential issues to SARIF findings with a credential_<category> rule ID
+func credentialFindings(issues []string, filePath string) []formatter.Finding {
+	findings := make([]formatter.Finding, 0, len(issues))
+	for _, issue := range issues {
+		ruleID, message := "credential", issue
+		if end := strings.Index(issue, "] "); strings.HasPrefix(issue, "[") && end > 0 {
+			ruleID = "credential_" + strings.ToLower(strings.ReplaceAll(issue[1:end], " ", "_"))
+			message = issue[end+2:]
+		}
+		findings = append(findings, formatter.Finding{RuleID: ruleID, Message: message, Level: formatter.SARIFLevelError, URI: filePath})
+	}
+	return findings
+}
+
+// azureCredentialPattern is an Azure credential format scanned for by scan_credentials
+type azureCredentialPattern struct {
+	RuleID   string
+	Name     string
+	Severity string
+	Pattern  *regexp.Regexp
+	// IgnoreAfter skips matches preceded by this text, ignoring case, so a more specific pattern reports them
+	IgnoreAfter string
+}
+
+// azureCredentialPatterns report plaintext keys as errors and settings that need review as warnings
+var azureCredentialPatterns = []azureCredentialPattern{
+	{
+		RuleID:   "credential_azure_sas_token",
+		Name:     "Azure SAS token with signature",
+		Severity: formatter.SARIFLevelError,
+		Pattern:  regexp.MustCompile(`(?i)\bsv=\d{4}-\d{2}-\d{2}[^"'<\s]*?\bsig=[A-Za-z0-9%+/=]{10,}`),
+	},
+	{
+		RuleID:   "credential_azure_storage_account_key",
+		Name:     "Azure Storage account key",
+		Severity: formatter.SARIFLevelError,
+		Pattern:  regexp.MustCompile(`(?i)\baccountkey\s*=\s*[A-Za-z0-9+/]{86}==`),
+	},
+	{
+		RuleID:      "credential_azure_possible_storage_key",
+		Name:        "88-character base64 value matching the Azure Storage key format",
+		Severity:    formatter.SARIFLevelWarning,
+		Pattern:     regexp.MustCompile(`[A-Za-z0-9+/]{86}==`),
+		IgnoreAfter: "accountkey=",
+	},
+	{
+		RuleID:   "credential_azure_client_secret",
+		Name:     "Azure service principal secret",
+		Severity: formatter.SARIFLevelError,
+		Pattern:  regexp.MustCompile(`(?i)\b(?:client_?secret|app_?secret|service_?principal_?(?:key|secret))\s*[=:]\s*["']?[^@$;"'<\s][^;"'<\s]*|\b[A-Za-z0-9_~.-]{3}8Q~[A-Za-z0-9_~.-]{31,34}\b`),
+	},
+	{
+		RuleID:   "credential_azure_ad_password",
+		Name:     "Azure SQL connection using Authentication=Active Directory Password",
+		Severity: formatter.SARIFLevelWarning,
+		Pattern:  regexp.MustCompile(`(?i)\bauthentication\s*=\s*["']?active\s+directory\s+password\b`),
+	},
+}
+
+// azureCredentialIssue counts the matches of an Azure credential pattern
+type azureCredentialIssue struct {
+	RuleID   string
+	Name     string
+	Severity string
+	Count    int
+}
+
+// Message describes the issue without repeating the credential value
+func (i azureCredentialIssue) Message() string {
+	return fmt.Sprintf("%s detected (%d occurrence(s))", i.Name, i.Count)
+}
+
+func (i azureCredentialIssue) String() string {
+	return fmt.Sprintf("[Azure] %s: %s", strings.ToUpper(i.Severity), i.Message())
+}
+
+// scanAzureCredentials matches the Azure credential patterns against raw package content
+func scanAzureCredentials(content string) []azureCredentialIssue {
+	var issues []azureCredentialIssue
+	for _, pattern := range azureCredentialPatterns {
+		count := 0
+		for _, loc := range pattern.Pattern.FindAllStringIndex(content, -1) {
+			if pattern.IgnoreAfter != "" && strings.HasSuffix(strings.ToLower(strings.TrimRight(content[:loc[0]], " ")), pattern.IgnoreAfter) {
+				continue
+			}
+			count++
+		}
+		if count > 0 {
+			issues = append(issues, azureCredentialIssue{RuleID: pattern.RuleID, Name: pattern.Name, Severity: pattern.Severity, Count: count})
+		}
+	}
+	return issues
+}
+
 // HandleDetectEncryption handles encryption detection and recommendations from DTSX files
 func HandleDetectEncryption(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
 	filePath, err := request.RequireString("file_path")
@@ -4905,34 +5411,7 @@ func HandleDetectEncryption(_ context.Context, request mcp.CallToolRequest, pack
 
 	// Check package protection level
 	result.WriteString("📦 Package Protection Level:\n")
-	protectionLevel := "Not specified"
-	for _, prop := range pkg.Properties {
-		if prop.Name == "ProtectionLevel" {
-			protectionLevel = prop.Value
-			break
-		}
-	}
-
-	switch protectionLevel {
-	case "0", "DontSaveSensitive":
-		result.WriteString("⚠️  Protection Level: DontSaveSensitive - Sensitive data will not be saved\n")
-		result.WriteString("   This is generally secure but requires runtime parameter input\n")
-	case "1", "EncryptSensitiveWithUserKey":
-		result.WriteString("⚠️  Protection Level: EncryptSensitiveWithUserKey - Uses current user key\n")
-		result.WriteString("   This may cause issues when deploying to different users/machines\n")
-	case "2", "EncryptSensitiveWithPassword":
-		result.WriteString("✅ Protection Level: EncryptSensitiveWithPassword - Uses password protection\n")
-		result.WriteString("   Good for deployment but requires secure password management\n")
-	case "3", "EncryptAllWithPassword":
-		result.WriteString("✅ Protection Level: EncryptAllWithPassword - Encrypts entire package\n")
-		result.WriteString("   Maximum security but requires password for execution\n")
-	case "4", "EncryptAllWithUserKey":
-		result.WriteString("⚠️  Protection Level: EncryptAllWithUserKey - Uses current user key for all data\n")
-		result.WriteString("   May cause deployment issues across different users/machines\n")
-	default:
-		result.WriteString("❓ Protection Level: Unknown or not set\n")
-		result.WriteString("   Consider setting an appropriate protection level\n")
-	}
+	result.WriteString(describeProtectionLevel(packageProtectionLevel(pkg)))
 	result.WriteString("\n")
 
 	// Check for encrypted connection strings
@@ -4990,6 +5469,73 @@ func HandleDetectEncryption(_ context.Context, request mcp.CallToolRequest, pack
 	return mcp.NewToolResultText(formatter.FormatAnalysisResult(analysisResult, format)), nil
 }
 
+// protectionLevelNames maps DTS:ProtectionLevel values to their SSIS names
+var protectionLevelNames = map[string]string{
+	"0": "DontSaveSensitive",
+	"1": "EncryptSensitiveWithUserKey",
+	"2": "EncryptSensitiveWithPassword",
+	"3": "EncryptAllWithPassword",
+	"4": "EncryptAllWithUserKey",
+	"5": "ServerStorage",
+}
+
+// packageProtectionLevel returns the package protection level, preferring the root ProtectionLevel attribute
+func packageProtectionLevel(pkg types.SSISPackage) string {
+	if level := strings.TrimSpace(pkg.ProtectionLevel); level != "" {
+		return level
+	}
+	for _, prop := range pkg.Properties {
+		if prop.Name == "ProtectionLevel" {
+			return strings.TrimSpace(prop.Value)
+		}
+	}
+	return ""
+}
+
+// describeProtectionLevel renders the protection level name and its recommendation
+func describeProtectionLevel(level string) string {
+	name, ok := protectionLevelNames[level]
+	if !ok {
+		for value, candidate := range protectionLevelNames {
+			if strings.EqualFold(level, candidate) {
+				level, name, ok = value, candidate, true
+				break
+			}
+		}
+	}
+	if !ok {
+		if level == "" {
+			return "❓ Protection Level: Not specified\n   Consider setting an appropriate protection level\n"
+		}
+		return fmt.Sprintf("❓ Protection Level: Unknown value '%s'\n   Consider setting an appropriate protection level\n", level)
+	}
+
+	var sb strings.Builder
+	switch level {
+	case "0", "1":
+		sb.WriteString(fmt.Sprintf("⚠️  Protection Level: %s (%s)\n", name, level))
+		if level == "0" {
+			sb.WriteString("   Sensitive data will not be saved and must be supplied at runtime\n")
+		} else {
+			sb.WriteString("   Uses the current user key, which breaks deployments to other users/machines\n")
+		}
+		sb.WriteString("   Recommendation: upgrade to EncryptAllWithPassword (3) or ServerStorage (5)\n")
+	case "2":
+		sb.WriteString(fmt.Sprintf("✅ Protection Level: %s (%s)\n", name, level))
+		sb.WriteString("   Good for deployment but requires secure password management\n")
+	case "3":
+		sb.WriteString(fmt.Sprintf("✅ Protection Level: %s (%s)\n", name, level))
+		sb.WriteString("   Maximum security but requires password for execution\n")
+	case "4":
+		sb.WriteString(fmt.Sprintf("⚠️  Protection Level: %s (%s)\n", name, level))
+		sb.WriteString("   May cause deployment issues across different users/machines\n")
+	case "5":
+		sb.WriteString(fmt.Sprintf("✅ Protection Level: %s (%s)\n", name, level))
+		sb.WriteString("   Sensitive data is protected by SQL Server database roles\n")
+	}
+	return sb.String()
+}
+
 // HandleCheckCompliance handles compliance checking for various standards from DTSX files
 func HandleCheckCompliance(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
 	filePath, err := request.RequireString("file_path")
@@ -5048,10 +5594,12 @@ func HandleCheckCompliance(_ context.Context, request mcp.CallToolRequest, packa
 	result.WriteString(fmt.Sprintf("Standard: %s\n\n", strings.ToUpper(complianceStandard)))
 
 	issuesFound := false
+	var complianceChecks []formatter.TestCase
+	findings := []complianceFinding{}
 
 	// checkGDPRCompliance checks for GDPR compliance issues
-	checkGDPRCompliance := func(pkg types.SSISPackage, content string) []string {
-		var issues []string
+	checkGDPRCompliance := func(pkg types.SSISPackage, content string) []complianceFinding {
+		var issues []complianceFinding
 		contentLower := strings.ToLower(content)
 
 		// GDPR-specific patterns
@@ -5063,21 +5611,21 @@ func HandleCheckCompliance(_ context.Context, request mcp.CallToolRequest, packa
 
 		for _, pattern := range gdprPatterns {
 			if strings.Contains(contentLower, pattern) {
-				issues = append(issues, fmt.Sprintf("Potential GDPR-sensitive data pattern detected: %s", pattern))
+				issues = append(issues, newComplianceFinding("gdpr", ruleGDPRSensitiveData, fmt.Sprintf("Potential GDPR-sensitive data pattern detected: %s", pattern)))
 			}
 		}
 
 		// Check for data retention patterns
 		if strings.Contains(contentLower, "delete") || strings.Contains(contentLower, "truncate") {
-			issues = append(issues, "Data deletion operations detected - ensure GDPR compliance for data retention")
+			issues = append(issues, newComplianceFinding("gdpr", ruleGDPRDataRetention, "Data deletion operations detected - ensure GDPR compliance for data retention"))
 		}
 
 		return issues
 	}
 
 	// checkHIPAACompliance checks for HIPAA compliance issues
-	checkHIPAACompliance := func(pkg types.SSISPackage, content string) []string {
-		var issues []string
+	checkHIPAACompliance := func(pkg types.SSISPackage, content string) []complianceFinding {
+		var issues []complianceFinding
 		contentLower := strings.ToLower(content)
 
 		// HIPAA-specific patterns
@@ -5089,7 +5637,7 @@ func HandleCheckCompliance(_ context.Context, request mcp.CallToolRequest, packa
 
 		for _, pattern := range hipaaPatterns {
 			if strings.Contains(contentLower, pattern) {
-				issues = append(issues, fmt.Sprintf("Potential HIPAA-sensitive data pattern detected: %s", pattern))
+				issues = append(issues, newComplianceFinding("hipaa", ruleHIPAAProtectedHealth, fmt.Sprintf("Potential HIPAA-sensitive data pattern detected: %s", pattern)))
 			}
 		}
 
@@ -5102,15 +5650,15 @@ func HandleCheckCompliance(_ context.Context, request mcp.CallToolRequest, packa
 			}
 		}
 		if !hasAudit {
-			issues = append(issues, "No audit logging detected - HIPAA requires audit trails for PHI access")
+			issues = append(issues, newComplianceFinding("hipaa", ruleHIPAAAuditLogging, "No audit logging detected - HIPAA requires audit trails for PHI access"))
 		}
 
 		return issues
 	}
 
 	// checkPCICompliance checks for PCI DSS compliance issues
-	checkPCICompliance := func(pkg types.SSISPackage, content string) []string {
-		var issues []string
+	checkPCICompliance := func(pkg types.SSISPackage, content string) []complianceFinding {
+		var issues []complianceFinding
 		contentLower := strings.ToLower(content)
 
 		// PCI DSS-specific patterns
@@ -5121,14 +5669,14 @@ func HandleCheckCompliance(_ context.Context, request mcp.CallToolRequest, packa
 
 		for _, pattern := range pciPatterns {
 			if strings.Contains(contentLower, pattern) {
-				issues = append(issues, fmt.Sprintf("Potential PCI DSS-sensitive data pattern detected: %s", pattern))
+				issues = append(issues, newComplianceFinding("pci", rulePCICardholderData, fmt.Sprintf("Potential PCI DSS-sensitive data pattern detected: %s", pattern)))
 			}
 		}
 
 		// Check for encryption of card data
 		if strings.Contains(contentLower, "creditcard") || strings.Contains(contentLower, "cardnumber") {
 			if !strings.Contains(contentLower, "encrypt") && !strings.Contains(contentLower, "mask") {
-				issues = append(issues, "Card data detected without apparent encryption or masking")
+				issues = append(issues, newComplianceFinding("pci", rulePCIUnprotectedCardData, "Card data detected without apparent encryption or masking"))
 			}
 		}
 
@@ -5136,8 +5684,8 @@ func HandleCheckCompliance(_ context.Context, request mcp.CallToolRequest, packa
 	}
 
 	// checkGeneralDataProtection checks for general data protection issues
-	checkGeneralDataProtection := func(pkg types.SSISPackage, content string) []string {
-		var issues []string
+	checkGeneralDataProtection := func(pkg types.SSISPackage, content string) []complianceFinding {
+		var issues []complianceFinding
 		contentLower := strings.ToLower(content)
 
 		// General data protection patterns
@@ -5148,7 +5696,7 @@ func HandleCheckCompliance(_ context.Context, request mcp.CallToolRequest, packa
 
 		for _, pattern := range generalPatterns {
 			if strings.Contains(contentLower, pattern) {
-				issues = append(issues, fmt.Sprintf("Potential sensitive data pattern detected: %s", pattern))
+				issues = append(issues, newComplianceFinding("general_data_protection", ruleGeneralSensitiveData, fmt.Sprintf("Potential sensitive data pattern detected: %s", pattern)))
 			}
 		}
 
@@ -5157,7 +5705,7 @@ func HandleCheckCompliance(_ context.Context, request mcp.CallToolRequest, packa
 			strings.Contains(contentLower, "pseudonymize")
 
 		if !hasMasking && (strings.Contains(contentLower, "personal") || strings.Contains(contentLower, "sensitive")) {
-			issues = append(issues, "Sensitive data handling detected without apparent masking/anonymization")
+			issues = append(issues, newComplianceFinding("general_data_protection", ruleGeneralMissingMasking, "Sensitive data handling detected without apparent masking/anonymization"))
 		}
 
 		return issues
@@ -5167,8 +5715,10 @@ func HandleCheckCompliance(_ context.Context, request mcp.CallToolRequest, packa
 	if complianceStandard == "gdpr" || complianceStandard == "all" {
 		result.WriteString("🇪🇺 GDPR Compliance Analysis:\n")
 		gdprIssues := checkGDPRCompliance(pkg, string(data))
+		complianceChecks = append(complianceChecks, complianceTestCase("gdpr", gdprIssues))
 		if len(gdprIssues) > 0 {
 			issuesFound = true
+			findings = append(findings, gdprIssues...)
 			for _, issue := range gdprIssues {
 				result.WriteString(fmt.Sprintf("⚠️  %s\n", issue))
 			}
@@ -5182,8 +5732,10 @@ func HandleCheckCompliance(_ context.Context, request mcp.CallToolRequest, packa
 	if complianceStandard == "hipaa" || complianceStandard == "all" {
 		result.WriteString("🏥 HIPAA Compliance Analysis:\n")
 		hipaaIssues := checkHIPAACompliance(pkg, string(data))
+		complianceChecks = append(complianceChecks, complianceTestCase("hipaa", hipaaIssues))
 		if len(hipaaIssues) > 0 {
 			issuesFound = true
+			findings = append(findings, hipaaIssues...)
 			for _, issue := range hipaaIssues {
 				result.WriteString(fmt.Sprintf("⚠️  %s\n", issue))
 			}
@@ -5197,8 +5749,10 @@ func HandleCheckCompliance(_ context.Context, request mcp.CallToolRequest, packa
 	if complianceStandard == "pci" || complianceStandard == "all" {
 		result.WriteString("💳 PCI DSS Compliance Analysis:\n")
 		pciIssues := checkPCICompliance(pkg, string(data))
+		complianceChecks = append(complianceChecks, complianceTestCase("pci", pciIssues))
 		if len(pciIssues) > 0 {
 			issuesFound = true
+			findings = append(findings, pciIssues...)
 			for _, issue := range pciIssues {
 				result.WriteString(fmt.Sprintf("⚠️  %s\n", issue))
 			}
@@ -5212,8 +5766,10 @@ func HandleCheckCompliance(_ context.Context, request mcp.CallToolRequest, packa
 	if complianceStandard == "all" {
 		result.WriteString("🔒 General Data Protection Analysis:\n")
 		generalIssues := checkGeneralDataProtection(pkg, string(data))
+		complianceChecks = append(complianceChecks, complianceTestCase("general_data_protection", generalIssues))
 		if len(generalIssues) > 0 {
 			issuesFound = true
+			findings = append(findings, generalIssues...)
 			for _, issue := range generalIssues {
 				result.WriteString(fmt.Sprintf("⚠️  %s\n", issue))
 			}
@@ -5241,6 +5797,10 @@ func HandleCheckCompliance(_ context.Context, request mcp.CallToolRequest, packa
 		result.WriteString("• Regular compliance audits and monitoring\n")
 	}
 
+	if format == formatter.FormatJUnit {
+		return mcp.NewToolResultText(formatter.FormatAsJUnit("check_compliance", filePath, complianceChecks)), nil
+	}
+
 	analysisResult := formatter.CreateAnalysisResult("check_compliance", filePath, result.String(), nil)
 
 	// For JSON format, return structured data
@@ -5252,6 +5812,7 @@ func HandleCheckCompliance(_ context.Context, request mcp.CallToolRequest, packa
 			"timestamp": analysisResult.Timestamp,
 			"status":    analysisResult.Status,
 			"analysis":  analysisResult.Data,
+			"findings":  findings,
 		}
 		if analysisResult.Error != "" {
 			jsonResult["error"] = analysisResult.Error
@@ -5262,6 +5823,197 @@ func HandleCheckCompliance(_ context.Context, request mcp.CallToolRequest, packa
 	return mcp.NewToolResultText(formatter.FormatAnalysisResult(analysisResult, format)), nil
 }
 
+// componentKeyProperty names a summarized component setting and the property names that may carry it
+type componentKeyProperty struct {
+	Label string
+	Names []string
+	// Connection falls back to the connection managers the component references when no property is set
+	Connection bool
+}
+
+// azureAuthenticationProperties lists property names used for Azure authentication settings
+var azureAuthenticationProperties = []string{"AuthenticationMode", "Authentication", "AuthenticationType"}
+
+// sourceKeyProperties lists the key properties reported for each source type
+var sourceKeyProperties = map[string][]componentKeyProperty{
+	"azure_blob": {
+		{Label: "Container", Names: []string{"BlobContainer", "ContainerName", "Container"}},
+		{Label: "File Path", Names: []string{"BlobName", "BlobPath", "FilePath"}},
+		{Label: "Authentication", Names: azureAuthenticationProperties},
+	},
+	"azure_dls": {
+		{Label: "Container", Names: []string{"FileSystem", "ContainerName", "Container"}},
+		{Label: "File Path", Names: []string{"FilePath", "FolderPath", "Path"}},
+		{Label: "Authentication", Names: azureAuthenticationProperties},
+	},
+	"json_source": {
+		{Label: "Connection", Names: []string{"ConnectionName", "ConnectionManager", "Connection"}},
+		{Label: "JSON Path", Names: []string{"JSONPath", "JsonPath"}},
+		{Label: "Root Path", Names: []string{"RootPath"}},
+		{Label: "Query Timeout", Names: []string{"QueryTimeout"}},
+	},
+	"odata": {
+		{Label: "Connection Manager", Names: []string{"ConnectionManagerName", "ConnectionManager", "Connection"}},
+		{Label: "Collection", Names: []string{"CollectionOrEntitySetName", "CollectionName", "EntitySetName"}},
+		{Label: "Use Default Credentials", Names: []string{"UseDefaultCredentials"}},
+		{Label: "Query", Names: []string{"Query"}},
+		{Label: "Default String Length", Names: []string{"DefaultStringLength"}},
+	},
+}
+
+// destinationKeyProperties lists the key properties reported for each destination type
+var destinationKeyProperties = map[string][]componentKeyProperty{
+	"azure_blob": {
+		{Label: "Container", Names: []string{"BlobContainer", "ContainerName", "Container"}},
+		{Label: "Blob Path", Names: []string{"BlobName", "BlobPath", "FilePath"}},
+		{Label: "Authentication", Names: azureAuthenticationProperties},
+	},
+	"azure_sql": {
+		{Label: "Table", Names: []string{"TableName", "OpenRowset", "DestinationTable"}},
+		{Label: "Batch Size", Names: []string{"BatchSize", "FastLoadMaxInsertCommitSize"}},
+		{Label: "Authentication", Names: azureAuthenticationProperties},
+	},
+	"ado_net": {
+		{Label: "Connection", Names: []string{"ConnectionName", "Connection"}, Connection: true},
+		{Label: "Table", Names: []string{"TableOrViewName", "TableName"}},
+		{Label: "Batch Size", Names: []string{"BatchSize"}},
+		{Label: "Use Bulk Insert When Possible", Names: []string{"UseBulkInsertWhenPossible"}},
+	},
+	"odbc": {
+		{Label: "Connection", Names: []string{"Connection", "ConnectionName"}, Connection: true},
+		{Label: "Target Table", Names: []string{"TargetTableName", "TableName"}},
+		{Label: "Batch Size", Names: []string{"BatchSize"}},
+		{Label: "Commit Size", Names: []string{"CommitSize", "TransactionSize"}},
+		{Label: "Transaction Option", Names: []string{"TransactionOption"}},
+	},
+}
+
+// componentProperties returns the component properties from the inline properties element and legacy objectData block
+func componentProperties(comp types.DataFlowComponent) []types.ComponentProperty {
+	props := append([]types.ComponentProperty{}, comp.Properties.Properties...)
+	return append(props, comp.ObjectData.PipelineComponent.Properties.Properties...)
+}
+
+// componentPropertyValue returns the first non-empty value among the named component properties
+func componentPropertyValue(comp types.DataFlowComponent, names ...string) string {
+	for _, name := range names {
+		for _, prop := range componentProperties(comp) {
+			if strings.EqualFold(prop.Name, name) {
+				if value := strings.TrimSpace(prop.Value); value != "" {
+					return value
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// componentConnectionManagers returns the names of the connection managers a component references
+func componentConnectionManagers(comp types.DataFlowComponent) []string {
+	var names []string
+	for _, conn := range comp.Connections.Connections {
+		name := conn.ConnectionManagerRefID
+		if start := strings.Index(name, "["); start != -1 && strings.HasSuffix(name, "]") {
+			name = name[start+1 : len(name)-1]
+		}
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// inputColumnMappings returns "input column → external column" pairs for the input columns mapped to external metadata
+func inputColumnMappings(input types.ComponentInput) []string {
+	externalNames := make(map[string]string)
+	for _, col := range input.ExternalMetadata.Columns {
+		externalNames[col.RefID] = col.Name
+	}
+	var mappings []string
+	for _, col := range input.InputColumns.Columns {
+		external, ok := externalNames[col.ExternalMetadataColumnID]
+		if !ok {
+			continue
+		}
+		name := col.CachedName
+		if name == "" {
+			name = col.Name
+		}
+		mappings = append(mappings, fmt.Sprintf("%s → %s", name, external))
+	}
+	return mappings
+}
+
+// writeKeyProperties writes a key property summary for a component
+func writeKeyProperties(result *strings.Builder, comp types.DataFlowComponent, keys []componentKeyProperty) {
+	result.WriteString("Key Properties:\n")
+	for _, key := range keys {
+		value := componentPropertyValue(comp, key.Names...)
+		if value == "" && key.Connection {
+			value = strings.Join(componentConnectionManagers(comp), ", ")
+		}
+		if value == "" {
+			value = "Not specified"
+		}
+		result.WriteString(fmt.Sprintf("  %s: %s\n", key.Label, value))
+	}
+}
+
+// Special source_type/destination_type values: generic_classid matches components by the class_id parameter and
+// all reports every supported type
+const (
+	genericClassIDType = "generic_classid"
+	allComponentTypes  = "all"
+)
+
+// requireClassID returns the class_id parameter used with the generic_classid type
+func requireClassID(request mcp.CallToolRequest) (string, error) {
+	classID := strings.TrimSpace(request.GetString("class_id", ""))
+	if classID == "" {
+		return "", fmt.Errorf("class_id is required when the type is %s", genericClassIDType)
+	}
+	return classID, nil
+}
+
+// sourceTypes lists the source_type values in the order they are reported by source_type=all
+var sourceTypes = []string{"ole_db", "ado_net", "odbc", "flat_file", "excel", "access", "xml", "raw_file", "cdc", "sap_bw", "azure_blob", "azure_dls", "json_source", "odata"}
+
+// sourceTypeMap maps source types to ComponentClassIDs
+var sourceTypeMap = map[string]string{
+	"ole_db":      "Microsoft.OLEDBSource",
+	"ado_net":     "Microsoft.SqlServer.Dts.Pipeline.DataReaderSourceAdapter",
+	"odbc":        "Microsoft.SqlServer.Dts.Pipeline.OdbcSourceAdapter",
+	"flat_file":   "Microsoft.SqlServer.Dts.Pipeline.FlatFileSourceAdapter",
+	"excel":       "Microsoft.SqlServer.Dts.Pipeline.ExcelSourceAdapter",
+	"access":      "Microsoft.SqlServer.Dts.Pipeline.AccessSourceAdapter",
+	"xml":         "Microsoft.SqlServer.Dts.Pipeline.XmlSourceAdapter",
+	"raw_file":    "Microsoft.SqlServer.Dts.Pipeline.RawFileSourceAdapter",
+	"cdc":         "Microsoft.SqlServer.Dts.Pipeline.CdcSourceAdapter",
+	"sap_bw":      "Microsoft.SqlServer.Dts.Pipeline.SapBwSourceAdapter",
+	"azure_blob":  "Microsoft.Azure.BlobSource",
+	"azure_dls":   "Microsoft.Azure.DataLakeStorageSource",
+	"json_source": "Microsoft.Json.Source",
+	"odata":       "Microsoft.SqlServer.Dts.Pipeline.ODataSource",
+}
+
+// sourceNameMap maps source types to display names
+var sourceNameMap = map[string]string{
+	"ole_db":      "OLE DB Source",
+	"ado_net":     "ADO.NET Source",
+	"odbc":        "ODBC Source",
+	"flat_file":   "Flat File Source",
+	"excel":       "Excel Source",
+	"access":      "Access Source",
+	"xml":         "XML Source",
+	"raw_file":    "Raw File Source",
+	"cdc":         "CDC Source",
+	"sap_bw":      "SAP BW Source",
+	"azure_blob":  "Azure Blob Source",
+	"azure_dls":   "Azure Data Lake Storage Source",
+	"json_source": "JSON Source",
+	"odata":       "OData Source",
+}
+
 // HandleAnalyzeSource provides unified analysis for various SSIS source components
 func HandleAnalyzeSource(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
 	filePath, err := request.RequireString("file_path")
@@ -5274,40 +6026,22 @@ func HandleAnalyzeSource(_ context.Context, request mcp.CallToolRequest, package
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	// Map source types to ComponentClassIDs
-	sourceTypeMap := map[string]string{
-		"ole_db":    "Microsoft.OLEDBSource",
-		"ado_net":   "Microsoft.SqlServer.Dts.Pipeline.DataReaderSourceAdapter",
-		"odbc":      "Microsoft.SqlServer.Dts.Pipeline.OdbcSourceAdapter",
-		"flat_file": "Microsoft.SqlServer.Dts.Pipeline.FlatFileSourceAdapter",
-		"excel":     "Microsoft.SqlServer.Dts.Pipeline.ExcelSourceAdapter",
-		"access":    "Microsoft.SqlServer.Dts.Pipeline.AccessSourceAdapter",
-		"xml":       "Microsoft.SqlServer.Dts.Pipeline.XmlSourceAdapter",
-		"raw_file":  "Microsoft.SqlServer.Dts.Pipeline.RawFileSourceAdapter",
-		"cdc":       "Microsoft.SqlServer.Dts.Pipeline.CdcSourceAdapter",
-		"sap_bw":    "Microsoft.SqlServer.Dts.Pipeline.SapBwSourceAdapter",
-	}
-
 	componentClassID, exists := sourceTypeMap[sourceType]
-	if !exists {
-		return mcp.NewToolResultError(fmt.Sprintf("Unknown source type: %s. Supported types: ole_db, ado_net, odbc, flat_file, excel, access, xml, raw_file, cdc, sap_bw", sourceType)), nil
-	}
-
-	// Map source types to display names
-	sourceNameMap := map[string]string{
-		"ole_db":    "OLE DB Source",
-		"ado_net":   "ADO.NET Source",
-		"odbc":      "ODBC Source",
-		"flat_file": "Flat File Source",
-		"excel":     "Excel Source",
-		"access":    "Access Source",
-		"xml":       "XML Source",
-		"raw_file":  "Raw File Source",
-		"cdc":       "CDC Source",
-		"sap_bw":    "SAP BW Source",
+	if sourceType == genericClassIDType {
+		if componentClassID, err = requireClassID(request); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	} else if !exists && sourceType != allComponentTypes {
+		return mcp.NewToolResultError(fmt.Sprintf("Unknown source type: %s. Supported types: %s, %s, %s", sourceType, strings.Join(sourceTypes, ", "), genericClassIDType, allComponentTypes)), nil
 	}
 
 	displayName := sourceNameMap[sourceType]
+	switch sourceType {
+	case genericClassIDType:
+		displayName = fmt.Sprintf("Source Component (%s)", componentClassID)
+	case allComponentTypes:
+		displayName = "All Sources"
+	}
 
 	resolvedPath := ResolveFilePath(filePath, packageDirectory)
 
@@ -5327,6 +6061,29 @@ func HandleAnalyzeSource(_ context.Context, request mcp.CallToolRequest, package
 	var result strings.Builder
 	result.WriteString(fmt.Sprintf("%s Analysis:\n\n", displayName))
 
+	if sourceType == allComponentTypes {
+		found := false
+		for _, srcType := range sourceTypes {
+			var section strings.Builder
+			if writeSourceComponents(&section, pkg, srcType, sourceTypeMap[srcType]) {
+				found = true
+				result.WriteString(fmt.Sprintf("=== %s ===\n\n", sourceNameMap[srcType]))
+				result.WriteString(section.String())
+			}
+		}
+		if !found {
+			result.WriteString("No source components found in this package.\n")
+		}
+	} else if !writeSourceComponents(&result, pkg, sourceType, componentClassID) {
+		result.WriteString(fmt.Sprintf("No %s components found in this package.\n", displayName))
+	}
+
+	return mcp.NewToolResultText(result.String()), nil
+}
+
+// writeSourceComponents writes the analysis of every data flow component with the given class ID and
+// reports whether any was found
+func writeSourceComponents(result *strings.Builder, pkg types.SSISPackage, sourceType, componentClassID string) bool {
 	found := false
 	for _, task := range pkg.Executables.Tasks {
 		if strings.Contains(task.CreationName, "Pipeline") {
@@ -5336,9 +6093,13 @@ func HandleAnalyzeSource(_ context.Context, request mcp.CallToolRequest, package
 					result.WriteString(fmt.Sprintf("Component: %s\n", comp.Name))
 					result.WriteString(fmt.Sprintf("Description: %s\n", comp.Description))
 
+					if keys, ok := sourceKeyProperties[sourceType]; ok {
+						writeKeyProperties(result, comp, keys)
+					}
+
 					// Properties
 					result.WriteString("Properties:\n")
-					for _, prop := range comp.ObjectData.PipelineComponent.Properties.Properties {
+					for _, prop := range componentProperties(comp) {
 						result.WriteString(fmt.Sprintf("  %s: %s\n", prop.Name, prop.Value))
 					}
 
@@ -5351,6 +6112,9 @@ func HandleAnalyzeSource(_ context.Context, request mcp.CallToolRequest, package
 								if col.Length > 0 {
 									result.WriteString(fmt.Sprintf(", length=%d", col.Length))
 								}
+								if col.Precision > 0 {
+									result.WriteString(fmt.Sprintf(", precision=%d, scale=%d", col.Precision, col.Scale))
+								}
 								result.WriteString(")\n")
 							}
 						}
@@ -5360,10 +6124,5 @@ func HandleAnalyzeSource(_ context.Context, request mcp.CallToolRequest, package
 			}
 		}
 	}
-
-	if !found {
-		result.WriteString(fmt.Sprintf("No %s components found in this package.\n", displayName))
-	}
-
-	return mcp.NewToolResultText(result.String()), nil
+	return found
 }
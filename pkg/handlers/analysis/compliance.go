@@ -0,0 +1,72 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MCPRUNNER/gossisMCP/pkg/formatter"
+)
+
+// Compliance rules reported by check_compliance
+const (
+	ruleGDPRSensitiveData      = "gdpr_sensitive_data"
+	ruleGDPRDataRetention      = "gdpr_data_retention"
+	ruleHIPAAProtectedHealth   = "hipaa_protected_health_information"
+	ruleHIPAAAuditLogging      = "hipaa_audit_logging"
+	rulePCICardholderData      = "pci_cardholder_data"
+	rulePCIUnprotectedCardData = "pci_unprotected_card_data"
+	ruleGeneralSensitiveData   = "general_sensitive_data"
+	ruleGeneralMissingMasking  = "general_missing_masking"
+)
+
+// regulationReferences maps each compliance rule to the regulation article or section it relates to
+var regulationReferences = map[string]string{
+	ruleGDPRSensitiveData:      "GDPR Art. 25 – Data protection by design and by default",
+	ruleGDPRDataRetention:      "GDPR Art. 5(1)(e) – Storage limitation",
+	ruleHIPAAProtectedHealth:   "HIPAA §164.312(a)(1) – Access control",
+	ruleHIPAAAuditLogging:      "HIPAA §164.312(b) – Audit controls",
+	rulePCICardholderData:      "PCI DSS Req. 3 – Protect stored account data",
+	rulePCIUnprotectedCardData: "PCI DSS Req. 3.5.1 – PAN is rendered unreadable anywhere it is stored",
+	ruleGeneralSensitiveData:   "GDPR Art. 32 – Security of processing",
+	ruleGeneralMissingMasking:  "GDPR Art. 32(1)(a) – Pseudonymisation and encryption of personal data",
+}
+
+// complianceFinding is a single check_compliance issue with the regulation it maps to
+type complianceFinding struct {
+	Standard            string `json:"standard"`
+	Rule                string `json:"rule"`
+	Message             string `json:"message"`
+	RegulationReference string `json:"regulation_reference"`
+}
+
+// newComplianceFinding creates a finding for a rule, looking up its regulation reference
+func newComplianceFinding(standard, rule, message string) complianceFinding {
+	return complianceFinding{
+		Standard:            standard,
+		Rule:                rule,
+		Message:             message,
+		RegulationReference: regulationReferences[rule],
+	}
+}
+
+// String renders the finding with its regulation reference for text reports
+func (f complianceFinding) String() string {
+	if f.RegulationReference == "" {
+		return f.Message
+	}
+	return fmt.Sprintf("%s [%s]", f.Message, f.RegulationReference)
+}
+
+// complianceTestCase reports a compliance standard as a JUnit check that fails when any issue was detected
+func complianceTestCase(standard string, findings []complianceFinding) formatter.TestCase {
+	testCase := formatter.TestCase{Name: standard}
+	if len(findings) > 0 {
+		details := make([]string, len(findings))
+		for i, finding := range findings {
+			details[i] = finding.String()
+		}
+		testCase.Failure = fmt.Sprintf("%d compliance issue(s) detected", len(findings))
+		testCase.Details = strings.Join(details, "\n")
+	}
+	return testCase
+}
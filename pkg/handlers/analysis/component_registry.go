@@ -0,0 +1,65 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ComponentRegistry maps component class IDs, or class ID prefixes such as a vendor namespace, to metadata about
+// the component. It is loaded from the registry_file parameter of analyze_custom_components.
+type ComponentRegistry struct {
+	Components map[string]ComponentRegistryEntry `json:"components"`
+}
+
+// ComponentRegistryEntry describes a custom or third-party component
+type ComponentRegistryEntry struct {
+	Vendor      string   `json:"vendor"`
+	Version     string   `json:"version"`
+	DocsURL     string   `json:"docs_url"`
+	KnownIssues []string `json:"known_issues"`
+}
+
+// loadComponentRegistry reads and validates a component registry file
+func loadComponentRegistry(path string) (*ComponentRegistry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry file: %w", err)
+	}
+
+	var registry ComponentRegistry
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return nil, fmt.Errorf("failed to parse registry file: %w", err)
+	}
+	for classID, entry := range registry.Components {
+		if strings.TrimSpace(classID) == "" {
+			return nil, fmt.Errorf("registry file contains an entry without a class ID")
+		}
+		if strings.TrimSpace(entry.Vendor) == "" {
+			return nil, fmt.Errorf("registry entry %s is missing a vendor", classID)
+		}
+	}
+	return &registry, nil
+}
+
+// Lookup returns the entry for a class ID, preferring an exact match over the longest matching class ID prefix.
+// Matching is case-insensitive.
+func (r *ComponentRegistry) Lookup(classID string) (ComponentRegistryEntry, bool) {
+	if r == nil {
+		return ComponentRegistryEntry{}, false
+	}
+	lowered := strings.ToLower(classID)
+	var match ComponentRegistryEntry
+	matchLength := -1
+	for key, entry := range r.Components {
+		loweredKey := strings.ToLower(key)
+		if lowered == loweredKey {
+			return entry, true
+		}
+		if strings.HasPrefix(lowered, loweredKey) && len(loweredKey) > matchLength {
+			match, matchLength = entry, len(loweredKey)
+		}
+	}
+	return match, matchLength >= 0
+}
@@ -0,0 +1,89 @@
+package analysis
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/MCPRUNNER/gossisMCP/pkg/formatter"
+	"github.com/MCPRUNNER/gossisMCP/pkg/types"
+)
+
+// scanConfigurationCredentials applies the scan_credentials patterns to the configured values of a .dtsConfig file
+func scanConfigurationCredentials(data []byte, filePath string, format formatter.OutputFormat) *mcp.CallToolResult {
+	var config types.DTSConfigurationFile
+	if err := xml.Unmarshal(data, &config); err != nil {
+		result := formatter.CreateAnalysisResult("scan_credentials", filePath, nil, fmt.Errorf("failed to parse configuration file: %v", err))
+		return mcp.NewToolResultText(formatter.FormatAnalysisResult(result, format))
+	}
+
+	var result strings.Builder
+	result.WriteString("🔍 Configuration Credential Scanning Report:\n\n")
+	result.WriteString(fmt.Sprintf("Configurations: %d\n\n", len(config.Configurations)))
+
+	var issues []string
+	var azureFindings []formatter.Finding
+	for _, entry := range config.Configurations {
+		valueLower := strings.ToLower(entry.ConfiguredValue)
+		if strings.TrimSpace(valueLower) == "" {
+			continue
+		}
+		for _, patternGroup := range credentialPatterns {
+			for _, pattern := range patternGroup.patterns {
+				if strings.Contains(valueLower, pattern) {
+					issues = append(issues, fmt.Sprintf("[%s] Configuration '%s' contains %s pattern: %s",
+						patternGroup.category, entry.Path, patternGroup.name, pattern))
+				}
+			}
+		}
+		// A configured Password property holds the password itself rather than a key=value pair
+		if strings.HasSuffix(strings.ToLower(entry.Path), ".properties[password]") {
+			issues = append(issues, fmt.Sprintf("[Database] Configuration '%s' sets a plaintext password", entry.Path))
+		}
+		for _, issue := range scanAzureCredentials(entry.ConfiguredValue) {
+			message := fmt.Sprintf("Configuration '%s': %s", entry.Path, issue.Message())
+			azureFindings = append(azureFindings, formatter.Finding{RuleID: issue.RuleID, Message: message, Level: issue.Severity, URI: filePath})
+		}
+	}
+	patternIssues := issues
+	for _, finding := range azureFindings {
+		issues = append(issues, fmt.Sprintf("[Azure] %s: %s", strings.ToUpper(finding.Level), finding.Message))
+	}
+
+	result.WriteString("⚙️ Configured Value Analysis:\n")
+	if len(issues) > 0 {
+		for _, issue := range issues {
+			result.WriteString(fmt.Sprintf("⚠️  %s\n", issue))
+		}
+		result.WriteString("\n🚨 Configuration Security Recommendations:\n")
+		result.WriteString("• Remove credentials from configuration files and use SSIS catalog environments or parameters\n")
+		result.WriteString("• Restrict file system access to configuration files that must hold sensitive values\n")
+		result.WriteString("• Use Windows authentication or managed identity instead of passwords where possible\n")
+	} else {
+		result.WriteString("No credential patterns detected in configured values.\n")
+	}
+
+	if format == formatter.FormatSARIF {
+		sarifFindings := append(credentialFindings(patternIssues, filePath), azureFindings...)
+		return mcp.NewToolResultText(formatter.FormatAsSARIF("scan_credentials", sarifFindings))
+	}
+
+	analysisResult := formatter.CreateAnalysisResult("scan_credentials", filePath, result.String(), nil)
+	if format == formatter.FormatJSON {
+		jsonResult := map[string]interface{}{
+			"tool_name": analysisResult.ToolName,
+			"file_path": analysisResult.FilePath,
+			"package":   filepath.Base(analysisResult.FilePath),
+			"timestamp": analysisResult.Timestamp,
+			"status":    analysisResult.Status,
+			"analysis":  analysisResult.Data,
+			"issues":    issues,
+		}
+		return mcp.NewToolResultStructured(jsonResult, "Credential scan")
+	}
+
+	return mcp.NewToolResultText(formatter.FormatAnalysisResult(analysisResult, format))
+}
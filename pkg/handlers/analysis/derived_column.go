@@ -0,0 +1,47 @@
+package analysis
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/MCPRUNNER/gossisMCP/pkg/expression"
+	"github.com/MCPRUNNER/gossisMCP/pkg/types"
+)
+
+// derivedColumnExpression returns a derived column's expression, preferring the FriendlyExpression, which names
+// columns directly, over the Expression, which refers to them by lineage
+func derivedColumnExpression(column types.OutputColumn) string {
+	expr := ""
+	for _, prop := range column.Properties.Properties {
+		switch prop.Name {
+		case "FriendlyExpression":
+			return strings.TrimSpace(html.UnescapeString(prop.Value))
+		case "Expression":
+			expr = strings.TrimSpace(html.UnescapeString(prop.Value))
+		}
+	}
+	return expr
+}
+
+// derivedColumnFindings parses and lints a derived column expression, and warns when the string cast producing
+// its result is longer than the output column
+func derivedColumnFindings(column types.OutputColumn, text string) []expression.Finding {
+	node, err := expression.Parse(text)
+	if err != nil {
+		return []expression.Finding{{Severity: expression.SeverityError, Message: fmt.Sprintf("expression does not parse: %v", err)}}
+	}
+	findings := expression.Lint(node)
+
+	if cast, ok := node.(*expression.Cast); ok && (cast.Type == "DT_WSTR" || cast.Type == "DT_STR") && len(cast.Params) > 0 {
+		if length, err := strconv.Atoi(cast.Params[0]); err == nil && column.Length > 0 && length > column.Length {
+			findings = append(findings, expression.Finding{
+				Severity: expression.SeverityWarning,
+				Offset:   cast.Pos(),
+				Message:  fmt.Sprintf("result is cast to %s length %d but column %s holds %d characters, so longer values fail or are truncated", cast.Type, length, column.Name, column.Length),
+			})
+		}
+	}
+	return findings
+}
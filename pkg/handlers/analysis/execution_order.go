@@ -0,0 +1,120 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MCPRUNNER/gossisMCP/pkg/types"
+)
+
+// executionStep is a task at an estimated position in its container's execution order; tasks that share a
+// position have no precedence constraints between them and can run in parallel
+type executionStep struct {
+	Position int
+	Name     string
+	// After lists the constraints the task waits on, e.g. "Load on Success"
+	After []string
+	// InCycle is set for tasks whose precedence constraints form a cycle, which have no position
+	InCycle bool
+}
+
+// constraintOutcomeNames maps precedence constraint Value settings to the execution result they require
+var constraintOutcomeNames = map[string]string{
+	"":  "Success",
+	"0": "Success",
+	"1": "Failure",
+	"2": "Completion",
+}
+
+// describeConstraint names the condition a precedence constraint evaluates
+func describeConstraint(constraint types.PrecedenceConstraint) string {
+	outcome, ok := constraintOutcomeNames[constraint.Value]
+	if !ok {
+		outcome = constraint.Value
+	}
+	// EvalOp 1, 3 and 4 are Expression, ExpressionAndConstraint and ExpressionOrConstraint
+	switch constraint.EvalOp {
+	case "1":
+		return "Expression"
+	case "3":
+		return outcome + " and Expression"
+	case "4":
+		return outcome + " or Expression"
+	default:
+		return outcome
+	}
+}
+
+// containerExecutionOrder sorts a container's tasks topologically by its precedence constraints. Each task is
+// placed one position after the latest task it depends on, so independent branches share positions; tasks
+// keep their document order within a position.
+func containerExecutionOrder(tasks []types.Task, constraints []types.PrecedenceConstraint) []executionStep {
+	index := make(map[string]int, len(tasks))
+	for i, task := range tasks {
+		index[task.RefId] = i
+	}
+
+	predecessors := make([][]int, len(tasks))
+	after := make([][]string, len(tasks))
+	for _, constraint := range constraints {
+		from, fromOK := index[constraint.From]
+		to, toOK := index[constraint.To]
+		if !fromOK || !toOK {
+			continue
+		}
+		predecessors[to] = append(predecessors[to], from)
+		after[to] = append(after[to], fmt.Sprintf("%s on %s", tasks[from].Name, describeConstraint(constraint)))
+	}
+
+	positions := make([]int, len(tasks))
+	var steps []executionStep
+	for position, placed := 1, 0; placed < len(tasks); position++ {
+		var ready []int
+		for i := range tasks {
+			if positions[i] != 0 {
+				continue
+			}
+			waiting := false
+			for _, predecessor := range predecessors[i] {
+				if positions[predecessor] == 0 {
+					waiting = true
+					break
+				}
+			}
+			if !waiting {
+				ready = append(ready, i)
+			}
+		}
+		if len(ready) == 0 {
+			break
+		}
+		for _, i := range ready {
+			positions[i] = position
+			steps = append(steps, executionStep{Position: position, Name: tasks[i].Name, After: after[i]})
+		}
+		placed += len(ready)
+	}
+
+	for i, task := range tasks {
+		if positions[i] == 0 {
+			steps = append(steps, executionStep{Name: task.Name, After: after[i], InCycle: true})
+		}
+	}
+	return steps
+}
+
+// formatExecutionOrder renders execution steps as indented lines for the container analysis report
+func formatExecutionOrder(steps []executionStep, indent string) string {
+	var result strings.Builder
+	for _, step := range steps {
+		switch {
+		case step.InCycle:
+			result.WriteString(fmt.Sprintf("%s-. %s (precedence cycle; after %s)\n", indent, step.Name, strings.Join(step.After, ", ")))
+		case len(step.After) == 0:
+			result.WriteString(fmt.Sprintf("%s%d. %s\n", indent, step.Position, step.Name))
+		default:
+			result.WriteString(fmt.Sprintf("%s%d. %s (after %s)\n", indent, step.Position, step.Name, strings.Join(step.After, ", ")))
+		}
+	}
+	return result.String()
+}
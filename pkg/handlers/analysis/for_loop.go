@@ -0,0 +1,296 @@
+package analysis
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/MCPRUNNER/gossisMCP/pkg/expression"
+	"github.com/MCPRUNNER/gossisMCP/pkg/formatter"
+	"github.com/MCPRUNNER/gossisMCP/pkg/types"
+	"github.com/MCPRUNNER/gossisMCP/pkg/util/output"
+)
+
+// forLoopFinding is a problem found in a For Loop container's expressions
+type forLoopFinding struct {
+	Severity string
+	Message  string
+}
+
+// forLoopExpressions returns a For Loop's init, eval and assign expressions from its attributes (SSIS 2012
+// and later) or its Property elements (SSIS 2008), whose values are raw inner XML and still entity-encoded
+func forLoopExpressions(task types.Task) (initExpr, evalExpr, assignExpr string) {
+	initExpr, evalExpr, assignExpr = task.InitExpression, task.EvalExpression, task.AssignExpression
+	for _, prop := range task.Properties {
+		switch {
+		case prop.Name == "InitExpression" && initExpr == "":
+			initExpr = html.UnescapeString(prop.Value)
+		case prop.Name == "EvalExpression" && evalExpr == "":
+			evalExpr = html.UnescapeString(prop.Value)
+		case prop.Name == "AssignExpression" && assignExpr == "":
+			assignExpr = html.UnescapeString(prop.Value)
+		}
+	}
+	return strings.TrimSpace(initExpr), strings.TrimSpace(evalExpr), strings.TrimSpace(assignExpr)
+}
+
+// forLoopAssignments maps the name of each variable assigned by a parsed AssignExpression to its new value
+func forLoopAssignments(node expression.Node) map[string]expression.Node {
+	assignments := make(map[string]expression.Node)
+	for assignment, ok := node.(*expression.Assignment); ok; assignment, ok = assignment.Value.(*expression.Assignment) {
+		assignments[assignment.Variable.Name] = assignment.Value
+	}
+	return assignments
+}
+
+// requiredDirection returns +1 when the loop continues while the variable is below a bound and must increase,
+// -1 when it continues while the variable is above a bound, and 0 when the comparisons do not say. Negated
+// conditions are not followed, which leaves their direction unknown.
+func requiredDirection(evalNode expression.Node, variable string) int {
+	direction := 0
+	conflict := false
+	expression.Walk(evalNode, func(n expression.Node) bool {
+		if unary, ok := n.(*expression.UnaryExpr); ok && unary.Op == "!" {
+			return false
+		}
+		comparison, ok := n.(*expression.BinaryExpr)
+		if !ok {
+			return true
+		}
+		left, right := isForLoopVariable(comparison.Left, variable), isForLoopVariable(comparison.Right, variable)
+		var found int
+		switch {
+		case left && (comparison.Op == "<" || comparison.Op == "<="):
+			found = 1
+		case left && (comparison.Op == ">" || comparison.Op == ">="):
+			found = -1
+		case right && (comparison.Op == ">" || comparison.Op == ">="):
+			found = 1
+		case right && (comparison.Op == "<" || comparison.Op == "<="):
+			found = -1
+		default:
+			return true
+		}
+		// Conflicting comparisons of the same variable leave the direction ambiguous
+		if direction != 0 && direction != found {
+			conflict = true
+		}
+		direction = found
+		return true
+	})
+	if conflict {
+		return 0
+	}
+	return direction
+}
+
+// stepDirection returns +1 or -1 when the assigned value adds a constant to or subtracts one from the variable
+// itself, and 0 for any other value
+func stepDirection(value expression.Node, variable string) int {
+	sum, ok := value.(*expression.BinaryExpr)
+	if !ok || (sum.Op != "+" && sum.Op != "-") {
+		return 0
+	}
+	amount, isConstant := forLoopConstant(sum.Right)
+	switch {
+	case isForLoopVariable(sum.Left, variable) && isConstant:
+	case sum.Op == "+" && isForLoopVariable(sum.Right, variable):
+		amount, isConstant = forLoopConstant(sum.Left)
+		if !isConstant {
+			return 0
+		}
+	default:
+		return 0
+	}
+	if sum.Op == "-" {
+		amount = -amount
+	}
+	switch {
+	case amount > 0:
+		return 1
+	case amount < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// isForLoopVariable reports whether node references the variable, ignoring its namespace
+func isForLoopVariable(node expression.Node, variable string) bool {
+	ref, ok := node.(*expression.VariableRef)
+	return ok && ref.Name == variable
+}
+
+// forLoopConstant returns the value of a numeric literal, allowing a leading minus and a type suffix such as 1L
+func forLoopConstant(node expression.Node) (float64, bool) {
+	switch n := node.(type) {
+	case *expression.NumberLiteral:
+		value, err := strconv.ParseFloat(strings.TrimRightFunc(n.Text, unicode.IsLetter), 64)
+		return value, err == nil
+	case *expression.UnaryExpr:
+		if n.Op == "-" {
+			value, ok := forLoopConstant(n.Operand)
+			return -value, ok
+		}
+	}
+	return 0, false
+}
+
+// checkForLoopTermination verifies that a For Loop's AssignExpression changes a variable tested by its
+// EvalExpression in the direction that ends the loop, and lints both expressions
+func checkForLoopTermination(name, evalExpr, assignExpr string) []forLoopFinding {
+	evalNode, err := expression.Parse(evalExpr)
+	if err != nil {
+		return []forLoopFinding{{Severity: expression.SeverityError, Message: fmt.Sprintf("For Loop '%s' EvalExpression '%s' does not parse: %v", name, evalExpr, err)}}
+	}
+	var findings []forLoopFinding
+	for _, finding := range expression.Lint(evalNode) {
+		findings = append(findings, forLoopFinding{Severity: finding.Severity, Message: fmt.Sprintf("For Loop '%s' EvalExpression: %s", name, finding.Message)})
+	}
+
+	var tested []string
+	seen := make(map[string]bool)
+	for _, ref := range expression.Variables(evalNode) {
+		if !seen[ref.Name] {
+			seen[ref.Name] = true
+			tested = append(tested, ref.Name)
+		}
+	}
+	if len(tested) == 0 {
+		return append(findings, forLoopFinding{Severity: expression.SeverityError, Message: fmt.Sprintf("For Loop '%s' EvalExpression '%s' does not test a variable, so its result never changes", name, evalExpr)})
+	}
+
+	assignments := map[string]expression.Node{}
+	if assignExpr != "" {
+		assignNode, err := expression.Parse(assignExpr)
+		if err != nil {
+			return append(findings, forLoopFinding{Severity: expression.SeverityError, Message: fmt.Sprintf("For Loop '%s' AssignExpression '%s' does not parse: %v", name, assignExpr, err)})
+		}
+		for _, finding := range expression.Lint(assignNode) {
+			findings = append(findings, forLoopFinding{Severity: finding.Severity, Message: fmt.Sprintf("For Loop '%s' AssignExpression: %s", name, finding.Message)})
+		}
+		assignments = forLoopAssignments(assignNode)
+	}
+
+	matched := false
+	for _, variable := range tested {
+		value, ok := assignments[variable]
+		if !ok {
+			continue
+		}
+		matched = true
+		needed, step := requiredDirection(evalNode, variable), stepDirection(value, variable)
+		switch {
+		case needed == 0 || step == 0:
+			findings = append(findings, forLoopFinding{Severity: expression.SeverityWarning, Message: fmt.Sprintf("For Loop '%s' assigns %s = %s; cannot tell whether it moves toward ending EvalExpression '%s'", name, variable, value, evalExpr)})
+		case needed != step:
+			findings = append(findings, forLoopFinding{Severity: expression.SeverityError, Message: fmt.Sprintf("For Loop '%s' assigns %s = %s, which moves away from ending EvalExpression '%s'", name, variable, value, evalExpr)})
+		}
+	}
+	if !matched {
+		findings = append(findings, forLoopFinding{Severity: expression.SeverityError, Message: fmt.Sprintf("For Loop '%s' AssignExpression does not modify %s tested in EvalExpression '%s'; the loop only ends if a task inside it changes the variable", name, strings.Join(tested, ", "), evalExpr)})
+	}
+	return findings
+}
+
+// forLoopContainer is a For Loop container and its path from the package
+type forLoopContainer struct {
+	Path string
+	Task types.Task
+}
+
+// collectForLoops returns the For Loop containers in tasks and in the containers nested inside them
+func collectForLoops(tasks []types.Task, parent string) []forLoopContainer {
+	var loops []forLoopContainer
+	for _, task := range tasks {
+		path := parent + `\` + task.Name
+		if task.CreationName == "Microsoft.ForLoop" {
+			loops = append(loops, forLoopContainer{Path: path, Task: task})
+		}
+		if task.Executables != nil {
+			loops = append(loops, collectForLoops(task.Executables.Tasks, path)...)
+		}
+	}
+	return loops
+}
+
+// HandleAnalyzeForLoopContainer checks every For Loop container, including nested ones, for an AssignExpression
+// that cannot end the loop
+func HandleAnalyzeForLoopContainer(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
+	filePath, err := request.RequireString("file_path")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	format := formatter.OutputFormat(request.GetString("format", "text"))
+	outputPath := request.GetString("output_file_path", "")
+
+	data, err := os.ReadFile(ResolveFilePath(filePath, packageDirectory))
+	if err != nil {
+		return forLoopResult(filePath, nil, err, format, outputPath, packageDirectory)
+	}
+
+	data = []byte(strings.ReplaceAll(string(data), "DTS:", ""))
+	data = []byte(strings.ReplaceAll(string(data), `xmlns="www.microsoft.com/SqlServer/Dts"`, ""))
+
+	var pkg types.SSISPackage
+	if err := xml.Unmarshal(data, &pkg); err != nil {
+		return forLoopResult(filePath, nil, err, format, outputPath, packageDirectory)
+	}
+
+	loops := collectForLoops(pkg.Executables.Tasks, "Package")
+
+	var result strings.Builder
+	result.WriteString("For Loop Container Analysis:\n\n")
+	if len(loops) == 0 {
+		result.WriteString("No For Loop containers found in this package.\n")
+		return forLoopResult(filePath, result.String(), nil, format, outputPath, packageDirectory)
+	}
+
+	errorCount, warningCount := 0, 0
+	for i, loop := range loops {
+		task := loop.Task
+		initExpr, evalExpr, assignExpr := forLoopExpressions(task)
+		result.WriteString(fmt.Sprintf("For Loop %d: %s\n", i+1, task.Name))
+		result.WriteString(fmt.Sprintf("  Path: %s\n", loop.Path))
+		result.WriteString(fmt.Sprintf("  Init Expression: %s\n", initExpr))
+		result.WriteString(fmt.Sprintf("  Eval Expression: %s\n", evalExpr))
+		result.WriteString(fmt.Sprintf("  Assign Expression: %s\n", assignExpr))
+		findings := checkForLoopTermination(task.Name, evalExpr, assignExpr)
+		if len(findings) == 0 {
+			result.WriteString("  Loop Termination: OK\n\n")
+			continue
+		}
+		result.WriteString("  Loop Termination:\n")
+		for _, finding := range findings {
+			if finding.Severity == expression.SeverityError {
+				errorCount++
+			} else {
+				warningCount++
+			}
+			result.WriteString(fmt.Sprintf("    - %s: %s\n", strings.ToUpper(finding.Severity), finding.Message))
+		}
+		result.WriteString("\n")
+	}
+	result.WriteString(fmt.Sprintf("Total For Loop containers: %d (errors: %d, warnings: %d)\n", len(loops), errorCount, warningCount))
+
+	return forLoopResult(filePath, result.String(), nil, format, outputPath, packageDirectory)
+}
+
+// forLoopResult formats the For Loop analysis and writes it to outputPath when one is given
+func forLoopResult(filePath string, data interface{}, analysisErr error, format formatter.OutputFormat, outputPath, packageDirectory string) (*mcp.CallToolResult, error) {
+	report := formatter.FormatAnalysisResult(formatter.CreateAnalysisResult("For Loop Container Analysis", filePath, data, analysisErr), format)
+	if outputPath != "" {
+		if err := output.WriteOutput(ResolveFilePath(outputPath, packageDirectory), report); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	return mcp.NewToolResultText(report), nil
+}
@@ -0,0 +1,152 @@
+//go:build integration
+
+package analysis
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type toolHandler func(context.Context, mcp.CallToolRequest, string) (*mcp.CallToolResult, error)
+
+// runFixture calls a handler against a package in testdata and returns its text output
+func runFixture(t *testing.T, handler toolHandler, fixture string) string {
+	t.Helper()
+	dir, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatalf("failed to resolve testdata directory: %v", err)
+	}
+	request := createRequest(map[string]interface{}{
+		"file_path": fixture,
+		"format":    "text",
+	})
+	result, err := handler(context.Background(), request, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result == nil || len(result.Content) == 0 {
+		t.Fatal("expected analysis result content")
+	}
+	textContent, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[0])
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error result %q", textContent.Text)
+	}
+	return textContent.Text
+}
+
+// assertContainsAll fails the test for every expected string missing from the output
+func assertContainsAll(t *testing.T, output string, expected ...string) {
+	t.Helper()
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestIntegrationDataFlow(t *testing.T) {
+	output := runFixture(t, HandleAnalyzeDataFlow, "DataFlow.dtsx")
+	assertContainsAll(t, output,
+		"Customer Source (Source)",
+		"Customer Destination (Destination)",
+		"SqlCommand: SELECT CustomerID, FirstName, LastName FROM Staging.Customer",
+		"OLE DB Source Output: Customer Source.Outputs[OLE DB Source Output] → Build Full Name.Inputs[Derived Column Input]",
+	)
+}
+
+func TestIntegrationDataFlowDetailed(t *testing.T) {
+	output := runFixture(t, HandleAnalyzeDataFlowDetailed, "DataFlow.dtsx")
+	assertContainsAll(t, output,
+		"Component: Build Full Name",
+		"OpenRowset: [dbo].[DimCustomer]",
+		"FastLoadMaxInsertCommitSize: 10000",
+		"CustomerID (i4)",
+		"FullName (wstr)",
+	)
+}
+
+func TestIntegrationDerivedColumn(t *testing.T) {
+	output := runFixture(t, HandleAnalyzeDerivedColumn, "DataFlow.dtsx")
+	assertContainsAll(t, output,
+		"Component: Build Full Name",
+		"Input: FirstName (wstr, length=50)",
+		"Input: LastName (wstr, length=50)",
+		"Output: FullName (wstr, length=101)",
+		`    Expression: [FirstName] + " " + [LastName]`,
+		"Output: LastNameKey (wstr, length=20)\n    Expression: (DT_WSTR, 50) UPPER(LastName)\n    - WARNING: result is cast to DT_WSTR length 50 but column LastNameKey holds 20 characters",
+		"    - WARNING: [FirstName] == NULL(DT_WSTR, 50) compares with NULL",
+	)
+}
+
+func TestIntegrationContainers(t *testing.T) {
+	output := runFixture(t, HandleAnalyzeContainers, "Containers.dtsx")
+	assertContainsAll(t, output,
+		"Prepare Staging (Sequence Container)",
+		"Process Batches (For Loop Container)",
+		"Archive Files (Foreach Loop Container)",
+		"Description: Moves each processed file to the archive folder",
+		"Disabled: True",
+		"Enumerator: Foreach File Enumerator",
+		`Directory: D:\Processed`,
+		"Filter: *.csv",
+		"FileNameRetrievalType: Fully qualified (0)",
+		"Fail Package On Failure: True",
+		"Total containers found: 3",
+		"Eval Expression: @[User::BatchNumber] < 10",
+		"Assign Expression: @[User::BatchNumber] = @[User::BatchNumber] + 1",
+		"Loop Termination: OK",
+		// Linear: the constraints run the tasks in a different order than they are listed
+		"  Execution Order:\n    1. Truncate Customer\n    2. Truncate Orders (after Truncate Customer on Success)\n    3. Load Staging (after Truncate Orders on Success)\n",
+		// Branching: Log Move and Alert Operator both wait only on Move File
+		"    1. Move File\n    2. Log Move (after Move File on Success)\n    2. Alert Operator (after Move File on Failure)\n    3. Clean Up (after Log Move on Completion)\n",
+	)
+}
+
+func TestIntegrationForLoopContainer(t *testing.T) {
+	output := runFixture(t, HandleAnalyzeForLoopContainer, "Containers.dtsx")
+	assertContainsAll(t, output,
+		"For Loop 1: Process Batches",
+		`Path: Package\Process Batches`,
+		"Eval Expression: @[User::BatchNumber] < 10",
+		"Loop Termination: OK",
+		"Total For Loop containers: 1 (errors: 0, warnings: 0)",
+	)
+}
+
+func TestIntegrationScriptTask(t *testing.T) {
+	output := runFixture(t, HandleAnalyzeCodeQuality, "ScriptTask.dtsx")
+	assertContainsAll(t, output,
+		"Package: ScriptTask.dtsx",
+		"Script Tasks: 1",
+		"Total Variables: 1",
+	)
+
+	output = runFixture(t, HandleScanCredentials, "ScriptTask.dtsx")
+	assertContainsAll(t, output,
+		"Script Task 'Refresh Prices' contains API Keys & Tokens pattern in code",
+	)
+}
+
+func TestIntegrationCustomComponents(t *testing.T) {
+	output := runFixture(t, HandleAnalyzeCustomComponents, "CustomComponents.dtsx")
+	assertContainsAll(t, output,
+		"CRM Accounts",
+		"Vendor: KingswaySoft",
+		"Class ID: KingswaySoft.IntegrationToolkit.DynamicsCrm.CrmSource",
+		"FileName: accounts.fetchxml",
+		"Upsert Accounts",
+		"Vendor: CozyRoc",
+		"TableOrViewName: [dbo].[Account]",
+		"Total custom/third-party components found: 2",
+	)
+	if strings.Contains(output, "Count Rows") {
+		t.Errorf("expected the standard Row Count component to be skipped, got:\n%s", output)
+	}
+}
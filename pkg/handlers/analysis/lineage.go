@@ -0,0 +1,285 @@
+package analysis
+
+import (
+	"encoding/xml"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ColumnLineage links a source column to the destination column it ultimately populates
+type ColumnLineage struct {
+	SourceColumn         string   `json:"source_column"`
+	SourceComponent      string   `json:"source_component"`
+	DestinationColumn    string   `json:"destination_column"`
+	DestinationComponent string   `json:"destination_component"`
+	Transformations      []string `json:"transformations"`
+}
+
+// DataFlowLineage is the column lineage of a package, emitted as "lineage" by analyze_data_flow_detailed
+// with format=json. Its schema is Documents/schemas/data_flow_lineage.schema.json.
+type DataFlowLineage struct {
+	Package      string          `json:"package"`
+	Sources      []string        `json:"sources"`
+	Destinations []string        `json:"destinations"`
+	ColumnCount  int             `json:"column_count"`
+	Columns      []ColumnLineage `json:"columns"`
+}
+
+// newDataFlowLineage summarizes column lineage entries, listing each source and destination component once
+func newDataFlowLineage(packageName string, lineages []ColumnLineage) DataFlowLineage {
+	result := DataFlowLineage{
+		Package:      packageName,
+		Sources:      []string{},
+		Destinations: []string{},
+		ColumnCount:  len(lineages),
+		Columns:      lineages,
+	}
+	for _, lineage := range lineages {
+		if !slices.Contains(result.Sources, lineage.SourceComponent) {
+			result.Sources = append(result.Sources, lineage.SourceComponent)
+		}
+		if !slices.Contains(result.Destinations, lineage.DestinationComponent) {
+			result.Destinations = append(result.Destinations, lineage.DestinationComponent)
+		}
+	}
+	return result
+}
+
+type lineageProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+type lineageColumn struct {
+	RefID                    string            `xml:"refId,attr"`
+	Name                     string            `xml:"name,attr"`
+	CachedName               string            `xml:"cachedName,attr"`
+	LineageID                string            `xml:"lineageId,attr"`
+	ExternalMetadataColumnID string            `xml:"externalMetadataColumnId,attr"`
+	Properties               []lineageProperty `xml:"properties>property"`
+}
+
+// lineage returns the id other components use to reference the column
+func (c lineageColumn) lineage() string {
+	if c.LineageID != "" {
+		return c.LineageID
+	}
+	return c.RefID
+}
+
+type lineageExternalColumn struct {
+	RefID string `xml:"refId,attr"`
+	Name  string `xml:"name,attr"`
+}
+
+type lineageInput struct {
+	RefID           string                  `xml:"refId,attr"`
+	Columns         []lineageColumn         `xml:"inputColumns>inputColumn"`
+	ExternalColumns []lineageExternalColumn `xml:"externalMetadata>externalMetadataColumn"`
+}
+
+type lineageOutput struct {
+	RefID              string          `xml:"refId,attr"`
+	IsErrorOut         bool            `xml:"isErrorOut,attr"`
+	SynchronousInputID string          `xml:"synchronousInputId,attr"`
+	Columns            []lineageColumn `xml:"outputColumns>outputColumn"`
+}
+
+type lineageComponent struct {
+	RefID   string          `xml:"refId,attr"`
+	Name    string          `xml:"name,attr"`
+	Inputs  []lineageInput  `xml:"inputs>input"`
+	Outputs []lineageOutput `xml:"outputs>output"`
+}
+
+// dataOutputs returns the component's outputs excluding error outputs
+func (c *lineageComponent) dataOutputs() []lineageOutput {
+	var outputs []lineageOutput
+	for _, output := range c.Outputs {
+		if !output.IsErrorOut {
+			outputs = append(outputs, output)
+		}
+	}
+	return outputs
+}
+
+type lineagePath struct {
+	StartID string `xml:"startId,attr"`
+	EndID   string `xml:"endId,attr"`
+}
+
+type lineagePipeline struct {
+	Components []lineageComponent `xml:"components>component"`
+	Paths      []lineagePath      `xml:"paths>path"`
+}
+
+// lineageReferencePattern matches the #{refId} references SSIS stores in expressions and column properties
+var lineageReferencePattern = regexp.MustCompile(`#\{([^}]+)\}`)
+
+// lineageGraph indexes a data flow by the ids used in data paths and column properties
+type lineageGraph struct {
+	inputOwners map[string]*lineageComponent
+	inputs      map[string]lineageInput
+	pathsFrom   map[string][]string
+	// derivedFrom maps an output column lineage id to the lineage ids of the columns it is computed from
+	derivedFrom map[string][]string
+}
+
+func newLineageGraph(pipeline *lineagePipeline) *lineageGraph {
+	graph := &lineageGraph{
+		inputOwners: make(map[string]*lineageComponent),
+		inputs:      make(map[string]lineageInput),
+		pathsFrom:   make(map[string][]string),
+		derivedFrom: make(map[string][]string),
+	}
+	for _, path := range pipeline.Paths {
+		graph.pathsFrom[path.StartID] = append(graph.pathsFrom[path.StartID], path.EndID)
+	}
+
+	for i := range pipeline.Components {
+		component := &pipeline.Components[i]
+		inputColumnLineage := make(map[string]string)
+		for _, input := range component.Inputs {
+			graph.inputOwners[input.RefID] = component
+			graph.inputs[input.RefID] = input
+			for _, column := range input.Columns {
+				inputColumnLineage[column.RefID] = column.lineage()
+			}
+		}
+		outputColumns := make(map[string]bool)
+		for _, output := range component.Outputs {
+			for _, column := range output.Columns {
+				outputColumns[column.lineage()] = true
+				// Derived Column expressions, Sort, Aggregate and Merge Join reference the input columns they read
+				for _, ref := range lineageReferences(column.Properties) {
+					if lineage, ok := inputColumnLineage[ref]; ok {
+						ref = lineage
+					}
+					graph.derivedFrom[column.lineage()] = append(graph.derivedFrom[column.lineage()], ref)
+				}
+			}
+		}
+		// Union All input columns reference the output column they feed
+		for _, input := range component.Inputs {
+			for _, column := range input.Columns {
+				for _, ref := range lineageReferences(column.Properties) {
+					if outputColumns[ref] {
+						graph.derivedFrom[ref] = append(graph.derivedFrom[ref], column.lineage())
+					}
+				}
+			}
+		}
+	}
+	return graph
+}
+
+// lineageReferences returns the ids referenced by #{...} in property values
+func lineageReferences(properties []lineageProperty) []string {
+	var refs []string
+	for _, prop := range properties {
+		for _, match := range lineageReferencePattern.FindAllStringSubmatch(prop.Value, -1) {
+			refs = append(refs, match[1])
+		}
+	}
+	return refs
+}
+
+// destinationColumnName returns the external column an input column is mapped to, falling back to its cached name
+func destinationColumnName(input lineageInput, column lineageColumn) string {
+	for _, external := range input.ExternalColumns {
+		if external.RefID == column.ExternalMetadataColumnID {
+			return external.Name
+		}
+	}
+	if column.CachedName != "" {
+		return column.CachedName
+	}
+	return column.Name
+}
+
+// trace follows the columns in tracked from an output along the data paths, appending a lineage
+// entry for each destination input column that reads one of them
+func (g *lineageGraph) trace(source lineageColumn, sourceComponent, outputRefID string, tracked map[string]bool, transformations []string, lineages *[]ColumnLineage) {
+	for _, inputRefID := range g.pathsFrom[outputRefID] {
+		component, ok := g.inputOwners[inputRefID]
+		if !ok || slices.Contains(transformations, component.Name) {
+			continue
+		}
+		input := g.inputs[inputRefID]
+		outputs := component.dataOutputs()
+
+		if len(outputs) == 0 {
+			for _, column := range input.Columns {
+				if tracked[column.lineage()] {
+					*lineages = append(*lineages, ColumnLineage{
+						SourceColumn:         source.Name,
+						SourceComponent:      sourceComponent,
+						DestinationColumn:    destinationColumnName(input, column),
+						DestinationComponent: component.Name,
+						Transformations:      append([]string{}, transformations...),
+					})
+				}
+			}
+			continue
+		}
+
+		path := append(append([]string{}, transformations...), component.Name)
+		for _, output := range outputs {
+			next := make(map[string]bool)
+			// Synchronous outputs pass every buffer column through; asynchronous outputs start a new buffer
+			if output.SynchronousInputID == inputRefID {
+				for lineage := range tracked {
+					next[lineage] = true
+				}
+			}
+			for _, column := range output.Columns {
+				for _, ref := range g.derivedFrom[column.lineage()] {
+					if tracked[ref] {
+						next[column.lineage()] = true
+						break
+					}
+				}
+			}
+			if len(next) > 0 {
+				g.trace(source, sourceComponent, output.RefID, next, path, lineages)
+			}
+		}
+	}
+}
+
+// extractColumnLineage traces every source output column through the data paths of each data flow to
+// the destination columns it populates
+func extractColumnLineage(xmlContent string) []ColumnLineage {
+	lineages := []ColumnLineage{}
+	decoder := xml.NewDecoder(strings.NewReader(xmlContent))
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "pipeline" {
+			continue
+		}
+		var pipeline lineagePipeline
+		if err := decoder.DecodeElement(&pipeline, &start); err != nil {
+			break
+		}
+
+		graph := newLineageGraph(&pipeline)
+		for _, component := range pipeline.Components {
+			if len(component.Inputs) > 0 {
+				continue
+			}
+			for _, output := range component.dataOutputs() {
+				for _, column := range output.Columns {
+					tracked := map[string]bool{column.lineage(): true}
+					graph.trace(column, component.Name, output.RefID, tracked, []string{}, &lineages)
+				}
+			}
+		}
+	}
+	return lineages
+}
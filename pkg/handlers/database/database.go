@@ -0,0 +1,224 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	_ "github.com/microsoft/go-mssqldb"
+
+	"github.com/MCPRUNNER/gossisMCP/pkg/formatter"
+	"github.com/MCPRUNNER/gossisMCP/pkg/types"
+	"github.com/MCPRUNNER/gossisMCP/pkg/util/file"
+	"github.com/MCPRUNNER/gossisMCP/pkg/util/output"
+)
+
+// Options controls whether SQL execution tools may connect to databases
+type Options struct {
+	// Enabled permits run_sql_query to execute queries
+	Enabled bool
+}
+
+// oleDBOnlyKeys are connection string keys used by SSIS OLE DB providers that database/sql drivers reject or misread
+var oleDBOnlyKeys = map[string]bool{
+	"provider":       true,
+	"auto translate": true,
+}
+
+// HandleRunSQLQuery executes a query against a connection string or a DTSX connection manager and returns the rows
+func HandleRunSQLQuery(ctx context.Context, request mcp.CallToolRequest, packageDirectory string, options Options) (*mcp.CallToolResult, error) {
+	if !options.Enabled {
+		return mcp.NewToolResultError("run_sql_query is disabled; set server.enable_sql_execution to enable it"), nil
+	}
+
+	query, err := request.RequireString("query")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Get format parameter (default to "text")
+	formatStr := request.GetString("format", "text")
+	format := formatter.OutputFormat(formatStr)
+
+	driver := request.GetString("driver", "sqlserver")
+	if !slices.Contains(sql.Drivers(), driver) {
+		return mcp.NewToolResultError(fmt.Sprintf("unsupported driver: %s (available: %s)", driver, strings.Join(sql.Drivers(), ", "))), nil
+	}
+
+	filePath := request.GetString("file_path", "")
+	source := request.GetString("connection_string", "")
+	connectionName := request.GetString("connection_name", "")
+	switch {
+	case source != "" && connectionName != "":
+		return mcp.NewToolResultError("provide either connection_string or connection_name, not both"), nil
+	case connectionName != "":
+		if filePath == "" {
+			return mcp.NewToolResultError("file_path is required when connection_name is used"), nil
+		}
+		source, err = lookupConnectionString(file.ResolveFilePath(filePath, packageDirectory), connectionName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	case source == "":
+		return mcp.NewToolResultError("connection_string or connection_name is required"), nil
+	}
+	if driver == "sqlserver" {
+		source = normalizeConnectionString(source)
+	}
+
+	maxRows := request.GetInt("max_rows", 100)
+	if maxRows < 1 {
+		return mcp.NewToolResultError("max_rows must be at least 1"), nil
+	}
+	timeoutSeconds := request.GetInt("timeout_seconds", 30)
+	if timeoutSeconds < 1 {
+		return mcp.NewToolResultError("timeout_seconds must be at least 1"), nil
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, time.Duration(timeoutSeconds)*time.Second)
+	defer cancel()
+
+	table, truncated, err := runQuery(queryCtx, driver, source, query, maxRows)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Query failed: %v", err)), nil
+	}
+
+	analysisResult := formatter.CreateAnalysisResult("run_sql_query", filePath, table, nil)
+	report := formatter.FormatAnalysisResult(analysisResult, format)
+	if truncated && (format == formatter.FormatText || format == formatter.FormatMarkdown) {
+		report += fmt.Sprintf("\nResults truncated to the first %d rows (max_rows)\n", maxRows)
+	}
+
+	if outputPath := request.GetString("output_file_path", ""); outputPath != "" {
+		if err := output.WriteOutput(file.ResolveFilePath(outputPath, packageDirectory), report); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	if format == formatter.FormatJSON {
+		jsonResult := map[string]interface{}{
+			"tool_name": analysisResult.ToolName,
+			"file_path": analysisResult.FilePath,
+			"package":   filepath.Base(analysisResult.FilePath),
+			"timestamp": time.Now().Format(time.RFC3339),
+			"status":    analysisResult.Status,
+			"analysis": map[string]interface{}{
+				"columns":   table.Headers,
+				"rows":      table.Rows,
+				"row_count": len(table.Rows),
+				"truncated": truncated,
+			},
+		}
+		return mcp.NewToolResultStructured(jsonResult, "SQL query results"), nil
+	}
+
+	return mcp.NewToolResultText(report), nil
+}
+
+// runQuery executes the query and collects at most maxRows rows as strings
+func runQuery(ctx context.Context, driver, source, query string, maxRows int) (*formatter.TableData, bool, error) {
+	db, err := sql.Open(driver, source)
+	if err != nil {
+		return nil, false, err
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, false, err
+	}
+
+	table := &formatter.TableData{Headers: columns}
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	truncated := false
+	for rows.Next() {
+		if len(table.Rows) == maxRows {
+			truncated = true
+			break
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, false, err
+		}
+		row := make([]string, len(columns))
+		for i, value := range values {
+			row[i] = formatValue(value)
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+	return table, truncated, nil
+}
+
+// formatValue renders a scanned column value as text
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// lookupConnectionString returns the connection string of the named connection manager in a DTSX file
+func lookupConnectionString(filePath, connectionName string) (string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %v", err)
+	}
+
+	// Remove namespace prefixes for easier parsing
+	data = []byte(strings.ReplaceAll(string(data), "DTS:", ""))
+
+	var pkg types.SSISPackage
+	if err := xml.Unmarshal(data, &pkg); err != nil {
+		return "", fmt.Errorf("failed to parse XML: %v", err)
+	}
+
+	for _, conn := range pkg.ConnectionMgr.Connections {
+		if strings.EqualFold(conn.Name, connectionName) {
+			if conn.ObjectData.ConnectionMgr.ConnectionString == "" {
+				return "", fmt.Errorf("connection manager %s has no connection string", connectionName)
+			}
+			return conn.ObjectData.ConnectionMgr.ConnectionString, nil
+		}
+	}
+	return "", fmt.Errorf("connection manager %s not found", connectionName)
+}
+
+// normalizeConnectionString removes OLE DB provider keys from an SSIS connection string
+func normalizeConnectionString(connectionString string) string {
+	var parts []string
+	for _, part := range strings.Split(connectionString, ";") {
+		key, _, _ := strings.Cut(part, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" || oleDBOnlyKeys[key] {
+			continue
+		}
+		parts = append(parts, strings.TrimSpace(part))
+	}
+	return strings.Join(parts, ";")
+}
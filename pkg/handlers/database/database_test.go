@@ -0,0 +1,135 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	_ "modernc.org/sqlite"
+)
+
+func createRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: args,
+		},
+	}
+}
+
+func createTestDatabase(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	statements := []string{
+		"CREATE TABLE Customers (Id INTEGER, Name TEXT)",
+		"INSERT INTO Customers VALUES (1, 'Contoso'), (2, 'Fabrikam'), (3, NULL)",
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("failed to prepare database: %v", err)
+		}
+	}
+	return path
+}
+
+func TestHandleRunSQLQueryDisabled(t *testing.T) {
+	result, err := HandleRunSQLQuery(context.Background(), createRequest(map[string]interface{}{
+		"connection_string": "ignored",
+		"query":             "SELECT 1",
+	}), "", Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected run_sql_query to be disabled by default")
+	}
+}
+
+func TestHandleRunSQLQuery(t *testing.T) {
+	dbPath := createTestDatabase(t)
+
+	result, err := HandleRunSQLQuery(context.Background(), createRequest(map[string]interface{}{
+		"driver":            "sqlite",
+		"connection_string": dbPath,
+		"query":             "SELECT Id, Name FROM Customers ORDER BY Id",
+		"max_rows":          2,
+		"format":            "json",
+	}), "", Options{Enabled: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error result: %+v", result.Content)
+	}
+
+	payload := result.StructuredContent.(map[string]interface{})
+	analysis := payload["analysis"].(map[string]interface{})
+	if analysis["row_count"] != 2 || analysis["truncated"] != true {
+		t.Fatalf("expected 2 truncated rows, got %+v", analysis)
+	}
+	rows := analysis["rows"].([][]string)
+	if rows[0][1] != "Contoso" || rows[1][1] != "Fabrikam" {
+		t.Fatalf("unexpected rows: %+v", rows)
+	}
+
+	text, err := HandleRunSQLQuery(context.Background(), createRequest(map[string]interface{}{
+		"driver":            "sqlite",
+		"connection_string": dbPath,
+		"query":             "SELECT Name FROM Customers WHERE Id = 3",
+	}), "", Options{Enabled: true})
+	if err != nil || text.IsError {
+		t.Fatalf("unexpected failure: %v %+v", err, text)
+	}
+	if output := text.Content[0].(mcp.TextContent).Text; !strings.Contains(output, "NULL") {
+		t.Fatalf("expected NULL value in output, got %q", output)
+	}
+}
+
+func TestHandleRunSQLQueryConnectionName(t *testing.T) {
+	dbPath := createTestDatabase(t)
+	dir := t.TempDir()
+	dtsx := `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:ObjectName="Package">
+  <DTS:ConnectionManagers>
+    <DTS:ConnectionManager DTS:ObjectName="Warehouse">
+      <DTS:ObjectData>
+        <DTS:ConnectionManager DTS:ConnectionString="` + dbPath + `" />
+      </DTS:ObjectData>
+    </DTS:ConnectionManager>
+  </DTS:ConnectionManagers>
+</DTS:Executable>`
+	if err := os.WriteFile(filepath.Join(dir, "Package.dtsx"), []byte(dtsx), 0o644); err != nil {
+		t.Fatalf("failed to write package: %v", err)
+	}
+
+	result, err := HandleRunSQLQuery(context.Background(), createRequest(map[string]interface{}{
+		"driver":          "sqlite",
+		"file_path":       "Package.dtsx",
+		"connection_name": "Warehouse",
+		"query":           "SELECT COUNT(*) AS Total FROM Customers",
+		"format":          "csv",
+	}), dir, Options{Enabled: true})
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected failure: %v %+v", err, result)
+	}
+	if output := result.Content[0].(mcp.TextContent).Text; !strings.Contains(output, "Total") || !strings.Contains(output, "3") {
+		t.Fatalf("expected row count in CSV output, got %q", output)
+	}
+}
+
+func TestNormalizeConnectionString(t *testing.T) {
+	got := normalizeConnectionString("Data Source=sql01;Initial Catalog=Sales;Provider=SQLNCLI11.1;Integrated Security=SSPI;Auto Translate=False;")
+	expected := "Data Source=sql01;Initial Catalog=Sales;Integrated Security=SSPI"
+	if got != expected {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
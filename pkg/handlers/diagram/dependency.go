@@ -0,0 +1,211 @@
+package diagram
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	packagehandlers "github.com/MCPRUNNER/gossisMCP/pkg/handlers/packages"
+	"github.com/MCPRUNNER/gossisMCP/pkg/types"
+	"github.com/MCPRUNNER/gossisMCP/pkg/util/file"
+	"github.com/MCPRUNNER/gossisMCP/pkg/util/output"
+)
+
+// dependencyEdge links two packages through a shared connection or an Execute Package Task
+type dependencyEdge struct {
+	From   string
+	To     string
+	Label  string
+	Shared bool
+}
+
+// dependencyGraph is the package-level dependency graph of a directory
+type dependencyGraph struct {
+	Packages []string
+	Edges    []dependencyEdge
+}
+
+// buildDependencyGraph scans the DTSX files in a directory, skipping those matched by the exclude file, for shared
+// connections and child package references
+func buildDependencyGraph(ctx context.Context, directory, excludeFile string) (dependencyGraph, error) {
+	var graph dependencyGraph
+	connectionUsers := make(map[string][]string)
+	known := make(map[string]bool)
+
+	files, err := packagehandlers.ListPackages(ctx, directory, excludeFile)
+	if err != nil {
+		return graph, err
+	}
+	for _, path := range files {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return graph, ctxErr
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(directory, path)
+		}
+
+		pkg, err := loadPackage(path)
+		if err != nil {
+			continue // Skip files that can't be parsed
+		}
+
+		packageName := filepath.Base(path)
+		graph.Packages = append(graph.Packages, packageName)
+		known[packageName] = true
+
+		// Record each package once per connection, so a connection listed twice adds no self-edge or duplicate edge
+		for _, conn := range pkg.ConnectionMgr.Connections {
+			if !slices.Contains(connectionUsers[conn.Name], packageName) {
+				connectionUsers[conn.Name] = append(connectionUsers[conn.Name], packageName)
+			}
+		}
+		for _, ref := range childPackageReferences(pkg, pkg.Executables.Tasks) {
+			graph.Edges = append(graph.Edges, dependencyEdge{From: packageName, To: ref.Package, Label: ref.Task})
+		}
+	}
+
+	// Child packages outside the scanned directory still appear as nodes
+	for _, edge := range graph.Edges {
+		if !known[edge.To] {
+			known[edge.To] = true
+			graph.Packages = append(graph.Packages, edge.To)
+		}
+	}
+
+	connectionNames := make([]string, 0, len(connectionUsers))
+	for name := range connectionUsers {
+		connectionNames = append(connectionNames, name)
+	}
+	sort.Strings(connectionNames)
+	for _, name := range connectionNames {
+		users := connectionUsers[name]
+		for i := 0; i < len(users); i++ {
+			for j := i + 1; j < len(users); j++ {
+				graph.Edges = append(graph.Edges, dependencyEdge{From: users[i], To: users[j], Label: name, Shared: true})
+			}
+		}
+	}
+
+	sort.Strings(graph.Packages)
+	return graph, nil
+}
+
+// childPackageReference is a child package run by an Execute Package Task
+type childPackageReference struct {
+	Task    string
+	Package string
+}
+
+// childPackageReferences finds Execute Package Tasks, including those nested in containers
+func childPackageReferences(pkg *types.SSISPackage, tasks []types.Task) []childPackageReference {
+	var refs []childPackageReference
+	for _, task := range tasks {
+		details := task.ObjectData.ExecutePackageTask
+		child := strings.TrimSpace(details.PackageName)
+		if child == "" && details.Connection != "" {
+			for _, conn := range pkg.ConnectionMgr.Connections {
+				if conn.Name == details.Connection || conn.DTSID == details.Connection {
+					child = strings.TrimSpace(conn.ObjectData.ConnectionMgr.ConnectionString)
+				}
+			}
+		}
+		if child != "" {
+			refs = append(refs, childPackageReference{Task: task.Name, Package: filepath.Base(strings.ReplaceAll(child, `\`, "/"))})
+		}
+		if task.Executables != nil {
+			refs = append(refs, childPackageReferences(pkg, task.Executables.Tasks)...)
+		}
+	}
+	return refs
+}
+
+// renderDOT renders the dependency graph in GraphViz DOT format
+func renderDOT(graph dependencyGraph) string {
+	var result strings.Builder
+	result.WriteString("digraph dependencies {\n")
+	result.WriteString("    rankdir=LR;\n")
+	result.WriteString("    node [shape=box];\n")
+	for _, name := range graph.Packages {
+		result.WriteString(fmt.Sprintf("    %s;\n", dotQuote(name)))
+	}
+	for _, edge := range graph.Edges {
+		attributes := fmt.Sprintf("label=%s", dotQuote(edge.Label))
+		if edge.Shared {
+			attributes += ", dir=none, style=dashed"
+		}
+		result.WriteString(fmt.Sprintf("    %s -> %s [%s];\n", dotQuote(edge.From), dotQuote(edge.To), attributes))
+	}
+	result.WriteString("}\n")
+	return result.String()
+}
+
+// dotQuote quotes a DOT identifier
+func dotQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ").Replace(value) + `"`
+}
+
+// renderSVG converts DOT to SVG with the GraphViz dot command
+func renderSVG(dot string) (string, error) {
+	path, err := exec.LookPath("dot")
+	if err != nil {
+		return "", err
+	}
+	cmd := exec.Command(path, "-Tsvg")
+	cmd.Stdin = strings.NewReader(dot)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// HandleGenerateDependencyGraph exports package-level dependencies as a GraphViz DOT (or SVG) graph
+func HandleGenerateDependencyGraph(ctx context.Context, request mcp.CallToolRequest, packageDirectory, excludeFile string) (*mcp.CallToolResult, error) {
+	directory := request.GetString("directory", "")
+	if directory == "" {
+		directory = packageDirectory
+	} else {
+		directory = file.ResolveFilePath(directory, packageDirectory)
+	}
+	if directory == "" {
+		return mcp.NewToolResultError("directory is required when no package directory is configured"), nil
+	}
+
+	format := strings.ToLower(request.GetString("format", "dot"))
+	if format != "dot" && format != "svg" {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid format: %s (supported: dot, svg)", format)), nil
+	}
+
+	graph, err := buildDependencyGraph(ctx, directory, excludeFile)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to scan directory: %v", err)), nil
+	}
+
+	report := renderDOT(graph)
+	if format == "svg" {
+		svg, err := renderSVG(report)
+		if err != nil {
+			slog.Warn("SVG rendering unavailable, returning DOT", "tool", "generate_dependency_graph", "directory", directory, "error", err)
+		} else {
+			report = svg
+		}
+	}
+
+	if outputPath := request.GetString("output_file_path", ""); outputPath != "" {
+		if err := output.WriteOutput(file.ResolveFilePath(outputPath, packageDirectory), report); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	return mcp.NewToolResultText(report), nil
+}
@@ -0,0 +1,168 @@
+package diagram
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/MCPRUNNER/gossisMCP/pkg/types"
+	"github.com/MCPRUNNER/gossisMCP/pkg/util/file"
+	"github.com/MCPRUNNER/gossisMCP/pkg/util/output"
+)
+
+// controlFlowNode is a task or container in the package control flow
+type controlFlowNode struct {
+	ID           string
+	RefID        string
+	Name         string
+	CreationName string
+	Children     []*controlFlowNode
+}
+
+// IsContainer reports whether the node groups other executables
+func (n *controlFlowNode) IsContainer() bool {
+	return len(n.Children) > 0
+}
+
+// controlFlowEdge is a precedence constraint between two nodes
+type controlFlowEdge struct {
+	From  string
+	To    string
+	Label string
+}
+
+// controlFlowGraph is the task and precedence constraint graph of a package
+type controlFlowGraph struct {
+	Nodes []*controlFlowNode
+	Edges []controlFlowEdge
+}
+
+// constraintOutcomes maps precedence constraint DTS:Value settings to their labels
+var constraintOutcomes = map[string]string{
+	"":  "Success",
+	"0": "Success",
+	"1": "Failure",
+	"2": "Completion",
+}
+
+// loadPackage reads and parses a DTSX file
+func loadPackage(filePath string) (*types.SSISPackage, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+
+	// Remove namespace prefixes for easier parsing
+	data = []byte(strings.ReplaceAll(string(data), "DTS:", ""))
+
+	var pkg types.SSISPackage
+	if err := xml.Unmarshal(data, &pkg); err != nil {
+		return nil, fmt.Errorf("failed to parse XML: %v", err)
+	}
+	return &pkg, nil
+}
+
+// buildControlFlowGraph collects tasks, containers and precedence constraints from a package
+func buildControlFlowGraph(pkg *types.SSISPackage) controlFlowGraph {
+	var graph controlFlowGraph
+	ids := make(map[string]string)
+
+	var addNodes func(tasks []types.Task) []*controlFlowNode
+	var constraints []types.PrecedenceConstraint
+	addNodes = func(tasks []types.Task) []*controlFlowNode {
+		var nodes []*controlFlowNode
+		for _, task := range tasks {
+			node := &controlFlowNode{
+				ID:           fmt.Sprintf("n%d", len(ids)+1),
+				RefID:        task.RefId,
+				Name:         task.Name,
+				CreationName: task.CreationName,
+			}
+			ids[task.RefId] = node.ID
+			if task.Executables != nil {
+				node.Children = addNodes(task.Executables.Tasks)
+			}
+			constraints = append(constraints, task.PrecedenceConstraints.Constraints...)
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+	graph.Nodes = addNodes(pkg.Executables.Tasks)
+	constraints = append(constraints, pkg.PrecedenceConstraints.Constraints...)
+
+	for _, constraint := range constraints {
+		from, fromOK := ids[constraint.From]
+		to, toOK := ids[constraint.To]
+		if !fromOK || !toOK {
+			continue
+		}
+		label, ok := constraintOutcomes[constraint.Value]
+		if !ok {
+			label = constraint.Value
+		}
+		graph.Edges = append(graph.Edges, controlFlowEdge{From: from, To: to, Label: label})
+	}
+	return graph
+}
+
+// renderMermaid renders the graph as a Mermaid flowchart
+func renderMermaid(graph controlFlowGraph) string {
+	var result strings.Builder
+	result.WriteString("flowchart TD\n")
+
+	var writeNodes func(nodes []*controlFlowNode, indent string)
+	writeNodes = func(nodes []*controlFlowNode, indent string) {
+		for _, node := range nodes {
+			if node.IsContainer() {
+				result.WriteString(fmt.Sprintf("%ssubgraph %s [\"%s\"]\n", indent, node.ID, mermaidLabel(node.Name)))
+				writeNodes(node.Children, indent+"    ")
+				result.WriteString(fmt.Sprintf("%send\n", indent))
+				continue
+			}
+			result.WriteString(fmt.Sprintf("%s%s[\"%s\"]\n", indent, node.ID, mermaidLabel(node.Name)))
+		}
+	}
+	writeNodes(graph.Nodes, "    ")
+
+	for _, edge := range graph.Edges {
+		result.WriteString(fmt.Sprintf("    %s -->|%s| %s\n", edge.From, edge.Label, edge.To))
+	}
+	return result.String()
+}
+
+// mermaidLabel escapes characters that would end a quoted Mermaid label
+func mermaidLabel(name string) string {
+	replacer := strings.NewReplacer(`"`, "#quot;", "\r", " ", "\n", " ")
+	return replacer.Replace(name)
+}
+
+// HandleGenerateMermaidDiagram renders the control flow of a DTSX file as a Mermaid flowchart
+func HandleGenerateMermaidDiagram(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
+	filePath, err := request.RequireString("file_path")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	pkg, err := loadPackage(file.ResolveFilePath(filePath, packageDirectory))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	diagram := renderMermaid(buildControlFlowGraph(pkg))
+
+	if outputPath := request.GetString("output_file_path", ""); outputPath != "" {
+		if filepath.Ext(outputPath) == "" {
+			outputPath += ".mmd"
+		}
+		if err := output.WriteOutput(file.ResolveFilePath(outputPath, packageDirectory), diagram); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	return mcp.NewToolResultText(diagram), nil
+}
@@ -0,0 +1,252 @@
+package diagram
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func createRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: args,
+		},
+	}
+}
+
+func testdataDir(t *testing.T) string {
+	t.Helper()
+	_, filename, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("unable to determine caller information")
+	}
+	return filepath.Join(filepath.Dir(filename), "..", "..", "..", "testdata")
+}
+
+var (
+	mermaidNodePattern     = regexp.MustCompile(`^(n\d+)\["[^"]*"\]$`)
+	mermaidSubgraphPattern = regexp.MustCompile(`^subgraph (n\d+) \["[^"]*"\]$`)
+	mermaidEdgePattern     = regexp.MustCompile(`^(n\d+) -->\|(Success|Failure|Completion)\| (n\d+)$`)
+)
+
+// validateMermaid checks the flowchart subset emitted by renderMermaid: declared nodes,
+// balanced subgraphs and edges that only reference declared identifiers
+func validateMermaid(t *testing.T, diagram string) {
+	t.Helper()
+	lines := strings.Split(strings.TrimRight(diagram, "\n"), "\n")
+	if lines[0] != "flowchart TD" {
+		t.Fatalf("expected flowchart header, got %q", lines[0])
+	}
+
+	declared := make(map[string]bool)
+	depth := 0
+	for _, raw := range lines[1:] {
+		line := strings.TrimSpace(raw)
+		switch {
+		case line == "end":
+			depth--
+			if depth < 0 {
+				t.Fatalf("unbalanced end in diagram:\n%s", diagram)
+			}
+		case mermaidSubgraphPattern.MatchString(line):
+			declared[mermaidSubgraphPattern.FindStringSubmatch(line)[1]] = true
+			depth++
+		case mermaidNodePattern.MatchString(line):
+			declared[mermaidNodePattern.FindStringSubmatch(line)[1]] = true
+		case mermaidEdgePattern.MatchString(line):
+			match := mermaidEdgePattern.FindStringSubmatch(line)
+			if !declared[match[1]] || !declared[match[3]] {
+				t.Fatalf("edge references undeclared node: %q", line)
+			}
+		default:
+			t.Fatalf("unexpected Mermaid line %q in diagram:\n%s", line, diagram)
+		}
+	}
+	if depth != 0 {
+		t.Fatalf("unclosed subgraph in diagram:\n%s", diagram)
+	}
+}
+
+func TestHandleGenerateMermaidDiagram(t *testing.T) {
+	outputDir := t.TempDir()
+	result, err := HandleGenerateMermaidDiagram(context.Background(), createRequest(map[string]interface{}{
+		"file_path":        "ControlFlow.dtsx",
+		"output_file_path": filepath.Join(outputDir, "control_flow"),
+	}), testdataDir(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error result: %+v", result.Content)
+	}
+
+	diagram := result.Content[0].(mcp.TextContent).Text
+	validateMermaid(t, diagram)
+
+	expected := []string{
+		`n1["Truncate Staging"]`,
+		`subgraph n2 ["Load Sequence"]`,
+		`n3["Load Customers"]`,
+		`n4["Load #quot;Orders#quot;"]`,
+		`n3 -->|Success| n4`,
+		`n1 -->|Success| n2`,
+		`n2 -->|Failure| n5`,
+		`n2 -->|Completion| n6`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(diagram, want) {
+			t.Fatalf("expected %q in diagram, got:\n%s", want, diagram)
+		}
+	}
+
+	written, err := os.ReadFile(filepath.Join(outputDir, "control_flow.mmd"))
+	if err != nil {
+		t.Fatalf("expected .mmd output file: %v", err)
+	}
+	if string(written) != diagram {
+		t.Fatalf("expected written diagram to match result")
+	}
+}
+
+func writePackage(t *testing.T, dir, name, body string) {
+	t.Helper()
+	content := `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:refId="Package" DTS:ObjectName="` + strings.TrimSuffix(name, ".dtsx") + `">
+` + body + `
+</DTS:Executable>`
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func TestHandleGenerateDependencyGraph(t *testing.T) {
+	dir := t.TempDir()
+	writePackage(t, dir, "Parent.dtsx", `
+  <DTS:ConnectionManagers>
+    <DTS:ConnectionManager DTS:ObjectName="Warehouse" DTS:DTSID="{A1}" />
+    <DTS:ConnectionManager DTS:ObjectName="Archive.dtsx" DTS:DTSID="{A2}">
+      <DTS:ObjectData><DTS:ConnectionManager DTS:ConnectionString="C:\SSIS\Archive.dtsx" /></DTS:ObjectData>
+    </DTS:ConnectionManager>
+  </DTS:ConnectionManagers>
+  <DTS:Executables>
+    <DTS:Executable DTS:refId="Package\Run Child" DTS:CreationName="Microsoft.ExecutePackageTask" DTS:ObjectName="Run Child">
+      <DTS:ObjectData>
+        <ExecutePackageTask>
+          <UseProjectReference>True</UseProjectReference>
+          <PackageName>Child.dtsx</PackageName>
+        </ExecutePackageTask>
+      </DTS:ObjectData>
+    </DTS:Executable>
+    <DTS:Executable DTS:refId="Package\Cleanup" DTS:CreationName="STOCK:SEQUENCE" DTS:ObjectName="Cleanup">
+      <DTS:Executables>
+        <DTS:Executable DTS:refId="Package\Cleanup\Run Archive" DTS:CreationName="Microsoft.ExecutePackageTask" DTS:ObjectName="Run Archive">
+          <DTS:ObjectData>
+            <ExecutePackageTask><Connection>{A2}</Connection></ExecutePackageTask>
+          </DTS:ObjectData>
+        </DTS:Executable>
+      </DTS:Executables>
+    </DTS:Executable>
+  </DTS:Executables>`)
+	writePackage(t, dir, "Child.dtsx", `
+  <DTS:ConnectionManagers>
+    <DTS:ConnectionManager DTS:ObjectName="Warehouse" DTS:DTSID="{B1}" />
+  </DTS:ConnectionManagers>`)
+
+	result, err := HandleGenerateDependencyGraph(context.Background(), createRequest(map[string]interface{}{
+		"output_file_path": "graph/dependencies.dot",
+	}), dir, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error result: %+v", result.Content)
+	}
+
+	dot := result.Content[0].(mcp.TextContent).Text
+	expected := []string{
+		"digraph dependencies {",
+		`"Archive.dtsx";`,
+		`"Child.dtsx";`,
+		`"Parent.dtsx";`,
+		`"Parent.dtsx" -> "Child.dtsx" [label="Run Child"];`,
+		`"Parent.dtsx" -> "Archive.dtsx" [label="Run Archive"];`,
+		`"Child.dtsx" -> "Parent.dtsx" [label="Warehouse", dir=none, style=dashed];`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(dot, want) {
+			t.Fatalf("expected %q in DOT output, got:\n%s", want, dot)
+		}
+	}
+
+	written, err := os.ReadFile(filepath.Join(dir, "graph", "dependencies.dot"))
+	if err != nil || string(written) != dot {
+		t.Fatalf("expected DOT output file to match result (err=%v)", err)
+	}
+}
+
+func TestBuildDependencyGraphSharedConnections(t *testing.T) {
+	dir := t.TempDir()
+	// Load.dtsx lists Warehouse twice, which must not add a self-edge or a second edge to Stage.dtsx
+	writePackage(t, dir, "Load.dtsx", `
+  <DTS:ConnectionManagers>
+    <DTS:ConnectionManager DTS:ObjectName="Warehouse" DTS:DTSID="{A1}" />
+    <DTS:ConnectionManager DTS:ObjectName="Warehouse" DTS:DTSID="{A2}" />
+  </DTS:ConnectionManagers>`)
+	writePackage(t, dir, "Stage.dtsx", `
+  <DTS:ConnectionManagers>
+    <DTS:ConnectionManager DTS:ObjectName="Warehouse" DTS:DTSID="{B1}" />
+  </DTS:ConnectionManagers>`)
+	if err := os.MkdirAll(filepath.Join(dir, "old"), 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	writePackage(t, filepath.Join(dir, "old"), "Legacy.dtsx", `
+  <DTS:ConnectionManagers>
+    <DTS:ConnectionManager DTS:ObjectName="Warehouse" DTS:DTSID="{C1}" />
+  </DTS:ConnectionManagers>`)
+	if err := os.WriteFile(filepath.Join(dir, ".gossisignore"), []byte("old/\n"), 0o644); err != nil {
+		t.Fatalf("failed to write exclude file: %v", err)
+	}
+
+	graph, err := buildDependencyGraph(context.Background(), dir, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"Load.dtsx", "Stage.dtsx"}; !reflect.DeepEqual(graph.Packages, want) {
+		t.Fatalf("expected the excluded package to be skipped, got %v", graph.Packages)
+	}
+	want := []dependencyEdge{{From: "Load.dtsx", To: "Stage.dtsx", Label: "Warehouse", Shared: true}}
+	if !reflect.DeepEqual(graph.Edges, want) {
+		t.Fatalf("expected one shared connection edge, got %+v", graph.Edges)
+	}
+}
+
+func TestHandleGenerateDependencyGraphSVG(t *testing.T) {
+	dir := t.TempDir()
+	writePackage(t, dir, "Only.dtsx", "")
+
+	result, err := HandleGenerateDependencyGraph(context.Background(), createRequest(map[string]interface{}{
+		"format": "svg",
+	}), dir, "")
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected failure: %v %+v", err, result)
+	}
+
+	text := result.Content[0].(mcp.TextContent).Text
+	if _, lookErr := exec.LookPath("dot"); lookErr != nil {
+		if !strings.HasPrefix(text, "digraph dependencies {") {
+			t.Fatalf("expected DOT fallback without GraphViz, got %q", text)
+		}
+		return
+	}
+	if !strings.Contains(text, "<svg") {
+		t.Fatalf("expected SVG output, got %q", text)
+	}
+}
@@ -0,0 +1,61 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Options controls access to the server's process environment
+type Options struct {
+	// AllowWrite permits set_environment_variable to modify the environment
+	AllowWrite bool
+}
+
+// HandleGetEnvironmentVariable returns the value of an environment variable and fails when it is not set
+func HandleGetEnvironmentVariable(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := request.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return mcp.NewToolResultError("name must not be empty"), nil
+	}
+
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("environment variable %s is not set", name)), nil
+	}
+	return mcp.NewToolResultText(value), nil
+}
+
+// HandleSetEnvironmentVariable sets an environment variable when environment writes are enabled
+func HandleSetEnvironmentVariable(_ context.Context, request mcp.CallToolRequest, options Options) (*mcp.CallToolResult, error) {
+	if !options.AllowWrite {
+		return mcp.NewToolResultError("set_environment_variable is disabled; set server.allow_env_write to enable it"), nil
+	}
+
+	name, err := request.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	name = strings.TrimSpace(name)
+	if name == "" || strings.Contains(name, "=") {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid environment variable name: %q", name)), nil
+	}
+	value, err := request.RequireString("value")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if err := os.Setenv(name, value); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to set environment variable: %v", err)), nil
+	}
+	slog.Info("environment variable set", "tool", "set_environment_variable", "name", name)
+	return mcp.NewToolResultText(fmt.Sprintf("Set %s\n", name)), nil
+}
@@ -0,0 +1,61 @@
+package environment
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func createRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: args,
+		},
+	}
+}
+
+func TestHandleGetEnvironmentVariable(t *testing.T) {
+	t.Setenv("GOSSIS_TEST_SERVER", "sql-prod-01")
+
+	result, err := HandleGetEnvironmentVariable(context.Background(), createRequest(map[string]interface{}{"name": "GOSSIS_TEST_SERVER"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error result: %+v", result.Content)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != "sql-prod-01" {
+		t.Fatalf("expected variable value, got %q", text)
+	}
+
+	missing, err := HandleGetEnvironmentVariable(context.Background(), createRequest(map[string]interface{}{"name": "GOSSIS_TEST_UNSET_VARIABLE"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !missing.IsError {
+		t.Fatal("expected an error for an unset variable")
+	}
+}
+
+func TestHandleSetEnvironmentVariable(t *testing.T) {
+	t.Setenv("GOSSIS_TEST_DATABASE", "")
+	args := map[string]interface{}{"name": "GOSSIS_TEST_DATABASE", "value": "Staging"}
+
+	disabled, err := HandleSetEnvironmentVariable(context.Background(), createRequest(args), Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !disabled.IsError {
+		t.Fatal("expected set_environment_variable to be disabled by default")
+	}
+
+	result, err := HandleSetEnvironmentVariable(context.Background(), createRequest(args), Options{AllowWrite: true})
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected failure: %v %+v", err, result)
+	}
+	if value := os.Getenv("GOSSIS_TEST_DATABASE"); value != "Staging" {
+		t.Fatalf("expected variable to be set, got %q", value)
+	}
+}
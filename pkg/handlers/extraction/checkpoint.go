@@ -0,0 +1,136 @@
+package extraction
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/MCPRUNNER/gossisMCP/pkg/formatter"
+	"github.com/MCPRUNNER/gossisMCP/pkg/types"
+	"github.com/MCPRUNNER/gossisMCP/pkg/util/output"
+)
+
+// checkpointUsages maps the DTSCheckpointUsage values stored in CheckpointUsage to their names and restart behavior
+var checkpointUsages = map[string]struct {
+	Name     string
+	Behavior string
+}{
+	"0": {Name: "Never", Behavior: "The checkpoint file is never used; the package always runs from the beginning"},
+	"1": {Name: "Always", Behavior: "The package restarts from the checkpoint file and fails if the file does not exist"},
+	"2": {Name: "IfExists", Behavior: "The package restarts from the checkpoint file when it exists, otherwise it runs from the beginning"},
+}
+
+// absoluteCheckpointPath matches Windows drive, UNC and Unix absolute paths
+var absoluteCheckpointPath = regexp.MustCompile(`^([A-Za-z]:[\\/]|\\\\|/)`)
+
+// checkpointReport is the extract_checkpoint_config result
+type checkpointReport struct {
+	Enabled         bool     `json:"enabled"`
+	FileName        string   `json:"checkpoint_file_name"`
+	Usage           string   `json:"checkpoint_usage"`
+	RestartBehavior string   `json:"restart_behavior"`
+	Issues          []string `json:"issues"`
+}
+
+// buildCheckpointReport interprets a package's checkpoint settings and flags deployment risks
+func buildCheckpointReport(checkpoint types.Checkpoint) checkpointReport {
+	report := checkpointReport{
+		Enabled:  strings.EqualFold(strings.TrimSpace(checkpoint.SaveCheckpoints), "true") || strings.TrimSpace(checkpoint.SaveCheckpoints) == "-1",
+		FileName: strings.TrimSpace(checkpoint.CheckpointFileName),
+		Issues:   []string{},
+	}
+
+	usage := strings.TrimSpace(checkpoint.CheckpointUsage)
+	if usage == "" {
+		usage = "0"
+	}
+	if known, ok := checkpointUsages[usage]; ok {
+		report.Usage = known.Name
+		report.RestartBehavior = known.Behavior
+	} else {
+		report.Usage = usage
+		report.RestartBehavior = "Unknown checkpoint usage"
+	}
+
+	if report.FileName != "" && absoluteCheckpointPath.MatchString(report.FileName) {
+		report.Issues = append(report.Issues, fmt.Sprintf("Checkpoint file path '%s' is absolute and environment-specific; set it from a parameter or expression so it can change per deployment", report.FileName))
+	}
+	if report.Enabled && report.FileName == "" {
+		report.Issues = append(report.Issues, "SaveCheckpoints is enabled but no CheckpointFileName is set")
+	}
+	if !report.Enabled && usage != "0" {
+		report.Issues = append(report.Issues, fmt.Sprintf("CheckpointUsage is %s but SaveCheckpoints is disabled, so no checkpoint file is written", report.Usage))
+	}
+	return report
+}
+
+// HandleExtractCheckpointConfig handles checkpoint configuration extraction from DTSX files
+func HandleExtractCheckpointConfig(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
+	filePath, err := request.RequireString("file_path")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	format := formatter.OutputFormat(request.GetString("format", "text"))
+	outputPath := request.GetString("output_file_path", "")
+
+	// Resolve the file path against the package directory
+	resolvedPath := ResolveFilePath(filePath, packageDirectory)
+
+	data, err := os.ReadFile(resolvedPath)
+	if err != nil {
+		return checkpointResult(formatter.CreateAnalysisResult("Checkpoint Configuration", filePath, nil, fmt.Errorf("failed to read file: %v", err)), format, outputPath, packageDirectory)
+	}
+
+	// Remove namespace prefixes for easier parsing
+	data = []byte(strings.ReplaceAll(string(data), "DTS:", ""))
+
+	var pkg types.SSISPackage
+	if err := xml.Unmarshal(data, &pkg); err != nil {
+		return checkpointResult(formatter.CreateAnalysisResult("Checkpoint Configuration", filePath, nil, fmt.Errorf("failed to parse XML: %v", err)), format, outputPath, packageDirectory)
+	}
+
+	report := buildCheckpointReport(pkg.Checkpoint)
+	if format == formatter.FormatJSON {
+		return checkpointResult(formatter.CreateAnalysisResult("Checkpoint Configuration", filePath, report, nil), format, outputPath, packageDirectory)
+	}
+
+	var result strings.Builder
+	result.WriteString("Checkpoint Configuration:\n")
+	result.WriteString(fmt.Sprintf("Save Checkpoints: %t\n", report.Enabled))
+	if report.FileName != "" {
+		result.WriteString(fmt.Sprintf("Checkpoint File: %s\n", report.FileName))
+	} else {
+		result.WriteString("Checkpoint File: (not set)\n")
+	}
+	result.WriteString(fmt.Sprintf("Checkpoint Usage: %s\n", report.Usage))
+	result.WriteString(fmt.Sprintf("Restart Behavior: %s\n", report.RestartBehavior))
+	if len(report.Issues) > 0 {
+		result.WriteString("\nIssues:\n")
+		for _, issue := range report.Issues {
+			result.WriteString(fmt.Sprintf("⚠️  %s\n", issue))
+		}
+	}
+
+	return checkpointResult(formatter.CreateAnalysisResult("Checkpoint Configuration", filePath, result.String(), nil), format, outputPath, packageDirectory)
+}
+
+// checkpointResult formats the checkpoint analysis, keeping the JSON result as structured content, and writes it
+// to outputPath when one is given
+func checkpointResult(analysis *formatter.AnalysisResult, format formatter.OutputFormat, outputPath, packageDirectory string) (*mcp.CallToolResult, error) {
+	report := formatter.FormatAnalysisResult(analysis, format)
+	if outputPath != "" {
+		if err := output.WriteOutput(ResolveFilePath(outputPath, packageDirectory), report); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	if format == formatter.FormatJSON {
+		return mcp.NewToolResultStructured(analysis, report), nil
+	}
+	return mcp.NewToolResultText(report), nil
+}
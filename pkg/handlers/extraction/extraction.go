@@ -7,12 +7,16 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/MCPRUNNER/gossisMCP/pkg/formatter"
 	"github.com/MCPRUNNER/gossisMCP/pkg/types"
 	"github.com/MCPRUNNER/gossisMCP/pkg/util/analysis"
 	"github.com/MCPRUNNER/gossisMCP/pkg/util/file"
+	"github.com/MCPRUNNER/gossisMCP/pkg/util/output"
 	"github.com/antchfx/xmlquery"
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -85,6 +89,15 @@ func HandleParseDtsx(_ context.Context, request mcp.CallToolRequest, packageDire
 	}
 	summaryData["variables"] = variables
 
+	// Add version metadata from the package root element
+	summaryData["package_version"] = map[string]string{
+		"version_major":    pkg.PackageVersion.VersionMajor,
+		"version_minor":    pkg.PackageVersion.VersionMinor,
+		"version_build":    pkg.PackageVersion.VersionBuild,
+		"version_comments": pkg.PackageVersion.VersionComments,
+		"creation_date":    pkg.PackageVersion.CreationDate,
+	}
+
 	result := formatter.CreateAnalysisResult("parse_dtsx", filePath, summaryData, nil)
 	return mcp.NewToolResultText(formatter.FormatAnalysisResult(result, format)), nil
 }
@@ -112,8 +125,13 @@ func HandleExtractTasks(_ context.Context, request mcp.CallToolRequest, packageD
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse XML: %v", err)), nil
 	}
 
+	selected, err := filterTasksByType(pkg.Executables.Tasks, request.GetString("task_type", ""), request.GetBool("negate", false))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	tasks := "Tasks:\n"
-	for i, task := range pkg.Executables.Tasks {
+	for i, task := range selected {
 		tasks += fmt.Sprintf("%d. %s\n", i+1, task.Name)
 		for _, prop := range task.Properties {
 			if prop.Name == "Description" {
@@ -129,6 +147,81 @@ func HandleExtractTasks(_ context.Context, request mcp.CallToolRequest, packageD
 	return mcp.NewToolResultText(tasks), nil
 }
 
+// taskTypePatterns maps the friendly task_type names to substrings of a task's lower-cased CreationName
+var taskTypePatterns = map[string][]string{
+	"execute_sql":     {"executesqltask"},
+	"script_task":     {"scripttask"},
+	"data_flow":       {"pipeline"},
+	"execute_package": {"executepackagetask"},
+	"execute_process": {"executeprocess"},
+	"file_system":     {"filesystemtask"},
+	"ftp":             {"ftptask"},
+	"send_mail":       {"sendmailtask"},
+	"bulk_insert":     {"bulkinserttask"},
+	"expression":      {"expressiontask"},
+	"cdc_control":     {"cdccontroltask"},
+	"web_service":     {"webservicetask"},
+	"xml":             {"xmltask"},
+	"message_queue":   {"messagequeuetask"},
+	"sequence":        {"stock:sequence"},
+	"for_loop":        {"stock:forloop"},
+	"foreach_loop":    {"stock:foreachloop"},
+}
+
+// taskCreationName returns the CreationName of a task from its attribute or, in older formats, its property
+func taskCreationName(task types.Task) string {
+	if task.CreationName != "" {
+		return task.CreationName
+	}
+	for _, prop := range task.Properties {
+		if prop.Name == "CreationName" {
+			return strings.TrimSpace(prop.Value)
+		}
+	}
+	return ""
+}
+
+// filterTasksByType keeps the tasks matching a comma-separated list of friendly task types,
+// or the tasks not matching them when negate is set. An empty list returns every task.
+func filterTasksByType(tasks []types.Task, taskTypes string, negate bool) ([]types.Task, error) {
+	var patterns []string
+	for _, name := range strings.Split(taskTypes, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		typePatterns, ok := taskTypePatterns[name]
+		if !ok {
+			supported := make([]string, 0, len(taskTypePatterns))
+			for known := range taskTypePatterns {
+				supported = append(supported, known)
+			}
+			sort.Strings(supported)
+			return nil, fmt.Errorf("unknown task_type: %s (supported: %s)", name, strings.Join(supported, ", "))
+		}
+		patterns = append(patterns, typePatterns...)
+	}
+	if len(patterns) == 0 {
+		return tasks, nil
+	}
+
+	var filtered []types.Task
+	for _, task := range tasks {
+		creationName := strings.ToLower(taskCreationName(task))
+		matched := false
+		for _, pattern := range patterns {
+			if strings.Contains(creationName, pattern) {
+				matched = true
+				break
+			}
+		}
+		if matched != negate {
+			filtered = append(filtered, task)
+		}
+	}
+	return filtered, nil
+}
+
 // HandleExtractConnections handles connection extraction from DTSX files
 func HandleExtractConnections(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
 	filePath, err := request.RequireString("file_path")
@@ -152,19 +245,51 @@ func HandleExtractConnections(_ context.Context, request mcp.CallToolRequest, pa
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse XML: %v", err)), nil
 	}
 
+	maskPasswords := request.GetBool("mask_passwords", true)
+	typeFilter := make(map[string]bool)
+	for _, connType := range strings.Split(request.GetString("connection_type", ""), ",") {
+		if connType = normalizeConnectionType(connType); connType != "" {
+			typeFilter[connType] = true
+		}
+	}
+
 	connections := "Connections:\n"
-	for i, conn := range pkg.ConnectionMgr.Connections {
-		connections += fmt.Sprintf("%d. %s\n", i+1, conn.Name)
+	index := 0
+	for _, conn := range pkg.ConnectionMgr.Connections {
+		if len(typeFilter) > 0 && !typeFilter[normalizeConnectionType(conn.CreationName)] {
+			continue
+		}
+		index++
+		connections += fmt.Sprintf("%d. %s\n", index, conn.Name)
+		if conn.CreationName != "" {
+			connections += fmt.Sprintf("   Type: %s\n", strings.SplitN(conn.CreationName, ":", 2)[0])
+		}
 		connStr := conn.ObjectData.ConnectionMgr.ConnectionString
 		if connStr == "" {
 			connStr = conn.ObjectData.MsmqConnMgr.ConnectionString
 		}
-		connections += fmt.Sprintf("   Connection String: %s\n", connStr)
-
-		// Check if connection string contains expressions and resolve them
-		if strings.Contains(connStr, "@[") {
+		displayConnStr := connStr
+		if maskPasswords {
+			displayConnStr = analysis.MaskConnectionStringPasswords(connStr)
+		}
+		connections += fmt.Sprintf("   Connection String: %s\n", displayConnStr)
+
+		// A ConnectionString property expression replaces the design-time connection string at run time
+		if expression := connectionStringExpression(conn); expression != "" {
+			resolvedConnStr := resolveStringExpression(expression, pkg.Variables.Vars)
+			if maskPasswords {
+				expression = analysis.MaskConnectionStringPasswords(expression)
+				resolvedConnStr = analysis.MaskConnectionStringPasswords(resolvedConnStr)
+			}
+			connections += fmt.Sprintf("   Connection String Expression: %s\n", expression)
+			connections += fmt.Sprintf("   Resolved Connection String: %s\n", resolvedConnStr)
+		} else if strings.Contains(connStr, "@[") {
+			// Check if connection string contains expressions and resolve them
 			resolvedConnStr := resolveVariableExpressions(connStr, pkg.Variables.Vars, 10)
 			if resolvedConnStr != connStr {
+				if maskPasswords {
+					resolvedConnStr = analysis.MaskConnectionStringPasswords(resolvedConnStr)
+				}
 				connections += fmt.Sprintf("   Resolved Connection String: %s\n", resolvedConnStr)
 			}
 		}
@@ -173,6 +298,94 @@ func HandleExtractConnections(_ context.Context, request mcp.CallToolRequest, pa
 	return mcp.NewToolResultText(connections), nil
 }
 
+// connectionStringExpression returns the connection manager's ConnectionString property expression, if any
+func connectionStringExpression(conn types.Connection) string {
+	for _, expression := range conn.PropertyExpressions {
+		if strings.EqualFold(expression.Name, "ConnectionString") {
+			return strings.TrimSpace(expression.Value)
+		}
+	}
+	return ""
+}
+
+// resolveStringExpression evaluates an expression made of string literals and variable references joined
+// by "+". Expressions using other operators or functions only have their variable references substituted.
+func resolveStringExpression(expression string, variables []types.Variable) string {
+	terms, ok := splitConcatenation(expression)
+	if !ok {
+		return resolveVariableExpressions(expression, variables, 10)
+	}
+
+	var resolved strings.Builder
+	for _, term := range terms {
+		if strings.HasPrefix(term, `"`) {
+			literal, err := strconv.Unquote(term)
+			if err != nil {
+				return resolveVariableExpressions(expression, variables, 10)
+			}
+			resolved.WriteString(literal)
+			continue
+		}
+		resolved.WriteString(resolveVariableExpressions(term, variables, 10))
+	}
+	return resolved.String()
+}
+
+// splitConcatenation splits an expression on top-level "+" operators, reporting false unless every term is a
+// string literal or a single @[...] variable reference
+func splitConcatenation(expression string) ([]string, bool) {
+	var terms []string
+	var current strings.Builder
+	inString, inVariable := false, false
+	for i := 0; i < len(expression); i++ {
+		c := expression[i]
+		switch {
+		case inString:
+			current.WriteByte(c)
+			if c == '\\' && i+1 < len(expression) {
+				i++
+				current.WriteByte(expression[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case inVariable:
+			current.WriteByte(c)
+			inVariable = c != ']'
+		case c == '"':
+			inString = true
+			current.WriteByte(c)
+		case c == '@' && i+1 < len(expression) && expression[i+1] == '[':
+			inVariable = true
+			current.WriteByte(c)
+		case c == '+':
+			terms = append(terms, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	if inString || inVariable {
+		return nil, false
+	}
+	terms = append(terms, strings.TrimSpace(current.String()))
+
+	for _, term := range terms {
+		isLiteral := len(term) >= 2 && strings.HasPrefix(term, `"`) && strings.HasSuffix(term, `"`)
+		isVariable := strings.HasPrefix(term, "@[") && strings.HasSuffix(term, "]") && !strings.Contains(term[2:], "@[")
+		if !isLiteral && !isVariable {
+			return nil, false
+		}
+	}
+	return terms, true
+}
+
+// normalizeConnectionType reduces a connection type or CreationName such as "ADO.NET:System.Data..." to a
+// comparable key like "adonet"
+func normalizeConnectionType(connType string) string {
+	connType = strings.SplitN(strings.TrimSpace(connType), ":", 2)[0]
+	return strings.ToLower(strings.NewReplacer(".", "", "_", "", "-", "", " ", "").Replace(connType))
+}
+
 // HandleExtractVariables handles variable extraction from DTSX files
 func HandleExtractVariables(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
 	filePath, err := request.RequireString("file_path")
@@ -197,8 +410,10 @@ func HandleExtractVariables(_ context.Context, request mcp.CallToolRequest, pack
 	}
 
 	variables := "Variables:\n"
-	for i, v := range pkg.Variables.Vars {
+	for i, scoped := range collectScopedVariables(pkg) {
+		v := scoped.Variable
 		variables += fmt.Sprintf("%d. %s = %s\n", i+1, v.Name, v.Value)
+		variables += fmt.Sprintf("   Scope: %s\n", scoped.Scope)
 		if v.Expression != "" {
 			variables += fmt.Sprintf("   Expression: %s\n", v.Expression)
 		}
@@ -207,6 +422,50 @@ func HandleExtractVariables(_ context.Context, request mcp.CallToolRequest, pack
 	return mcp.NewToolResultText(variables), nil
 }
 
+// scopedVariable is a variable together with the name of the package, container or event handler that owns it
+type scopedVariable struct {
+	Variable types.Variable
+	Scope    string
+}
+
+// collectScopedVariables gathers package-level variables followed by the variables of every container,
+// task and event handler, walking nested executables depth-first
+func collectScopedVariables(pkg types.SSISPackage) []scopedVariable {
+	var collected []scopedVariable
+	add := func(vars []types.Variable, scope string) {
+		for _, v := range vars {
+			collected = append(collected, scopedVariable{Variable: v, Scope: scope})
+		}
+	}
+
+	var walkTasks func(tasks []types.Task)
+	var walkEventHandlers func(handlers []types.EventHandler, owner string)
+	walkTasks = func(tasks []types.Task) {
+		for _, task := range tasks {
+			add(task.Variables.Vars, task.Name)
+			if task.Executables != nil {
+				walkTasks(task.Executables.Tasks)
+			}
+			walkEventHandlers(task.EventHandlers.EventHandlers, task.Name)
+		}
+	}
+	walkEventHandlers = func(handlers []types.EventHandler, owner string) {
+		for _, handler := range handlers {
+			event := handler.EventName
+			if event == "" {
+				event = handler.EventHandlerType
+			}
+			add(handler.Variables.Vars, fmt.Sprintf("%s.EventHandlers[%s]", owner, event))
+			walkTasks(handler.Executables.Tasks)
+		}
+	}
+
+	add(pkg.Variables.Vars, "Package")
+	walkTasks(pkg.Executables.Tasks)
+	walkEventHandlers(pkg.EventHandlers.EventHandlers, "Package")
+	return collected
+}
+
 // HandleExtractPrecedenceConstraints handles precedence constraint extraction from DTSX files
 func HandleExtractPrecedenceConstraints(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
 	filePath, err := request.RequireString("file_path")
@@ -230,6 +489,10 @@ func HandleExtractPrecedenceConstraints(_ context.Context, request mcp.CallToolR
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse XML: %v", err)), nil
 	}
 
+	if request.GetString("format", "text") == "graph" {
+		return mcp.NewToolResultStructured(buildPrecedenceGraph(&pkg), "Precedence constraint graph"), nil
+	}
+
 	constraints := "Precedence Constraints:\n"
 	for i, constraint := range pkg.PrecedenceConstraints.Constraints {
 		constraints += fmt.Sprintf("%d. %s\n", i+1, constraint.Name)
@@ -254,6 +517,78 @@ func HandleExtractPrecedenceConstraints(_ context.Context, request mcp.CallToolR
 	return mcp.NewToolResultText(constraints), nil
 }
 
+// precedenceGraphNode is a task or container in the precedence constraint graph
+type precedenceGraphNode struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// precedenceGraphEdge is a precedence constraint between two executables
+type precedenceGraphEdge struct {
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Eval       string `json:"eval"`
+	Expression string `json:"expression"`
+}
+
+// precedenceGraph is the adjacency list returned by extract_precedence_constraints with format=graph
+type precedenceGraph struct {
+	Nodes []precedenceGraphNode `json:"nodes"`
+	Edges []precedenceGraphEdge `json:"edges"`
+}
+
+// constraintOutcomes maps precedence constraint DTS:Value settings to their labels
+var constraintOutcomes = map[string]string{
+	"":  "Success",
+	"0": "Success",
+	"1": "Failure",
+	"2": "Completion",
+}
+
+// buildPrecedenceGraph collects every task, container and precedence constraint in a package,
+// using executable refIds as node ids
+func buildPrecedenceGraph(pkg *types.SSISPackage) precedenceGraph {
+	graph := precedenceGraph{Nodes: []precedenceGraphNode{}, Edges: []precedenceGraphEdge{}}
+
+	addConstraints := func(constraints []types.PrecedenceConstraint) {
+		for _, constraint := range constraints {
+			eval, ok := constraintOutcomes[constraint.Value]
+			if !ok {
+				eval = constraint.Value
+			}
+			// EvalOp 1 evaluates only the expression, so the execution outcome does not apply
+			if constraint.EvalOp == "1" {
+				eval = "Expression"
+			}
+			graph.Edges = append(graph.Edges, precedenceGraphEdge{
+				From:       constraint.From,
+				To:         constraint.To,
+				Eval:       eval,
+				Expression: constraint.Expression,
+			})
+		}
+	}
+
+	var walk func(tasks []types.Task)
+	walk = func(tasks []types.Task) {
+		for _, task := range tasks {
+			node := precedenceGraphNode{ID: task.RefId, Name: task.Name, Type: "task"}
+			if task.Executables != nil {
+				node.Type = "container"
+			}
+			graph.Nodes = append(graph.Nodes, node)
+			if task.Executables != nil {
+				walk(task.Executables.Tasks)
+			}
+			addConstraints(task.PrecedenceConstraints.Constraints)
+		}
+	}
+	walk(pkg.Executables.Tasks)
+	addConstraints(pkg.PrecedenceConstraints.Constraints)
+	return graph
+}
+
 // HandleExtractParameters handles parameter extraction from DTSX files
 func HandleExtractParameters(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
 	filePath, err := request.RequireString("file_path")
@@ -287,7 +622,7 @@ func HandleExtractParameters(_ context.Context, request mcp.CallToolRequest, pac
 
 	for i, p := range pkg.Parameters.Params {
 		result.WriteString(fmt.Sprintf("%d. %s\n", i+1, p.Name))
-		result.WriteString(fmt.Sprintf("   Data Type: %s\n", p.DataType))
+		result.WriteString(fmt.Sprintf("   Data Type: %s\n", describeParameterDataType(p.DataType)))
 		result.WriteString(fmt.Sprintf("   Value: %s\n", p.Value))
 		if p.Description != "" {
 			result.WriteString(fmt.Sprintf("   Description: %s\n", p.Description))
@@ -300,6 +635,47 @@ func HandleExtractParameters(_ context.Context, request mcp.CallToolRequest, pac
 	return mcp.NewToolResultText(result.String()), nil
 }
 
+// parameterDataType pairs the SSIS data type name with the .NET TypeCode stored in a parameter's DataType
+type parameterDataType struct {
+	Name     string
+	TypeCode string
+}
+
+// parameterDataTypes maps the TypeCode values used by package and project parameters to SSIS data types
+var parameterDataTypes = map[string]parameterDataType{
+	"0":  {Name: "DT_EMPTY", TypeCode: "Empty"},
+	"1":  {Name: "Object", TypeCode: "Object"},
+	"2":  {Name: "DT_NULL", TypeCode: "DBNull"},
+	"3":  {Name: "DT_BOOL", TypeCode: "Boolean"},
+	"4":  {Name: "DT_UI2", TypeCode: "Char"},
+	"5":  {Name: "DT_I1", TypeCode: "SByte"},
+	"6":  {Name: "DT_UI1", TypeCode: "Byte"},
+	"7":  {Name: "DT_I2", TypeCode: "Int16"},
+	"8":  {Name: "DT_UI2", TypeCode: "UInt16"},
+	"9":  {Name: "DT_I4", TypeCode: "Int32"},
+	"10": {Name: "DT_UI4", TypeCode: "UInt32"},
+	"11": {Name: "DT_I8", TypeCode: "Int64"},
+	"12": {Name: "DT_UI8", TypeCode: "UInt64"},
+	"13": {Name: "DT_R4", TypeCode: "Single"},
+	"14": {Name: "DT_R8", TypeCode: "Double"},
+	"15": {Name: "DT_DECIMAL", TypeCode: "Decimal"},
+	"16": {Name: "DT_DBTIMESTAMP", TypeCode: "DateTime"},
+	"18": {Name: "DT_WSTR", TypeCode: "String"},
+}
+
+// describeParameterDataType renders a parameter data type code as "DT_WSTR (18, String)",
+// falling back to the raw code when it is not a known TypeCode
+func describeParameterDataType(code string) string {
+	code = strings.TrimSpace(code)
+	if dataType, ok := parameterDataTypes[code]; ok {
+		return fmt.Sprintf("%s (%s, %s)", dataType.Name, code, dataType.TypeCode)
+	}
+	if code == "" {
+		return "Unknown"
+	}
+	return code
+}
+
 // HandleExtractScriptCode handles script code extraction from DTSX files
 func HandleExtractScriptCode(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
 	filePath, err := request.RequireString("file_path")
@@ -343,6 +719,7 @@ func HandleExtractScriptCode(_ context.Context, request mcp.CallToolRequest, pac
 			} else {
 				scriptCode += "No script code found in this task.\n"
 			}
+			scriptCode += formatAssemblyReferences(scriptTaskAssemblyReferences(task))
 		}
 	}
 
@@ -350,9 +727,208 @@ func HandleExtractScriptCode(_ context.Context, request mcp.CallToolRequest, pac
 		scriptCode += "No Script Tasks found in this package.\n"
 	}
 
+	scriptCode += "\nScript Components Code:\n"
+	components := collectScriptComponents(pkg.Executables.Tasks)
+	for i, component := range components {
+		scriptCode += fmt.Sprintf("Script Component %d: %s\n", i+1, component.Name)
+		scriptCode += "Type: Script Component\n"
+		scriptCode += fmt.Sprintf("Data Flow Task: %s\n", component.DataFlowTask)
+		if len(component.Files) == 0 {
+			scriptCode += "No script code found in this component.\n"
+		}
+		for _, file := range component.Files {
+			scriptCode += fmt.Sprintf("File: %s\nCode:\n%s\n", file.Name, strings.TrimSpace(file.Code))
+		}
+		scriptCode += formatAssemblyReferences(component.References)
+		scriptCode += "\n"
+	}
+	if len(components) == 0 {
+		scriptCode += "No Script Components found in this package.\n"
+	}
+
 	return mcp.NewToolResultText(scriptCode), nil
 }
 
+// scriptSourceFile is a source file stored in a Script Component's SourceCode property
+type scriptSourceFile struct {
+	Name string
+	Code string
+}
+
+// scriptComponent is a Script Component found in a data flow
+type scriptComponent struct {
+	Name         string
+	DataFlowTask string
+	Files        []scriptSourceFile
+	References   []string
+}
+
+// isScriptComponent reports whether a data flow component hosts script code
+func isScriptComponent(component types.DataFlowComponent) bool {
+	if strings.Contains(component.ComponentClassID, "ScriptComponent") {
+		return true
+	}
+	for _, prop := range component.Properties.Properties {
+		if prop.Name == "UserComponentTypeName" && strings.Contains(prop.Value, "ScriptComponent") {
+			return true
+		}
+	}
+	return false
+}
+
+// collectScriptComponents walks tasks and containers and returns the Script Components of every Data Flow Task
+func collectScriptComponents(tasks []types.Task) []scriptComponent {
+	var components []scriptComponent
+	for _, task := range tasks {
+		for _, component := range task.ObjectData.DataFlow.Components.Components {
+			if !isScriptComponent(component) {
+				continue
+			}
+			components = append(components, scriptComponent{
+				Name:         component.Name,
+				DataFlowTask: task.Name,
+				Files:        scriptComponentSourceFiles(component),
+				References:   scriptComponentAssemblyReferences(component),
+			})
+		}
+		if task.Executables != nil {
+			components = append(components, collectScriptComponents(task.Executables.Tasks)...)
+		}
+	}
+	return components
+}
+
+// scriptComponentSourceFiles returns the C# and VB files of a Script Component. The SourceCode property
+// stores each project file as three array elements: file name, encoding and content
+func scriptComponentSourceFiles(component types.DataFlowComponent) []scriptSourceFile {
+	var files []scriptSourceFile
+	for _, prop := range component.Properties.Properties {
+		if prop.Name != "SourceCode" {
+			continue
+		}
+		var sourceCode struct {
+			Elements []string `xml:"arrayElements>arrayElement"`
+		}
+		if err := xml.Unmarshal([]byte("<property>"+prop.Value+"</property>"), &sourceCode); err != nil {
+			continue
+		}
+		for i := 0; i+2 < len(sourceCode.Elements); i += 3 {
+			name := sourceCode.Elements[i]
+			ext := strings.ToLower(filepath.Ext(name))
+			// Skip project metadata such as Properties\AssemblyInfo.cs and generated settings classes
+			if (ext != ".cs" && ext != ".vb") || strings.HasPrefix(name, `Properties\`) {
+				continue
+			}
+			files = append(files, scriptSourceFile{Name: name, Code: sourceCode.Elements[i+2]})
+		}
+	}
+	return files
+}
+
+// standardAssemblyPrefixes lists the .NET Framework and SQL Server runtime assembly namespaces available on every SSIS server
+var standardAssemblyPrefixes = []string{
+	"mscorlib", "netstandard", "System", "WindowsBase", "PresentationCore", "PresentationFramework",
+	"Microsoft.CSharp", "Microsoft.VisualBasic", "Microsoft.Win32", "Microsoft.SqlServer",
+}
+
+// isStandardAssembly reports whether an assembly reference is part of the .NET Framework or SQL Server runtime
+func isStandardAssembly(reference string) bool {
+	name := strings.TrimSpace(strings.Split(reference, ",")[0])
+	for _, prefix := range standardAssemblyPrefixes {
+		if name == prefix || strings.HasPrefix(name, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAssemblyReferences returns the assemblies named by MSBuild Reference items and AssemblyReference elements
+func parseAssemblyReferences(content string) []string {
+	var references []string
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || (start.Name.Local != "Reference" && start.Name.Local != "AssemblyReference") {
+			continue
+		}
+		for _, attr := range start.Attr {
+			if attr.Name.Local == "Include" || attr.Name.Local == "Name" || attr.Name.Local == "AssemblyName" {
+				if name := strings.TrimSpace(attr.Value); name != "" {
+					references = append(references, name)
+				}
+				break
+			}
+		}
+	}
+	return references
+}
+
+// isScriptProjectFile reports whether a script project file is the C# or VB.NET project holding assembly references
+func isScriptProjectFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".csproj" || ext == ".vbproj"
+}
+
+// scriptTaskAssemblyReferences returns the assemblies referenced by a Script Task's project
+func scriptTaskAssemblyReferences(task types.Task) []string {
+	var references []string
+	for _, project := range []types.ScriptProject{task.ObjectData.ScriptProject, task.ObjectData.ScriptTask.ScriptTaskData.ScriptProject} {
+		for _, item := range project.ProjectItems {
+			if isScriptProjectFile(item.Name) {
+				references = append(references, parseAssemblyReferences(item.Value)...)
+			}
+		}
+		if len(project.ProjectItems) == 0 {
+			references = append(references, parseAssemblyReferences(project.ScriptCode)...)
+		}
+	}
+	return references
+}
+
+// scriptComponentAssemblyReferences returns the assemblies referenced by the project file in a Script Component's SourceCode property
+func scriptComponentAssemblyReferences(component types.DataFlowComponent) []string {
+	var references []string
+	for _, prop := range component.Properties.Properties {
+		if prop.Name != "SourceCode" {
+			continue
+		}
+		var sourceCode struct {
+			Elements []string `xml:"arrayElements>arrayElement"`
+		}
+		if err := xml.Unmarshal([]byte("<property>"+prop.Value+"</property>"), &sourceCode); err != nil {
+			continue
+		}
+		for i := 0; i+2 < len(sourceCode.Elements); i += 3 {
+			if isScriptProjectFile(sourceCode.Elements[i]) {
+				references = append(references, parseAssemblyReferences(sourceCode.Elements[i+2])...)
+			}
+		}
+	}
+	return references
+}
+
+// formatAssemblyReferences lists assembly references, flagging those that must be deployed with the package
+func formatAssemblyReferences(references []string) string {
+	if len(references) == 0 {
+		return ""
+	}
+	var result strings.Builder
+	result.WriteString("Assembly References:\n")
+	for _, reference := range references {
+		if isStandardAssembly(reference) {
+			result.WriteString(fmt.Sprintf("  - %s\n", reference))
+		} else {
+			result.WriteString(fmt.Sprintf("  - %s (custom dependency: must be installed in the GAC of every server that runs the package)\n", reference))
+		}
+	}
+	return result.String()
+}
+
 // resolveVariableExpressions resolves SSIS variable expressions by substituting variable references
 func resolveVariableExpressions(value string, variables []types.Variable, maxDepth int) string {
 	if maxDepth <= 0 {
@@ -484,12 +1060,8 @@ func HandleXPathQuery(_ context.Context, request mcp.CallToolRequest, packageDir
 	// Handle output file if specified
 	if outputPath := request.GetString("output_file_path", ""); outputPath != "" {
 		resolvedOutputPath := ResolveFilePath(outputPath, packageDirectory)
-		outputDir := filepath.Dir(resolvedOutputPath)
-		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to create output directory: %v", err)), nil
-		}
-		if err := os.WriteFile(resolvedOutputPath, []byte(formatter.FormatAnalysisResult(result, format)), 0644); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to write output file: %v", err)), nil
+		if err := output.WriteOutput(resolvedOutputPath, formatter.FormatAnalysisResult(result, format)); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 	}
 
@@ -503,21 +1075,45 @@ func HandleReadTextFile(_ context.Context, request mcp.CallToolRequest, packageD
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 	isLineNumberNeeded := request.GetBool("line_numbers", true)
+	force := request.GetBool("force", false)
+	encodingName := request.GetString("encoding", "auto")
+	startLine := request.GetInt("start_line", 0)
+	endLine := request.GetInt("end_line", 0)
+	explicitEncoding := encodingName != "" && !strings.EqualFold(encodingName, "auto")
 
 	// Resolve the file path against the package directory
 	resolvedPath := file.ResolveFilePath(filePath, packageDirectory)
-	isBinary, err := file.IsFileBinary(resolvedPath)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to check if file is binary: %v", err)), nil
-	}
-	if isBinary {
-		return mcp.NewToolResultError("File is binary, cannot read as text"), nil
+	// An explicit encoding declares the file as text, which BOM-less UTF-16 content needs
+	if !force && !explicitEncoding {
+		contentType, size, err := file.DetectContentType(resolvedPath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to detect file type: %v", err)), nil
+		}
+		if !strings.HasPrefix(contentType, "text/") {
+			message := fmt.Sprintf("File appears to be binary (%s, %d bytes); set force=true to read it as text", contentType, size)
+			result := mcp.NewToolResultStructured(map[string]interface{}{
+				"tool_name":    "read_text_file",
+				"file_path":    filePath,
+				"timestamp":    time.Now().Format(time.RFC3339),
+				"status":       "error",
+				"error":        "file appears to be binary",
+				"content_type": contentType,
+				"size_bytes":   size,
+			}, message)
+			result.IsError = true
+			return result, nil
+		}
 	}
 	data, err := os.ReadFile(resolvedPath)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to read file: %v", err)), nil
 	}
 
+	content, detectedEncoding, err := file.DecodeText(data, encodingName)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	var result strings.Builder
 	result.WriteString("📄 Options\n\n")
 	result.WriteString(fmt.Sprintf("Line Numbers: %t\n", isLineNumberNeeded))
@@ -526,33 +1122,44 @@ func HandleReadTextFile(_ context.Context, request mcp.CallToolRequest, packageD
 	result.WriteString(fmt.Sprintf("File: %s\n", filepath.Base(resolvedPath)))
 	result.WriteString(fmt.Sprintf("Path: %s\n\n", resolvedPath))
 
-	content := string(data)
 	lines := strings.Split(content, "\n")
+	from, to, err := selectLineRange(len(lines), startLine, endLine)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	selectedLines := lines[from:to]
+	selectedContent := strings.Join(selectedLines, "\n")
+
 	result.WriteString("📊 File Statistics:\n")
+	result.WriteString(fmt.Sprintf("• Encoding: %s\n", detectedEncoding))
 	result.WriteString(fmt.Sprintf("• Total Lines: %d\n", len(lines)))
 	result.WriteString(fmt.Sprintf("• Total Characters: %d\n", len(content)))
-	result.WriteString(fmt.Sprintf("• File Size: %d bytes\n\n", len(data)))
+	result.WriteString(fmt.Sprintf("• File Size: %d bytes\n", len(data)))
+	if startLine > 0 || endLine > 0 {
+		result.WriteString(fmt.Sprintf("• Line Range: %d-%d\n", from+1, to))
+	}
+	result.WriteString("\n")
 
 	// Detect file type and parse accordingly
 	ext := strings.ToLower(filepath.Ext(resolvedPath))
 	switch ext {
 	case ".bat", ".cmd":
 		result.WriteString("🛠 Batch File Analysis:\n")
-		analysis.AnalyzeBatchFile(content, isLineNumberNeeded, &result)
+		analysis.AnalyzeBatchFile(selectedContent, isLineNumberNeeded, &result)
 	case ".config", ".cfg":
 		result.WriteString("⚙️ Configuration File Analysis:\n")
-		analysis.AnalyzeConfigFile(content, isLineNumberNeeded, &result)
+		analysis.AnalyzeConfigFile(selectedContent, isLineNumberNeeded, &result)
 	case ".sql":
 		result.WriteString("🗄️ SQL File Analysis:\n")
-		analysis.AnalyzeSQLFile(content, isLineNumberNeeded, &result)
+		analysis.AnalyzeSQLFile(selectedContent, isLineNumberNeeded, &result)
 	default:
 		result.WriteString("🗄️ Text File Analysis:\n")
-		analysis.AnalyzeGenericTextFile(content, isLineNumberNeeded, &result)
+		analysis.AnalyzeGenericTextFile(selectedContent, isLineNumberNeeded, &result)
 	}
 	result.WriteString("📘 File Content:\n")
-	for i, line := range lines {
+	for i, line := range selectedLines {
 		if isLineNumberNeeded {
-			result.WriteString(fmt.Sprintf("%d  %v\n", i, line))
+			result.WriteString(fmt.Sprintf("%d  %v\n", from+i+1, line))
 		} else {
 			result.WriteString(fmt.Sprintf("%v\n", line))
 		}
@@ -560,3 +1167,28 @@ func HandleReadTextFile(_ context.Context, request mcp.CallToolRequest, packageD
 	}
 	return mcp.NewToolResultText(result.String()), nil
 }
+
+// selectLineRange converts 1-based inclusive start/end lines into slice bounds; zero means unbounded
+// and an end line past the last line stops at end of file
+func selectLineRange(total, startLine, endLine int) (int, int, error) {
+	if startLine < 0 || endLine < 0 {
+		return 0, 0, fmt.Errorf("start_line and end_line must be positive")
+	}
+	from := 0
+	if startLine > 0 {
+		if startLine > total {
+			return 0, 0, fmt.Errorf("start_line %d exceeds file length of %d lines", startLine, total)
+		}
+		from = startLine - 1
+	}
+	to := total
+	if endLine > 0 {
+		if endLine < from+1 {
+			return 0, 0, fmt.Errorf("end_line %d is before start_line %d", endLine, from+1)
+		}
+		if endLine < total {
+			to = endLine
+		}
+	}
+	return from, to, nil
+}
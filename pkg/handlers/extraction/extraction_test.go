@@ -2,6 +2,7 @@ package extraction
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -109,6 +110,362 @@ func TestHandleExtractConnections(t *testing.T) {
 	}
 }
 
+func TestHandleExtractTasksTypeFilter(t *testing.T) {
+	dir := filepath.Join(repoRoot(t), "testdata")
+	extract := func(args map[string]interface{}) *mcp.CallToolResult {
+		args["file_path"] = "ControlFlow.dtsx"
+		result, err := HandleExtractTasks(context.Background(), createRequest(args), dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	sqlOnly := text(extract(map[string]interface{}{"task_type": "execute_sql"}))
+	if !strings.Contains(sqlOnly, "1. Truncate Staging") || !strings.Contains(sqlOnly, "2. Write Audit Row") {
+		t.Fatalf("expected Execute SQL Tasks, got %q", sqlOnly)
+	}
+	if strings.Contains(sqlOnly, "Load Sequence") || strings.Contains(sqlOnly, "Send Failure Mail") {
+		t.Fatalf("expected other task types to be filtered, got %q", sqlOnly)
+	}
+
+	combined := text(extract(map[string]interface{}{"task_type": "execute_sql, send_mail"}))
+	if !strings.Contains(combined, "Send Failure Mail") || !strings.Contains(combined, "Truncate Staging") {
+		t.Fatalf("expected both task types, got %q", combined)
+	}
+
+	negated := text(extract(map[string]interface{}{"task_type": "execute_sql", "negate": true}))
+	if strings.Contains(negated, "Truncate Staging") || !strings.Contains(negated, "Load Sequence") || !strings.Contains(negated, "Send Failure Mail") {
+		t.Fatalf("expected every task except Execute SQL Tasks, got %q", negated)
+	}
+
+	if result := extract(map[string]interface{}{"task_type": "not_a_task"}); !result.IsError {
+		t.Fatalf("expected unknown task type to return an error, got %q", text(result))
+	}
+}
+
+const connectionsPackage = `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:ObjectName="Connections">
+  <DTS:ConnectionManagers>
+    <DTS:ConnectionManager DTS:ObjectName="Warehouse" DTS:CreationName="OLEDB">
+      <DTS:ObjectData>
+        <DTS:ConnectionManager DTS:ConnectionString="Data Source=srv;Initial Catalog=DW;User ID=etl;Password=Secret123;Provider=SQLNCLI11.1;" />
+      </DTS:ObjectData>
+    </DTS:ConnectionManager>
+    <DTS:ConnectionManager DTS:ObjectName="Extract File" DTS:CreationName="FLATFILE">
+      <DTS:ObjectData>
+        <DTS:ConnectionManager DTS:ConnectionString="C:\\data\\extract.csv" />
+      </DTS:ObjectData>
+    </DTS:ConnectionManager>
+    <DTS:ConnectionManager DTS:ObjectName="Reporting" DTS:CreationName="ADO.NET:System.Data.SqlClient.SqlConnection, System.Data">
+      <DTS:ObjectData>
+        <DTS:ConnectionManager DTS:ConnectionString="Data Source=rpt;Initial Catalog=Reports;User ID=rpt;Password=Reports!2024;" />
+      </DTS:ObjectData>
+    </DTS:ConnectionManager>
+  </DTS:ConnectionManagers>
+</DTS:Executable>`
+
+func extractConnectionsText(t *testing.T, args map[string]interface{}) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Connections.dtsx"), []byte(connectionsPackage), 0o644); err != nil {
+		t.Fatalf("failed to write package: %v", err)
+	}
+	args["file_path"] = "Connections.dtsx"
+	result, err := HandleExtractConnections(context.Background(), createRequest(args), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return result.Content[0].(mcp.TextContent).Text
+}
+
+func TestHandleExtractPrecedenceConstraintsGraph(t *testing.T) {
+	path := testdataFile(t, "ControlFlow.dtsx")
+	request := createRequest(map[string]interface{}{
+		"file_path": filepath.Base(path),
+		"format":    "graph",
+	})
+	result, err := HandleExtractPrecedenceConstraints(context.Background(), request, filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError || result.StructuredContent == nil {
+		t.Fatalf("expected structured graph result, got %+v", result)
+	}
+
+	data, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		t.Fatalf("failed to marshal graph: %v", err)
+	}
+	var graph struct {
+		Nodes []map[string]string `json:"nodes"`
+		Edges []map[string]string `json:"edges"`
+	}
+	if err := json.Unmarshal(data, &graph); err != nil {
+		t.Fatalf("failed to decode graph JSON: %v", err)
+	}
+
+	wantNodes := map[string]string{
+		`Package\Truncate Staging`:             "task",
+		`Package\Load Sequence`:                "container",
+		`Package\Load Sequence\Load Customers`: "task",
+		`Package\Load Sequence\Load "Orders"`:  "task",
+		`Package\Send Failure Mail`:            "task",
+		`Package\Write Audit Row`:              "task",
+	}
+	if len(graph.Nodes) != len(wantNodes) {
+		t.Fatalf("expected %d nodes, got %d: %v", len(wantNodes), len(graph.Nodes), graph.Nodes)
+	}
+	for _, node := range graph.Nodes {
+		if wantNodes[node["id"]] != node["type"] {
+			t.Fatalf("unexpected node %v", node)
+		}
+	}
+
+	wantEdges := []map[string]string{
+		{"from": `Package\Load Sequence\Load Customers`, "to": `Package\Load Sequence\Load "Orders"`, "eval": "Success", "expression": ""},
+		{"from": `Package\Truncate Staging`, "to": `Package\Load Sequence`, "eval": "Success", "expression": ""},
+		{"from": `Package\Load Sequence`, "to": `Package\Send Failure Mail`, "eval": "Failure", "expression": ""},
+		{"from": `Package\Load Sequence`, "to": `Package\Write Audit Row`, "eval": "Completion", "expression": ""},
+	}
+	if len(graph.Edges) != len(wantEdges) {
+		t.Fatalf("expected %d edges, got %d: %v", len(wantEdges), len(graph.Edges), graph.Edges)
+	}
+	for i, want := range wantEdges {
+		for key, value := range want {
+			if graph.Edges[i][key] != value {
+				t.Fatalf("edge %d: expected %s=%q, got %v", i, key, value, graph.Edges[i])
+			}
+		}
+	}
+}
+
+func TestHandleExtractScriptCodeAssemblyReferences(t *testing.T) {
+	path := testdataFile(t, "ScriptAssemblies.dtsx")
+	request := createRequest(map[string]interface{}{
+		"file_path": filepath.Base(path),
+	})
+	result, err := HandleExtractScriptCode(context.Background(), request, filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	for _, want := range []string{
+		"Assembly References:\n  - System\n  - System.Data\n",
+		"  - Microsoft.SqlServer.ManagedDTS, Version=16.0.0.0, Culture=neutral, PublicKeyToken=89845dcd8080cc91\n",
+		"  - Contoso.Pricing.Client, Version=2.3.0.0, Culture=neutral, PublicKeyToken=4f2a7c9e1b3d5f60 (custom dependency: must be installed in the GAC of every server that runs the package)\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in output, got %q", want, text)
+		}
+	}
+	if strings.Count(text, "custom dependency") != 1 {
+		t.Fatalf("expected only the Contoso assembly to be flagged, got %q", text)
+	}
+}
+
+func TestHandleExtractScriptCodeScriptComponents(t *testing.T) {
+	path := testdataFile(t, "Scanner.dtsx")
+	request := createRequest(map[string]interface{}{
+		"file_path": filepath.Base(path),
+	})
+	result, err := HandleExtractScriptCode(context.Background(), request, filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	textContent, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[0])
+	}
+	for _, want := range []string{
+		"Script Components Code:",
+		"Script Component 1: GetAllEnvVar",
+		"Type: Script Component",
+		"Data Flow Task: GetAllEnvironmentVariablesFlow",
+		"File: main.cs",
+		"public class ScriptMain : UserComponent",
+	} {
+		if !strings.Contains(textContent.Text, want) {
+			t.Fatalf("expected %q in output, got %q", want, textContent.Text)
+		}
+	}
+	if strings.Contains(textContent.Text, "File: Properties") {
+		t.Fatalf("expected project property files to be skipped, got %q", textContent.Text)
+	}
+}
+
+func TestHandleExtractConnectionsMasksPasswords(t *testing.T) {
+	text := extractConnectionsText(t, map[string]interface{}{})
+	if strings.Contains(text, "Secret123") || strings.Contains(text, "Reports!2024") {
+		t.Fatalf("expected passwords to be masked by default, got %q", text)
+	}
+	if !strings.Contains(text, "User ID=etl;Password=Se*****23;Provider=SQLNCLI11.1;") {
+		t.Fatalf("expected masked OLE DB connection string, got %q", text)
+	}
+
+	unmasked := extractConnectionsText(t, map[string]interface{}{"mask_passwords": false})
+	if !strings.Contains(unmasked, "Password=Secret123;") {
+		t.Fatalf("expected plain password when mask_passwords is false, got %q", unmasked)
+	}
+}
+
+func TestHandleExtractConnectionsTypeFilter(t *testing.T) {
+	text := extractConnectionsText(t, map[string]interface{}{"connection_type": "oledb"})
+	if !strings.Contains(text, "1. Warehouse") || strings.Contains(text, "Extract File") || strings.Contains(text, "Reporting") {
+		t.Fatalf("expected only OLE DB connections, got %q", text)
+	}
+
+	text = extractConnectionsText(t, map[string]interface{}{"connection_type": "flatfile, ado.net"})
+	if strings.Contains(text, "Warehouse") || !strings.Contains(text, "1. Extract File") || !strings.Contains(text, "2. Reporting") {
+		t.Fatalf("expected flat file and ADO.NET connections, got %q", text)
+	}
+}
+
+func TestHandleExtractConnectionsResolvesConnectionStringExpression(t *testing.T) {
+	path := testdataFile(t, "ConnectionExpressions.dtsx")
+	request := createRequest(map[string]interface{}{
+		"file_path": filepath.Base(path),
+	})
+	result, err := HandleExtractConnections(context.Background(), request, filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, `Connection String Expression: "Data Source=" + @[User::ServerName] + ";Initial Catalog=" + @[User::DatabaseName]`) {
+		t.Fatalf("expected connection string expression, got %q", text)
+	}
+	if !strings.Contains(text, "Resolved Connection String: Data Source=PRODSQL01;Initial Catalog=Warehouse;Provider=SQLNCLI11.1;Integrated Security=SSPI;") {
+		t.Fatalf("expected resolved connection string, got %q", text)
+	}
+}
+
+func TestResolveStringExpression(t *testing.T) {
+	variables := []types.Variable{{Name: "Folder", Value: `C:\Data`}, {Name: "File", Value: "orders.csv"}}
+	cases := map[string]string{
+		`@[User::Folder] + "\\" + @[User::File]`: `C:\Data\orders.csv`,
+		`"Data Source=" + @[User::Missing]`:      "Data Source=@[User::Missing]",
+		`UPPER(@[User::File])`:                   "UPPER(orders.csv)",
+		`"a+b" + @[User::File]`:                  "a+borders.csv",
+	}
+	for expression, expected := range cases {
+		if resolved := resolveStringExpression(expression, variables); resolved != expected {
+			t.Fatalf("resolveStringExpression(%q) = %q, want %q", expression, resolved, expected)
+		}
+	}
+}
+
+func TestDescribeParameterDataType(t *testing.T) {
+	expected := map[string]string{
+		"2":  "DT_NULL (2, DBNull)",
+		"3":  "DT_BOOL (3, Boolean)",
+		"4":  "DT_UI2 (4, Char)",
+		"5":  "DT_I1 (5, SByte)",
+		"6":  "DT_UI1 (6, Byte)",
+		"7":  "DT_I2 (7, Int16)",
+		"8":  "DT_UI2 (8, UInt16)",
+		"9":  "DT_I4 (9, Int32)",
+		"10": "DT_UI4 (10, UInt32)",
+		"11": "DT_I8 (11, Int64)",
+		"12": "DT_UI8 (12, UInt64)",
+		"13": "DT_R4 (13, Single)",
+		"14": "DT_R8 (14, Double)",
+		"15": "DT_DECIMAL (15, Decimal)",
+		"16": "DT_DBTIMESTAMP (16, DateTime)",
+		"18": "DT_WSTR (18, String)",
+		"99": "99",
+		"":   "Unknown",
+	}
+	for code, want := range expected {
+		if got := describeParameterDataType(code); got != want {
+			t.Fatalf("describeParameterDataType(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestHandleExtractParametersDataTypeNames(t *testing.T) {
+	dir := t.TempDir()
+	contents := `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:ObjectName="Params">
+  <DTS:Parameters>
+    <DTS:Parameter DTS:ObjectName="ServerName" DTS:DataType="18" DTS:Required="True" />
+    <DTS:Parameter DTS:ObjectName="BatchSize" DTS:DataType="9" />
+  </DTS:Parameters>
+</DTS:Executable>`
+	if err := os.WriteFile(filepath.Join(dir, "Params.dtsx"), []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write package: %v", err)
+	}
+	result, err := HandleExtractParameters(context.Background(), createRequest(map[string]interface{}{"file_path": "Params.dtsx"}), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	for _, want := range []string{"Data Type: DT_WSTR (18, String)", "Data Type: DT_I4 (9, Int32)"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in output, got %q", want, text)
+		}
+	}
+}
+
+func TestHandleExtractVariablesScope(t *testing.T) {
+	dir := t.TempDir()
+	contents := `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:ObjectName="Scopes">
+  <DTS:Variables>
+    <DTS:Variable DTS:ObjectName="RunDate"><DTS:VariableValue>2024-01-01</DTS:VariableValue></DTS:Variable>
+  </DTS:Variables>
+  <DTS:Executables>
+    <DTS:Executable DTS:ObjectName="Load Sequence" DTS:CreationName="STOCK:SEQUENCE">
+      <DTS:Variables>
+        <DTS:Variable DTS:ObjectName="BatchId"><DTS:VariableValue>7</DTS:VariableValue></DTS:Variable>
+      </DTS:Variables>
+      <DTS:Executables>
+        <DTS:Executable DTS:ObjectName="Load Orders" DTS:CreationName="Microsoft.Pipeline">
+          <DTS:Variables>
+            <DTS:Variable DTS:ObjectName="RowCount"><DTS:VariableValue>0</DTS:VariableValue></DTS:Variable>
+          </DTS:Variables>
+        </DTS:Executable>
+      </DTS:Executables>
+    </DTS:Executable>
+  </DTS:Executables>
+  <DTS:EventHandlers>
+    <DTS:EventHandler DTS:EventName="OnError">
+      <DTS:Variables>
+        <DTS:Variable DTS:ObjectName="Propagate"><DTS:VariableValue>true</DTS:VariableValue></DTS:Variable>
+      </DTS:Variables>
+      <DTS:Executables>
+        <DTS:Executable DTS:ObjectName="Log Failure" DTS:CreationName="Microsoft.ExecuteSQLTask">
+          <DTS:Variables>
+            <DTS:Variable DTS:ObjectName="ErrorText"><DTS:VariableValue /></DTS:Variable>
+          </DTS:Variables>
+        </DTS:Executable>
+      </DTS:Executables>
+    </DTS:EventHandler>
+  </DTS:EventHandlers>
+</DTS:Executable>`
+	if err := os.WriteFile(filepath.Join(dir, "Scopes.dtsx"), []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write package: %v", err)
+	}
+	result, err := HandleExtractVariables(context.Background(), createRequest(map[string]interface{}{"file_path": "Scopes.dtsx"}), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	for _, want := range []string{
+		"1. RunDate = 2024-01-01\n   Scope: Package",
+		"2. BatchId = 7\n   Scope: Load Sequence",
+		"3. RowCount = 0\n   Scope: Load Orders",
+		"4. Propagate = true\n   Scope: Package.EventHandlers[OnError]",
+		"5. ErrorText = \n   Scope: Log Failure",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in output, got %q", want, text)
+		}
+	}
+}
+
 func TestHandleXPathQuery(t *testing.T) {
 	// Test with raw XML string
 	xmlContent := `<root><item id="1">First</item><item id="2">Second</item></root>`
@@ -150,3 +507,276 @@ func TestHandleXPathQuery(t *testing.T) {
 		t.Fatal("expected error result for invalid xpath")
 	}
 }
+
+func TestHandleXPathQueryCreatesOutputDirectories(t *testing.T) {
+	dir := t.TempDir()
+	request := createRequest(map[string]interface{}{
+		"xpath":            "//item",
+		"xml":              `<root><item>First</item></root>`,
+		"output_file_path": filepath.Join("reports", "xpath", "items.txt"),
+	})
+
+	result, err := HandleXPathQuery(context.Background(), request, dir)
+	if err != nil {
+		t.Fatalf("HandleXPathQuery failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got %+v", result.Content)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "reports", "xpath", "items.txt"))
+	if err != nil {
+		t.Fatalf("expected output file to be created: %v", err)
+	}
+	if !strings.Contains(string(data), "First") {
+		t.Fatalf("expected query results in output file, got %q", string(data))
+	}
+}
+
+func TestHandleReadTextFileBinaryDetection(t *testing.T) {
+	dir := t.TempDir()
+	binary := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+	if err := os.WriteFile(filepath.Join(dir, "image.raw"), binary, 0o644); err != nil {
+		t.Fatalf("failed to write binary file: %v", err)
+	}
+
+	result, err := HandleReadTextFile(context.Background(), createRequest(map[string]interface{}{
+		"file_path": "image.raw",
+	}), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected binary file to be rejected")
+	}
+	structured, ok := result.StructuredContent.(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected structured error, got %T", result.StructuredContent)
+	}
+	if structured["content_type"] != "image/png" || structured["size_bytes"] != int64(len(binary)) {
+		t.Fatalf("unexpected binary details: %+v", structured)
+	}
+
+	forced, err := HandleReadTextFile(context.Background(), createRequest(map[string]interface{}{
+		"file_path": "image.raw",
+		"force":     true,
+	}), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if forced.IsError {
+		t.Fatalf("expected force to bypass binary detection, got %+v", forced.Content)
+	}
+}
+
+func TestHandleReadTextFileUTF16(t *testing.T) {
+	dir := t.TempDir()
+	data := []byte{0xFF, 0xFE}
+	for _, r := range "SELECT 'Café'" {
+		data = append(data, byte(r), 0)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "query.sql"), data, 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	result, err := HandleReadTextFile(context.Background(), createRequest(map[string]interface{}{
+		"file_path": "query.sql",
+	}), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	textContent, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[0])
+	}
+	if !strings.Contains(textContent.Text, "Encoding: utf-16le") || !strings.Contains(textContent.Text, "SELECT 'Café'") {
+		t.Fatalf("expected decoded UTF-16 content, got %q", textContent.Text)
+	}
+}
+
+func TestHandleReadTextFileLineRange(t *testing.T) {
+	dir := t.TempDir()
+	content := "line one\nline two\nline three\nline four\nline five"
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		start    int
+		end      int
+		expected []string
+		excluded []string
+	}{
+		{"start", 1, 2, []string{"1  line one", "2  line two"}, []string{"line three"}},
+		{"middle", 2, 4, []string{"2  line two", "4  line four"}, []string{"line one", "line five"}},
+		{"end", 4, 5, []string{"4  line four", "5  line five"}, []string{"line three"}},
+		{"end beyond eof", 3, 50, []string{"3  line three", "5  line five", "Line Range: 3-5"}, []string{"line two"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := HandleReadTextFile(context.Background(), createRequest(map[string]interface{}{
+				"file_path":  "notes.txt",
+				"start_line": float64(tt.start),
+				"end_line":   float64(tt.end),
+			}), dir)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			text := result.Content[0].(mcp.TextContent).Text
+			if result.IsError {
+				t.Fatalf("unexpected error result: %s", text)
+			}
+			content := text[strings.Index(text, "File Content:"):]
+			for _, want := range tt.expected {
+				if !strings.Contains(text, want) {
+					t.Fatalf("expected %q in output, got %q", want, text)
+				}
+			}
+			for _, unwanted := range tt.excluded {
+				if strings.Contains(content, unwanted) {
+					t.Fatalf("did not expect %q in content, got %q", unwanted, content)
+				}
+			}
+		})
+	}
+
+	result, err := HandleReadTextFile(context.Background(), createRequest(map[string]interface{}{
+		"file_path":  "notes.txt",
+		"start_line": float64(10),
+	}), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected error when start_line is past the end of the file")
+	}
+}
+
+func TestHandleParseDtsxPackageVersion(t *testing.T) {
+	const dtsx = `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:ObjectName="Versioned"
+  DTS:CreationDate="10/25/2024 5:22:36 PM" DTS:VersionBuild="24" DTS:VersionMajor="2" DTS:VersionMinor="3" DTS:VersionComments="Added audit columns">
+</DTS:Executable>`
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Versioned.dtsx"), []byte(dtsx), 0o644); err != nil {
+		t.Fatalf("failed to write package: %v", err)
+	}
+
+	result, err := HandleParseDtsx(context.Background(), createRequest(map[string]interface{}{"file_path": "Versioned.dtsx", "format": "json"}), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var output struct {
+		Data struct {
+			PackageVersion map[string]string `json:"package_version"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output); err != nil {
+		t.Fatalf("failed to decode JSON output: %v", err)
+	}
+	expected := map[string]string{
+		"version_major":    "2",
+		"version_minor":    "3",
+		"version_build":    "24",
+		"version_comments": "Added audit columns",
+		"creation_date":    "10/25/2024 5:22:36 PM",
+	}
+	for key, value := range expected {
+		if output.Data.PackageVersion[key] != value {
+			t.Fatalf("expected %s=%q, got %+v", key, value, output.Data.PackageVersion)
+		}
+	}
+}
+
+func TestHandleExtractCheckpointConfig(t *testing.T) {
+	path := testdataFile(t, "Checkpoint.dtsx")
+	result, err := HandleExtractCheckpointConfig(context.Background(), createRequest(map[string]interface{}{"file_path": path}), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	for _, expected := range []string{
+		"Save Checkpoints: true",
+		`Checkpoint File: C:\SSIS\Checkpoints\Checkpoint.chk`,
+		"Checkpoint Usage: IfExists",
+		"Restart Behavior: The package restarts from the checkpoint file when it exists",
+		"is absolute and environment-specific",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Fatalf("expected %q in output, got:\n%s", expected, text)
+		}
+	}
+}
+
+func TestHandleExtractCheckpointConfigFormatAndOutputFile(t *testing.T) {
+	path := testdataFile(t, "Checkpoint.dtsx")
+	outputPath := filepath.Join(t.TempDir(), "checkpoint.json")
+	result, err := HandleExtractCheckpointConfig(context.Background(), createRequest(map[string]interface{}{
+		"file_path":        path,
+		"format":           "json",
+		"output_file_path": outputPath,
+	}), "")
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected failure: %v %+v", err, result)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+
+	var decoded struct {
+		ToolName string `json:"tool_name"`
+		Data     struct {
+			Enabled bool     `json:"enabled"`
+			Usage   string   `json:"checkpoint_usage"`
+			Issues  []string `json:"issues"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(text), &decoded); err != nil {
+		t.Fatalf("expected JSON output, got %q: %v", text, err)
+	}
+	if decoded.ToolName != "Checkpoint Configuration" || !decoded.Data.Enabled || decoded.Data.Usage != "IfExists" || len(decoded.Data.Issues) != 1 {
+		t.Fatalf("unexpected JSON result: %+v", decoded)
+	}
+	if result.StructuredContent == nil {
+		t.Fatal("expected the JSON result to be returned as structured content")
+	}
+
+	written, err := os.ReadFile(outputPath)
+	if err != nil || string(written) != text {
+		t.Fatalf("expected the formatted result in %s, got %q (err=%v)", outputPath, string(written), err)
+	}
+
+	result, err = HandleExtractCheckpointConfig(context.Background(), createRequest(map[string]interface{}{"file_path": path, "format": "markdown"}), "")
+	if err != nil || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "Checkpoint Usage: IfExists") {
+		t.Fatalf("expected a markdown report, got %+v (err=%v)", result, err)
+	}
+}
+
+func TestBuildCheckpointReport(t *testing.T) {
+	report := buildCheckpointReport(types.Checkpoint{})
+	if report.Enabled || report.Usage != "Never" || len(report.Issues) != 0 {
+		t.Fatalf("expected checkpointing to be disabled without issues, got %+v", report)
+	}
+
+	report = buildCheckpointReport(types.Checkpoint{SaveCheckpoints: "True", CheckpointFileName: "checkpoints/run.chk", CheckpointUsage: "1"})
+	if !report.Enabled || report.Usage != "Always" || len(report.Issues) != 0 {
+		t.Fatalf("expected a relative checkpoint path to pass, got %+v", report)
+	}
+
+	report = buildCheckpointReport(types.Checkpoint{SaveCheckpoints: "True", CheckpointUsage: "2"})
+	if len(report.Issues) != 1 || !strings.Contains(report.Issues[0], "no CheckpointFileName") {
+		t.Fatalf("expected a missing file name issue, got %+v", report)
+	}
+}
+
+func TestBuildPrecedenceGraphExpressionOnlyConstraint(t *testing.T) {
+	pkg := &types.SSISPackage{PrecedenceConstraints: types.PrecedenceConstraints{Constraints: []types.PrecedenceConstraint{
+		{From: `Package\Check`, To: `Package\Alert`, EvalOp: "1", Expression: "@[User::Count] > 0"},
+		{From: `Package\Check`, To: `Package\Load`, EvalOp: "2", Value: "1"},
+	}}}
+
+	graph := buildPrecedenceGraph(pkg)
+	if len(graph.Edges) != 2 || graph.Edges[0].Eval != "Expression" || graph.Edges[1].Eval != "Failure" {
+		t.Fatalf("expected an expression-only edge and a failure edge, got %+v", graph.Edges)
+	}
+}
@@ -0,0 +1,236 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/MCPRUNNER/gossisMCP/pkg/util/file"
+	"github.com/MCPRUNNER/gossisMCP/pkg/util/output"
+)
+
+// Options controls where the file management tools may modify files
+type Options struct {
+	// AllowAbsolutePaths permits targets outside the package directory
+	AllowAbsolutePaths bool
+}
+
+// ResolveWritablePath resolves a target or source path against the package directory and rejects
+// paths that escape it, directly or through a symlink, unless absolute paths are allowed
+func ResolveWritablePath(path, packageDirectory string, options Options) (string, error) {
+	if strings.TrimSpace(path) == "" {
+		return "", fmt.Errorf("path must not be empty")
+	}
+	resolved := filepath.Clean(file.ResolveFilePath(path, packageDirectory))
+	if options.AllowAbsolutePaths {
+		return resolved, nil
+	}
+
+	root := packageDirectory
+	if root == "" {
+		if filepath.IsAbs(path) {
+			return "", fmt.Errorf("absolute path %s is not allowed; set packages.allow_absolute_paths to enable it", path)
+		}
+		root = "."
+	}
+	realRoot, err := evalExistingPath(filepath.Clean(root))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve package directory %s: %w", root, err)
+	}
+	realPath, err := evalExistingPath(resolved)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path %s: %w", path, err)
+	}
+	rel, err := filepath.Rel(realRoot, realPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %s is outside the package directory; set packages.allow_absolute_paths to enable it", path)
+	}
+	return resolved, nil
+}
+
+// evalExistingPath returns the absolute path with symlinks resolved in its deepest existing ancestor, followed
+// by the components that do not exist yet, so a file can be checked before it is created
+func evalExistingPath(path string) (string, error) {
+	current, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	var missing []string
+	for {
+		if _, err := os.Lstat(current); err == nil {
+			// A dangling symlink fails here rather than being written through
+			linked, err := filepath.EvalSymlinks(current)
+			if err != nil {
+				return "", err
+			}
+			return filepath.Join(append([]string{linked}, missing...)...), nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return filepath.Join(append([]string{current}, missing...)...), nil
+		}
+		missing = append([]string{filepath.Base(current)}, missing...)
+		current = parent
+	}
+}
+
+// HandleWriteTextFile writes, appends or prepends text content to a file
+func HandleWriteTextFile(_ context.Context, request mcp.CallToolRequest, packageDirectory string, options Options) (*mcp.CallToolResult, error) {
+	filePath, err := request.RequireString("file_path")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	targetPath, err := ResolveWritablePath(filePath, packageDirectory, options)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	content := request.GetString("content", "")
+	if contentFilePath := request.GetString("content_file_path", ""); contentFilePath != "" {
+		if content != "" {
+			return mcp.NewToolResultError("provide either content or content_file_path, not both"), nil
+		}
+		// The source is held to the same sandbox as the target so files outside the package directory
+		// cannot be copied into it
+		sourcePath, err := ResolveWritablePath(contentFilePath, packageDirectory, options)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		data, err := os.ReadFile(sourcePath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to read content file: %v", err)), nil
+		}
+		content = string(data)
+	}
+
+	mode := strings.ToLower(request.GetString("mode", "overwrite"))
+	if mode != "overwrite" && mode != "append" && mode != "prepend" {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid mode: %s (supported: overwrite, append, prepend)", mode)), nil
+	}
+
+	existing, err := os.ReadFile(targetPath)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to read existing file: %v", err)), nil
+	}
+
+	var result strings.Builder
+	if request.GetBool("create_backup", false) && exists {
+		backupPath := targetPath + ".bak"
+		if err := output.WriteOutput(backupPath, string(existing)); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to create backup: %v", err)), nil
+		}
+		result.WriteString(fmt.Sprintf("Backup: %s\n", backupPath))
+	}
+
+	newContent := content
+	switch mode {
+	case "append":
+		newContent = string(existing) + content
+	case "prepend":
+		newContent = content + string(existing)
+	}
+
+	if err := output.WriteOutput(targetPath, newContent); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result.WriteString(fmt.Sprintf("Wrote %d bytes to %s (mode: %s)\n", len(newContent), targetPath, mode))
+	return mcp.NewToolResultText(result.String()), nil
+}
+
+// HandleDeleteFile deletes one or more files; missing files are reported but not treated as errors
+func HandleDeleteFile(_ context.Context, request mcp.CallToolRequest, packageDirectory string, options Options) (*mcp.CallToolResult, error) {
+	paths := request.GetStringSlice("file_paths", nil)
+	if filePath := request.GetString("file_path", ""); filePath != "" {
+		paths = append([]string{filePath}, paths...)
+	}
+	if len(paths) == 0 {
+		return mcp.NewToolResultError("file_path or file_paths is required"), nil
+	}
+
+	var result strings.Builder
+	deleted, missing := 0, 0
+	for _, path := range paths {
+		targetPath, err := ResolveWritablePath(path, packageDirectory, options)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		info, err := os.Stat(targetPath)
+		if os.IsNotExist(err) {
+			slog.Info("file not found", "tool", "delete_file", "file", targetPath)
+			result.WriteString(fmt.Sprintf("Not found: %s\n", targetPath))
+			missing++
+			continue
+		}
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to access %s: %v", targetPath, err)), nil
+		}
+		if info.IsDir() {
+			return mcp.NewToolResultError(fmt.Sprintf("%s is a directory, not a file", targetPath)), nil
+		}
+
+		if err := os.Remove(targetPath); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to delete %s: %v", targetPath, err)), nil
+		}
+		slog.Info("file deleted", "tool", "delete_file", "file", targetPath)
+		result.WriteString(fmt.Sprintf("Deleted: %s\n", targetPath))
+		deleted++
+	}
+
+	result.WriteString(fmt.Sprintf("Deleted %d file(s), %d not found\n", deleted, missing))
+	return mcp.NewToolResultText(result.String()), nil
+}
+
+// HandleMoveFile moves or renames a file, creating the destination directory if needed
+func HandleMoveFile(_ context.Context, request mcp.CallToolRequest, packageDirectory string, options Options) (*mcp.CallToolResult, error) {
+	sourcePath, err := request.RequireString("source_path")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	destinationPath, err := request.RequireString("destination_path")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	source, err := ResolveWritablePath(sourcePath, packageDirectory, options)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	destination, err := ResolveWritablePath(destinationPath, packageDirectory, options)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to access source file: %v", err)), nil
+	}
+	if info.IsDir() {
+		return mcp.NewToolResultError(fmt.Sprintf("%s is a directory, not a file", source)), nil
+	}
+	if _, err := os.Stat(destination); err == nil && !request.GetBool("overwrite", false) {
+		return mcp.NewToolResultError(fmt.Sprintf("destination %s already exists; set overwrite to replace it", destination)), nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to create destination directory: %v", err)), nil
+	}
+	if err := os.Rename(source, destination); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to move file: %v", err)), nil
+	}
+
+	slog.Info("file moved", "tool", "move_file", "file", source, "destination", destination)
+	return mcp.NewToolResultText(fmt.Sprintf("Moved %s to %s\n", source, destination)), nil
+}
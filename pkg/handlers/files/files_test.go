@@ -0,0 +1,249 @@
+package files
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func createRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: args,
+		},
+	}
+}
+
+func TestResolveWritablePath(t *testing.T) {
+	root := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "other.txt")
+
+	if got, err := ResolveWritablePath("nested/file.sql", root, Options{}); err != nil || got != filepath.Join(root, "nested", "file.sql") {
+		t.Fatalf("expected relative path inside package directory, got %s (err=%v)", got, err)
+	}
+	if _, err := ResolveWritablePath("../escape.txt", root, Options{}); err == nil {
+		t.Fatal("expected path escaping the package directory to be rejected")
+	}
+	if _, err := ResolveWritablePath(outside, root, Options{}); err == nil {
+		t.Fatal("expected absolute path outside the package directory to be rejected")
+	}
+	if got, err := ResolveWritablePath(outside, root, Options{AllowAbsolutePaths: true}); err != nil || got != outside {
+		t.Fatalf("expected absolute path to be allowed, got %s (err=%v)", got, err)
+	}
+}
+
+func TestResolveWritablePathFollowsSymlinks(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "packages")
+	outside := filepath.Join(base, "outside")
+	for _, dir := range []string{root, outside} {
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Skipf("symlinks are not supported: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "secret.txt"), filepath.Join(root, "dangling.txt")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	for _, path := range []string{"escape/new.txt", "escape/nested/new.txt", "dangling.txt"} {
+		if _, err := ResolveWritablePath(path, root, Options{}); err == nil {
+			t.Fatalf("expected %s to be rejected because it resolves outside the package directory", path)
+		}
+	}
+
+	// A package directory reached through a symlink still accepts paths inside it
+	linkedRoot := filepath.Join(base, "linked")
+	if err := os.Symlink(root, linkedRoot); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	if got, err := ResolveWritablePath("nested/file.sql", linkedRoot, Options{}); err != nil || got != filepath.Join(linkedRoot, "nested", "file.sql") {
+		t.Fatalf("expected a path inside the linked package directory, got %s (err=%v)", got, err)
+	}
+}
+
+func TestHandleWriteTextFileModes(t *testing.T) {
+	root := t.TempDir()
+	target := filepath.Join(root, "sql", "query.sql")
+
+	steps := []struct {
+		args     map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{"content": "SELECT 1;"}, "SELECT 1;"},
+		{map[string]interface{}{"content": "\nSELECT 2;", "mode": "append"}, "SELECT 1;\nSELECT 2;"},
+		{map[string]interface{}{"content": "-- header\n", "mode": "prepend", "create_backup": true}, "-- header\nSELECT 1;\nSELECT 2;"},
+	}
+	for _, step := range steps {
+		step.args["file_path"] = "sql/query.sql"
+		result, err := HandleWriteTextFile(context.Background(), createRequest(step.args), root, Options{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.IsError {
+			t.Fatalf("unexpected error result: %+v", result.Content)
+		}
+		data, err := os.ReadFile(target)
+		if err != nil {
+			t.Fatalf("failed to read target: %v", err)
+		}
+		if string(data) != step.expected {
+			t.Fatalf("expected %q, got %q", step.expected, string(data))
+		}
+	}
+
+	backup, err := os.ReadFile(target + ".bak")
+	if err != nil {
+		t.Fatalf("expected backup file: %v", err)
+	}
+	if string(backup) != "SELECT 1;\nSELECT 2;" {
+		t.Fatalf("expected backup of previous content, got %q", string(backup))
+	}
+}
+
+func TestHandleWriteTextFileFromContentFile(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "source.config"), []byte("<Configuration />"), 0o644); err != nil {
+		t.Fatalf("failed to write source: %v", err)
+	}
+
+	result, err := HandleWriteTextFile(context.Background(), createRequest(map[string]interface{}{
+		"file_path":         "copy.config",
+		"content_file_path": "source.config",
+	}), root, Options{})
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected failure: %v %+v", err, result)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "copy.config"))
+	if err != nil || string(data) != "<Configuration />" {
+		t.Fatalf("expected copied content, got %q (err=%v)", string(data), err)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "Wrote 17 bytes") {
+		t.Fatalf("expected the copied byte count, got %q", text)
+	}
+
+	rejected, err := HandleWriteTextFile(context.Background(), createRequest(map[string]interface{}{
+		"file_path": filepath.Join(t.TempDir(), "outside.txt"),
+		"content":   "x",
+	}), root, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !rejected.IsError {
+		t.Fatal("expected write outside the package directory to be rejected")
+	}
+}
+
+func TestHandleWriteTextFileRejectsContentFileOutsidePackageDirectory(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "packages")
+	if err := os.Mkdir(root, 0o755); err != nil {
+		t.Fatalf("failed to create package directory: %v", err)
+	}
+	secret := filepath.Join(parent, "secret.txt")
+	if err := os.WriteFile(secret, []byte("password=hunter2"), 0o644); err != nil {
+		t.Fatalf("failed to write secret: %v", err)
+	}
+
+	for _, source := range []string{"../secret.txt", secret} {
+		result, err := HandleWriteTextFile(context.Background(), createRequest(map[string]interface{}{
+			"file_path":         "copy.txt",
+			"content_file_path": source,
+		}), root, Options{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "outside the package directory") {
+			t.Fatalf("expected content_file_path %s to be rejected, got %+v", source, result.Content)
+		}
+		if _, err := os.Stat(filepath.Join(root, "copy.txt")); !os.IsNotExist(err) {
+			t.Fatalf("expected nothing to be written for content_file_path %s", source)
+		}
+	}
+}
+
+func TestHandleWriteTextFileReportsBytesWritten(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "log.txt"), []byte("first\n"), 0o644); err != nil {
+		t.Fatalf("failed to write log: %v", err)
+	}
+
+	result, err := HandleWriteTextFile(context.Background(), createRequest(map[string]interface{}{
+		"file_path": "log.txt",
+		"content":   "second\n",
+		"mode":      "append",
+	}), root, Options{})
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected failure: %v %+v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "Wrote 13 bytes") {
+		t.Fatalf("expected the size of the written file, got %q", text)
+	}
+}
+
+func TestHandleDeleteFile(t *testing.T) {
+	root := t.TempDir()
+	existing := filepath.Join(root, "intermediate.json")
+	if err := os.WriteFile(existing, []byte("{}"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	result, err := HandleDeleteFile(context.Background(), createRequest(map[string]interface{}{
+		"file_paths": []interface{}{"intermediate.json", "missing.json"},
+	}), root, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error result: %+v", result.Content)
+	}
+	if _, err := os.Stat(existing); !os.IsNotExist(err) {
+		t.Fatalf("expected file to be deleted, stat err=%v", err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "Deleted 1 file(s), 1 not found") {
+		t.Fatalf("expected deletion summary, got %q", text)
+	}
+
+	rejected, err := HandleDeleteFile(context.Background(), createRequest(map[string]interface{}{
+		"file_path": "../outside.json",
+	}), root, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !rejected.IsError {
+		t.Fatal("expected deletion outside the package directory to be rejected")
+	}
+}
+
+func TestHandleMoveFileAcrossSubdirectories(t *testing.T) {
+	root := t.TempDir()
+	source := filepath.Join(root, "staging", "report.json")
+	if err := os.MkdirAll(filepath.Dir(source), 0o755); err != nil {
+		t.Fatalf("failed to create staging dir: %v", err)
+	}
+	if err := os.WriteFile(source, []byte(`{"ok":true}`), 0o644); err != nil {
+		t.Fatalf("failed to write source: %v", err)
+	}
+
+	result, err := HandleMoveFile(context.Background(), createRequest(map[string]interface{}{
+		"source_path":      "staging/report.json",
+		"destination_path": "archive/2024/report.json",
+	}), root, Options{})
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected failure: %v %+v", err, result)
+	}
+	if _, err := os.Stat(source); !os.IsNotExist(err) {
+		t.Fatalf("expected source to be removed, stat err=%v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "archive", "2024", "report.json"))
+	if err != nil || string(data) != `{"ok":true}` {
+		t.Fatalf("expected moved content, got %q (err=%v)", string(data), err)
+	}
+}
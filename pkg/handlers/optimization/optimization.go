@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -83,6 +85,11 @@ func HandleOptimizeBufferSize(_ context.Context, request mcp.CallToolRequest, pa
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse XML: %v", err)), nil
 	}
 
+	assumedVarcharLength := request.GetInt("assumed_varchar_length", defaultAssumedVarcharLength)
+	if assumedVarcharLength < 1 {
+		return mcp.NewToolResultError("assumed_varchar_length must be at least 1"), nil
+	}
+
 	var result strings.Builder
 	result.WriteString("🔄 Buffer Size Optimization Analysis:\n\n")
 
@@ -112,6 +119,16 @@ func HandleOptimizeBufferSize(_ context.Context, request mcp.CallToolRequest, pa
 
 				result.WriteString(fmt.Sprintf("  Components: %d\n", componentCount))
 
+				// Size the buffer from the output column widths
+				rowSize, columnCount := estimateRowSize(task.ObjectData.DataFlow.Components.Components, assumedVarcharLength)
+				if rowSize > 0 {
+					maxRows := bufferMaxRows(bufferSettings)
+					recommended := recommendedBufferSize(rowSize, maxRows)
+					result.WriteString(fmt.Sprintf("  Estimated Row Size: %d bytes (%d output columns)\n", rowSize, columnCount))
+					result.WriteString(fmt.Sprintf("  Recommended DefaultBufferSize: %d bytes (%s) = %d bytes × %d rows × 1.2\n",
+						recommended, formatBytes(recommended), rowSize, maxRows))
+				}
+
 				// Estimate buffer requirements based on component types
 				bufferRecommendations := generateBufferRecommendations(task.ObjectData.DataFlow.Components.Components, bufferSettings)
 				if len(bufferRecommendations) > 0 {
@@ -216,6 +233,14 @@ func HandleAnalyzeParallelProcessing(_ context.Context, request mcp.CallToolRequ
 	}
 	result.WriteString("\n")
 
+	// Loop containers whose children run strictly one after another
+	result.WriteString("🔁 Loop Container Sequencing:\n")
+	loopAnalysis := analyzeLoopSequencing(pkg.Executables.Tasks, collectExecutableXML(data))
+	for _, analysis := range loopAnalysis {
+		result.WriteString(fmt.Sprintf("• %s\n", analysis))
+	}
+	result.WriteString("\n")
+
 	// Performance recommendations
 	result.WriteString("🚀 Parallel Processing Optimization Recommendations:\n")
 	result.WriteString("• Set MaxConcurrentExecutables to 2-4 times the number of CPU cores\n")
@@ -252,6 +277,19 @@ func HandleProfileMemoryUsage(_ context.Context, request mcp.CallToolRequest, pa
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse XML: %v", err)), nil
 	}
 
+	thresholdMB := request.GetInt("memory_threshold_mb", defaultMemoryThresholdMB)
+	if thresholdMB < 1 {
+		return mcp.NewToolResultError("memory_threshold_mb must be at least 1"), nil
+	}
+	defaultRows := int64(request.GetInt("row_estimate", defaultRowEstimate))
+	if defaultRows < 1 {
+		return mcp.NewToolResultError("row_estimate must be at least 1"), nil
+	}
+	rowEstimates, err := parseRowEstimates(request.GetArguments()["row_estimates"])
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	var result strings.Builder
 	result.WriteString("🧠 Memory Usage Profiling:\n\n")
 
@@ -282,6 +320,19 @@ func HandleProfileMemoryUsage(_ context.Context, request mcp.CallToolRequest, pa
 				}
 			}
 
+			// Estimate memory held by blocking transformations
+			blocking := estimateBlockingMemory(task.ObjectData.DataFlow.Components.Components, rowEstimates, defaultRows, int64(thresholdMB)*1024*1024)
+			if len(blocking) > 0 {
+				result.WriteString("  Blocking Transformations:\n")
+				for _, estimate := range blocking {
+					result.WriteString(fmt.Sprintf("    • %s (%s): ~%s = %d rows × %d bytes\n",
+						estimate.Component, estimate.Type, formatBytes(estimate.Bytes), estimate.Rows, estimate.RowSize))
+					if estimate.ExceedsThreshold {
+						result.WriteString(fmt.Sprintf("      🚨 Exceeds memory threshold of %d MB - reduce columns, pre-sort or pre-aggregate at the source\n", thresholdMB))
+					}
+				}
+			}
+
 			// Check for memory-intensive operations
 			memoryIssues := detectMemoryIntensiveOperations(task)
 			if len(memoryIssues) > 0 {
@@ -342,6 +393,75 @@ func analyzeBufferSettings(task types.Task) []BufferSetting {
 	return settings
 }
 
+const (
+	// defaultAssumedVarcharLength is the width assumed for variable-length columns without a length
+	defaultAssumedVarcharLength = 50
+	// defaultBufferMaxRows is the SSIS default for DefaultBufferMaxRows
+	defaultBufferMaxRows = 10000
+	// bufferSafetyMarginPercent is the 20% safety margin applied to the estimated buffer size
+	bufferSafetyMarginPercent = 120
+)
+
+// fixedColumnWidths maps fixed-width SSIS data types to their size in bytes
+var fixedColumnWidths = map[string]int64{
+	"bool": 1, "i1": 1, "ui1": 1,
+	"i2": 2, "ui2": 2,
+	"i4": 4, "ui4": 4, "r4": 4,
+	"i8": 8, "ui8": 8, "r8": 8, "cy": 8, "date": 8, "dbDate": 4, "dbTime": 4, "dbTime2": 8,
+	"dbTimeStamp": 8, "dbTimeStamp2": 8, "dbTimeStampOffset": 12, "filetime": 8,
+	"numeric": 19, "decimal": 16, "guid": 16,
+}
+
+// columnWidth estimates the buffer width of a column in bytes. Unicode strings use two bytes per
+// character and variable-length columns without a length use assumedVarcharLength
+func columnWidth(dataType string, length, assumedVarcharLength int) int64 {
+	if width, ok := fixedColumnWidths[dataType]; ok {
+		return width
+	}
+	if length <= 0 {
+		length = assumedVarcharLength
+	}
+	if dataType == "wstr" || dataType == "nText" {
+		return int64(length) * 2
+	}
+	return int64(length)
+}
+
+// estimateRowSize sums the widths of the non-error output columns of the components
+func estimateRowSize(components []types.DataFlowComponent, assumedVarcharLength int) (int64, int) {
+	var rowSize int64
+	columns := 0
+	for _, comp := range components {
+		for _, output := range comp.Outputs.Outputs {
+			if output.IsErrorOut {
+				continue
+			}
+			for _, col := range output.OutputColumns.Columns {
+				rowSize += columnWidth(col.DataType, col.Length, assumedVarcharLength)
+				columns++
+			}
+		}
+	}
+	return rowSize, columns
+}
+
+// bufferMaxRows returns the configured DefaultBufferMaxRows or the SSIS default
+func bufferMaxRows(bufferSettings []BufferSetting) int {
+	for _, setting := range bufferSettings {
+		if setting.Name == "DefaultBufferMaxRows" {
+			if val, err := strconv.Atoi(setting.Value); err == nil && val > 0 {
+				return val
+			}
+		}
+	}
+	return defaultBufferMaxRows
+}
+
+// recommendedBufferSize computes DefaultBufferSize = row size × DefaultBufferMaxRows × safety margin
+func recommendedBufferSize(rowSize int64, maxRows int) int64 {
+	return (rowSize*int64(maxRows)*bufferSafetyMarginPercent + 99) / 100
+}
+
 // generateBufferRecommendations generates buffer optimization recommendations based on components and settings
 func generateBufferRecommendations(components []types.DataFlowComponent, bufferSettings []BufferSetting) []string {
 	var recommendations []string
@@ -522,6 +642,167 @@ func analyzeContainerParallelization(tasks []types.Task) []string {
 	return analysis
 }
 
+// rawExecutable captures the raw XML of each executable for variable reference scanning
+type rawExecutable struct {
+	RefID       string          `xml:"refId,attr"`
+	Inner       string          `xml:",innerxml"`
+	Executables []rawExecutable `xml:"Executables>Executable"`
+}
+
+// collectExecutableXML maps executable refIds to their inner XML
+func collectExecutableXML(data []byte) map[string]string {
+	var root rawExecutable
+	executableXML := make(map[string]string)
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return executableXML
+	}
+	var walk func(executables []rawExecutable)
+	walk = func(executables []rawExecutable) {
+		for _, executable := range executables {
+			executableXML[executable.RefID] = executable.Inner
+			walk(executable.Executables)
+		}
+	}
+	walk(root.Executables)
+	return executableXML
+}
+
+// variableReferencePattern matches user, package and project variable references; System variables
+// are read-only at runtime and never make two tasks dependent
+var variableReferencePattern = regexp.MustCompile(`(User|\$Package|\$Project)::[A-Za-z_][A-Za-z0-9_]*`)
+
+// taskVariables returns the variables referenced by a task's properties and raw XML
+func taskVariables(task types.Task, executableXML map[string]string) map[string]bool {
+	variables := make(map[string]bool)
+	sources := []string{executableXML[task.RefId]}
+	for _, prop := range task.Properties {
+		sources = append(sources, prop.Value)
+	}
+	for _, source := range sources {
+		for _, match := range variableReferencePattern.FindAllString(source, -1) {
+			variables[match] = true
+		}
+	}
+	return variables
+}
+
+// sharedVariables lists the variables referenced by both sets
+func sharedVariables(a, b map[string]bool) []string {
+	var shared []string
+	for name := range a {
+		if b[name] {
+			shared = append(shared, name)
+		}
+	}
+	sort.Strings(shared)
+	return shared
+}
+
+// isLoopContainer reports whether a task is a For Loop or Foreach Loop container
+func isLoopContainer(task types.Task) bool {
+	creationName := strings.ToLower(task.CreationName)
+	taskType := getTaskType(task)
+	return strings.Contains(creationName, "forloop") || strings.Contains(creationName, "foreachloop") ||
+		taskType == "ForLoop" || taskType == "ForeachLoop"
+}
+
+// sequentialChain returns the children of a container in execution order when they form a single
+// chain of Success-only constraints without expressions, or nil when the container already branches
+func sequentialChain(container types.Task) []types.Task {
+	if container.Executables == nil || len(container.Executables.Tasks) < 2 {
+		return nil
+	}
+	children := container.Executables.Tasks
+	constraints := container.PrecedenceConstraints.Constraints
+	if len(constraints) != len(children)-1 {
+		return nil
+	}
+
+	byRef := make(map[string]types.Task, len(children))
+	for _, child := range children {
+		byRef[child.RefId] = child
+	}
+	next := make(map[string]string)
+	hasIncoming := make(map[string]bool)
+	for _, constraint := range constraints {
+		if (constraint.Value != "" && constraint.Value != "0") || constraint.Expression != "" {
+			return nil
+		}
+		if _, ok := next[constraint.From]; ok || hasIncoming[constraint.To] {
+			return nil
+		}
+		next[constraint.From] = constraint.To
+		hasIncoming[constraint.To] = true
+	}
+
+	var chain []types.Task
+	for _, child := range children {
+		if hasIncoming[child.RefId] {
+			continue
+		}
+		for ref := child.RefId; ref != ""; ref = next[ref] {
+			task, ok := byRef[ref]
+			if !ok || len(chain) == len(children) {
+				return nil
+			}
+			chain = append(chain, task)
+		}
+		break
+	}
+	if len(chain) != len(children) {
+		return nil
+	}
+	return chain
+}
+
+// analyzeLoopSequencing reports For Loop and Foreach Loop containers whose children run strictly in
+// sequence and suggests parallel branches for steps that share no variables
+func analyzeLoopSequencing(tasks []types.Task, executableXML map[string]string) []string {
+	var analysis []string
+
+	var walk func(tasks []types.Task)
+	walk = func(tasks []types.Task) {
+		for _, task := range tasks {
+			if task.Executables != nil {
+				walk(task.Executables.Tasks)
+			}
+			if !isLoopContainer(task) {
+				continue
+			}
+			chain := sequentialChain(task)
+			if chain == nil {
+				continue
+			}
+
+			names := make([]string, len(chain))
+			for i, child := range chain {
+				names[i] = child.Name
+			}
+			analysis = append(analysis, fmt.Sprintf("Loop '%s' runs %d tasks strictly in sequence: %s", task.Name, len(chain), strings.Join(names, " → ")))
+
+			independent := 0
+			for i := 0; i+1 < len(chain); i++ {
+				shared := sharedVariables(taskVariables(chain[i], executableXML), taskVariables(chain[i+1], executableXML))
+				if len(shared) == 0 {
+					independent++
+					analysis = append(analysis, fmt.Sprintf("  '%s' and '%s' share no variables - remove the constraint between them so they run as parallel branches", chain[i].Name, chain[i+1].Name))
+				} else {
+					analysis = append(analysis, fmt.Sprintf("  '%s' → '%s' must stay sequential (shared: %s)", chain[i].Name, chain[i+1].Name, strings.Join(shared, ", ")))
+				}
+			}
+			if independent == len(chain)-1 {
+				analysis = append(analysis, fmt.Sprintf("  All tasks in '%s' are independent - remove the constraints and let MaxConcurrentExecutables run them in parallel", task.Name))
+			}
+		}
+	}
+	walk(tasks)
+
+	if len(analysis) == 0 {
+		analysis = append(analysis, "No loop containers with strictly sequential tasks found")
+	}
+	return analysis
+}
+
 // estimateBufferMemoryUsage estimates memory usage based on buffer settings
 func estimateBufferMemoryUsage(task types.Task) int64 {
 	bufferSize := int64(1048576) // Default 1MB
@@ -561,6 +842,80 @@ func formatBytes(bytes int64) string {
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
 
+const (
+	// defaultMemoryThresholdMB is the blocking transformation memory above which components are flagged
+	defaultMemoryThresholdMB = 512
+	// defaultRowEstimate is the row count assumed for components without a row estimate
+	defaultRowEstimate = 1000000
+)
+
+// BlockingComponentMemory is the estimated memory held by a Sort or Aggregate transformation
+type BlockingComponentMemory struct {
+	Component        string
+	Type             string
+	Rows             int64
+	RowSize          int64
+	Bytes            int64
+	ExceedsThreshold bool
+}
+
+// parseRowEstimates reads the row_estimates argument, a map of component names to row counts
+func parseRowEstimates(value interface{}) (map[string]int64, error) {
+	estimates := make(map[string]int64)
+	if value == nil {
+		return estimates, nil
+	}
+	raw, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("row_estimates must be an object mapping component names to row counts")
+	}
+	for name, rows := range raw {
+		count, ok := rows.(float64)
+		if !ok || count < 1 {
+			return nil, fmt.Errorf("row_estimates[%s] must be a positive number", name)
+		}
+		estimates[name] = int64(count)
+	}
+	return estimates, nil
+}
+
+// estimateBlockingMemory estimates row_estimate × average row size for each Sort and Aggregate
+// component, using output column widths (or input columns when no outputs are defined)
+func estimateBlockingMemory(components []types.DataFlowComponent, rowEstimates map[string]int64, defaultRows, thresholdBytes int64) []BlockingComponentMemory {
+	var estimates []BlockingComponentMemory
+	for _, comp := range components {
+		compType := getComponentType(comp.ComponentClassID)
+		lowerType := strings.ToLower(compType + " " + comp.ComponentClassID)
+		if !strings.Contains(lowerType, "sort") && !strings.Contains(lowerType, "aggregate") {
+			continue
+		}
+
+		rowSize, _ := estimateRowSize([]types.DataFlowComponent{comp}, defaultAssumedVarcharLength)
+		if rowSize == 0 {
+			for _, input := range comp.Inputs.Inputs {
+				for _, col := range input.InputColumns.Columns {
+					rowSize += columnWidth(col.DataType, col.Length, defaultAssumedVarcharLength)
+				}
+			}
+		}
+
+		rows, ok := rowEstimates[comp.Name]
+		if !ok {
+			rows = defaultRows
+		}
+		bytes := rows * rowSize
+		estimates = append(estimates, BlockingComponentMemory{
+			Component:        comp.Name,
+			Type:             compType,
+			Rows:             rows,
+			RowSize:          rowSize,
+			Bytes:            bytes,
+			ExceedsThreshold: bytes > thresholdBytes,
+		})
+	}
+	return estimates
+}
+
 // analyzeComponentMemoryUsage analyzes memory usage patterns of data flow components
 func analyzeComponentMemoryUsage(components []types.DataFlowComponent) []ComponentMemory {
 	var memoryAnalysis []ComponentMemory
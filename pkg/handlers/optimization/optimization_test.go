@@ -1,8 +1,10 @@
 package optimization
 
 import (
+	"encoding/xml"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/MCPRUNNER/gossisMCP/pkg/types"
@@ -47,6 +49,50 @@ func TestBufferRecommendations(t *testing.T) {
 	}
 }
 
+func TestEstimateRowSize(t *testing.T) {
+	components := []types.DataFlowComponent{
+		{Outputs: types.ComponentOutputs{Outputs: []types.ComponentOutput{
+			{OutputColumns: types.OutputColumns{Columns: []types.OutputColumn{
+				{Name: "Id", DataType: "i4"},
+				{Name: "Code", DataType: "str", Length: 10},
+				{Name: "Name", DataType: "wstr", Length: 25},
+				{Name: "Notes", DataType: "str"},
+			}}},
+			{IsErrorOut: true, OutputColumns: types.OutputColumns{Columns: []types.OutputColumn{
+				{Name: "ErrorCode", DataType: "i4"},
+			}}},
+		}}},
+		{Outputs: types.ComponentOutputs{Outputs: []types.ComponentOutput{
+			{OutputColumns: types.OutputColumns{Columns: []types.OutputColumn{
+				{Name: "Comment", DataType: "wstr"},
+			}}},
+		}}},
+	}
+
+	// 4 + 10 + 25*2 + 50 + 50*2
+	rowSize, columns := estimateRowSize(components, 50)
+	if rowSize != 214 || columns != 5 {
+		t.Fatalf("expected 214 bytes over 5 columns, got %d bytes over %d columns", rowSize, columns)
+	}
+	// 4 + 10 + 25*2 + 100 + 100*2
+	if rowSize, _ := estimateRowSize(components, 100); rowSize != 364 {
+		t.Fatalf("expected assumed length to apply to variable-length columns, got %d", rowSize)
+	}
+}
+
+func TestRecommendedBufferSize(t *testing.T) {
+	if size := recommendedBufferSize(214, bufferMaxRows(nil)); size != 2568000 {
+		t.Fatalf("expected 214 × 10000 × 1.2 = 2568000, got %d", size)
+	}
+	settings := []BufferSetting{{Name: "DefaultBufferMaxRows", Value: "5000"}}
+	if size := recommendedBufferSize(333, bufferMaxRows(settings)); size != 1998000 {
+		t.Fatalf("expected 333 × 5000 × 1.2 = 1998000, got %d", size)
+	}
+	if size := recommendedBufferSize(7, 3); size != 26 {
+		t.Fatalf("expected 7 × 3 × 1.2 to round up to 26, got %d", size)
+	}
+}
+
 func TestAnalyzeTaskParallelization(t *testing.T) {
 	tasks := []types.Task{
 		{Properties: []types.Property{{Name: "TaskType", Value: "SSIS.Pipeline.3"}}},
@@ -58,6 +104,56 @@ func TestAnalyzeTaskParallelization(t *testing.T) {
 	}
 }
 
+func TestAnalyzeLoopSequencing(t *testing.T) {
+	data := []byte(`<Executable refId="Package">
+  <Executables>
+    <Executable refId="Package\Loop" CreationName="STOCK:FOREACHLOOP" ObjectName="Loop">
+      <Executables>
+        <Executable refId="Package\Loop\Stage" ObjectName="Stage">
+          <ObjectData><SqlTaskData SqlStatementSource="EXEC stage" /></ObjectData>
+        </Executable>
+        <Executable refId="Package\Loop\Archive" ObjectName="Archive">
+          <ObjectData><SqlTaskData><ResultBinding VariableName="User::RowCount" /></SqlTaskData></ObjectData>
+        </Executable>
+        <Executable refId="Package\Loop\Audit" ObjectName="Audit">
+          <Property Name="Expression">@[User::RowCount] &gt; 0 &amp;&amp; @[System::PackageName] != ""</Property>
+        </Executable>
+      </Executables>
+      <PrecedenceConstraints>
+        <PrecedenceConstraint From="Package\Loop\Archive" To="Package\Loop\Audit" />
+        <PrecedenceConstraint From="Package\Loop\Stage" To="Package\Loop\Archive" Value="0" />
+      </PrecedenceConstraints>
+    </Executable>
+  </Executables>
+</Executable>`)
+
+	var pkg types.SSISPackage
+	if err := xml.Unmarshal(data, &pkg); err != nil {
+		t.Fatalf("failed to parse package: %v", err)
+	}
+	analysis := strings.Join(analyzeLoopSequencing(pkg.Executables.Tasks, collectExecutableXML(data)), "\n")
+
+	expected := []string{
+		"Loop 'Loop' runs 3 tasks strictly in sequence: Stage → Archive → Audit",
+		"'Stage' and 'Archive' share no variables",
+		"'Archive' → 'Audit' must stay sequential (shared: User::RowCount)",
+	}
+	for _, want := range expected {
+		if !strings.Contains(analysis, want) {
+			t.Fatalf("expected %q in analysis, got:\n%s", want, analysis)
+		}
+	}
+	if strings.Contains(analysis, "All tasks") {
+		t.Fatalf("expected dependent tasks to prevent full parallelization, got:\n%s", analysis)
+	}
+
+	// A failure branch means the loop already branches and is not reported
+	pkg.Executables.Tasks[0].PrecedenceConstraints.Constraints[0].Value = "1"
+	if analysis := analyzeLoopSequencing(pkg.Executables.Tasks, nil); !strings.Contains(analysis[0], "No loop containers") {
+		t.Fatalf("expected branching loop to be skipped, got %v", analysis)
+	}
+}
+
 func TestAnalyzeDataFlowParallelization(t *testing.T) {
 	tasks := []types.Task{
 		{Name: "DataFlow", Properties: []types.Property{{Name: "TaskType", Value: "SSIS.Pipeline.3"}, {Name: "EngineThreads", Value: "1"}}},
@@ -98,6 +194,48 @@ func TestDetectMemoryIntensiveOperations(t *testing.T) {
 	}
 }
 
+func TestEstimateBlockingMemory(t *testing.T) {
+	components := []types.DataFlowComponent{
+		{Name: "OLE DB Source", ComponentClassID: "Microsoft.OLEDBSource"},
+		{Name: "Sort Customers", ComponentClassID: "Microsoft.Sort", Outputs: types.ComponentOutputs{Outputs: []types.ComponentOutput{
+			{OutputColumns: types.OutputColumns{Columns: []types.OutputColumn{
+				{Name: "Id", DataType: "i4"},
+				{Name: "Name", DataType: "wstr", Length: 100},
+			}}},
+		}}},
+		{Name: "Aggregate Sales", ComponentClassID: "Microsoft.Aggregate", Inputs: types.ComponentInputs{Inputs: []types.ComponentInput{
+			{InputColumns: types.InputColumns{Columns: []types.InputColumn{
+				{Name: "Amount", DataType: "cy"},
+				{Name: "Region", DataType: "str"},
+			}}},
+		}}},
+	}
+
+	rowEstimates, err := parseRowEstimates(map[string]interface{}{"Sort Customers": float64(4000000)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	estimates := estimateBlockingMemory(components, rowEstimates, 1000, 512*1024*1024)
+	if len(estimates) != 2 {
+		t.Fatalf("expected Sort and Aggregate estimates, got %+v", estimates)
+	}
+
+	// Sort: 4,000,000 rows × (4 + 100*2) bytes
+	sortEstimate := estimates[0]
+	if sortEstimate.Rows != 4000000 || sortEstimate.RowSize != 204 || sortEstimate.Bytes != 816000000 || !sortEstimate.ExceedsThreshold {
+		t.Fatalf("unexpected sort estimate: %+v", sortEstimate)
+	}
+	// Aggregate: default 1,000 rows × (8 + 50) bytes from input columns
+	aggregateEstimate := estimates[1]
+	if aggregateEstimate.Rows != 1000 || aggregateEstimate.RowSize != 58 || aggregateEstimate.Bytes != 58000 || aggregateEstimate.ExceedsThreshold {
+		t.Fatalf("unexpected aggregate estimate: %+v", aggregateEstimate)
+	}
+
+	if _, err := parseRowEstimates(map[string]interface{}{"Sort Customers": "many"}); err == nil {
+		t.Fatal("expected non-numeric row estimate to be rejected")
+	}
+}
+
 func TestEstimateBufferMemoryUsage(t *testing.T) {
 	task := types.Task{
 		Properties: []types.Property{{Name: "DefaultBufferSize", Value: "2048"}, {Name: "DefaultBufferMaxRows", Value: "20"}},
@@ -6,14 +6,20 @@ import (
 	"encoding/xml"
 	"fmt"
 	"html"
+	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 
 	"github.com/MCPRUNNER/gossisMCP/pkg/types"
+	"github.com/MCPRUNNER/gossisMCP/pkg/util/output"
 )
 
 type batchAnalysisResult struct {
@@ -32,6 +38,93 @@ type batchSummary struct {
 	AverageDuration  time.Duration         `json:"average_duration"`
 	Errors           []string              `json:"errors,omitempty"`
 	PackageSummaries []batchAnalysisResult `json:"package_summaries"`
+	Benchmark        *batchBenchmarkReport `json:"benchmark,omitempty"`
+	OutputFiles      []string              `json:"output_files,omitempty"`
+}
+
+type batchBenchmark struct {
+	File       string  `json:"file"`
+	ParseMs    float64 `json:"parse_ms"`
+	AnalysisMs float64 `json:"analysis_ms"`
+	TotalMs    float64 `json:"total_ms"`
+}
+
+type batchBenchmarkReport struct {
+	Files []batchBenchmark `json:"files"`
+	P50Ms float64          `json:"p50_ms"`
+	P90Ms float64          `json:"p90_ms"`
+	P99Ms float64          `json:"p99_ms"`
+}
+
+// batchProgress is reported each time a file in a batch finishes
+type batchProgress struct {
+	File      string `json:"file"`
+	Status    string `json:"status"`
+	Completed int    `json:"completed"`
+	Total     int    `json:"total"`
+}
+
+// batchProgressMethod is the client notification carrying batchProgress on streaming transports
+const batchProgressMethod = "notifications/batch_progress"
+
+// progressOutput receives progress events when there is no streaming client session, e.g. in stdio mode
+var progressOutput io.Writer = os.Stderr
+
+// reportBatchProgress sends progress to the HTTP streaming client, which receives it as an SSE event,
+// and writes it to progressOutput in stdio mode so it does not interleave with JSON-RPC on stdout
+func reportBatchProgress(ctx context.Context, progress batchProgress) {
+	session := server.ClientSessionFromContext(ctx)
+	mcpServer := server.ServerFromContext(ctx)
+	if session != nil && mcpServer != nil && session.SessionID() != "stdio" {
+		params := map[string]any{
+			"file":      progress.File,
+			"status":    progress.Status,
+			"completed": progress.Completed,
+			"total":     progress.Total,
+		}
+		if err := mcpServer.SendNotificationToClient(ctx, batchProgressMethod, params); err == nil {
+			return
+		}
+	}
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(progressOutput, "data: %s\n\n", data)
+}
+
+// globPackageFiles expands a filepath.Glob pattern, relative to the package directory unless absolute,
+// into the regular files it matches
+func globPackageFiles(pattern, packageDirectory string) ([]string, error) {
+	matches, err := filepath.Glob(resolveFilePath(pattern, packageDirectory))
+	if err != nil {
+		return nil, fmt.Errorf("invalid glob_pattern %q: %w", pattern, err)
+	}
+	var files []string
+	for _, match := range matches {
+		if info, err := os.Stat(match); err == nil && !info.IsDir() {
+			files = append(files, match)
+		}
+	}
+	return files, nil
+}
+
+// dedupePackagePaths removes paths that resolve to the same absolute file, keeping the first occurrence
+func dedupePackagePaths(paths []string, packageDirectory string) []string {
+	seen := make(map[string]bool, len(paths))
+	var unique []string
+	for _, path := range paths {
+		key := resolveFilePath(path, packageDirectory)
+		if abs, err := filepath.Abs(key); err == nil {
+			key = abs
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, path)
+	}
+	return unique
 }
 
 func HandleBatchAnalyze(ctx context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
@@ -40,16 +133,31 @@ func HandleBatchAnalyze(ctx context.Context, request mcp.CallToolRequest, packag
 		return mcp.NewToolResultError("invalid arguments"), nil
 	}
 
-	rawPaths, ok := args["file_paths"].([]interface{})
-	if !ok {
-		return mcp.NewToolResultError("file_paths parameter is required and must be an array"), nil
+	globPattern, _ := args["glob_pattern"].(string)
+	globPattern = strings.TrimSpace(globPattern)
+	rawPaths, hasFilePaths := args["file_paths"]
+	if !hasFilePaths && globPattern == "" {
+		return mcp.NewToolResultError("file_paths or glob_pattern parameter is required"), nil
 	}
 
 	var paths []string
-	for _, raw := range rawPaths {
-		if pathStr, ok := raw.(string); ok && pathStr != "" {
-			paths = append(paths, pathStr)
+	if hasFilePaths {
+		list, ok := rawPaths.([]interface{})
+		if !ok {
+			return mcp.NewToolResultError("file_paths parameter must be an array"), nil
 		}
+		for _, raw := range list {
+			if pathStr, ok := raw.(string); ok && pathStr != "" {
+				paths = append(paths, pathStr)
+			}
+		}
+	}
+	if globPattern != "" {
+		matches, err := globPackageFiles(globPattern, packageDirectory)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		paths = dedupePackagePaths(append(paths, matches...), packageDirectory)
 	}
 
 	if len(paths) == 0 {
@@ -66,20 +174,57 @@ func HandleBatchAnalyze(ctx context.Context, request mcp.CallToolRequest, packag
 		maxConcurrency = int(mc)
 	}
 
+	benchmark, _ := args["benchmark"].(bool)
+	disableProgress, _ := args["disable_progress"].(bool)
+
+	// Per-file results are written next to output_file_path as each file completes
+	outputDir := ""
+	if outputPath, ok := args["output_file_path"].(string); ok && strings.TrimSpace(outputPath) != "" {
+		outputDir = filepath.Dir(resolveFilePath(outputPath, packageDirectory))
+	}
+	var outputFiles []string
+	outputNames := make(map[string]int)
+
 	sem := make(chan struct{}, maxConcurrency)
 	results := make(chan batchAnalysisResult, len(paths))
 	startTime := time.Now()
 
+	var timingsMu sync.Mutex
+	var timings []batchBenchmark
+
 	for _, path := range paths {
 		go func(filePath string) {
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
 			result := batchAnalysisResult{PackagePath: filePath}
+			if err := ctx.Err(); err != nil {
+				result.Error = err.Error()
+				results <- result
+				return
+			}
 			resultStart := time.Now()
 
-			analysisResult, err := performBatchPackageAnalysis(filePath, packageDirectory)
-			result.Duration = time.Since(resultStart)
+			var analysisResult map[string]interface{}
+			pkg, err := parseBatchPackage(filePath, packageDirectory)
+			parseEnd := time.Now()
+			if err == nil {
+				analysisResult = summarizeBatchPackage(pkg, filePath)
+			}
+			analysisEnd := time.Now()
+			result.Duration = analysisEnd.Sub(resultStart)
+
+			if benchmark {
+				timing := batchBenchmark{
+					File:       filePath,
+					ParseMs:    durationMs(parseEnd.Sub(resultStart)),
+					AnalysisMs: durationMs(analysisEnd.Sub(parseEnd)),
+					TotalMs:    durationMs(result.Duration),
+				}
+				timingsMu.Lock()
+				timings = append(timings, timing)
+				timingsMu.Unlock()
+			}
 
 			if err != nil {
 				result.Success = false
@@ -98,6 +243,25 @@ func HandleBatchAnalyze(ctx context.Context, request mcp.CallToolRequest, packag
 		select {
 		case result := <-results:
 			batchResults = append(batchResults, result)
+			if outputDir != "" {
+				written, err := writeBatchResultFile(outputDir, result, outputNames)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				outputFiles = append(outputFiles, written)
+			}
+			if !disableProgress {
+				status := "done"
+				if !result.Success {
+					status = "failed"
+				}
+				reportBatchProgress(ctx, batchProgress{
+					File:      result.PackagePath,
+					Status:    status,
+					Completed: len(batchResults),
+					Total:     len(paths),
+				})
+			}
 		case <-ctx.Done():
 			return mcp.NewToolResultError("batch analysis cancelled"), nil
 		}
@@ -124,12 +288,19 @@ func HandleBatchAnalyze(ctx context.Context, request mcp.CallToolRequest, packag
 		TotalDuration:    totalDuration,
 		Errors:           errors,
 		PackageSummaries: batchResults,
+		OutputFiles:      outputFiles,
 	}
 
 	if summary.TotalPackages > 0 {
 		summary.AverageDuration = totalDuration / time.Duration(summary.TotalPackages)
 	}
 
+	if benchmark {
+		timingsMu.Lock()
+		summary.Benchmark = buildBenchmarkReport(timings)
+		timingsMu.Unlock()
+	}
+
 	switch format {
 	case "json":
 		jsonData, _ := json.MarshalIndent(summary, "", "  ")
@@ -145,22 +316,51 @@ func HandleBatchAnalyze(ctx context.Context, request mcp.CallToolRequest, packag
 	}
 }
 
+// writeBatchResultFile writes one package's result to <outputDir>/<package name>.json, numbering repeated names
+func writeBatchResultFile(outputDir string, result batchAnalysisResult, usedNames map[string]int) (string, error) {
+	name := strings.TrimSuffix(filepath.Base(result.PackagePath), filepath.Ext(result.PackagePath))
+	usedNames[name]++
+	if count := usedNames[name]; count > 1 {
+		name = fmt.Sprintf("%s-%d", name, count)
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode result for %s: %w", result.PackagePath, err)
+	}
+	path := filepath.Join(outputDir, name+".json")
+	if err := output.WriteOutput(path, string(data)+"\n"); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
 func performBatchPackageAnalysis(filePath, packageDirectory string) (map[string]interface{}, error) {
+	pkg, err := parseBatchPackage(filePath, packageDirectory)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeBatchPackage(pkg, filePath), nil
+}
+
+func parseBatchPackage(filePath, packageDirectory string) (types.SSISPackage, error) {
 	fullPath := resolveFilePath(filePath, packageDirectory)
 
+	var pkg types.SSISPackage
 	data, err := os.ReadFile(fullPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return pkg, fmt.Errorf("failed to read file: %w", err)
 	}
 
 	data = []byte(strings.ReplaceAll(string(data), "DTS:", ""))
 	data = []byte(strings.ReplaceAll(string(data), `xmlns="www.microsoft.com/SqlServer/Dts"`, ""))
 
-	var pkg types.SSISPackage
 	if err := xml.Unmarshal(data, &pkg); err != nil {
-		return nil, fmt.Errorf("failed to parse DTSX file: %w", err)
+		return pkg, fmt.Errorf("failed to parse DTSX file: %w", err)
 	}
+	return pkg, nil
+}
 
+func summarizeBatchPackage(pkg types.SSISPackage, filePath string) map[string]interface{} {
 	packageName := strings.TrimSpace(pkg.ObjectName)
 	if packageName == "" {
 		for _, prop := range pkg.Properties {
@@ -191,7 +391,43 @@ func performBatchPackageAnalysis(filePath, packageDirectory string) (map[string]
 	}
 	result["task_types"] = taskTypes
 
-	return result, nil
+	return result
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// buildBenchmarkReport sorts per-file timings by path and computes total_ms percentiles
+func buildBenchmarkReport(timings []batchBenchmark) *batchBenchmarkReport {
+	report := &batchBenchmarkReport{Files: append([]batchBenchmark(nil), timings...)}
+	sort.Slice(report.Files, func(i, j int) bool { return report.Files[i].File < report.Files[j].File })
+
+	totals := make([]float64, len(report.Files))
+	for i, timing := range report.Files {
+		totals[i] = timing.TotalMs
+	}
+	sort.Float64s(totals)
+
+	report.P50Ms = percentile(totals, 50)
+	report.P90Ms = percentile(totals, 90)
+	report.P99Ms = percentile(totals, 99)
+	return report
+}
+
+// percentile returns the nearest-rank percentile of an ascending slice
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
 }
 
 func describeTaskType(task types.Task) string {
@@ -253,6 +489,23 @@ func formatBatchSummaryAsText(summary batchSummary) string {
 		}
 	}
 
+	if summary.Benchmark != nil {
+		output.WriteString("\nBenchmark:\n")
+		output.WriteString(fmt.Sprintf("p50: %.2f ms, p90: %.2f ms, p99: %.2f ms\n",
+			summary.Benchmark.P50Ms, summary.Benchmark.P90Ms, summary.Benchmark.P99Ms))
+		for _, timing := range summary.Benchmark.Files {
+			output.WriteString(fmt.Sprintf("- %s: parse %.2f ms, analysis %.2f ms, total %.2f ms\n",
+				timing.File, timing.ParseMs, timing.AnalysisMs, timing.TotalMs))
+		}
+	}
+
+	if len(summary.OutputFiles) > 0 {
+		output.WriteString("\nOutput Files:\n")
+		for _, path := range summary.OutputFiles {
+			output.WriteString(fmt.Sprintf("- %s\n", path))
+		}
+	}
+
 	return output.String()
 }
 
@@ -284,6 +537,25 @@ func formatBatchSummaryAsCSV(summary batchSummary) string {
 			pkg.PackagePath, pkg.Success, errorStr, pkg.Duration))
 	}
 
+	if summary.Benchmark != nil {
+		output.WriteString("\nBenchmark\n")
+		output.WriteString("P50 ms,P90 ms,P99 ms\n")
+		output.WriteString(fmt.Sprintf("%.2f,%.2f,%.2f\n\n",
+			summary.Benchmark.P50Ms, summary.Benchmark.P90Ms, summary.Benchmark.P99Ms))
+		output.WriteString("File,Parse ms,Analysis ms,Total ms\n")
+		for _, timing := range summary.Benchmark.Files {
+			output.WriteString(fmt.Sprintf("\"%s\",%.2f,%.2f,%.2f\n",
+				timing.File, timing.ParseMs, timing.AnalysisMs, timing.TotalMs))
+		}
+	}
+
+	if len(summary.OutputFiles) > 0 {
+		output.WriteString("\nOutput Files\n")
+		for _, path := range summary.OutputFiles {
+			output.WriteString(fmt.Sprintf("\"%s\"\n", strings.ReplaceAll(path, "\"", "\"\"")))
+		}
+	}
+
 	return output.String()
 }
 
@@ -356,7 +628,43 @@ func formatBatchSummaryAsHTML(summary batchSummary) string {
 	}
 
 	output.WriteString(`
-    </table>
+    </table>`)
+
+	if summary.Benchmark != nil {
+		output.WriteString(fmt.Sprintf(`
+    <h2>Benchmark</h2>
+    <p>p50: %.2f ms, p90: %.2f ms, p99: %.2f ms</p>
+    <table>
+        <tr>
+            <th>File</th>
+            <th>Parse (ms)</th>
+            <th>Analysis (ms)</th>
+            <th>Total (ms)</th>
+        </tr>`, summary.Benchmark.P50Ms, summary.Benchmark.P90Ms, summary.Benchmark.P99Ms))
+		for _, timing := range summary.Benchmark.Files {
+			output.WriteString(fmt.Sprintf(`
+        <tr>
+            <td>%s</td>
+            <td>%.2f</td>
+            <td>%.2f</td>
+            <td>%.2f</td>
+        </tr>`, html.EscapeString(timing.File), timing.ParseMs, timing.AnalysisMs, timing.TotalMs))
+		}
+		output.WriteString(`
+    </table>`)
+	}
+
+	if len(summary.OutputFiles) > 0 {
+		output.WriteString(`
+    <h2>Output Files</h2>
+    <ul>`)
+		for _, path := range summary.OutputFiles {
+			output.WriteString(fmt.Sprintf("<li>%s</li>", html.EscapeString(path)))
+		}
+		output.WriteString("    </ul>")
+	}
+
+	output.WriteString(`
 </body>
 </html>`)
 
@@ -398,5 +706,25 @@ func formatBatchSummaryAsMarkdown(summary batchSummary) string {
 			pkg.PackagePath, status, pkg.Duration, errorCell))
 	}
 
+	if summary.Benchmark != nil {
+		output.WriteString("\n## Benchmark\n\n")
+		output.WriteString(fmt.Sprintf("- **p50**: %.2f ms\n", summary.Benchmark.P50Ms))
+		output.WriteString(fmt.Sprintf("- **p90**: %.2f ms\n", summary.Benchmark.P90Ms))
+		output.WriteString(fmt.Sprintf("- **p99**: %.2f ms\n\n", summary.Benchmark.P99Ms))
+		output.WriteString("| File | Parse (ms) | Analysis (ms) | Total (ms) |\n")
+		output.WriteString("|------|------------|---------------|------------|\n")
+		for _, timing := range summary.Benchmark.Files {
+			output.WriteString(fmt.Sprintf("| %s | %.2f | %.2f | %.2f |\n",
+				timing.File, timing.ParseMs, timing.AnalysisMs, timing.TotalMs))
+		}
+	}
+
+	if len(summary.OutputFiles) > 0 {
+		output.WriteString("\n## Output Files\n\n")
+		for _, path := range summary.OutputFiles {
+			output.WriteString(fmt.Sprintf("- %s\n", path))
+		}
+	}
+
 	return output.String()
 }
@@ -100,6 +100,26 @@ func HandleComparePackages(_ context.Context, request mcp.CallToolRequest, packa
 		return mcp.NewToolResultText(formatter.FormatAnalysisResult(result, format)), nil
 	}
 
+	if basePath := request.GetString("file_path_base", ""); basePath != "" {
+		base, err := loadComparedPackage(basePath, packageDirectory, "base")
+		if err != nil {
+			result := formatter.CreateAnalysisResult("compare_packages", basePath, nil, err)
+			if format == formatter.FormatJSON {
+				jsonResult := map[string]interface{}{
+					"tool_name": "compare_packages",
+					"file_path": basePath,
+					"package":   filepath.Base(basePath),
+					"timestamp": time.Now().Format(time.RFC3339),
+					"status":    "error",
+					"error":     err.Error(),
+				}
+				return mcp.NewToolResultStructured(jsonResult, "Package comparison error"), nil
+			}
+			return mcp.NewToolResultText(formatter.FormatAnalysisResult(result, format)), nil
+		}
+		return threeWayCompareResult(basePath, filePath1, filePath2, base, pkg1, pkg2, format), nil
+	}
+
 	var result strings.Builder
 	result.WriteString("ðŸ“Š Package Comparison Report\n\n")
 	result.WriteString(fmt.Sprintf("File 1: %s\n", filepath.Base(resolvedPath1)))
@@ -0,0 +1,85 @@
+package packages
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func writeComparePackage(t *testing.T, dir, name, serverName, timeout, extraVariable string) {
+	t.Helper()
+	contents := `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:ObjectName="Merge">
+  <DTS:ConnectionManagers>
+    <DTS:ConnectionManager DTS:ObjectName="Warehouse">
+      <DTS:ObjectData><DTS:ConnectionManager DTS:ConnectionString="Data Source=` + serverName + `;Initial Catalog=DW;" /></DTS:ObjectData>
+    </DTS:ConnectionManager>
+  </DTS:ConnectionManagers>
+  <DTS:Variables>
+    <DTS:Variable DTS:ObjectName="Timeout"><DTS:VariableValue>` + timeout + `</DTS:VariableValue></DTS:Variable>` + extraVariable + `
+  </DTS:Variables>
+</DTS:Executable>`
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func TestComparePackagesThreeWay(t *testing.T) {
+	dir := t.TempDir()
+	writeComparePackage(t, dir, "Base.dtsx", "PROD01", "30", "")
+	// File 1 changes the server and adds a variable; file 2 changes the server differently and the timeout
+	writeComparePackage(t, dir, "File1.dtsx", "PROD02", "30", `
+    <DTS:Variable DTS:ObjectName="BatchSize"><DTS:VariableValue>500</DTS:VariableValue></DTS:Variable>`)
+	writeComparePackage(t, dir, "File2.dtsx", "PROD03", "60", "")
+
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"file_path1":     "File1.dtsx",
+		"file_path2":     "File2.dtsx",
+		"file_path_base": "Base.dtsx",
+		"format":         "json",
+	}}}
+	result, err := HandleComparePackages(context.Background(), request, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	structured, ok := result.StructuredContent.(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected structured result, got %T", result.StructuredContent)
+	}
+
+	baseToFile1 := structured["base_to_file1"].([]PackageChange)
+	if len(baseToFile1) != 2 || baseToFile1[0].Element != "Warehouse" || baseToFile1[1].Element != "BatchSize" || baseToFile1[1].Kind != changeAdded {
+		t.Fatalf("unexpected base_to_file1 changes: %+v", baseToFile1)
+	}
+	baseToFile2 := structured["base_to_file2"].([]PackageChange)
+	if len(baseToFile2) != 2 || baseToFile2[1].Element != "Timeout" || baseToFile2[1].Kind != changeModified {
+		t.Fatalf("unexpected base_to_file2 changes: %+v", baseToFile2)
+	}
+
+	conflicts := structured["conflicts"].([]PackageConflict)
+	if len(conflicts) != 1 {
+		t.Fatalf("expected one conflict, got %+v", conflicts)
+	}
+	conflict := conflicts[0]
+	if conflict.Section != "connections" || conflict.Element != "Warehouse" ||
+		conflict.Base != "Data Source=PROD01;Initial Catalog=DW;" ||
+		conflict.File1.Value != "Data Source=PROD02;Initial Catalog=DW;" ||
+		conflict.File2.Value != "Data Source=PROD03;Initial Catalog=DW;" {
+		t.Fatalf("unexpected conflict: %+v", conflict)
+	}
+}
+
+func TestFindConflictsIgnoresIdenticalChanges(t *testing.T) {
+	change := PackageChange{Section: "variables", Element: "Timeout", Kind: changeModified, Base: "30", Value: "60"}
+	if conflicts := findConflicts([]PackageChange{change}, []PackageChange{change}); len(conflicts) != 0 {
+		t.Fatalf("expected identical changes not to conflict, got %+v", conflicts)
+	}
+
+	removed := PackageChange{Section: "variables", Element: "Timeout", Kind: changeRemoved, Base: "30"}
+	if conflicts := findConflicts([]PackageChange{change}, []PackageChange{removed}); len(conflicts) != 1 {
+		t.Fatalf("expected modify/remove to conflict, got %+v", conflicts)
+	}
+}
@@ -0,0 +1,251 @@
+package packages
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/MCPRUNNER/gossisMCP/pkg/formatter"
+	"github.com/MCPRUNNER/gossisMCP/pkg/types"
+)
+
+// Kinds of change reported by the three-way comparison
+const (
+	changeAdded    = "added"
+	changeRemoved  = "removed"
+	changeModified = "modified"
+)
+
+// PackageChange is a single element that differs between the base package and a changed package
+type PackageChange struct {
+	Section string `json:"section"`
+	Element string `json:"element"`
+	Kind    string `json:"kind"`
+	Base    string `json:"base,omitempty"`
+	Value   string `json:"value,omitempty"`
+}
+
+// PackageConflict is an element changed differently on both sides of a three-way comparison
+type PackageConflict struct {
+	Section string        `json:"section"`
+	Element string        `json:"element"`
+	Base    string        `json:"base,omitempty"`
+	File1   PackageChange `json:"file1"`
+	File2   PackageChange `json:"file2"`
+}
+
+// comparableSections lists the package elements compared by diffPackages, keyed by a stable element name
+var comparableSections = []struct {
+	name     string
+	elements func(pkg types.SSISPackage) map[string]string
+}{
+	{"properties", func(pkg types.SSISPackage) map[string]string {
+		elements := make(map[string]string)
+		for _, p := range pkg.Properties {
+			elements[p.Name] = p.Value
+		}
+		return elements
+	}},
+	{"connections", func(pkg types.SSISPackage) map[string]string {
+		elements := make(map[string]string)
+		for _, c := range pkg.ConnectionMgr.Connections {
+			connStr := c.ObjectData.ConnectionMgr.ConnectionString
+			if connStr == "" {
+				connStr = c.ObjectData.MsmqConnMgr.ConnectionString
+			}
+			elements[c.Name] = connStr
+		}
+		return elements
+	}},
+	{"variables", func(pkg types.SSISPackage) map[string]string {
+		elements := make(map[string]string)
+		for _, v := range pkg.Variables.Vars {
+			elements[v.Name] = fmt.Sprintf("Value='%s', Expression='%s'", v.Value, v.Expression)
+		}
+		return elements
+	}},
+	{"parameters", func(pkg types.SSISPackage) map[string]string {
+		elements := make(map[string]string)
+		for _, p := range pkg.Parameters.Params {
+			elements[p.Name] = fmt.Sprintf("Type='%s', Value='%s'", p.DataType, p.Value)
+		}
+		return elements
+	}},
+	{"tasks", func(pkg types.SSISPackage) map[string]string {
+		elements := make(map[string]string)
+		for _, t := range pkg.Executables.Tasks {
+			props := make([]string, 0, len(t.Properties))
+			for _, p := range t.Properties {
+				props = append(props, fmt.Sprintf("%s=%s", p.Name, strings.TrimSpace(p.Value)))
+			}
+			sort.Strings(props)
+			elements[t.Name] = fmt.Sprintf("Type='%s', Properties=[%s]", t.CreationName, strings.Join(props, "; "))
+		}
+		return elements
+	}},
+	{"event_handlers", func(pkg types.SSISPackage) map[string]string {
+		elements := make(map[string]string)
+		for _, h := range pkg.EventHandlers.EventHandlers {
+			elements[eventHandlerName(h)] = fmt.Sprintf("Tasks=%d", len(h.Executables.Tasks))
+		}
+		return elements
+	}},
+	{"precedence_constraints", func(pkg types.SSISPackage) map[string]string {
+		elements := make(map[string]string)
+		for _, c := range pkg.PrecedenceConstraints.Constraints {
+			elements[fmt.Sprintf("%s -> %s", c.From, c.To)] = fmt.Sprintf("EvalOp='%s', Value='%s', Expression='%s'", c.EvalOp, c.Value, c.Expression)
+		}
+		return elements
+	}},
+}
+
+// diffPackages lists the elements added, removed or modified in other relative to base, ordered by section and element
+func diffPackages(base, other types.SSISPackage) []PackageChange {
+	changes := []PackageChange{}
+	for _, section := range comparableSections {
+		baseElements := section.elements(base)
+		otherElements := section.elements(other)
+
+		names := make([]string, 0, len(baseElements)+len(otherElements))
+		for name := range baseElements {
+			names = append(names, name)
+		}
+		for name := range otherElements {
+			if _, exists := baseElements[name]; !exists {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			baseValue, inBase := baseElements[name]
+			otherValue, inOther := otherElements[name]
+			switch {
+			case !inBase:
+				changes = append(changes, PackageChange{Section: section.name, Element: name, Kind: changeAdded, Value: otherValue})
+			case !inOther:
+				changes = append(changes, PackageChange{Section: section.name, Element: name, Kind: changeRemoved, Base: baseValue})
+			case baseValue != otherValue:
+				changes = append(changes, PackageChange{Section: section.name, Element: name, Kind: changeModified, Base: baseValue, Value: otherValue})
+			}
+		}
+	}
+	return changes
+}
+
+// findConflicts returns the elements changed on both sides where the two changes do not agree
+func findConflicts(changes1, changes2 []PackageChange) []PackageConflict {
+	byElement := make(map[string]PackageChange, len(changes2))
+	for _, change := range changes2 {
+		byElement[change.Section+"\x00"+change.Element] = change
+	}
+
+	conflicts := []PackageConflict{}
+	for _, change1 := range changes1 {
+		change2, ok := byElement[change1.Section+"\x00"+change1.Element]
+		if !ok || (change1.Kind == change2.Kind && change1.Value == change2.Value) {
+			continue
+		}
+		conflicts = append(conflicts, PackageConflict{
+			Section: change1.Section,
+			Element: change1.Element,
+			Base:    change1.Base,
+			File1:   change1,
+			File2:   change2,
+		})
+	}
+	return conflicts
+}
+
+// loadComparedPackage reads and parses one of the packages passed to compare_packages
+func loadComparedPackage(filePath, packageDirectory, label string) (types.SSISPackage, error) {
+	var pkg types.SSISPackage
+	data, err := os.ReadFile(resolveFilePath(filePath, packageDirectory))
+	if err != nil {
+		return pkg, fmt.Errorf("failed to read %s file: %v", label, err)
+	}
+	if err := xml.Unmarshal([]byte(strings.ReplaceAll(string(data), "DTS:", "")), &pkg); err != nil {
+		return pkg, fmt.Errorf("failed to parse %s file: %v", label, err)
+	}
+	return pkg, nil
+}
+
+// writeChanges renders the changes of one side of a three-way comparison
+func writeChanges(report *strings.Builder, changes []PackageChange) {
+	if len(changes) == 0 {
+		report.WriteString("  No changes\n")
+		return
+	}
+	for _, change := range changes {
+		switch change.Kind {
+		case changeAdded:
+			report.WriteString(fmt.Sprintf("  [%s] Added %s: %s\n", change.Section, change.Element, change.Value))
+		case changeRemoved:
+			report.WriteString(fmt.Sprintf("  [%s] Removed %s\n", change.Section, change.Element))
+		default:
+			report.WriteString(fmt.Sprintf("  [%s] Modified %s: %s -> %s\n", change.Section, change.Element, change.Base, change.Value))
+		}
+	}
+}
+
+// describeChange summarizes one side of a conflict
+func describeChange(change PackageChange) string {
+	if change.Kind == changeRemoved {
+		return "removed"
+	}
+	return fmt.Sprintf("%s to %s", change.Kind, change.Value)
+}
+
+// threeWayCompareResult compares file1 and file2 against a common base package
+func threeWayCompareResult(basePath, filePath1, filePath2 string, base, pkg1, pkg2 types.SSISPackage, format formatter.OutputFormat) *mcp.CallToolResult {
+	baseToFile1 := diffPackages(base, pkg1)
+	baseToFile2 := diffPackages(base, pkg2)
+	conflicts := findConflicts(baseToFile1, baseToFile2)
+
+	var report strings.Builder
+	report.WriteString("Three-Way Package Comparison Report\n\n")
+	report.WriteString(fmt.Sprintf("Base: %s\n", filepath.Base(basePath)))
+	report.WriteString(fmt.Sprintf("File 1: %s\n", filepath.Base(filePath1)))
+	report.WriteString(fmt.Sprintf("File 2: %s\n\n", filepath.Base(filePath2)))
+
+	report.WriteString(fmt.Sprintf("Base -> File 1 (%d change(s)):\n", len(baseToFile1)))
+	writeChanges(&report, baseToFile1)
+	report.WriteString(fmt.Sprintf("\nBase -> File 2 (%d change(s)):\n", len(baseToFile2)))
+	writeChanges(&report, baseToFile2)
+
+	report.WriteString(fmt.Sprintf("\nConflicts (%d):\n", len(conflicts)))
+	if len(conflicts) == 0 {
+		report.WriteString("  None - the changes can be merged\n")
+	}
+	for _, conflict := range conflicts {
+		report.WriteString(fmt.Sprintf("  [%s] %s\n", conflict.Section, conflict.Element))
+		report.WriteString(fmt.Sprintf("    Base: %s\n", conflict.Base))
+		report.WriteString(fmt.Sprintf("    File 1: %s\n", describeChange(conflict.File1)))
+		report.WriteString(fmt.Sprintf("    File 2: %s\n", describeChange(conflict.File2)))
+	}
+
+	analysisResult := formatter.CreateAnalysisResult("compare_packages", fmt.Sprintf("%s vs %s (base %s)", filePath1, filePath2, basePath), report.String(), nil)
+
+	if format == formatter.FormatJSON {
+		jsonResult := map[string]interface{}{
+			"tool_name":      analysisResult.ToolName,
+			"file_path_base": basePath,
+			"file_path1":     filePath1,
+			"file_path2":     filePath2,
+			"timestamp":      analysisResult.Timestamp,
+			"status":         analysisResult.Status,
+			"base_to_file1":  baseToFile1,
+			"base_to_file2":  baseToFile2,
+			"conflicts":      conflicts,
+			"analysis":       analysisResult.Data,
+		}
+		return mcp.NewToolResultStructured(jsonResult, "Three-way package comparison")
+	}
+
+	return mcp.NewToolResultText(formatter.FormatAnalysisResult(analysisResult, format))
+}
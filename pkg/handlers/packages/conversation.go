@@ -0,0 +1,197 @@
+package packages
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/MCPRUNNER/gossisMCP/pkg/types"
+)
+
+// ConversationTurn is a prior question or answer passed to ask_about_dtsx
+type ConversationTurn struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Topics answered by ask_about_dtsx
+const (
+	topicSummary       = ""
+	topicErrorHandling = "error_handling"
+	topicTasks         = "tasks"
+	topicConnections   = "connections"
+	topicVariables     = "variables"
+	topicValidation    = "validation"
+)
+
+// parseConversation reads the optional conversation argument, given either as an array or as a JSON string
+func parseConversation(request mcp.CallToolRequest) ([]ConversationTurn, error) {
+	raw, ok := request.GetArguments()["conversation"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	var data []byte
+	if text, ok := raw.(string); ok {
+		if strings.TrimSpace(text) == "" {
+			return nil, nil
+		}
+		data = []byte(text)
+	} else {
+		encoded, err := json.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid conversation: %v", err)
+		}
+		data = encoded
+	}
+
+	var turns []ConversationTurn
+	if err := json.Unmarshal(data, &turns); err != nil {
+		return nil, fmt.Errorf("invalid conversation: expected an array of {role, content} objects: %v", err)
+	}
+	for i := range turns {
+		turns[i].Role = strings.ToLower(strings.TrimSpace(turns[i].Role))
+		if turns[i].Role != "user" && turns[i].Role != "assistant" {
+			return nil, fmt.Errorf("invalid conversation: turn %d has role %q (supported: user, assistant)", i, turns[i].Role)
+		}
+	}
+	return turns, nil
+}
+
+// questionTopic classifies a lower-cased question by the keywords it contains
+func questionTopic(prompt string) string {
+	switch {
+	case strings.Contains(prompt, "error handling") || strings.Contains(prompt, "event handler") || strings.Contains(prompt, "onerror"):
+		return topicErrorHandling
+	case strings.Contains(prompt, "task") || strings.Contains(prompt, "executables"):
+		return topicTasks
+	case strings.Contains(prompt, "connection"):
+		return topicConnections
+	case strings.Contains(prompt, "variable"):
+		return topicVariables
+	case strings.Contains(prompt, "validate") || strings.Contains(prompt, "valid"):
+		return topicValidation
+	default:
+		return topicSummary
+	}
+}
+
+// conversationTopic returns the topic of the most recent prior user turn that names one
+func conversationTopic(conversation []ConversationTurn) string {
+	for i := len(conversation) - 1; i >= 0; i-- {
+		if conversation[i].Role != "user" {
+			continue
+		}
+		if topic := questionTopic(strings.ToLower(conversation[i].Content)); topic != topicSummary {
+			return topic
+		}
+	}
+	return topicSummary
+}
+
+// refersToPriorTurn reports whether a lower-cased question uses a pronoun that points back to an earlier turn
+func refersToPriorTurn(prompt string) bool {
+	for _, word := range strings.FieldsFunc(prompt, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		switch word {
+		case "it", "its", "that", "this", "they", "them", "their":
+			return true
+		}
+	}
+	return false
+}
+
+// referencedTask returns the task named in the question or, failing that, in the most recent prior turn that names one
+func referencedTask(pkg types.SSISPackage, question string, conversation []ConversationTurn) *types.Task {
+	var tasks []*types.Task
+	var collect func(executables []types.Task)
+	collect = func(executables []types.Task) {
+		for i := range executables {
+			tasks = append(tasks, &executables[i])
+			if executables[i].Executables != nil {
+				collect(executables[i].Executables.Tasks)
+			}
+		}
+	}
+	collect(pkg.Executables.Tasks)
+
+	find := func(text string) *types.Task {
+		text = strings.ToLower(text)
+		var best *types.Task
+		for _, task := range tasks {
+			// Prefer the longest matching name so "Load Customers Archive" wins over "Load Customers"
+			if task.Name != "" && strings.Contains(text, strings.ToLower(task.Name)) && (best == nil || len(task.Name) > len(best.Name)) {
+				best = task
+			}
+		}
+		return best
+	}
+
+	if task := find(question); task != nil {
+		return task
+	}
+	for i := len(conversation) - 1; i >= 0; i-- {
+		if task := find(conversation[i].Content); task != nil {
+			return task
+		}
+	}
+	return nil
+}
+
+// writeConversationContext prepends the prior turns to an ask_about_dtsx answer
+func writeConversationContext(answer *strings.Builder, conversation []ConversationTurn) {
+	if len(conversation) == 0 {
+		return
+	}
+	answer.WriteString("Conversation Context:\n")
+	for _, turn := range conversation {
+		answer.WriteString(fmt.Sprintf("- %s: %s\n", turn.Role, strings.TrimSpace(turn.Content)))
+	}
+	answer.WriteString("\n")
+}
+
+// writeErrorHandlingAnswer describes the event handlers of a task, or of the whole package when task is nil
+func writeErrorHandlingAnswer(answer *strings.Builder, pkg types.SSISPackage, task *types.Task) {
+	describe := func(handlers []types.EventHandler) {
+		if len(handlers) == 0 {
+			answer.WriteString("  No event handlers defined\n")
+			return
+		}
+		for _, handler := range handlers {
+			answer.WriteString(fmt.Sprintf("  - %s (%d task(s))\n", eventHandlerName(handler), len(handler.Executables.Tasks)))
+		}
+	}
+
+	if task != nil {
+		answer.WriteString(fmt.Sprintf("Error Handling for task '%s':\n", task.Name))
+		describe(task.EventHandlers.EventHandlers)
+		if len(pkg.EventHandlers.EventHandlers) > 0 {
+			answer.WriteString("Package-level event handlers also apply:\n")
+			describe(pkg.EventHandlers.EventHandlers)
+		}
+		return
+	}
+
+	answer.WriteString("Error Handling:\n")
+	answer.WriteString("Package:\n")
+	describe(pkg.EventHandlers.EventHandlers)
+	for _, t := range pkg.Executables.Tasks {
+		if len(t.EventHandlers.EventHandlers) > 0 {
+			answer.WriteString(fmt.Sprintf("Task '%s':\n", t.Name))
+			describe(t.EventHandlers.EventHandlers)
+		}
+	}
+}
+
+// eventHandlerName returns the event an event handler responds to
+func eventHandlerName(handler types.EventHandler) string {
+	if handler.EventName != "" {
+		return handler.EventName
+	}
+	if handler.EventHandlerType != "" {
+		return handler.EventHandlerType
+	}
+	return handler.ObjectName
+}
@@ -0,0 +1,117 @@
+package packages
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+)
+
+// CustomRuleSet is the contents of a validate_best_practices rules file
+type CustomRuleSet struct {
+	Rules []CustomRule `json:"rules"`
+}
+
+// CustomRule is an organization-specific best-practice check expressed as an XPath query over the DTSX XML.
+// In "forbid" mode (the default) every node matched by the query is reported as a finding; in "require"
+// mode a single finding is reported when the query matches nothing.
+type CustomRule struct {
+	ID       string `json:"id"`
+	XPath    string `json:"xpath"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Mode     string `json:"mode"`
+}
+
+// loadCustomRules reads and validates a custom rules file
+func loadCustomRules(path string) ([]CustomRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+
+	var ruleSet CustomRuleSet
+	if err := json.Unmarshal(data, &ruleSet); err != nil {
+		return nil, fmt.Errorf("failed to parse rules file: %w", err)
+	}
+
+	for i := range ruleSet.Rules {
+		rule := &ruleSet.Rules[i]
+		if strings.TrimSpace(rule.ID) == "" {
+			return nil, fmt.Errorf("rule %d is missing an id", i)
+		}
+		if strings.TrimSpace(rule.Message) == "" {
+			return nil, fmt.Errorf("rule %s is missing a message", rule.ID)
+		}
+		if _, err := xpath.Compile(rule.XPath); err != nil {
+			return nil, fmt.Errorf("rule %s has an invalid xpath: %v", rule.ID, err)
+		}
+		rule.Severity = strings.ToLower(rule.Severity)
+		if rule.Severity == "" {
+			rule.Severity = SeverityWarning
+		}
+		if _, ok := severityRank[rule.Severity]; !ok {
+			return nil, fmt.Errorf("rule %s has an invalid severity: %s", rule.ID, rule.Severity)
+		}
+		rule.Mode = strings.ToLower(rule.Mode)
+		if rule.Mode == "" {
+			rule.Mode = "forbid"
+		}
+		if rule.Mode != "forbid" && rule.Mode != "require" {
+			return nil, fmt.Errorf("rule %s has an invalid mode: %s (supported: forbid, require)", rule.ID, rule.Mode)
+		}
+	}
+	return ruleSet.Rules, nil
+}
+
+// evaluateCustomRules runs the custom rules against the DTSX XML. In forbid mode a {name} placeholder in
+// the message is replaced with the object name of the matched node or its nearest named ancestor.
+func evaluateCustomRules(rules []CustomRule, xmlData string) ([]BestPracticeFinding, error) {
+	doc, err := xmlquery.Parse(strings.NewReader(xmlData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse XML: %w", err)
+	}
+
+	var findings []BestPracticeFinding
+	for _, rule := range rules {
+		nodes, err := xmlquery.QueryAll(doc, rule.XPath)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.ID, err)
+		}
+
+		if rule.Mode == "require" {
+			if len(nodes) == 0 {
+				findings = append(findings, BestPracticeFinding{
+					Severity: rule.Severity,
+					Rule:     rule.ID,
+					Message:  rule.Message,
+				})
+			}
+			continue
+		}
+
+		for _, node := range nodes {
+			findings = append(findings, BestPracticeFinding{
+				Severity: rule.Severity,
+				Rule:     rule.ID,
+				Message:  strings.ReplaceAll(rule.Message, "{name}", nodeObjectName(node)),
+			})
+		}
+	}
+	return findings, nil
+}
+
+// nodeObjectName returns the ObjectName (or pipeline component name) of a node or its nearest named ancestor
+func nodeObjectName(node *xmlquery.Node) string {
+	for current := node; current != nil; current = current.Parent {
+		for _, attr := range current.Attr {
+			if attr.Name.Local == "ObjectName" || (attr.Name.Local == "name" && attr.Name.Space == "") {
+				return attr.Value
+			}
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,254 @@
+package packages
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/MCPRUNNER/gossisMCP/pkg/types"
+)
+
+// Log provider types reported by analyze_logging_configuration
+const (
+	logProviderSQLServer = "SQL Server"
+	logProviderEventLog  = "Windows Event Log"
+	logProviderTextFile  = "Text File"
+	logProviderXMLFile   = "XML File"
+	logProviderSSIS      = "SSIS Log Provider"
+)
+
+var logProviderTypes = []string{logProviderSQLServer, logProviderEventLog, logProviderTextFile, logProviderXMLFile, logProviderSSIS}
+
+// Logging modes of a LoggingOptions element
+const (
+	loggingModeUseParent = "0"
+	loggingModeEnabled   = "1"
+	loggingModeDisabled  = "2"
+)
+
+// loggingProvider is a log provider together with the events logged to it
+type loggingProvider struct {
+	Name         string   `json:"name"`
+	ID           string   `json:"id"`
+	Type         string   `json:"type"`
+	CreationName string   `json:"creation_name"`
+	Description  string   `json:"description,omitempty"`
+	ConfigString string   `json:"config_string"`
+	Events       []string `json:"events"`
+	Selected     bool     `json:"selected"`
+}
+
+// containerLogging is the logging setting of a task or container
+type containerLogging struct {
+	Name   string
+	Mode   string
+	Events []string
+}
+
+// loggingProviders converts the log providers defined by a package into report entries
+func loggingProviders(logProviders []types.LogProvider) []loggingProvider {
+	var providers []loggingProvider
+	for _, provider := range logProviders {
+		providers = append(providers, loggingProvider{
+			Name:         provider.ObjectName,
+			ID:           provider.DTSID,
+			Type:         logProviderType(provider.CreationName),
+			CreationName: provider.CreationName,
+			Description:  provider.Description,
+			ConfigString: provider.ConfigString,
+		})
+	}
+	return providers
+}
+
+// logProviderType maps a log provider CreationName to its provider type
+func logProviderType(creationName string) string {
+	switch {
+	case strings.Contains(creationName, "LogProviderSQLServer"):
+		return logProviderSQLServer
+	case strings.Contains(creationName, "LogProviderEventLog"):
+		return logProviderEventLog
+	case strings.Contains(creationName, "LogProviderTextFile"):
+		return logProviderTextFile
+	case strings.Contains(creationName, "LogProviderXMLFile"):
+		return logProviderXMLFile
+	default:
+		return logProviderSSIS
+	}
+}
+
+// normalizeLogProviderType resolves a provider_type argument such as "sql_server" or "Text File" to a provider type
+func normalizeLogProviderType(value string) (string, error) {
+	normalize := func(s string) string {
+		return strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(s))
+	}
+	wanted := normalize(value)
+	if wanted == "eventlog" {
+		return logProviderEventLog, nil
+	}
+	for _, providerType := range logProviderTypes {
+		if normalize(providerType) == wanted {
+			return providerType, nil
+		}
+	}
+	return "", fmt.Errorf("unknown provider_type: %s (supported: %s)", value, strings.Join(logProviderTypes, ", "))
+}
+
+// loggingMode returns the logging mode of a LoggingOptions element, falling back to the LoggingMode property used by older packages
+func loggingMode(options *types.LoggingOptions) string {
+	if options == nil {
+		return loggingModeUseParent
+	}
+	if options.LoggingMode != "" {
+		return options.LoggingMode
+	}
+	for _, prop := range options.Properties {
+		if prop.Name == "LoggingMode" {
+			return strings.TrimSpace(prop.Value)
+		}
+	}
+	return loggingModeUseParent
+}
+
+// loggingEvents parses the EventFilter property, e.g. "2,7,OnError,9,OnWarning", into event names
+func loggingEvents(options *types.LoggingOptions) []string {
+	if options == nil {
+		return nil
+	}
+	var events []string
+	for _, prop := range options.Properties {
+		if prop.Name != "EventFilter" {
+			continue
+		}
+		for _, part := range strings.Split(prop.Value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" || strings.Trim(part, "0123456789") == "" {
+				continue
+			}
+			events = append(events, part)
+		}
+	}
+	return events
+}
+
+// describeLoggingMode renders a logging mode for the report
+func describeLoggingMode(mode string) string {
+	switch mode {
+	case loggingModeEnabled:
+		return "Enabled"
+	case loggingModeDisabled:
+		return "Disabled"
+	default:
+		return "Use parent setting"
+	}
+}
+
+// loggingAnalysis is the result of walking the package and container logging settings
+type loggingAnalysis struct {
+	Providers         []loggingProvider
+	PackageMode       string
+	PackageEvents     []string
+	TaskLogging       []containerLogging
+	Misconfigurations []string
+}
+
+// analyzeLogging resolves the effective logging settings of every container, records the events logged
+// to each provider and reports settings that conflict between the package and its tasks
+func analyzeLogging(pkg types.SSISPackage, providers []loggingProvider) loggingAnalysis {
+	analysis := loggingAnalysis{
+		Providers:     providers,
+		PackageMode:   loggingMode(pkg.LoggingOptions),
+		PackageEvents: loggingEvents(pkg.LoggingOptions),
+	}
+
+	byID := make(map[string]int, len(providers))
+	for i, provider := range providers {
+		byID[provider.ID] = i
+	}
+	events := make(map[string]map[string]bool)
+	unknown := make(map[string]bool)
+
+	record := func(owner string, options *types.LoggingOptions) {
+		if len(options.SelectedProviders) == 0 {
+			analysis.Misconfigurations = append(analysis.Misconfigurations, fmt.Sprintf("Logging is enabled for '%s' but no log providers are selected", owner))
+			return
+		}
+		containerEvents := loggingEvents(options)
+		if len(containerEvents) == 0 {
+			containerEvents = []string{"(no event filter)"}
+		}
+		for _, selected := range options.SelectedProviders {
+			index, ok := byID[selected.InstanceID]
+			if !ok {
+				if !unknown[selected.InstanceID] {
+					unknown[selected.InstanceID] = true
+					analysis.Misconfigurations = append(analysis.Misconfigurations, fmt.Sprintf("'%s' selects log provider %s which is not defined in the package", owner, selected.InstanceID))
+				}
+				continue
+			}
+			analysis.Providers[index].Selected = true
+			if events[selected.InstanceID] == nil {
+				events[selected.InstanceID] = make(map[string]bool)
+			}
+			for _, event := range containerEvents {
+				events[selected.InstanceID][event] = true
+			}
+		}
+	}
+
+	packageEnabled := analysis.PackageMode == loggingModeEnabled
+	if packageEnabled {
+		record(pkg.ObjectName, pkg.LoggingOptions)
+	}
+
+	// Containers that use the parent setting log to the same providers as their parent, so only
+	// containers with their own setting need to be recorded
+	var walk func(tasks []types.Task)
+	walk = func(tasks []types.Task) {
+		for _, task := range tasks {
+			mode := loggingMode(task.LoggingOptions)
+			switch mode {
+			case loggingModeEnabled:
+				record(task.Name, task.LoggingOptions)
+				if !packageEnabled {
+					analysis.Misconfigurations = append(analysis.Misconfigurations, fmt.Sprintf("Logging is enabled for task '%s' but disabled at package level", task.Name))
+				}
+			case loggingModeDisabled:
+				if packageEnabled {
+					analysis.Misconfigurations = append(analysis.Misconfigurations, fmt.Sprintf("Logging is enabled at package level but disabled for task '%s'", task.Name))
+				}
+			}
+			if mode != loggingModeUseParent {
+				analysis.TaskLogging = append(analysis.TaskLogging, containerLogging{Name: task.Name, Mode: mode, Events: loggingEvents(task.LoggingOptions)})
+			}
+			if task.Executables != nil {
+				walk(task.Executables.Tasks)
+			}
+		}
+	}
+	walk(pkg.Executables.Tasks)
+
+	for i := range analysis.Providers {
+		provider := &analysis.Providers[i]
+		for event := range events[provider.ID] {
+			provider.Events = append(provider.Events, event)
+		}
+		sort.Strings(provider.Events)
+		if !provider.Selected {
+			analysis.Misconfigurations = append(analysis.Misconfigurations, fmt.Sprintf("Log provider '%s' is defined but not selected by any container", provider.Name))
+		}
+	}
+	return analysis
+}
+
+// groupLogProviders groups providers by type, keeping only providerType when it is set
+func groupLogProviders(providers []loggingProvider, providerType string) map[string][]loggingProvider {
+	groups := make(map[string][]loggingProvider)
+	for _, provider := range providers {
+		if providerType != "" && provider.Type != providerType {
+			continue
+		}
+		groups[provider.Type] = append(groups[provider.Type], provider)
+	}
+	return groups
+}
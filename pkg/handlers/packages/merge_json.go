@@ -9,6 +9,8 @@ import (
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/MCPRUNNER/gossisMCP/pkg/util/output"
 )
 
 // MergeJSONFilesHandler merges multiple JSON files into a single JSON object
@@ -56,6 +58,9 @@ func MergeJSONFilesHandler(ctx context.Context, request mcp.CallToolRequest, pac
 
 	// Process each file
 	for _, filePath := range filePaths {
+		if err := ctx.Err(); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("merge cancelled: %v", err)), nil
+		}
 		// Resolve file path
 		resolvedPath := resolveFilePath(filePath, packageDirectory)
 
@@ -94,15 +99,8 @@ func MergeJSONFilesHandler(ctx context.Context, request mcp.CallToolRequest, pac
 	// Write to output file if specified
 	if outputFilePath != "" {
 		resolvedOutputPath := resolveFilePath(outputFilePath, packageDirectory)
-
-		// Create directory if it doesn't exist
-		outputDir := filepath.Dir(resolvedOutputPath)
-		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to create output directory: %v", err)), nil
-		}
-
-		if err := os.WriteFile(resolvedOutputPath, []byte(outputContent), 0644); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to write output file: %v", err)), nil
+		if err := output.WriteOutput(resolvedOutputPath, outputContent); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 	}
 
@@ -1,20 +1,27 @@
 package packages
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"io"
+	"net"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/mark3labs/mcp-go/mcp"
 
 	"github.com/MCPRUNNER/gossisMCP/pkg/formatter"
 	"github.com/MCPRUNNER/gossisMCP/pkg/types"
+	"github.com/MCPRUNNER/gossisMCP/pkg/util/output"
 )
 
 // HandleValidateBestPractices performs a simple best-practices sweep of an SSIS package.
@@ -28,6 +35,15 @@ func HandleValidateBestPractices(_ context.Context, request mcp.CallToolRequest,
 	formatStr := request.GetString("format", "text")
 	format := formatter.OutputFormat(formatStr)
 
+	minSeverity := strings.ToLower(request.GetString("min_severity", SeverityInfo))
+	if _, ok := severityRank[minSeverity]; !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid min_severity: %s (supported: error, warning, info)", minSeverity)), nil
+	}
+	minDescriptionLength := request.GetInt("min_description_length", defaultMinDescriptionLength)
+	if minDescriptionLength < 1 {
+		return mcp.NewToolResultError("min_description_length must be at least 1"), nil
+	}
+
 	resolvedPath := resolveFilePath(filePath, packageDirectory)
 
 	data, err := os.ReadFile(resolvedPath)
@@ -67,34 +83,57 @@ func HandleValidateBestPractices(_ context.Context, request mcp.CallToolRequest,
 		return mcp.NewToolResultText(formatter.FormatAnalysisResult(result, format)), nil
 	}
 
+	passes, findings := evaluateBestPractices(pkg, cleaned, minDescriptionLength)
+	if rulesFile := request.GetString("rules_file", ""); rulesFile != "" {
+		rules, err := loadCustomRules(resolveFilePath(rulesFile, packageDirectory))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		customFindings, err := evaluateCustomRules(rules, string(data))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		findings = append(findings, customFindings...)
+	}
+	findings = filterFindings(findings, minSeverity)
+
 	var report strings.Builder
 	report.WriteString("Best Practices Validation Report:\n")
-
-	if len(pkg.Variables.Vars) == 0 {
-		report.WriteString("- WARNING: No user-defined variables found\n")
-	} else {
-		report.WriteString(fmt.Sprintf("- OK: %d variables defined\n", len(pkg.Variables.Vars)))
+	for _, pass := range passes {
+		report.WriteString(fmt.Sprintf("- OK: %s\n", pass.Message))
 	}
-
-	if len(pkg.ConnectionMgr.Connections) == 0 {
-		report.WriteString("- WARNING: No connection managers defined\n")
-	} else {
-		report.WriteString(fmt.Sprintf("- OK: %d connection managers defined\n", len(pkg.ConnectionMgr.Connections)))
+	counts := make(map[string]int)
+	for _, finding := range findings {
+		report.WriteString(fmt.Sprintf("- %s: %s\n", strings.ToUpper(finding.Severity), finding.Message))
+		counts[finding.Severity]++
 	}
+	report.WriteString(fmt.Sprintf("\nFindings (min severity %s): %d error(s), %d warning(s), %d info\n",
+		minSeverity, counts[SeverityError], counts[SeverityWarning], counts[SeverityInfo]))
 
-	if len(pkg.Executables.Tasks) == 0 {
-		report.WriteString("- ERROR: No executable tasks found\n")
-	} else {
-		report.WriteString(fmt.Sprintf("- OK: %d tasks defined\n", len(pkg.Executables.Tasks)))
-	}
+	report.WriteString("- Note: This is a basic validation. Review SSIS best-practices for deeper guidance.\n")
 
-	if strings.Contains(cleaned, "LoggingOptions") {
-		report.WriteString("- OK: Logging configuration detected\n")
-	} else {
-		report.WriteString("- WARNING: No logging configuration found\n")
+	if format == formatter.FormatSARIF {
+		sarifFindings := make([]formatter.Finding, 0, len(findings))
+		for _, finding := range findings {
+			sarifFindings = append(sarifFindings, formatter.Finding{RuleID: finding.Rule, Message: finding.Message, Level: finding.Severity, URI: filePath})
+		}
+		return mcp.NewToolResultText(formatter.FormatAsSARIF("validate_best_practices", sarifFindings)), nil
+	}
+	if format == formatter.FormatJUnit {
+		cases := make([]formatter.TestCase, 0, len(passes)+len(findings))
+		for _, pass := range passes {
+			cases = append(cases, formatter.TestCase{Name: pass.Rule})
+		}
+		for _, finding := range findings {
+			cases = append(cases, formatter.TestCase{Name: finding.Rule, Failure: finding.Message, Details: "Severity: " + finding.Severity})
+		}
+		return mcp.NewToolResultText(formatter.FormatAsJUnit("validate_best_practices", filePath, cases)), nil
 	}
 
-	report.WriteString("- Note: This is a basic validation. Review SSIS best-practices for deeper guidance.\n")
+	if format == formatter.FormatTable {
+		analysisResult := formatter.CreateAnalysisResult("validate_best_practices", filePath, findings, nil)
+		return mcp.NewToolResultText(formatter.FormatAnalysisResult(analysisResult, format)), nil
+	}
 
 	analysisResult := formatter.CreateAnalysisResult("validate_best_practices", filePath, report.String(), nil)
 
@@ -107,6 +146,7 @@ func HandleValidateBestPractices(_ context.Context, request mcp.CallToolRequest,
 			"timestamp": analysisResult.Timestamp,
 			"status":    analysisResult.Status,
 			"analysis":  analysisResult.Data,
+			"findings":  findings,
 		}
 		if analysisResult.Error != "" {
 			jsonResult["error"] = analysisResult.Error
@@ -117,6 +157,233 @@ func HandleValidateBestPractices(_ context.Context, request mcp.CallToolRequest,
 	return mcp.NewToolResultText(formatter.FormatAnalysisResult(analysisResult, format)), nil
 }
 
+// Severity levels for best-practice findings, from most to least severe
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+var severityRank = map[string]int{SeverityInfo: 0, SeverityWarning: 1, SeverityError: 2}
+
+// defaultMinDescriptionLength is the shortest package or task description that is not flagged
+const defaultMinDescriptionLength = 10
+
+// BestPracticeFinding is a single best-practice violation with its severity
+type BestPracticeFinding struct {
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+}
+
+// bestPracticePass is a built-in best-practice check that passed
+type bestPracticePass struct {
+	Rule    string
+	Message string
+}
+
+// evaluateBestPractices runs the built-in checks and returns the passed checks and the findings
+func evaluateBestPractices(pkg types.SSISPackage, cleaned string, minDescriptionLength int) ([]bestPracticePass, []BestPracticeFinding) {
+	var passes []bestPracticePass
+	var findings []BestPracticeFinding
+	add := func(severity, rule, message string) {
+		findings = append(findings, BestPracticeFinding{Severity: severity, Rule: rule, Message: message})
+	}
+	pass := func(rule, message string) {
+		passes = append(passes, bestPracticePass{Rule: rule, Message: message})
+	}
+
+	if len(pkg.Executables.Tasks) == 0 {
+		add(SeverityError, "no_tasks", "No executable tasks found")
+	} else {
+		pass("no_tasks", fmt.Sprintf("%d tasks defined", len(pkg.Executables.Tasks)))
+	}
+
+	if hasOnErrorHandler(pkg) {
+		pass("missing_error_handling", "OnError event handler defined")
+	} else if len(pkg.Executables.Tasks) > 0 {
+		add(SeverityError, "missing_error_handling", "No OnError event handler found; task failures are not handled")
+	}
+
+	if len(pkg.Variables.Vars) == 0 {
+		add(SeverityWarning, "no_variables", "No user-defined variables found")
+	} else {
+		pass("no_variables", fmt.Sprintf("%d variables defined", len(pkg.Variables.Vars)))
+	}
+
+	if len(pkg.ConnectionMgr.Connections) == 0 {
+		add(SeverityWarning, "no_connections", "No connection managers defined")
+	} else {
+		pass("no_connections", fmt.Sprintf("%d connection managers defined", len(pkg.ConnectionMgr.Connections)))
+	}
+
+	if strings.Contains(cleaned, "LoggingOptions") {
+		pass("no_logging", "Logging configuration detected")
+	} else {
+		add(SeverityWarning, "no_logging", "No logging configuration found")
+	}
+
+	for _, conn := range pkg.ConnectionMgr.Connections {
+		if strings.TrimSpace(conn.Description) == "" {
+			add(SeverityInfo, "connection_description", fmt.Sprintf("Connection manager '%s' has no description", conn.Name))
+		}
+	}
+
+	tasks := flattenTasks(pkg.Executables.Tasks)
+	describedTasks := 0
+	if issue := descriptionIssue(pkg.Description, minDescriptionLength); issue != "" {
+		add(SeverityWarning, "package_description", fmt.Sprintf("Package %s", issue))
+	} else {
+		pass("package_description", "Package has a description")
+	}
+	for _, task := range tasks {
+		if issue := descriptionIssue(task.Description, minDescriptionLength); issue != "" {
+			add(SeverityWarning, "task_description", fmt.Sprintf("Task '%s' %s", task.Name, issue))
+		} else {
+			describedTasks++
+		}
+	}
+	if len(tasks) > 0 && describedTasks == len(tasks) {
+		pass("task_description", fmt.Sprintf("All %d tasks have descriptions", len(tasks)))
+	}
+
+	lookups, unconnected := 0, 0
+	for _, task := range tasks {
+		for _, component := range task.ObjectData.DataFlow.Components.Components {
+			if !strings.EqualFold(component.ComponentClassID, "Microsoft.Sort") {
+				continue
+			}
+			add(SeverityWarning, "sort_transformation", fmt.Sprintf("Sort '%s' in data flow '%s' is a blocking transformation that holds every row in memory; sort with ORDER BY in the upstream source query instead", component.Name, task.Name))
+			if componentPropertyEnabled(component, "EliminateDuplicates") {
+				add(SeverityWarning, "sort_eliminate_duplicates", fmt.Sprintf("Sort '%s' in data flow '%s' has EliminateDuplicates enabled and discards rows with duplicate sort key values; confirm this is intended", component.Name, task.Name))
+			}
+		}
+
+		connected := make(map[string]bool)
+		for _, path := range task.ObjectData.DataFlow.Paths.Paths {
+			connected[path.StartID] = true
+		}
+		for _, component := range task.ObjectData.DataFlow.Components.Components {
+			if !isLookupComponent(component) {
+				continue
+			}
+			lookups++
+			for _, output := range unconnectedLookupOutputs(component, connected) {
+				unconnected++
+				add(SeverityError, "lookup_unconnected_output", fmt.Sprintf("Lookup '%s' in data flow '%s' has no path from its '%s' output; rows sent to it are silently dropped", component.Name, task.Name, output))
+			}
+		}
+	}
+	if lookups > 0 && unconnected == 0 {
+		pass("lookup_unconnected_output", fmt.Sprintf("All outputs of %d Lookup components are connected", lookups))
+	}
+
+	implicitTransactions := 0
+	for _, task := range tasks {
+		if isSupportedTransactionOption(task.TransactionOption) {
+			implicitTransactions++
+			add(SeverityWarning, "transaction_option", fmt.Sprintf("Task '%s' uses TransactionOption Supported, which silently joins any ambient transaction; set it explicitly to Required or NotSupported", task.Name))
+		}
+	}
+	if len(tasks) > 0 && implicitTransactions == 0 {
+		pass("transaction_option", "All tasks set TransactionOption to Required or NotSupported")
+	}
+
+	return passes, findings
+}
+
+// descriptionIssue describes why a description is missing or too short, or returns "" when it is acceptable
+func descriptionIssue(description string, minLength int) string {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return "has no description"
+	}
+	if utf8.RuneCountInString(description) < minLength {
+		return fmt.Sprintf("description '%s' is shorter than %d characters", description, minLength)
+	}
+	return ""
+}
+
+// componentPropertyEnabled reports whether a boolean data flow component property is set to true
+func componentPropertyEnabled(component types.DataFlowComponent, name string) bool {
+	for _, prop := range component.Properties.Properties {
+		if prop.Name == name {
+			value := strings.TrimSpace(prop.Value)
+			return strings.EqualFold(value, "true") || value == "1" || value == "-1"
+		}
+	}
+	return false
+}
+
+// isLookupComponent reports whether a data flow component is a Lookup transformation, identified by its SSIS 2012+
+// class ID or the class ID GUID used by SSIS 2008
+func isLookupComponent(component types.DataFlowComponent) bool {
+	return strings.EqualFold(component.ComponentClassID, "Microsoft.Lookup") ||
+		strings.EqualFold(component.ComponentClassID, "{671046B0-AA63-4C9F-90E4-C06E0B710CE3}")
+}
+
+// unconnectedLookupOutputs returns the names of a Lookup's non-error outputs that no data path starts from. The
+// no match output only receives rows when NoMatchBehavior is 1 (redirect rows to no match output)
+func unconnectedLookupOutputs(component types.DataFlowComponent, connected map[string]bool) []string {
+	redirectsNoMatch := false
+	for _, prop := range component.Properties.Properties {
+		if prop.Name == "NoMatchBehavior" {
+			redirectsNoMatch = strings.TrimSpace(prop.Value) == "1"
+		}
+	}
+
+	var outputs []string
+	for _, output := range component.Outputs.Outputs {
+		if output.IsErrorOut || connected[output.RefID] {
+			continue
+		}
+		if strings.Contains(strings.ToLower(output.Name), "no match") && !redirectsNoMatch {
+			continue
+		}
+		outputs = append(outputs, output.Name)
+	}
+	return outputs
+}
+
+// isSupportedTransactionOption reports whether a TransactionOption value is Supported, the default when the
+// attribute is omitted. DTSX files store NotSupported, Supported and Required as 0, 1 and 2
+func isSupportedTransactionOption(option string) bool {
+	option = strings.TrimSpace(option)
+	return option == "" || option == "1" || strings.EqualFold(option, "Supported")
+}
+
+// hasOnErrorHandler reports whether the package or any of its tasks defines an OnError event handler
+func hasOnErrorHandler(pkg types.SSISPackage) bool {
+	isOnError := func(handlers []types.EventHandler) bool {
+		for _, handler := range handlers {
+			if strings.EqualFold(handler.EventName, "OnError") || strings.EqualFold(handler.EventHandlerType, "OnError") {
+				return true
+			}
+		}
+		return false
+	}
+	if isOnError(pkg.EventHandlers.EventHandlers) {
+		return true
+	}
+	for _, task := range flattenTasks(pkg.Executables.Tasks) {
+		if isOnError(task.EventHandlers.EventHandlers) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterFindings keeps the findings at or above the minimum severity
+func filterFindings(findings []BestPracticeFinding, minSeverity string) []BestPracticeFinding {
+	filtered := make([]BestPracticeFinding, 0, len(findings))
+	for _, finding := range findings {
+		if severityRank[finding.Severity] >= severityRank[minSeverity] {
+			filtered = append(filtered, finding)
+		}
+	}
+	return filtered
+}
+
 // HandleAskAboutDtsx answers lightweight questions about a DTSX file.
 func HandleAskAboutDtsx(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
 	filePath, err := request.RequireString("file_path")
@@ -129,6 +396,11 @@ func HandleAskAboutDtsx(_ context.Context, request mcp.CallToolRequest, packageD
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
+	conversation, err := parseConversation(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	// Get format parameter (default to "text")
 	formatStr := request.GetString("format", "text")
 	format := formatter.OutputFormat(formatStr)
@@ -173,10 +445,39 @@ func HandleAskAboutDtsx(_ context.Context, request mcp.CallToolRequest, packageD
 	}
 
 	answer := strings.Builder{}
+	writeConversationContext(&answer, conversation)
+
+	// Follow-up questions such as "what about its error handling?" take their topic and subject from earlier turns
 	prompt := strings.ToLower(question)
+	topic := questionTopic(prompt)
+	subject := referencedTask(pkg, question, nil)
+	if len(conversation) > 0 && (topic == topicSummary || refersToPriorTurn(prompt)) {
+		if subject == nil {
+			subject = referencedTask(pkg, "", conversation)
+		}
+		if topic == topicSummary {
+			topic = conversationTopic(conversation)
+			if topic == topicSummary && subject != nil {
+				topic = topicTasks
+			}
+		}
+	}
 
-	switch {
-	case strings.Contains(prompt, "task") || strings.Contains(prompt, "executables"):
+	switch topic {
+	case topicErrorHandling:
+		writeErrorHandlingAnswer(&answer, pkg, subject)
+	case topicTasks:
+		if subject != nil {
+			answer.WriteString(fmt.Sprintf("Task '%s':\n", subject.Name))
+			answer.WriteString(fmt.Sprintf("   Type: %s\n", subject.CreationName))
+			if subject.Description != "" {
+				answer.WriteString(fmt.Sprintf("   Description: %s\n", subject.Description))
+			}
+			if subject.Executables != nil && len(subject.Executables.Tasks) > 0 {
+				answer.WriteString(fmt.Sprintf("   Child Tasks: %d\n", len(subject.Executables.Tasks)))
+			}
+			break
+		}
 		answer.WriteString("Tasks:\n")
 		for i, task := range pkg.Executables.Tasks {
 			answer.WriteString(fmt.Sprintf("%d. %s\n", i+1, task.Name))
@@ -186,7 +487,7 @@ func HandleAskAboutDtsx(_ context.Context, request mcp.CallToolRequest, packageD
 				}
 			}
 		}
-	case strings.Contains(prompt, "connection"):
+	case topicConnections:
 		answer.WriteString("Connections:\n")
 		for i, conn := range pkg.ConnectionMgr.Connections {
 			answer.WriteString(fmt.Sprintf("%d. %s\n", i+1, conn.Name))
@@ -196,12 +497,12 @@ func HandleAskAboutDtsx(_ context.Context, request mcp.CallToolRequest, packageD
 			}
 			answer.WriteString(fmt.Sprintf("   Connection String: %s\n", connStr))
 		}
-	case strings.Contains(prompt, "variable"):
+	case topicVariables:
 		answer.WriteString("Variables:\n")
 		for i, v := range pkg.Variables.Vars {
 			answer.WriteString(fmt.Sprintf("%d. %s = %s\n", i+1, v.Name, v.Value))
 		}
-	case strings.Contains(prompt, "validate") || strings.Contains(prompt, "valid"):
+	case topicValidation:
 		if len(pkg.Properties) == 0 {
 			answer.WriteString("Validation: Warning - No properties found\n")
 		} else {
@@ -270,24 +571,99 @@ func HandleAnalyzeMessageQueueTasks(_ context.Context, request mcp.CallToolReque
 	var report strings.Builder
 	report.WriteString("Message Queue Tasks Analysis:\n")
 
+	queueConnections := make(map[string]types.Connection)
+	for _, conn := range pkg.ConnectionMgr.Connections {
+		queueConnections[conn.Name] = conn
+		queueConnections[conn.DTSID] = conn
+	}
+
 	found := false
 	for i, task := range pkg.Executables.Tasks {
-		if strings.Contains(strings.ToLower(task.Name), "message queue") {
-			found = true
-			report.WriteString(fmt.Sprintf("Task %d: %s\n", i+1, task.Name))
+		if !strings.Contains(task.CreationName, "MessageQueueTask") && !strings.Contains(strings.ToLower(task.Name), "message queue") {
+			continue
+		}
+		found = true
+		report.WriteString(fmt.Sprintf("Task %d: %s\n", i+1, task.Name))
 
-			mqData := task.ObjectData.Task.MessageQueueTask.MessageQueueTaskData
-			if mqData.MessageType != "" {
-				report.WriteString(fmt.Sprintf("  Message Type: %s\n", mqData.MessageType))
+		mqProps := task.ObjectData.MessageQueueTask
+		legacyData := task.ObjectData.Task.MessageQueueTask.MessageQueueTaskData
+
+		operation := "Send"
+		if strings.Contains(strings.ToLower(mqProps.Attribute("TaskType")), "receiver") {
+			operation = "Receive"
+		}
+		report.WriteString(fmt.Sprintf("  Operation: %s\n", operation))
+
+		var securityFindings []string
+		if queueRef := mqProps.Attribute("QueuePath", "MQConnection", "Connection"); queueRef != "" {
+			if conn, ok := queueConnections[queueRef]; ok {
+				if isFalseSetting(conn.ObjectData.MsmqConnMgr.UseWindowsAuthentication) {
+					securityFindings = append(securityFindings, fmt.Sprintf("WARNING: Connection '%s' sets UseWindowsAuthentication to False; messages are not authenticated with a Windows identity", conn.Name))
+				}
+				report.WriteString(fmt.Sprintf("  Queue Connection: %s\n", conn.Name))
+				if queuePath := msmqQueuePath(conn.ObjectData.MsmqConnMgr.ConnectionString); queuePath != "" {
+					report.WriteString(fmt.Sprintf("  Queue Path: %s\n", queuePath))
+				}
+			} else {
+				report.WriteString(fmt.Sprintf("  Queue Connection: %s (not found in connection managers)\n", queueRef))
 			}
-			if mqData.Message != "" {
-				report.WriteString(fmt.Sprintf("  Message Content: %s\n", mqData.Message))
+		}
+
+		messageType := mqProps.Attribute("MessageType", "ReceiveMessageType")
+		if messageType == "" {
+			messageType = legacyData.MessageType
+		}
+		if messageType != "" {
+			report.WriteString(fmt.Sprintf("  Message Type: %s\n", describeMessageType(messageType)))
+		}
+
+		message := mqProps.Attribute("StringMessage")
+		if message == "" {
+			message = legacyData.Message
+		}
+		if message != "" {
+			report.WriteString(fmt.Sprintf("  Message Content: %s\n", message))
+		}
+		if variable := mqProps.Attribute("StringMessageToVariableName"); variable != "" {
+			report.WriteString(fmt.Sprintf("  Target Variable: %s\n", variable))
+		}
+		if filter := mqProps.Attribute("Filter"); filter != "" {
+			report.WriteString(fmt.Sprintf("  Filter: %s\n", strings.TrimPrefix(filter, "DTSMQFilter_")))
+		}
+		if remove := mqProps.Attribute("RemoveFromQueue"); remove != "" {
+			report.WriteString(fmt.Sprintf("  Remove From Queue: %s\n", remove))
+		}
+		useEncryption := mqProps.Attribute("UseEncryption")
+		if useEncryption != "" {
+			report.WriteString(fmt.Sprintf("  Use Encryption: %s\n", useEncryption))
+		}
+		// UseEncryption defaults to False when the attribute is omitted
+		if useEncryption == "" || isFalseSetting(useEncryption) {
+			securityFindings = append(securityFindings, "WARNING: UseEncryption is False; messages are transmitted over MSMQ in plaintext")
+		}
+		if isFalseSetting(mqProps.Attribute("UseWindowsAuthentication")) {
+			securityFindings = append(securityFindings, "WARNING: UseWindowsAuthentication is False; messages are not authenticated with a Windows identity")
+		}
+		if algorithm := mqProps.Attribute("EncryptionAlgorithm"); algorithm != "" {
+			report.WriteString(fmt.Sprintf("  Encryption Algorithm: %s\n", describeEncryptionAlgorithm(algorithm)))
+		}
+
+		description := task.Description
+		for _, prop := range task.Properties {
+			if prop.Name == "Description" {
+				description = prop.Value
 			}
+		}
+		if strings.TrimSpace(description) != "" {
+			report.WriteString(fmt.Sprintf("  Description: %s\n", strings.TrimSpace(description)))
+		}
 
-			for _, prop := range task.Properties {
-				if prop.Name == "Description" {
-					report.WriteString(fmt.Sprintf("  Description: %s\n", strings.TrimSpace(prop.Value)))
-				}
+		if len(securityFindings) == 0 {
+			report.WriteString("  Security Findings: none\n")
+		} else {
+			report.WriteString("  Security Findings:\n")
+			for _, finding := range securityFindings {
+				report.WriteString(fmt.Sprintf("    - %s\n", finding))
 			}
 		}
 	}
@@ -299,6 +675,257 @@ func HandleAnalyzeMessageQueueTasks(_ context.Context, request mcp.CallToolReque
 	return mcp.NewToolResultText(report.String()), nil
 }
 
+// isFalseSetting reports whether a boolean task or connection setting is explicitly false
+func isFalseSetting(value string) bool {
+	value = strings.TrimSpace(value)
+	return strings.EqualFold(value, "false") || value == "0"
+}
+
+// msmqQueuePath returns the queue path of an MSMQ connection string, stripping a FormatName:DIRECT= prefix
+func msmqQueuePath(connStr string) string {
+	path := strings.TrimSpace(connStr)
+	if len(path) >= len("FormatName:") && strings.EqualFold(path[:len("FormatName:")], "FormatName:") {
+		path = path[len("FormatName:"):]
+		if len(path) >= len("DIRECT=") && strings.EqualFold(path[:len("DIRECT=")], "DIRECT=") {
+			if _, address, ok := strings.Cut(path, ":"); ok {
+				path = address
+			}
+		}
+	}
+	return path
+}
+
+// describeMessageType maps DTSMQMessageType values, named or numeric, to Data File, Variables or String
+func describeMessageType(value string) string {
+	lower := strings.ToLower(value)
+	switch {
+	case lower == "0" || strings.Contains(lower, "datafile"):
+		return "Data File"
+	case lower == "3" || strings.Contains(lower, "stringmessagetovariable"):
+		return "String Message to Variable"
+	case lower == "1" || strings.Contains(lower, "variable"):
+		return "Variables"
+	case lower == "2" || strings.Contains(lower, "string"):
+		return "String"
+	default:
+		return value
+	}
+}
+
+// describeEncryptionAlgorithm maps DTSMQEncryptionAlgorithm values to RC2 or RC4
+func describeEncryptionAlgorithm(value string) string {
+	lower := strings.ToLower(value)
+	switch {
+	case lower == "0" || strings.HasSuffix(lower, "rc2"):
+		return "RC2"
+	case lower == "1" || strings.HasSuffix(lower, "rc4"):
+		return "RC4"
+	default:
+		return value
+	}
+}
+
+// HandleAnalyzeCdcControlTask inspects CDC Control Tasks that manage CDC state.
+func HandleAnalyzeCdcControlTask(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
+	filePath, err := request.RequireString("file_path")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	format := formatter.OutputFormat(request.GetString("format", "text"))
+	outputPath := request.GetString("output_file_path", "")
+
+	resolvedPath := resolveFilePath(filePath, packageDirectory)
+
+	data, err := os.ReadFile(resolvedPath)
+	if err != nil {
+		return formattedTaskResult("CDC Control Task Analysis", filePath, nil, err, format, outputPath, packageDirectory)
+	}
+
+	cleaned := strings.ReplaceAll(string(data), "DTS:", "")
+
+	var pkg types.SSISPackage
+	if err := xml.Unmarshal([]byte(cleaned), &pkg); err != nil {
+		return formattedTaskResult("CDC Control Task Analysis", filePath, nil, err, format, outputPath, packageDirectory)
+	}
+
+	connectionName := func(ref string) string {
+		for _, conn := range pkg.ConnectionMgr.Connections {
+			if conn.DTSID == ref || conn.Name == ref {
+				return conn.Name
+			}
+		}
+		return ref
+	}
+	valueOrDefault := func(value string) string {
+		if value == "" {
+			return "Not specified"
+		}
+		return value
+	}
+
+	var report strings.Builder
+	report.WriteString("CDC Control Tasks Analysis:\n")
+
+	count := 0
+	for _, task := range flattenTasks(pkg.Executables.Tasks) {
+		if !strings.Contains(strings.ToLower(task.CreationName), "cdccontroltask") {
+			continue
+		}
+		count++
+		details := task.ObjectData.CdcControlTask
+		report.WriteString(fmt.Sprintf("Task %d: %s\n", count, task.Name))
+		report.WriteString(fmt.Sprintf("  Operation: %s\n", valueOrDefault(describeCdcOperation(details.Attribute("CdcOperationType", "TaskOperation")))))
+		report.WriteString(fmt.Sprintf("  Connection: %s\n", valueOrDefault(connectionName(details.Attribute("Connection")))))
+		report.WriteString(fmt.Sprintf("  State Name: %s\n", valueOrDefault(details.Attribute("StateName"))))
+		report.WriteString(fmt.Sprintf("  State Variable: %s\n", valueOrDefault(details.Attribute("StateVariableName", "StateVariable"))))
+		report.WriteString(fmt.Sprintf("  Automatic State Persistence: %s\n", valueOrDefault(details.Attribute("AutomaticStatePersistence"))))
+		if stateConnection := details.Attribute("StateConnection"); stateConnection != "" {
+			report.WriteString(fmt.Sprintf("  State Connection: %s\n", connectionName(stateConnection)))
+		}
+		if stateTable := details.Attribute("StateTable"); stateTable != "" {
+			report.WriteString(fmt.Sprintf("  State Table: %s\n", stateTable))
+		}
+		report.WriteString("\n")
+	}
+
+	if count == 0 {
+		report.WriteString("No CDC Control Tasks found in this package.\n")
+	}
+
+	return formattedTaskResult("CDC Control Task Analysis", filePath, report.String(), nil, format, outputPath, packageDirectory)
+}
+
+// formattedTaskResult formats a task analysis report and writes it to outputPath when one is given
+func formattedTaskResult(toolName, filePath string, data interface{}, analysisErr error, format formatter.OutputFormat, outputPath, packageDirectory string) (*mcp.CallToolResult, error) {
+	report := formatter.FormatAnalysisResult(formatter.CreateAnalysisResult(toolName, filePath, data, analysisErr), format)
+	if outputPath != "" {
+		if err := output.WriteOutput(resolveFilePath(outputPath, packageDirectory), report); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	return mcp.NewToolResultText(report), nil
+}
+
+// cdcOperationNames maps the numeric CDC Control Task operation codes to their names
+var cdcOperationNames = map[string]string{
+	"0": "MarkInitialLoadStart",
+	"1": "MarkInitialLoadEnd",
+	"2": "MarkCdcStart",
+	"3": "GetProcessingRange",
+	"4": "MarkProcessedRange",
+	"5": "ResetCdcState",
+}
+
+// describeCdcOperation names a CDC Control Task operation stored as a numeric code; names pass through unchanged
+func describeCdcOperation(value string) string {
+	if name, ok := cdcOperationNames[value]; ok {
+		return fmt.Sprintf("%s (%s)", name, value)
+	}
+	return value
+}
+
+// HandleAnalyzeWmiTask inspects WMI Data Reader and WMI Event Watcher tasks.
+func HandleAnalyzeWmiTask(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
+	filePath, err := request.RequireString("file_path")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	format := formatter.OutputFormat(request.GetString("format", "text"))
+	outputPath := request.GetString("output_file_path", "")
+
+	resolvedPath := resolveFilePath(filePath, packageDirectory)
+
+	data, err := os.ReadFile(resolvedPath)
+	if err != nil {
+		return formattedTaskResult("WMI Task Analysis", filePath, nil, err, format, outputPath, packageDirectory)
+	}
+
+	cleaned := strings.ReplaceAll(string(data), "DTS:", "")
+
+	var pkg types.SSISPackage
+	if err := xml.Unmarshal([]byte(cleaned), &pkg); err != nil {
+		return formattedTaskResult("WMI Task Analysis", filePath, nil, err, format, outputPath, packageDirectory)
+	}
+
+	connectionName := func(ref string) string {
+		for _, conn := range pkg.ConnectionMgr.Connections {
+			if conn.DTSID == ref || conn.Name == ref {
+				return conn.Name
+			}
+		}
+		return ref
+	}
+	valueOrDefault := func(value string) string {
+		if value == "" {
+			return "Not specified"
+		}
+		return value
+	}
+
+	var report strings.Builder
+	report.WriteString("WMI Tasks Analysis:\n")
+
+	count := 0
+	for _, task := range flattenTasks(pkg.Executables.Tasks) {
+		creationName := strings.ToLower(task.CreationName)
+		switch {
+		case strings.Contains(creationName, "wmidatareadertask"):
+			count++
+			details := task.ObjectData.WmiDataReaderTask
+			report.WriteString(fmt.Sprintf("Task %d: %s (WMI Data Reader)\n", count, task.Name))
+			report.WriteString(fmt.Sprintf("  WMI Connection: %s\n", valueOrDefault(connectionName(details.Attribute("WmiConnection")))))
+			report.WriteString(fmt.Sprintf("  WQL Query Source Type: %s\n", valueOrDefault(details.Attribute("WqlQuerySourceType"))))
+			report.WriteString(fmt.Sprintf("  WQL Query Source: %s\n", valueOrDefault(details.Attribute("WqlQuerySource"))))
+			report.WriteString(fmt.Sprintf("  Output Type: %s\n", valueOrDefault(details.Attribute("OutputType"))))
+			if destinationType := details.Attribute("DestinationType"); destinationType != "" {
+				report.WriteString(fmt.Sprintf("  Destination Type: %s\n", destinationType))
+			}
+			if destination := details.Attribute("Destination"); destination != "" {
+				report.WriteString(fmt.Sprintf("  Destination: %s\n", connectionName(destination)))
+			}
+			report.WriteString("\n")
+		case strings.Contains(creationName, "wmieventwatchertask"):
+			count++
+			details := task.ObjectData.WmiEventWatcher
+			report.WriteString(fmt.Sprintf("Task %d: %s (WMI Event Watcher)\n", count, task.Name))
+			report.WriteString(fmt.Sprintf("  WMI Connection: %s\n", valueOrDefault(connectionName(details.Attribute("WmiConnection")))))
+			report.WriteString(fmt.Sprintf("  WQL Query Source Type: %s\n", valueOrDefault(details.Attribute("WqlQuerySourceType"))))
+			report.WriteString(fmt.Sprintf("  WQL Query Source: %s\n", valueOrDefault(details.Attribute("WqlQuerySource"))))
+			report.WriteString(fmt.Sprintf("  Action At Event: %s\n", valueOrDefault(details.Attribute("ActionAtEvent"))))
+			report.WriteString(fmt.Sprintf("  After Event: %s\n", valueOrDefault(details.Attribute("AfterEvent"))))
+			report.WriteString(fmt.Sprintf("  Action At Timeout: %s\n", valueOrDefault(details.Attribute("ActionAtTimeout"))))
+			report.WriteString(fmt.Sprintf("  After Timeout: %s\n", valueOrDefault(details.Attribute("AfterTimeout"))))
+			if events := details.Attribute("NumberOfEvents"); events != "" {
+				report.WriteString(fmt.Sprintf("  Number Of Events: %s\n", events))
+			}
+			if timeout := details.Attribute("Timeout"); timeout != "" {
+				report.WriteString(fmt.Sprintf("  Timeout: %s seconds\n", timeout))
+			}
+			report.WriteString("\n")
+		}
+	}
+
+	if count == 0 {
+		report.WriteString("No WMI Tasks found in this package.\n")
+	}
+
+	return formattedTaskResult("WMI Task Analysis", filePath, report.String(), nil, format, outputPath, packageDirectory)
+}
+
+// flattenTasks returns the tasks and containers of a package, including those nested in containers.
+func flattenTasks(tasks []types.Task) []types.Task {
+	var all []types.Task
+	for _, task := range tasks {
+		all = append(all, task)
+		if task.Executables != nil {
+			all = append(all, flattenTasks(task.Executables.Tasks)...)
+		}
+	}
+	return all
+}
+
 // HandleAnalyzeScriptTask extracts script task details.
 func HandleAnalyzeScriptTask(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
 	filePath, err := request.RequireString("file_path")
@@ -335,9 +962,21 @@ func HandleAnalyzeScriptTask(_ context.Context, request mcp.CallToolRequest, pac
 				}
 			}
 
-			scriptTaskData := task.ObjectData.ScriptTask.ScriptTaskData
-			if scriptTaskData.ScriptProject.ScriptCode != "" {
-				code := strings.TrimSpace(scriptTaskData.ScriptProject.ScriptCode)
+			project := task.ObjectData.ScriptProject
+			if len(project.ProjectItems) == 0 && len(project.BinaryItems) == 0 {
+				project = task.ObjectData.ScriptTask.ScriptTaskData.ScriptProject
+			}
+			sources, binaries := scriptProjectSources(project)
+			if len(sources) > 0 {
+				report.WriteString("  Script Code:\n")
+				for _, source := range sources {
+					report.WriteString(fmt.Sprintf("    --- %s ---\n", source.Name))
+					for _, line := range strings.Split(strings.TrimRight(source.Code, "\r\n\t "), "\n") {
+						report.WriteString(fmt.Sprintf("    %s\n", strings.TrimRight(line, "\r")))
+					}
+				}
+			} else if len(project.ProjectItems) == 0 && len(project.BinaryItems) == 0 && project.ScriptCode != "" {
+				code := strings.TrimSpace(project.ScriptCode)
 				code = strings.ReplaceAll(code, "&lt;", "<")
 				code = strings.ReplaceAll(code, "&gt;", ">")
 				code = strings.ReplaceAll(code, "&amp;", "&")
@@ -346,6 +985,9 @@ func HandleAnalyzeScriptTask(_ context.Context, request mcp.CallToolRequest, pac
 			} else {
 				report.WriteString("  Script Code: not present\n")
 			}
+			for _, binary := range binaries {
+				report.WriteString(fmt.Sprintf("  Binary Item: %s\n", binary))
+			}
 
 			rawData := string(data)
 			taskStart := strings.Index(rawData, fmt.Sprintf("<Executable Name=\"%s\"", task.Name))
@@ -392,6 +1034,73 @@ func HandleAnalyzeScriptTask(_ context.Context, request mcp.CallToolRequest, pac
 	return mcp.NewToolResultText(report.String()), nil
 }
 
+// scriptSource is a C# or VB.NET source file recovered from a script project
+type scriptSource struct {
+	Name string
+	Code string
+}
+
+// isScriptSourceFile reports whether a script project file holds C# or VB.NET source code
+func isScriptSourceFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".cs" || ext == ".vb"
+}
+
+// scriptProjectSources returns the source files of a script project, including those inside base64-encoded
+// ZIP archives stored as binary items, and a description of each binary item that holds no source code
+func scriptProjectSources(project types.ScriptProject) ([]scriptSource, []string) {
+	var sources []scriptSource
+	var binaries []string
+	for _, item := range project.ProjectItems {
+		if isScriptSourceFile(item.Name) {
+			sources = append(sources, scriptSource{Name: item.Name, Code: item.Value})
+		}
+	}
+	for _, item := range project.BinaryItems {
+		archived, err := decodeScriptArchive(item.Value)
+		if err != nil {
+			binaries = append(binaries, fmt.Sprintf("%s (%v)", item.Name, err))
+			continue
+		}
+		if len(archived) == 0 {
+			binaries = append(binaries, fmt.Sprintf("%s (no C# or VB.NET source files)", item.Name))
+			continue
+		}
+		sources = append(sources, archived...)
+	}
+	return sources, binaries
+}
+
+// decodeScriptArchive decodes base64 binary item content and reads the C# and VB.NET files from the ZIP archive
+func decodeScriptArchive(encoded string) ([]scriptSource, error) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(encoded), ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 content: %v", err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(decoded), int64(len(decoded)))
+	if err != nil {
+		return nil, fmt.Errorf("compiled binary, %d bytes", len(decoded))
+	}
+
+	var sources []scriptSource
+	for _, file := range archive.File {
+		if !isScriptSourceFile(file.Name) {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %v", file.Name, err)
+		}
+		code, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", file.Name, err)
+		}
+		sources = append(sources, scriptSource{Name: file.Name, Code: string(code)})
+	}
+	return sources, nil
+}
+
 // HandleDetectHardcodedValues scans for obvious literal values.
 func HandleDetectHardcodedValues(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
 	filePath, err := request.RequireString("file_path")
@@ -444,7 +1153,123 @@ func HandleDetectHardcodedValues(_ context.Context, request mcp.CallToolRequest,
 	var report strings.Builder
 	report.WriteString("Hard-coded Values Detection Report:\n")
 
-	found := false
+	patterns := serverNamePatterns
+	if names := strings.TrimSpace(request.GetString("environment_names", "")); names != "" {
+		patterns = nil
+		for _, name := range strings.Split(names, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				patterns = append(patterns, name)
+			}
+		}
+	}
+
+	hardcoded := detectHardcodedValues(pkg, patterns)
+	found := len(hardcoded) > 0
+	for _, value := range hardcoded {
+		report.WriteString(fmt.Sprintf("- WARNING: %s\n", value.Message))
+	}
+
+	if !found {
+		report.WriteString("No obvious hard-coded values detected. Manual review recommended for sensitive scenarios.\n")
+	}
+
+	if format == formatter.FormatSARIF {
+		sarifFindings := make([]formatter.Finding, 0, len(hardcoded))
+		for _, value := range hardcoded {
+			sarifFindings = append(sarifFindings, formatter.Finding{RuleID: "hardcoded_" + value.Category, Message: value.Message, Level: formatter.SARIFLevelWarning, URI: filePath})
+		}
+		return mcp.NewToolResultText(formatter.FormatAsSARIF("detect_hardcoded_values", sarifFindings)), nil
+	}
+
+	analysisResult := formatter.CreateAnalysisResult("detect_hardcoded_values", filePath, report.String(), nil)
+
+	// For JSON format, return structured data
+	if format == formatter.FormatJSON {
+		jsonResult := map[string]interface{}{
+			"tool_name": analysisResult.ToolName,
+			"file_path": analysisResult.FilePath,
+			"package":   filepath.Base(analysisResult.FilePath),
+			"timestamp": analysisResult.Timestamp,
+			"status":    analysisResult.Status,
+			"analysis":  analysisResult.Data,
+		}
+		if analysisResult.Error != "" {
+			jsonResult["error"] = analysisResult.Error
+		}
+		return mcp.NewToolResultStructured(jsonResult, "Hard-coded values detection"), nil
+	}
+
+	return mcp.NewToolResultText(formatter.FormatAnalysisResult(analysisResult, format)), nil
+}
+
+// Categories reported by count_hardcoded_values
+const (
+	hardcodedConnectionStrings = "connection_strings"
+	hardcodedVariables         = "variables"
+	hardcodedMessages          = "messages"
+	hardcodedSQLCommands       = "sql_commands"
+	hardcodedTaskProperties    = "task_properties"
+)
+
+var hardcodedCategories = []string{hardcodedConnectionStrings, hardcodedVariables, hardcodedMessages, hardcodedSQLCommands, hardcodedTaskProperties}
+
+// hardcodedValue is a literal value found by the hard-coded value detection
+type hardcodedValue struct {
+	Category string
+	Message  string
+}
+
+// serverNamePatterns are the hostname substrings flagged as environment-specific, set from the analysis config
+var serverNamePatterns = []string{"PROD", "DEV", "UAT", "QA"}
+
+// SetServerNamePatterns replaces the hostname substrings that mark a connection's server as environment-specific
+func SetServerNamePatterns(patterns []string) {
+	serverNamePatterns = patterns
+}
+
+// connectionHostKeys are the connection string keys that name the target server
+var connectionHostKeys = map[string]bool{
+	"data source": true, "server": true, "address": true, "addr": true,
+	"network address": true, "host": true, "hostname": true,
+}
+
+// connectionHosts returns the host names in a connection string with any protocol prefix, instance name and port removed
+func connectionHosts(connStr string) []string {
+	var hosts []string
+	for _, part := range strings.Split(connStr, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || !connectionHostKeys[strings.ToLower(strings.TrimSpace(key))] {
+			continue
+		}
+		host := strings.TrimSpace(value)
+		if i := strings.Index(host, ":"); i != -1 && !strings.Contains(host[i+1:], ":") {
+			host = host[i+1:]
+		}
+		if i := strings.IndexAny(host, `\,`); i != -1 {
+			host = host[:i]
+		}
+		if host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// matchServerNamePattern returns the first pattern found in host, ignoring case
+func matchServerNamePattern(host string, patterns []string) (string, bool) {
+	upper := strings.ToUpper(host)
+	for _, pattern := range patterns {
+		if pattern != "" && strings.Contains(upper, strings.ToUpper(pattern)) {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// detectHardcodedValues finds literal hosts, paths and messages shared by detect_hardcoded_values and count_hardcoded_values,
+// flagging connection servers whose names contain one of the given environment patterns
+func detectHardcodedValues(pkg types.SSISPackage, environmentPatterns []string) []hardcodedValue {
+	var values []hardcodedValue
 
 	for _, conn := range pkg.ConnectionMgr.Connections {
 		connStr := conn.ObjectData.ConnectionMgr.ConnectionString
@@ -452,16 +1277,21 @@ func HandleDetectHardcodedValues(_ context.Context, request mcp.CallToolRequest,
 			connStr = conn.ObjectData.MsmqConnMgr.ConnectionString
 		}
 		if strings.Contains(connStr, "localhost") || strings.Contains(connStr, "127.0.0.1") || strings.Contains(strings.ToLower(connStr), "hardcoded") {
-			report.WriteString(fmt.Sprintf("- WARNING: Connection '%s' contains literal value: %s\n", conn.Name, connStr))
-			found = true
+			values = append(values, hardcodedValue{hardcodedConnectionStrings, fmt.Sprintf("Connection '%s' contains literal value: %s", conn.Name, connStr)})
+		} else if ips := findIPAddresses(connStr); len(ips) > 0 {
+			values = append(values, hardcodedValue{hardcodedConnectionStrings, fmt.Sprintf("Connection '%s' contains IP address %s: %s", conn.Name, strings.Join(ips, ", "), connStr)})
+		}
+		for _, host := range connectionHosts(connStr) {
+			if pattern, ok := matchServerNamePattern(host, environmentPatterns); ok {
+				values = append(values, hardcodedValue{hardcodedConnectionStrings, fmt.Sprintf("Connection '%s' targets environment-specific server %s (matches %s): %s", conn.Name, host, pattern, connStr)})
+			}
 		}
 	}
 
 	for _, v := range pkg.Variables.Vars {
 		valueLower := strings.ToLower(v.Value)
 		if strings.Contains(valueLower, "c:\\") || strings.Contains(valueLower, "localhost") {
-			report.WriteString(fmt.Sprintf("- WARNING: Variable '%s' contains literal path/value: %s\n", v.Name, v.Value))
-			found = true
+			values = append(values, hardcodedValue{hardcodedVariables, fmt.Sprintf("Variable '%s' contains literal path/value: %s", v.Name, v.Value)})
 		}
 	}
 
@@ -469,42 +1299,107 @@ func HandleDetectHardcodedValues(_ context.Context, request mcp.CallToolRequest,
 		if strings.Contains(strings.ToLower(task.Name), "message queue") {
 			message := task.ObjectData.Task.MessageQueueTask.MessageQueueTaskData.Message
 			if message != "" && !strings.Contains(message, "@[") {
-				report.WriteString(fmt.Sprintf("- WARNING: Message Queue Task '%s' contains literal message: %s\n", task.Name, message))
-				found = true
+				values = append(values, hardcodedValue{hardcodedMessages, fmt.Sprintf("Message Queue Task '%s' contains literal message: %s", task.Name, message)})
 			}
 		}
 		for _, prop := range task.Properties {
 			valueLower := strings.ToLower(prop.Value)
 			if strings.Contains(valueLower, "localhost") || strings.Contains(prop.Value, "127.0.0.1") {
-				report.WriteString(fmt.Sprintf("- WARNING: Task '%s' property '%s' contains literal value: %s\n", task.Name, prop.Name, prop.Value))
-				found = true
+				category := hardcodedTaskProperties
+				if prop.Name == "SqlStatementSource" {
+					category = hardcodedSQLCommands
+				}
+				values = append(values, hardcodedValue{category, fmt.Sprintf("Task '%s' property '%s' contains literal value: %s", task.Name, prop.Name, prop.Value)})
+			} else if prop.Name == "SqlStatementSource" {
+				if ips := findIPAddresses(prop.Value); len(ips) > 0 {
+					values = append(values, hardcodedValue{hardcodedSQLCommands, fmt.Sprintf("Task '%s' SQL command contains IP address %s: %s", task.Name, strings.Join(ips, ", "), prop.Value)})
+				}
 			}
 		}
 	}
 
-	if !found {
-		report.WriteString("No obvious hard-coded values detected. Manual review recommended for sensitive scenarios.\n")
+	return values
+}
+
+var (
+	ipv4Pattern = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+	ipv6Pattern = regexp.MustCompile(`(?i)(?:[0-9a-f]{0,4}:){2,7}[0-9a-f]{0,4}`)
+)
+
+// findIPAddresses returns the distinct IPv4 and IPv6 addresses in s, ignoring loopback and unspecified
+// addresses and assembly version numbers such as Version=11.0.0.0
+func findIPAddresses(s string) []string {
+	var ips []string
+	seen := make(map[string]bool)
+	for _, pattern := range []*regexp.Regexp{ipv4Pattern, ipv6Pattern} {
+		for _, loc := range pattern.FindAllStringIndex(s, -1) {
+			candidate := s[loc[0]:loc[1]]
+			if strings.HasSuffix(strings.ToLower(s[:loc[0]]), "version=") {
+				continue
+			}
+			if pattern == ipv6Pattern && len(strings.FieldsFunc(candidate, func(r rune) bool { return r == ':' })) < 2 {
+				continue
+			}
+			ip := net.ParseIP(candidate)
+			if ip == nil || ip.IsLoopback() || ip.IsUnspecified() || seen[candidate] {
+				continue
+			}
+			seen[candidate] = true
+			ips = append(ips, candidate)
+		}
 	}
+	return ips
+}
 
-	analysisResult := formatter.CreateAnalysisResult("detect_hardcoded_values", filePath, report.String(), nil)
+// HandleCountHardcodedValues runs the hard-coded value detection and returns only the counts as JSON
+func HandleCountHardcodedValues(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
+	filePath, err := request.RequireString("file_path")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	threshold := request.GetInt("threshold", 0)
+	if threshold < 0 {
+		return mcp.NewToolResultError("threshold must not be negative"), nil
+	}
 
-	// For JSON format, return structured data
-	if format == formatter.FormatJSON {
-		jsonResult := map[string]interface{}{
-			"tool_name": analysisResult.ToolName,
-			"file_path": analysisResult.FilePath,
-			"package":   filepath.Base(analysisResult.FilePath),
-			"timestamp": analysisResult.Timestamp,
-			"status":    analysisResult.Status,
-			"analysis":  analysisResult.Data,
+	data, err := os.ReadFile(resolveFilePath(filePath, packageDirectory))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to read file: %v", err)), nil
+	}
+
+	var pkg types.SSISPackage
+	if err := xml.Unmarshal([]byte(strings.ReplaceAll(string(data), "DTS:", "")), &pkg); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse XML: %v", err)), nil
+	}
+
+	byCategory := make(map[string]int, len(hardcodedCategories))
+	for _, category := range hardcodedCategories {
+		byCategory[category] = 0
+	}
+	values := detectHardcodedValues(pkg, serverNamePatterns)
+	for _, value := range values {
+		byCategory[value.Category]++
+	}
+
+	result := map[string]interface{}{
+		"file_path":         filePath,
+		"total_count":       len(values),
+		"by_category":       byCategory,
+		"threshold":         threshold,
+		"exceeds_threshold": len(values) > threshold,
+	}
+
+	if outputPath := request.GetString("output_file_path", ""); outputPath != "" {
+		encoded, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to encode result: %v", err)), nil
 		}
-		if analysisResult.Error != "" {
-			jsonResult["error"] = analysisResult.Error
+		if err := output.WriteOutput(resolveFilePath(outputPath, packageDirectory), string(encoded)); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
-		return mcp.NewToolResultStructured(jsonResult, "Hard-coded values detection"), nil
 	}
 
-	return mcp.NewToolResultText(formatter.FormatAnalysisResult(analysisResult, format)), nil
+	return mcp.NewToolResultStructured(result, fmt.Sprintf("%d hard-coded value(s) found", len(values))), nil
 }
 
 // HandleAnalyzeLoggingConfiguration reviews logging configuration blocks.
@@ -528,83 +1423,98 @@ func HandleAnalyzeLoggingConfiguration(_ context.Context, request mcp.CallToolRe
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse XML: %v", err)), nil
 	}
 
+	providerType := ""
+	if value := request.GetString("provider_type", ""); value != "" {
+		if providerType, err = normalizeLogProviderType(value); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	analysis := analyzeLogging(pkg, loggingProviders(pkg.LogProviders))
+	groups := groupLogProviders(analysis.Providers, providerType)
+
 	var report strings.Builder
 	report.WriteString("Logging Configuration Analysis:\n")
 
-	if !strings.Contains(cleaned, "LoggingOptions") {
-		report.WriteString("[WARN] No logging configuration found in this package.\n")
+	if pkg.LoggingOptions == nil && len(analysis.Providers) == 0 {
+		report.WriteString("[WARN] No logging configuration found in this package.\n\n")
 		// Do not early-return; continue to produce a JSON result so workflow outputs
 		// are consistently JSON formatted for every package.
+	} else {
+		report.WriteString("[OK] Logging configuration detected.\n\n")
 	}
 
-	report.WriteString("[OK] Logging configuration detected.\n\n")
-
-	rawData := string(data)
-
-	providersSection := extractSection(rawData, "<LogProviders>", "</LogProviders>")
-	if providersSection != "" {
-		report.WriteString("Log Providers:\n")
-		if strings.Contains(providersSection, `CreationName="Microsoft.LogProviderSQLServer"`) {
-			report.WriteString("  - SQL Server Log Provider\n")
-			connMatch := regexp.MustCompile(`ConfigString="([^"]*)"`).FindStringSubmatch(providersSection)
-			if len(connMatch) > 1 {
-				report.WriteString(fmt.Sprintf("    Connection: %s\n", connMatch[1]))
-			}
+	if providerType != "" {
+		report.WriteString(fmt.Sprintf("Log Providers (type: %s):\n", providerType))
+	} else {
+		report.WriteString(fmt.Sprintf("Log Providers (%d):\n", len(analysis.Providers)))
+	}
+	if len(groups) == 0 {
+		report.WriteString("  None\n")
+	}
+	for _, groupType := range logProviderTypes {
+		providers := groups[groupType]
+		if len(providers) == 0 {
+			continue
 		}
-		if strings.Contains(providersSection, `CreationName="Microsoft.LogProviderTextFile"`) {
-			report.WriteString("  - Text File Log Provider\n")
-			connMatch := regexp.MustCompile(`ConfigString="([^"]*)"`).FindStringSubmatch(providersSection)
-			if len(connMatch) > 1 {
-				report.WriteString(fmt.Sprintf("    File Path: %s\n", connMatch[1]))
+		report.WriteString(fmt.Sprintf("  %s:\n", groupType))
+		for _, provider := range providers {
+			report.WriteString(fmt.Sprintf("    - %s\n", provider.Name))
+			if provider.ConfigString != "" {
+				report.WriteString(fmt.Sprintf("      Connection: %s\n", provider.ConfigString))
+			}
+			if len(provider.Events) > 0 {
+				report.WriteString(fmt.Sprintf("      Events Enabled: %s\n", strings.Join(provider.Events, ", ")))
+			} else {
+				report.WriteString("      Events Enabled: none (provider not selected)\n")
 			}
 		}
-		if strings.Contains(providersSection, `CreationName="Microsoft.LogProviderEventLog"`) {
-			report.WriteString("  - Windows Event Log Provider\n")
-		}
-		report.WriteString("\n")
 	}
+	report.WriteString("\n")
 
-	loggingSection := extractSection(rawData, "<LoggingOptions", "</LoggingOptions>")
-	if loggingSection != "" {
-		report.WriteString("Package-Level Logging Settings:\n")
-		if strings.Contains(loggingSection, `LoggingMode="1"`) {
-			report.WriteString("  Mode: Enabled\n")
-		} else {
-			report.WriteString("  Mode: Disabled\n")
-		}
-
-		if matches := regexp.MustCompile(`EventFilter">([^<]+)</`).FindStringSubmatch(loggingSection); len(matches) > 1 {
-			report.WriteString(fmt.Sprintf("  Events Logged: %s\n", matches[1]))
-		}
-
-		selectedProviders := regexp.MustCompile(`SelectedLogProvider[^}]*InstanceID="([^"]*)"`).FindAllStringSubmatch(loggingSection, -1)
-		if len(selectedProviders) > 0 {
-			report.WriteString("  Selected Providers:\n")
-			for _, match := range selectedProviders {
-				if len(match) > 1 {
-					report.WriteString(fmt.Sprintf("    - %s\n", match[1]))
-				}
+	report.WriteString("Package-Level Logging Settings:\n")
+	report.WriteString(fmt.Sprintf("  Mode: %s\n", describeLoggingMode(analysis.PackageMode)))
+	if len(analysis.PackageEvents) > 0 {
+		report.WriteString(fmt.Sprintf("  Events Logged: %s\n", strings.Join(analysis.PackageEvents, ", ")))
+	}
+	report.WriteString("\n")
+
+	if len(analysis.TaskLogging) > 0 {
+		report.WriteString(fmt.Sprintf("Task-Level Overrides: %d task(s) define custom logging.\n", len(analysis.TaskLogging)))
+		for _, task := range analysis.TaskLogging {
+			line := fmt.Sprintf("  - %s: %s", task.Name, describeLoggingMode(task.Mode))
+			if len(task.Events) > 0 {
+				line += fmt.Sprintf(" (%s)", strings.Join(task.Events, ", "))
 			}
+			report.WriteString(line + "\n")
 		}
 		report.WriteString("\n")
 	}
 
-	overrides := strings.Count(rawData, "<LoggingOptions")
-	if overrides > 1 {
-		report.WriteString(fmt.Sprintf("Task-Level Overrides: %d task(s) define custom logging.\n\n", overrides-1))
+	if len(analysis.Misconfigurations) > 0 {
+		report.WriteString("Misconfigurations:\n")
+		for _, issue := range analysis.Misconfigurations {
+			report.WriteString(fmt.Sprintf("- [WARN] %s\n", issue))
+		}
+		report.WriteString("\n")
 	}
 
 	report.WriteString("Recommendations:\n")
-	if strings.Contains(loggingSection, `LoggingMode="1"`) {
+	if analysis.PackageMode == loggingModeEnabled {
 		report.WriteString("- Ensure captured events align with operational requirements.\n")
 	} else {
 		report.WriteString("- Enable package logging to aid troubleshooting.\n")
 	}
 
-	if strings.Contains(providersSection, `CreationName="Microsoft.LogProviderTextFile"`) {
+	hasFileProvider, hasSQLProvider := false, false
+	for _, provider := range analysis.Providers {
+		hasFileProvider = hasFileProvider || provider.Type == logProviderTextFile || provider.Type == logProviderXMLFile
+		hasSQLProvider = hasSQLProvider || provider.Type == logProviderSQLServer
+	}
+	if hasFileProvider {
 		report.WriteString("- Validate file storage location and retention.\n")
 	}
-	if strings.Contains(providersSection, `CreationName="Microsoft.LogProviderSQLServer"`) {
+	if hasSQLProvider {
 		report.WriteString("- Confirm SQL log tables are monitored and maintained.\n")
 	}
 
@@ -612,12 +1522,17 @@ func HandleAnalyzeLoggingConfiguration(_ context.Context, request mcp.CallToolRe
 
 	// Standardize output structure to match analyze_data_flow
 	jsonResult := map[string]interface{}{
-		"tool_name": "analyze_logging_configuration",
-		"file_path": filePath,
-		"package":   filepath.Base(filePath),
-		"timestamp": time.Now().Format(time.RFC3339),
-		"status":    "success",
-		"analysis":  report.String(),
+		"tool_name":         "analyze_logging_configuration",
+		"file_path":         filePath,
+		"package":           filepath.Base(filePath),
+		"timestamp":         time.Now().Format(time.RFC3339),
+		"status":            "success",
+		"analysis":          report.String(),
+		"providers_by_type": groups,
+		"misconfigurations": analysis.Misconfigurations,
+	}
+	if providerType != "" {
+		jsonResult["provider_type"] = providerType
 	}
 
 	jsonBytes, err := json.Marshal(jsonResult)
@@ -626,9 +1541,9 @@ func HandleAnalyzeLoggingConfiguration(_ context.Context, request mcp.CallToolRe
 	}
 	if argsMap, ok := args.(map[string]interface{}); ok {
 		if outputFilePath, ok := argsMap["output_file_path"].(string); ok && outputFilePath != "" {
-			resolvedPath := resolveFilePath(outputFilePath, packageDirectory)
-			os.MkdirAll(filepath.Dir(resolvedPath), 0755)
-			os.WriteFile(resolvedPath, jsonBytes, 0644)
+			if err := output.WriteOutput(resolveFilePath(outputFilePath, packageDirectory), string(jsonBytes)); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
 		}
 	}
 	// Return a structured result so callers (workflow runner) can detect and
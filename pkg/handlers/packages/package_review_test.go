@@ -0,0 +1,808 @@
+package packages
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const bestPracticesPackage = `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:ObjectName="BestPractices">
+  <DTS:ConnectionManagers>
+    <DTS:ConnectionManager DTS:ObjectName="Warehouse" DTS:DTSID="{11111111-1111-1111-1111-111111111111}" />
+    <DTS:ConnectionManager DTS:ObjectName="Staging" DTS:Description="Staging database" DTS:DTSID="{22222222-2222-2222-2222-222222222222}" />
+  </DTS:ConnectionManagers>
+  <DTS:Executables>
+    <DTS:Executable DTS:ObjectName="Load Data" DTS:CreationName="Microsoft.ExecuteSQLTask" />
+  </DTS:Executables>
+</DTS:Executable>`
+
+func runBestPractices(t *testing.T, contents string, args map[string]interface{}) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Package.dtsx"), []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write package: %v", err)
+	}
+	arguments := map[string]interface{}{"file_path": "Package.dtsx"}
+	for key, value := range args {
+		arguments[key] = value
+	}
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: arguments}}
+	result, err := HandleValidateBestPractices(context.Background(), request, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	textContent, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[0])
+	}
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %s", textContent.Text)
+	}
+	return textContent.Text
+}
+
+func TestValidateBestPracticesSeverityInfo(t *testing.T) {
+	text := runBestPractices(t, bestPracticesPackage, nil)
+	for _, want := range []string{
+		"- ERROR: No OnError event handler found",
+		"- WARNING: No user-defined variables found",
+		"- WARNING: No logging configuration found",
+		"- INFO: Connection manager 'Warehouse' has no description",
+		"- WARNING: Task 'Load Data' uses TransactionOption Supported",
+		"- WARNING: Package has no description",
+		"- WARNING: Task 'Load Data' has no description",
+		"1 error(s), 5 warning(s), 1 info",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in report, got %q", want, text)
+		}
+	}
+	if strings.Contains(text, "'Staging' has no description") {
+		t.Fatalf("expected described connection to pass, got %q", text)
+	}
+}
+
+func TestValidateBestPracticesSeverityWarning(t *testing.T) {
+	text := runBestPractices(t, bestPracticesPackage, map[string]interface{}{"min_severity": "warning"})
+	if strings.Contains(text, "- INFO:") {
+		t.Fatalf("expected info findings to be filtered, got %q", text)
+	}
+	if !strings.Contains(text, "- WARNING: No logging configuration found") || !strings.Contains(text, "- ERROR:") {
+		t.Fatalf("expected warning and error findings, got %q", text)
+	}
+}
+
+func TestValidateBestPracticesSeverityError(t *testing.T) {
+	text := runBestPractices(t, bestPracticesPackage, map[string]interface{}{"min_severity": "error"})
+	if strings.Contains(text, "- WARNING:") || strings.Contains(text, "- INFO:") {
+		t.Fatalf("expected only error findings, got %q", text)
+	}
+	if !strings.Contains(text, "- ERROR: No OnError event handler found") {
+		t.Fatalf("expected error finding, got %q", text)
+	}
+
+	withHandler := strings.Replace(bestPracticesPackage, "</DTS:Executables>",
+		`</DTS:Executables><DTS:EventHandlers><DTS:EventHandler DTS:EventName="OnError" /></DTS:EventHandlers>`, 1)
+	text = runBestPractices(t, withHandler, map[string]interface{}{"min_severity": "error"})
+	if strings.Contains(text, "- ERROR:") || !strings.Contains(text, "- OK: OnError event handler defined") {
+		t.Fatalf("expected OnError handler to satisfy error handling check, got %q", text)
+	}
+}
+
+func TestValidateBestPracticesSARIF(t *testing.T) {
+	text := runBestPractices(t, bestPracticesPackage, map[string]interface{}{"format": "sarif", "min_severity": "warning"})
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal([]byte(text), &log); err != nil {
+		t.Fatalf("expected SARIF JSON, got %q: %v", text, err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != 6 {
+		t.Fatalf("unexpected SARIF log: %s", text)
+	}
+	first := log.Runs[0].Results[0]
+	if first.RuleID != "missing_error_handling" || first.Level != "error" || first.Locations[0].PhysicalLocation.ArtifactLocation.URI != "Package.dtsx" {
+		t.Fatalf("unexpected first result: %+v", first)
+	}
+}
+
+func TestValidateBestPracticesJUnit(t *testing.T) {
+	text := runBestPractices(t, bestPracticesPackage, map[string]interface{}{"format": "junit"})
+	var suites struct {
+		Suites []struct {
+			Tests    int    `xml:"tests,attr"`
+			Failures int    `xml:"failures,attr"`
+			File     string `xml:"file,attr"`
+			Cases    []struct {
+				ClassName string    `xml:"classname,attr"`
+				Name      string    `xml:"name,attr"`
+				Failure   *struct{} `xml:"failure"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	if err := xml.Unmarshal([]byte(text), &suites); err != nil {
+		t.Fatalf("expected JUnit XML, got %q: %v", text, err)
+	}
+	if len(suites.Suites) != 1 {
+		t.Fatalf("expected one test suite, got %q", text)
+	}
+	suite := suites.Suites[0]
+	// 2 passed checks (tasks, connections) and 7 findings (error handling, variables, logging, one undescribed connection,
+	// no package description, one undescribed task, one task with the default TransactionOption)
+	failures := 0
+	for _, c := range suite.Cases {
+		if c.ClassName != "validate_best_practices" {
+			t.Fatalf("unexpected classname %q", c.ClassName)
+		}
+		if c.Failure != nil {
+			failures++
+		}
+	}
+	if suite.Tests != 9 || len(suite.Cases) != 9 || suite.Failures != 7 || failures != 7 || suite.File != "Package.dtsx" {
+		t.Fatalf("unexpected JUnit suite: %s", text)
+	}
+}
+
+func TestValidateBestPracticesTable(t *testing.T) {
+	text := runBestPractices(t, bestPracticesPackage, map[string]interface{}{"format": "table"})
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	// One header row and one row per finding
+	if len(lines) != 8 || !strings.HasPrefix(lines[0], "SEVERITY") {
+		t.Fatalf("expected a header and 7 finding rows, got %q", text)
+	}
+	ruleColumn := strings.Index(lines[0], "RULE")
+	for _, line := range lines[1:] {
+		if !strings.HasPrefix(line, "warning") && !strings.HasPrefix(line, "error") && !strings.HasPrefix(line, "info") {
+			t.Fatalf("expected the severity in the first column, got %q", line)
+		}
+		if line[ruleColumn-1] != ' ' || line[ruleColumn] == ' ' {
+			t.Fatalf("expected the rule column at offset %d, got %q", ruleColumn, line)
+		}
+	}
+}
+
+func TestValidateBestPracticesInvalidSeverity(t *testing.T) {
+	dir := t.TempDir()
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"file_path":    "Package.dtsx",
+		"min_severity": "critical",
+	}}}
+	result, err := HandleValidateBestPractices(context.Background(), request, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected invalid min_severity to return a tool error")
+	}
+}
+
+func TestValidateBestPracticesTransactionOption(t *testing.T) {
+	contents := `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:ObjectName="Transactions">
+  <DTS:Executables>
+    <DTS:Executable DTS:ObjectName="Not Supported Task" DTS:CreationName="Microsoft.ExecuteSQLTask" DTS:TransactionOption="0" />
+    <DTS:Executable DTS:ObjectName="Supported Task" DTS:CreationName="Microsoft.ExecuteSQLTask" DTS:TransactionOption="1" />
+    <DTS:Executable DTS:ObjectName="Required Container" DTS:CreationName="STOCK:SEQUENCE" DTS:TransactionOption="2">
+      <DTS:Executables>
+        <DTS:Executable DTS:ObjectName="Default Task" DTS:CreationName="Microsoft.ExecuteSQLTask" />
+      </DTS:Executables>
+    </DTS:Executable>
+  </DTS:Executables>
+</DTS:Executable>`
+	text := runBestPractices(t, contents, map[string]interface{}{"min_severity": "warning"})
+	for _, want := range []string{
+		"- WARNING: Task 'Supported Task' uses TransactionOption Supported",
+		"- WARNING: Task 'Default Task' uses TransactionOption Supported",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in report, got %q", want, text)
+		}
+	}
+	if strings.Contains(text, "'Not Supported Task' uses") || strings.Contains(text, "'Required Container' uses") {
+		t.Fatalf("expected explicit NotSupported and Required settings to pass, got %q", text)
+	}
+
+	explicit := strings.ReplaceAll(contents, `DTS:TransactionOption="1"`, `DTS:TransactionOption="0"`)
+	explicit = strings.Replace(explicit, `DTS:ObjectName="Default Task"`, `DTS:ObjectName="Default Task" DTS:TransactionOption="2"`, 1)
+	text = runBestPractices(t, explicit, nil)
+	if strings.Contains(text, "TransactionOption Supported") || !strings.Contains(text, "- OK: All tasks set TransactionOption to Required or NotSupported") {
+		t.Fatalf("expected explicit transaction options to pass, got %q", text)
+	}
+}
+
+func TestValidateBestPracticesDescriptions(t *testing.T) {
+	contents := `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:ObjectName="Descriptions" DTS:Description="Loads the nightly sales extract">
+  <DTS:Executables>
+    <DTS:Executable DTS:ObjectName="Truncate" DTS:CreationName="Microsoft.ExecuteSQLTask" DTS:Description="Truncate" />
+    <DTS:Executable DTS:ObjectName="Load Sales" DTS:CreationName="Microsoft.Pipeline" DTS:Description="Copies sales rows to staging" />
+    <DTS:Executable DTS:ObjectName="Cleanup" DTS:CreationName="Microsoft.ExecuteSQLTask" />
+  </DTS:Executables>
+</DTS:Executable>`
+	text := runBestPractices(t, contents, nil)
+	for _, want := range []string{
+		"- OK: Package has a description",
+		"- WARNING: Task 'Truncate' description 'Truncate' is shorter than 10 characters",
+		"- WARNING: Task 'Cleanup' has no description",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in report, got %q", want, text)
+		}
+	}
+	if strings.Contains(text, "'Load Sales' description") {
+		t.Fatalf("expected long description to pass, got %q", text)
+	}
+
+	text = runBestPractices(t, contents, map[string]interface{}{"min_description_length": 40})
+	for _, want := range []string{
+		"- WARNING: Package description 'Loads the nightly sales extract' is shorter than 40 characters",
+		"- WARNING: Task 'Load Sales' description 'Copies sales rows to staging' is shorter than 40 characters",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q with min_description_length 40, got %q", want, text)
+		}
+	}
+
+	dir := t.TempDir()
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"file_path":              "Package.dtsx",
+		"min_description_length": 0,
+	}}}
+	result, err := HandleValidateBestPractices(context.Background(), request, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected invalid min_description_length to return a tool error")
+	}
+}
+
+func TestValidateBestPracticesLookupNoMatchOutput(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(repoRoot(t), "testdata", "LookupNoMatch.dtsx"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	text := runBestPractices(t, string(data), map[string]interface{}{"min_severity": "error"})
+	want := "- ERROR: Lookup 'Customer Lookup' in data flow 'Load Orders' has no path from its 'Lookup No Match Output' output; rows sent to it are silently dropped"
+	if !strings.Contains(text, want) {
+		t.Fatalf("expected %q in report, got %q", want, text)
+	}
+	if strings.Contains(text, "'Lookup Match Output' output") || strings.Contains(text, "'Lookup Error Output' output") {
+		t.Fatalf("expected connected and error outputs to pass, got %q", text)
+	}
+
+	// Rows without a match fail the component instead of reaching the no match output
+	failOnNoMatch := strings.Replace(string(data), `name="NoMatchBehavior">1<`, `name="NoMatchBehavior">0<`, 1)
+	text = runBestPractices(t, failOnNoMatch, nil)
+	if strings.Contains(text, "Lookup No Match Output") || !strings.Contains(text, "- OK: All outputs of 1 Lookup components are connected") {
+		t.Fatalf("expected unused no match output to pass, got %q", text)
+	}
+}
+
+func TestValidateBestPracticesSortTransformation(t *testing.T) {
+	sortPackage := func(properties string) string {
+		return `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:ObjectName="Sorts">
+  <DTS:Executables>
+    <DTS:Executable DTS:ObjectName="Load Sales" DTS:CreationName="Microsoft.Pipeline">
+      <DTS:ObjectData>
+        <pipeline>
+          <components>
+            <component name="Sort By Date" componentClassID="Microsoft.Sort">
+              <properties>` + properties + `</properties>
+            </component>
+          </components>
+        </pipeline>
+      </DTS:ObjectData>
+    </DTS:Executable>
+  </DTS:Executables>
+</DTS:Executable>`
+	}
+	sortWarning := "- WARNING: Sort 'Sort By Date' in data flow 'Load Sales' is a blocking transformation that holds every row in memory; sort with ORDER BY in the upstream source query instead"
+	duplicatesWarning := "- WARNING: Sort 'Sort By Date' in data flow 'Load Sales' has EliminateDuplicates enabled"
+
+	for name, properties := range map[string]string{
+		"default":                 "",
+		"keeps duplicates":        `<property name="EliminateDuplicates">false</property>`,
+		"eliminates duplicates":   `<property name="EliminateDuplicates">true</property>`,
+		"eliminates duplicates 1": `<property name="EliminateDuplicates">1</property>`,
+	} {
+		text := runBestPractices(t, sortPackage(properties), map[string]interface{}{"min_severity": "warning"})
+		if !strings.Contains(text, sortWarning) {
+			t.Fatalf("%s: expected %q in report, got %q", name, sortWarning, text)
+		}
+		eliminates := strings.HasPrefix(name, "eliminates")
+		if strings.Contains(text, duplicatesWarning) != eliminates {
+			t.Fatalf("%s: expected EliminateDuplicates warning %t, got %q", name, eliminates, text)
+		}
+	}
+
+	text := runBestPractices(t, bestPracticesPackage, nil)
+	if strings.Contains(text, "Sort '") {
+		t.Fatalf("expected no Sort findings without Sort transformations, got %q", text)
+	}
+}
+
+const customRulesPackage = `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:ObjectName="CustomRules">
+  <DTS:Executables>
+    <DTS:Executable DTS:ObjectName="Truncate Staging" DTS:CreationName="Microsoft.ExecuteSQLTask">
+      <DTS:ObjectData>
+        <SQLTask:SqlTaskData xmlns:SQLTask="www.microsoft.com/sqlserver/dts/tasks/sqltask" SQLTask:SqlStatementSource="TRUNCATE TABLE stg.Sales" />
+      </DTS:ObjectData>
+    </DTS:Executable>
+    <DTS:Executable DTS:ObjectName="Merge Sales" DTS:CreationName="Microsoft.ExecuteSQLTask">
+      <DTS:ObjectData>
+        <SQLTask:SqlTaskData xmlns:SQLTask="www.microsoft.com/sqlserver/dts/tasks/sqltask" SQLTask:TimeOut="300" SQLTask:SqlStatementSource="EXEC dbo.MergeSales" />
+      </DTS:ObjectData>
+    </DTS:Executable>
+    <DTS:Executable DTS:ObjectName="Load Sales" DTS:CreationName="Microsoft.Pipeline">
+      <DTS:ObjectData>
+        <pipeline>
+          <components>
+            <component name="Sales Source" componentClassID="Microsoft.OLEDBSource">
+              <properties>
+                <property name="SqlCommand">SELECT * FROM dbo.Sales</property>
+              </properties>
+            </component>
+          </components>
+        </pipeline>
+      </DTS:ObjectData>
+    </DTS:Executable>
+  </DTS:Executables>
+</DTS:Executable>`
+
+func TestValidateBestPracticesCustomRules(t *testing.T) {
+	rulesPath := filepath.Join(repoRoot(t), "testdata", "best_practices_rules.json")
+	text := runBestPractices(t, customRulesPackage, map[string]interface{}{"rules_file": rulesPath})
+	for _, want := range []string{
+		"- ERROR: Execute SQL Task 'Truncate Staging' has no timeout set",
+		"- WARNING: OLE DB Source 'Sales Source' does not use a parameterized query",
+		"- INFO: No executable in the package has a description",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in report, got %q", want, text)
+		}
+	}
+	if strings.Contains(text, "'Merge Sales' has no timeout") {
+		t.Fatalf("expected task with a timeout to pass, got %q", text)
+	}
+
+	text = runBestPractices(t, customRulesPackage, map[string]interface{}{"rules_file": rulesPath, "min_severity": "error"})
+	if strings.Contains(text, "parameterized query") || !strings.Contains(text, "'Truncate Staging' has no timeout set") {
+		t.Fatalf("expected min_severity to apply to custom findings, got %q", text)
+	}
+}
+
+func TestLoadCustomRulesValidation(t *testing.T) {
+	dir := t.TempDir()
+	cases := map[string]string{
+		"invalid xpath":    `{"rules":[{"id":"r1","xpath":"//[","message":"m"}]}`,
+		"invalid severity": `{"rules":[{"id":"r1","xpath":"//a","message":"m","severity":"fatal"}]}`,
+		"missing message":  `{"rules":[{"id":"r1","xpath":"//a"}]}`,
+		"invalid mode":     `{"rules":[{"id":"r1","xpath":"//a","message":"m","mode":"maybe"}]}`,
+	}
+	for name, contents := range cases {
+		path := filepath.Join(dir, strings.ReplaceAll(name, " ", "_")+".json")
+		if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+			t.Fatalf("failed to write rules: %v", err)
+		}
+		if _, err := loadCustomRules(path); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+
+	path := filepath.Join(dir, "defaults.json")
+	if err := os.WriteFile(path, []byte(`{"rules":[{"id":"r1","xpath":"//a","message":"m"}]}`), 0o644); err != nil {
+		t.Fatalf("failed to write rules: %v", err)
+	}
+	rules, err := loadCustomRules(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rules[0].Severity != SeverityWarning || rules[0].Mode != "forbid" {
+		t.Fatalf("expected default severity and mode, got %+v", rules[0])
+	}
+}
+
+func TestHandleCountHardcodedValues(t *testing.T) {
+	dir := t.TempDir()
+	contents := `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:ObjectName="Hardcoded">
+  <DTS:ConnectionManagers>
+    <DTS:ConnectionManager DTS:ObjectName="Local">
+      <DTS:ObjectData><DTS:ConnectionManager DTS:ConnectionString="Data Source=localhost;Initial Catalog=DW;" /></DTS:ObjectData>
+    </DTS:ConnectionManager>
+  </DTS:ConnectionManagers>
+  <DTS:Variables>
+    <DTS:Variable DTS:ObjectName="ImportPath"><DTS:VariableValue>C:\imports\daily</DTS:VariableValue></DTS:Variable>
+    <DTS:Variable DTS:ObjectName="Archive"><DTS:VariableValue>C:\archive</DTS:VariableValue></DTS:Variable>
+  </DTS:Variables>
+  <DTS:Executables>
+    <DTS:Executable DTS:ObjectName="Load">
+      <DTS:Property DTS:Name="SqlStatementSource">SELECT * FROM [127.0.0.1].DW.dbo.Sales</DTS:Property>
+    </DTS:Executable>
+  </DTS:Executables>
+</DTS:Executable>`
+	if err := os.WriteFile(filepath.Join(dir, "Hardcoded.dtsx"), []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write package: %v", err)
+	}
+
+	count := func(args map[string]interface{}) map[string]interface{} {
+		args["file_path"] = "Hardcoded.dtsx"
+		result, err := HandleCountHardcodedValues(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}, dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		structured, ok := result.StructuredContent.(map[string]interface{})
+		if !ok {
+			t.Fatalf("expected structured result, got %T", result.StructuredContent)
+		}
+		return structured
+	}
+
+	result := count(map[string]interface{}{})
+	if result["total_count"] != 4 || result["exceeds_threshold"] != true {
+		t.Fatalf("expected 4 values exceeding the default threshold, got %+v", result)
+	}
+	byCategory := result["by_category"].(map[string]int)
+	expected := map[string]int{"connection_strings": 1, "variables": 2, "messages": 0, "sql_commands": 1, "task_properties": 0}
+	for category, want := range expected {
+		if byCategory[category] != want {
+			t.Fatalf("expected %d %s, got %+v", want, category, byCategory)
+		}
+	}
+
+	if result := count(map[string]interface{}{"threshold": float64(4)}); result["exceeds_threshold"] != false {
+		t.Fatalf("expected threshold of 4 not to be exceeded, got %+v", result)
+	}
+}
+
+func TestDetectHardcodedValuesIPAddresses(t *testing.T) {
+	dir := t.TempDir()
+	contents := `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:ObjectName="Addresses">
+  <DTS:ConnectionManagers>
+    <DTS:ConnectionManager DTS:ObjectName="Remote">
+      <DTS:ObjectData><DTS:ConnectionManager DTS:ConnectionString="Data Source=192.168.1.100;Initial Catalog=DW;" /></DTS:ObjectData>
+    </DTS:ConnectionManager>
+    <DTS:ConnectionManager DTS:ObjectName="RemoteV6">
+      <DTS:ObjectData><DTS:ConnectionManager DTS:ConnectionString="Data Source=fe80::1ff:fe23:4567:890a;Initial Catalog=DW;" /></DTS:ObjectData>
+    </DTS:ConnectionManager>
+    <DTS:ConnectionManager DTS:ObjectName="Loopback">
+      <DTS:ObjectData><DTS:ConnectionManager DTS:ConnectionString="Data Source=::1;Initial Catalog=DW;" /></DTS:ObjectData>
+    </DTS:ConnectionManager>
+    <DTS:ConnectionManager DTS:ObjectName="Provider">
+      <DTS:ObjectData><DTS:ConnectionManager DTS:ConnectionString="Data Source=WAREHOUSE;Provider=SQLNCLI11.1;Version=11.0.0.0;" /></DTS:ObjectData>
+    </DTS:ConnectionManager>
+  </DTS:ConnectionManagers>
+  <DTS:Executables>
+    <DTS:Executable DTS:ObjectName="Load">
+      <DTS:Property DTS:Name="SqlStatementSource">SELECT * FROM OPENROWSET('SQLNCLI', 'Server=10.0.0.5;Trusted_Connection=yes;', 'SELECT 1')</DTS:Property>
+    </DTS:Executable>
+  </DTS:Executables>
+</DTS:Executable>`
+	if err := os.WriteFile(filepath.Join(dir, "Addresses.dtsx"), []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write package: %v", err)
+	}
+
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"file_path": "Addresses.dtsx"}}}
+	result, err := HandleDetectHardcodedValues(context.Background(), request, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+
+	for _, expected := range []string{
+		"Connection 'Remote' contains IP address 192.168.1.100",
+		"Connection 'RemoteV6' contains IP address fe80::1ff:fe23:4567:890a",
+		"Task 'Load' SQL command contains IP address 10.0.0.5",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Fatalf("expected %q in output, got:\n%s", expected, text)
+		}
+	}
+	for _, unexpected := range []string{"'Loopback'", "'Provider'"} {
+		if strings.Contains(text, unexpected) {
+			t.Fatalf("did not expect %s to be flagged, got:\n%s", unexpected, text)
+		}
+	}
+}
+
+const loggingPackage = `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:ObjectName="Logging">
+  <DTS:LogProviders>
+    <DTS:LogProvider DTS:ConfigString="LogDB" DTS:CreationName="Microsoft.LogProviderSQLServer" DTS:DTSID="{AAAAAAAA-0000-0000-0000-000000000001}" DTS:ObjectName="SQL Log" />
+    <DTS:LogProvider DTS:ConfigString="LogFile" DTS:CreationName="Microsoft.LogProviderTextFile" DTS:DTSID="{AAAAAAAA-0000-0000-0000-000000000002}" DTS:ObjectName="Text Log" />
+    <DTS:LogProvider DTS:CreationName="Microsoft.LogProviderXMLFile" DTS:DTSID="{AAAAAAAA-0000-0000-0000-000000000003}" DTS:ObjectName="XML Log" />
+  </DTS:LogProviders>
+  <DTS:LoggingOptions DTS:FilterKind="0" DTS:LoggingMode="1">
+    <DTS:Property DTS:DataType="8" DTS:Name="EventFilter">2,7,OnError,9,OnWarning</DTS:Property>
+    <DTS:SelectedLogProviders>
+      <DTS:SelectedLogProvider DTS:InstanceID="{AAAAAAAA-0000-0000-0000-000000000001}" />
+    </DTS:SelectedLogProviders>
+  </DTS:LoggingOptions>
+  <DTS:Executables>
+    <DTS:Executable DTS:ObjectName="Load">
+      <DTS:LoggingOptions DTS:FilterKind="0" DTS:LoggingMode="1">
+        <DTS:Property DTS:DataType="8" DTS:Name="EventFilter">1,13,OnTaskFailed</DTS:Property>
+        <DTS:SelectedLogProviders>
+          <DTS:SelectedLogProvider DTS:InstanceID="{AAAAAAAA-0000-0000-0000-000000000002}" />
+        </DTS:SelectedLogProviders>
+      </DTS:LoggingOptions>
+    </DTS:Executable>
+    <DTS:Executable DTS:ObjectName="Archive">
+      <DTS:LoggingOptions DTS:FilterKind="0" DTS:LoggingMode="2" />
+    </DTS:Executable>
+  </DTS:Executables>
+</DTS:Executable>`
+
+func runLoggingAnalysis(t *testing.T, args map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Logging.dtsx"), []byte(loggingPackage), 0o644); err != nil {
+		t.Fatalf("failed to write package: %v", err)
+	}
+	args["file_path"] = "Logging.dtsx"
+	result, err := HandleAnalyzeLoggingConfiguration(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %+v", result.Content)
+	}
+	structured, ok := result.StructuredContent.(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected structured result, got %T", result.StructuredContent)
+	}
+	return structured
+}
+
+func TestDetectHardcodedValuesEnvironmentServerNames(t *testing.T) {
+	dir := t.TempDir()
+	contents := `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:ObjectName="Servers">
+  <DTS:ConnectionManagers>
+    <DTS:ConnectionManager DTS:ObjectName="Warehouse">
+      <DTS:ObjectData><DTS:ConnectionManager DTS:ConnectionString="Data Source=tcp:PROD-SQL-01\DW,1433;Initial Catalog=DW;Provider=SQLNCLI11.1;" /></DTS:ObjectData>
+    </DTS:ConnectionManager>
+    <DTS:ConnectionManager DTS:ObjectName="Staging">
+      <DTS:ObjectData><DTS:ConnectionManager DTS:ConnectionString="Server=stg-sql-02;Database=Staging;" /></DTS:ObjectData>
+    </DTS:ConnectionManager>
+    <DTS:ConnectionManager DTS:ObjectName="Catalog">
+      <DTS:ObjectData><DTS:ConnectionManager DTS:ConnectionString="Data Source=SQL01;Initial Catalog=PRODUCTION;" /></DTS:ObjectData>
+    </DTS:ConnectionManager>
+  </DTS:ConnectionManagers>
+</DTS:Executable>`
+	if err := os.WriteFile(filepath.Join(dir, "Servers.dtsx"), []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write package: %v", err)
+	}
+
+	run := func(args map[string]interface{}) string {
+		args["file_path"] = "Servers.dtsx"
+		result, err := HandleDetectHardcodedValues(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}, dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	text := run(map[string]interface{}{})
+	if !strings.Contains(text, "Connection 'Warehouse' targets environment-specific server PROD-SQL-01 (matches PROD)") {
+		t.Fatalf("expected production server to be flagged, got:\n%s", text)
+	}
+	for _, unexpected := range []string{"'Staging'", "'Catalog'"} {
+		if strings.Contains(text, unexpected) {
+			t.Fatalf("did not expect %s to be flagged with default patterns, got:\n%s", unexpected, text)
+		}
+	}
+
+	text = run(map[string]interface{}{"environment_names": "stg, qa"})
+	if !strings.Contains(text, "Connection 'Staging' targets environment-specific server stg-sql-02 (matches stg)") {
+		t.Fatalf("expected environment_names to flag staging server, got:\n%s", text)
+	}
+	if strings.Contains(text, "'Warehouse'") {
+		t.Fatalf("expected environment_names to replace the default patterns, got:\n%s", text)
+	}
+}
+
+func TestAnalyzeLoggingConfigurationGroupsProviders(t *testing.T) {
+	result := runLoggingAnalysis(t, map[string]interface{}{})
+	analysis := result["analysis"].(string)
+
+	for _, expected := range []string{
+		"  SQL Server:\n    - SQL Log\n      Connection: LogDB\n      Events Enabled: OnError, OnWarning",
+		"  Text File:\n    - Text Log\n      Connection: LogFile\n      Events Enabled: OnTaskFailed",
+		"  XML File:\n    - XML Log\n      Events Enabled: none (provider not selected)",
+		"  - Load: Enabled (OnTaskFailed)",
+		"Logging is enabled at package level but disabled for task 'Archive'",
+		"Log provider 'XML Log' is defined but not selected by any container",
+	} {
+		if !strings.Contains(analysis, expected) {
+			t.Fatalf("expected %q in analysis, got:\n%s", expected, analysis)
+		}
+	}
+
+	groups := result["providers_by_type"].(map[string][]loggingProvider)
+	if len(groups) != 3 || len(groups[logProviderSQLServer]) != 1 {
+		t.Fatalf("expected three provider groups, got %+v", groups)
+	}
+}
+
+func TestAnalyzeLoggingConfigurationProviderTypeFilter(t *testing.T) {
+	result := runLoggingAnalysis(t, map[string]interface{}{"provider_type": "text_file"})
+	groups := result["providers_by_type"].(map[string][]loggingProvider)
+	if len(groups) != 1 || len(groups[logProviderTextFile]) != 1 {
+		t.Fatalf("expected only the text file provider, got %+v", groups)
+	}
+	if strings.Contains(result["analysis"].(string), "SQL Log") {
+		t.Fatalf("expected SQL Server provider to be filtered out, got:\n%s", result["analysis"])
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Logging.dtsx"), []byte(loggingPackage), 0o644); err != nil {
+		t.Fatalf("failed to write package: %v", err)
+	}
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"file_path": "Logging.dtsx", "provider_type": "syslog"}}}
+	invalid, err := HandleAnalyzeLoggingConfiguration(context.Background(), request, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !invalid.IsError || !strings.Contains(invalid.Content[0].(mcp.TextContent).Text, "unknown provider_type") {
+		t.Fatalf("expected an unknown provider_type error, got %+v", invalid.Content)
+	}
+}
+
+const conversationPackage = `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:ObjectName="Conversation">
+  <DTS:Executables>
+    <DTS:Executable DTS:ObjectName="Load Customers" DTS:CreationName="Microsoft.Pipeline">
+      <DTS:EventHandlers>
+        <DTS:EventHandler DTS:EventName="OnError" DTS:ObjectName="OnError">
+          <DTS:Executables>
+            <DTS:Executable DTS:ObjectName="Notify Operator" DTS:CreationName="Microsoft.SendMailTask" />
+          </DTS:Executables>
+        </DTS:EventHandler>
+      </DTS:EventHandlers>
+    </DTS:Executable>
+    <DTS:Executable DTS:ObjectName="Archive Files" DTS:CreationName="Microsoft.FileSystemTask" />
+  </DTS:Executables>
+</DTS:Executable>`
+
+func askAboutDtsx(t *testing.T, args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Conversation.dtsx"), []byte(conversationPackage), 0o644); err != nil {
+		t.Fatalf("failed to write package: %v", err)
+	}
+	args["file_path"] = "Conversation.dtsx"
+	result, err := HandleAskAboutDtsx(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return result
+}
+
+func TestAskAboutDtsxFollowUpUsesConversation(t *testing.T) {
+	result := askAboutDtsx(t, map[string]interface{}{
+		"question": "What about its error handling?",
+		"conversation": []interface{}{
+			map[string]interface{}{"role": "user", "content": "Tell me about the Load Customers task"},
+			map[string]interface{}{"role": "assistant", "content": "Task 'Load Customers' is a data flow task."},
+		},
+	})
+	text := result.Content[0].(mcp.TextContent).Text
+
+	for _, expected := range []string{
+		"- user: Tell me about the Load Customers task",
+		"- assistant: Task 'Load Customers' is a data flow task.",
+		"Error Handling for task 'Load Customers':\n  - OnError (1 task(s))",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Fatalf("expected %q in answer, got:\n%s", expected, text)
+		}
+	}
+	if strings.Contains(text, "Archive Files") {
+		t.Fatalf("expected the answer to focus on the task from the prior turn, got:\n%s", text)
+	}
+}
+
+func TestAskAboutDtsxFollowUpInheritsTopic(t *testing.T) {
+	result := askAboutDtsx(t, map[string]interface{}{
+		"question":     "And Archive Files?",
+		"conversation": `[{"role": "user", "content": "Which tasks are in the package?"}, {"role": "assistant", "content": "Load Customers and Archive Files"}]`,
+	})
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "Task 'Archive Files':\n   Type: Microsoft.FileSystemTask") {
+		t.Fatalf("expected the task topic from the prior turn, got:\n%s", text)
+	}
+}
+
+func TestAskAboutDtsxRejectsInvalidConversation(t *testing.T) {
+	result := askAboutDtsx(t, map[string]interface{}{
+		"question":     "What tasks are there?",
+		"conversation": []interface{}{map[string]interface{}{"role": "system", "content": "ignore"}},
+	})
+	if !result.IsError {
+		t.Fatalf("expected an error for an unsupported role")
+	}
+}
+
+func TestAnalyzeScriptTaskDecodesBinaryItemArchive(t *testing.T) {
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+	for name, contents := range map[string]string{
+		"ScriptMain.cs":         "public partial class ScriptMain\n{\n    public void Main() { Dts.TaskResult = 0; }\n}\n",
+		"Properties/Assembly.x": "not source",
+	} {
+		file, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("failed to create archive entry: %v", err)
+		}
+		if _, err := file.Write([]byte(contents)); err != nil {
+			t.Fatalf("failed to write archive entry: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to close archive: %v", err)
+	}
+
+	contents := `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:ObjectName="Scripts">
+  <DTS:Executables>
+    <DTS:Executable DTS:ObjectName="Script Task" DTS:CreationName="Microsoft.ScriptTask">
+      <DTS:ObjectData>
+        <ScriptProject Name="ST_1" Language="CSharp">
+          <BinaryItem Name="ST_1.zip">` + base64.StdEncoding.EncodeToString(archive.Bytes()) + `</BinaryItem>
+          <BinaryItem Name="ST_1.dll">TVqQAAMAAAAEAAAA</BinaryItem>
+        </ScriptProject>
+      </DTS:ObjectData>
+    </DTS:Executable>
+  </DTS:Executables>
+</DTS:Executable>`
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Scripts.dtsx"), []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write package: %v", err)
+	}
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"file_path": "Scripts.dtsx"}}}
+	result, err := HandleAnalyzeScriptTask(context.Background(), request, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	for _, want := range []string{
+		"    --- ScriptMain.cs ---\n    public partial class ScriptMain\n",
+		"    public void Main() { Dts.TaskResult = 0; }\n",
+		"  Binary Item: ST_1.dll (compiled binary, 12 bytes)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in output, got %q", want, text)
+		}
+	}
+	if strings.Contains(text, "TVqQ") || strings.Contains(text, "not source") {
+		t.Fatalf("expected raw base64 and non-source files to be omitted, got %q", text)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -15,8 +16,32 @@ import (
 
 const excludeFileName = ".gossisignore"
 
-// ListPackages scans a directory for DTSX files
-func ListPackages(packageDirectory, excludeFile string) ([]string, error) {
+// packageFileType is a deployment artifact list_packages can include
+type packageFileType struct {
+	Extension string
+	Label     string
+}
+
+// packageFileTypes maps the lower-cased file_types accepted by list_packages to their extension and label
+var packageFileTypes = map[string]packageFileType{
+	"dtsx":      {Extension: ".dtsx", Label: "DTSX package"},
+	"params":    {Extension: ".params", Label: "Project parameters"},
+	"dtsconfig": {Extension: ".dtsconfig", Label: "Package configuration"},
+}
+
+// listedFile is a non-DTSX artifact found by list_packages
+type listedFile struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+// ListPackages scans a directory for DTSX files, stopping early when ctx is cancelled
+func ListPackages(ctx context.Context, packageDirectory, excludeFile string) ([]string, error) {
+	return ListPackageFiles(ctx, packageDirectory, excludeFile, ".dtsx")
+}
+
+// ListPackageFiles scans a directory for files with any of the given lower-case extensions
+func ListPackageFiles(ctx context.Context, packageDirectory, excludeFile string, extensions ...string) ([]string, error) {
 	var packages []string
 
 	excludePatterns, err := loadExcludePatterns(packageDirectory, excludeFile)
@@ -29,6 +54,9 @@ func ListPackages(packageDirectory, excludeFile string) ([]string, error) {
 		if err != nil {
 			return err
 		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 
 		relPath, relErr := filepath.Rel(packageDirectory, path)
 		if relErr != nil {
@@ -41,7 +69,7 @@ func ListPackages(packageDirectory, excludeFile string) ([]string, error) {
 			}
 			return nil
 		}
-		if !info.IsDir() && strings.ToLower(filepath.Ext(path)) == ".dtsx" {
+		if !info.IsDir() && slices.Contains(extensions, strings.ToLower(filepath.Ext(path))) {
 			// Get relative path from package directory
 			if relErr != nil {
 				relPath = path // fallback to absolute path if relative fails
@@ -54,7 +82,7 @@ func ListPackages(packageDirectory, excludeFile string) ([]string, error) {
 	return packages, err
 }
 
-func HandleListPackages(_ context.Context, request mcp.CallToolRequest, packageDirectory, excludeFile string) (*mcp.CallToolResult, error) {
+func HandleListPackages(ctx context.Context, request mcp.CallToolRequest, packageDirectory, excludeFile string) (*mcp.CallToolResult, error) {
 	args, _ := request.Params.Arguments.(map[string]interface{})
 
 	targetDir := strings.TrimSpace(packageDirectory)
@@ -82,12 +110,40 @@ func HandleListPackages(_ context.Context, request mcp.CallToolRequest, packageD
 		format = strings.ToLower(f)
 	}
 
-	packs, err := ListPackages(targetDir, excludeFile)
+	fileTypes := "dtsx"
+	if ft, ok := getStringArgument(args, "file_types"); ok {
+		fileTypes = ft
+	}
+	var extensions []string
+	for _, name := range strings.Split(fileTypes, ",") {
+		name = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "."))
+		if name == "" {
+			continue
+		}
+		fileType, ok := packageFileTypes[name]
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("unsupported file type %q (supported: dtsx, params, dtsConfig)", name)), nil
+		}
+		extensions = append(extensions, fileType.Extension)
+	}
+
+	found, err := ListPackageFiles(ctx, targetDir, excludeFile, extensions...)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to scan directory: %v", err)), nil
 	}
 
-	if len(packs) == 0 {
+	var packs []string
+	var others []listedFile
+	for _, rel := range found {
+		ext := strings.ToLower(filepath.Ext(rel))
+		if ext == ".dtsx" {
+			packs = append(packs, rel)
+			continue
+		}
+		others = append(others, listedFile{Path: rel, Type: packageFileTypes[strings.TrimPrefix(ext, ".")].Label})
+	}
+
+	if len(packs) == 0 && len(others) == 0 {
 		switch format {
 		case "json":
 			payload := map[string]interface{}{
@@ -122,6 +178,9 @@ func HandleListPackages(_ context.Context, request mcp.CallToolRequest, packageD
 			"packages":          packs,
 			"packages_absolute": absolute,
 		}
+		if len(others) > 0 {
+			payload["other_files"] = others
+		}
 		data, marshalErr := json.MarshalIndent(payload, "", "  ")
 		if marshalErr != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal list_packages result: %v", marshalErr)), nil
@@ -133,12 +192,21 @@ func HandleListPackages(_ context.Context, request mcp.CallToolRequest, packageD
 		for _, pkg := range packs {
 			builder.WriteString(fmt.Sprintf("- %s\n", pkg))
 		}
+		for _, other := range others {
+			builder.WriteString(fmt.Sprintf("- %s (%s)\n", other.Path, other.Type))
+		}
 		return mcp.NewToolResultText(builder.String()), nil
 	default:
 		result := fmt.Sprintf("Found %d DTSX package(s) in directory: %s\n\n", len(packs), targetDir)
+		if len(others) > 0 {
+			result = fmt.Sprintf("Found %d DTSX package(s) and %d other file(s) in directory: %s\n\n", len(packs), len(others), targetDir)
+		}
 		for i, pkg := range packs {
 			result += fmt.Sprintf("%d. %s\n", i+1, pkg)
 		}
+		for i, other := range others {
+			result += fmt.Sprintf("%d. %s (%s)\n", len(packs)+i+1, other.Path, other.Type)
+		}
 		return mcp.NewToolResultText(result), nil
 	}
 }
@@ -216,7 +284,7 @@ func shouldExcludePath(relPath string, isDir bool, patterns []string) bool {
 			continue
 		}
 
-		if match, _ := path.Match(pattern, rel); match {
+		if matchGlob(strings.Split(pattern, "/"), strings.Split(rel, "/")) {
 			return true
 		}
 	}
@@ -224,6 +292,28 @@ func shouldExcludePath(relPath string, isDir bool, patterns []string) bool {
 	return false
 }
 
+// matchGlob matches path segments against pattern segments, where a "**" segment matches zero or more path segments
+func matchGlob(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchGlob(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if match, _ := path.Match(pattern[0], segments[0]); !match {
+		return false
+	}
+	return matchGlob(pattern[1:], segments[1:])
+}
+
 func normalizePattern(pattern string) (string, bool) {
 	trimmed := strings.TrimSpace(pattern)
 	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
@@ -1,10 +1,16 @@
 package packages
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -66,7 +72,7 @@ func TestListPackages(t *testing.T) {
 			t.Fatalf("failed to create file %s: %v", file, err)
 		}
 	}
-	results, err := ListPackages(dir, "")
+	results, err := ListPackages(context.Background(), dir, "")
 	if err != nil {
 		t.Fatalf("unexpected error listing packages: %v", err)
 	}
@@ -78,7 +84,7 @@ func TestListPackages(t *testing.T) {
 	if err := os.WriteFile(excludePath, []byte("nested/\n"), 0o644); err != nil {
 		t.Fatalf("failed to write exclude file: %v", err)
 	}
-	filtered, err := ListPackages(dir, "")
+	filtered, err := ListPackages(context.Background(), dir, "")
 	if err != nil {
 		t.Fatalf("unexpected error listing packages with exclude: %v", err)
 	}
@@ -87,6 +93,69 @@ func TestListPackages(t *testing.T) {
 	}
 }
 
+func TestListPackagesExcludeGlobPatterns(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{
+		"keep.dtsx",
+		"legacy/old.dtsx",
+		"sales/legacy/older.dtsx",
+		"sales/legacy/notes.dtsx.bak",
+		"sales/current.dtsx",
+		"archive/2023/q1.dtsx",
+		"archived/q2.dtsx",
+	}
+	for _, name := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatalf("failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(file, []byte("<xml />"), 0o644); err != nil {
+			t.Fatalf("failed to create file %s: %v", name, err)
+		}
+	}
+	patterns := "# glob patterns\n**/legacy/*.dtsx\narchive/**\n**/nomatch/*.dtsx\nsales/*.txt\n"
+	if err := os.WriteFile(filepath.Join(dir, "exclude.txt"), []byte(patterns), 0o644); err != nil {
+		t.Fatalf("failed to write exclude file: %v", err)
+	}
+
+	results, err := ListPackages(context.Background(), dir, filepath.Join(dir, "exclude.txt"))
+	if err != nil {
+		t.Fatalf("unexpected error listing packages: %v", err)
+	}
+	var listed []string
+	for _, rel := range results {
+		listed = append(listed, filepath.ToSlash(rel))
+	}
+	want := []string{"archived/q2.dtsx", "keep.dtsx", "sales/current.dtsx"}
+	if strings.Join(listed, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected %v after exclusions, got %v", want, listed)
+	}
+}
+
+func TestShouldExcludePathGlobs(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"**/legacy/*.dtsx", "legacy/a.dtsx", true},
+		{"**/legacy/*.dtsx", "x/y/legacy/a.dtsx", true},
+		{"**/legacy/*.dtsx", "legacy/sub/a.dtsx", false},
+		{"**/legacy/*.dtsx", "legacyish/a.dtsx", false},
+		{"archive/**", "archive", true},
+		{"archive/**", "archive/2023/a.dtsx", true},
+		{"archive/**", "archives/a.dtsx", false},
+		{"*.dtsx", "a.dtsx", true},
+		{"*.dtsx", "sub/a.dtsx", false},
+		{"sub/**/*.dtsx", "sub/a.dtsx", true},
+	}
+	for _, tc := range cases {
+		if got := shouldExcludePath(filepath.FromSlash(tc.path), false, []string{tc.pattern}); got != tc.want {
+			t.Errorf("shouldExcludePath(%q, %q) = %v, want %v", tc.path, tc.pattern, got, tc.want)
+		}
+	}
+}
+
 func TestHandleListPackagesJSONOutput(t *testing.T) {
 	dir := t.TempDir()
 	if err := os.WriteFile(filepath.Join(dir, "sample.dtsx"), []byte("<xml />"), 0o644); err != nil {
@@ -136,6 +205,61 @@ func TestHandleListPackagesJSONOutput(t *testing.T) {
 	}
 }
 
+func TestHandleListPackagesFileTypes(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"sample.dtsx", "Project.params", "Sample.dtsConfig", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("<xml />"), 0o644); err != nil {
+			t.Fatalf("failed to create file %s: %v", name, err)
+		}
+	}
+
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"directory":  dir,
+				"format":     "json",
+				"file_types": "dtsx, params,.dtsConfig",
+			},
+		},
+	}
+	result, err := HandleListPackages(context.Background(), request, "", "")
+	if err != nil {
+		t.Fatalf("unexpected error handling list_packages: %v", err)
+	}
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[0])
+	}
+
+	var payload struct {
+		Count      int          `json:"count"`
+		Packages   []string     `json:"packages"`
+		OtherFiles []listedFile `json:"other_files"`
+	}
+	if err := json.Unmarshal([]byte(textContent.Text), &payload); err != nil {
+		t.Fatalf("failed to decode JSON payload: %v", err)
+	}
+	if payload.Count != 1 || len(payload.Packages) != 1 {
+		t.Fatalf("expected only the DTSX file in packages, got %+v", payload)
+	}
+	labels := map[string]string{}
+	for _, other := range payload.OtherFiles {
+		labels[other.Path] = other.Type
+	}
+	if len(labels) != 2 || labels["Project.params"] != "Project parameters" || labels["Sample.dtsConfig"] != "Package configuration" {
+		t.Fatalf("unexpected other files: %+v", payload.OtherFiles)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"directory": dir, "file_types": "ispac"}
+	result, err = HandleListPackages(context.Background(), request, "", "")
+	if err != nil {
+		t.Fatalf("unexpected error handling list_packages: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected an error result for an unsupported file type")
+	}
+}
+
 func TestResolveFilePath(t *testing.T) {
 	base := t.TempDir()
 	file := "sample.dtsx"
@@ -156,6 +280,405 @@ func TestPerformBatchPackageAnalysis(t *testing.T) {
 	}
 }
 
+func TestBuildBenchmarkReportPercentiles(t *testing.T) {
+	var timings []batchBenchmark
+	for i := 100; i >= 1; i-- {
+		timings = append(timings, batchBenchmark{File: fmt.Sprintf("pkg%03d.dtsx", i), TotalMs: float64(i)})
+	}
+	report := buildBenchmarkReport(timings)
+	if report.P50Ms != 50 || report.P90Ms != 90 || report.P99Ms != 99 {
+		t.Fatalf("unexpected percentiles: p50=%v p90=%v p99=%v", report.P50Ms, report.P90Ms, report.P99Ms)
+	}
+	if report.Files[0].File != "pkg001.dtsx" {
+		t.Fatalf("expected files sorted by path, got %s first", report.Files[0].File)
+	}
+}
+
+func TestListPackagesStopsWhenCancelled(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Package.dtsx"), []byte("<Executable/>"), 0644); err != nil {
+		t.Fatalf("failed to write package: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := ListPackages(ctx, dir, ""); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestHandleBatchAnalyzeBenchmark(t *testing.T) {
+	dir, file := locateTestdata(t, "Expressions.dtsx")
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"file_paths": []interface{}{file, "missing.dtsx"},
+				"format":     "json",
+				"benchmark":  true,
+			},
+		},
+	}
+	result, err := HandleBatchAnalyze(context.Background(), request, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	textContent, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[0])
+	}
+	var summary batchSummary
+	if err := json.Unmarshal([]byte(textContent.Text), &summary); err != nil {
+		t.Fatalf("failed to decode summary: %v", err)
+	}
+	if summary.Benchmark == nil || len(summary.Benchmark.Files) != 2 {
+		t.Fatalf("expected benchmark timings for both files, got %+v", summary.Benchmark)
+	}
+	for _, timing := range summary.Benchmark.Files {
+		if timing.TotalMs < timing.ParseMs {
+			t.Fatalf("expected total time to include parse time: %+v", timing)
+		}
+	}
+}
+
+func TestHandleBatchAnalyzeProgress(t *testing.T) {
+	original := progressOutput
+	t.Cleanup(func() { progressOutput = original })
+	var buf bytes.Buffer
+	progressOutput = &buf
+
+	dir, file := locateTestdata(t, "Expressions.dtsx")
+	arguments := map[string]interface{}{"file_paths": []interface{}{file, "missing.dtsx"}}
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: arguments}}
+	if _, err := HandleBatchAnalyze(context.Background(), request, dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events := strings.Split(strings.TrimSpace(buf.String()), "\n\n")
+	if len(events) != 2 {
+		t.Fatalf("expected one progress event per file, got %q", buf.String())
+	}
+	statuses := map[string]string{}
+	for i, event := range events {
+		var progress batchProgress
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(event, "data: ")), &progress); err != nil {
+			t.Fatalf("failed to decode progress event %q: %v", event, err)
+		}
+		if progress.Completed != i+1 || progress.Total != 2 {
+			t.Fatalf("unexpected progress counts: %+v", progress)
+		}
+		statuses[progress.File] = progress.Status
+	}
+	if statuses[file] != "done" || statuses["missing.dtsx"] != "failed" {
+		t.Fatalf("unexpected progress statuses: %v", statuses)
+	}
+
+	buf.Reset()
+	arguments["disable_progress"] = true
+	if _, err := HandleBatchAnalyze(context.Background(), request, dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected no progress with disable_progress, got %q", buf.String())
+	}
+}
+
+func TestHandleBatchAnalyzePerFileOutput(t *testing.T) {
+	original := progressOutput
+	t.Cleanup(func() { progressOutput = original })
+	var buf bytes.Buffer
+	progressOutput = &buf
+
+	dir, file := locateTestdata(t, "Expressions.dtsx")
+	outputDir := filepath.Join(t.TempDir(), "results")
+	arguments := map[string]interface{}{
+		"file_paths":       []interface{}{file, "missing.dtsx", filepath.Join("nested", file)},
+		"format":           "json",
+		"max_concurrent":   float64(1),
+		"output_file_path": filepath.Join(outputDir, "summary.json"),
+	}
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: arguments}}
+	result, err := HandleBatchAnalyze(context.Background(), request, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var summary batchSummary
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &summary); err != nil {
+		t.Fatalf("failed to decode batch summary: %v", err)
+	}
+	if len(summary.OutputFiles) != 3 {
+		t.Fatalf("expected one output file per package, got %v", summary.OutputFiles)
+	}
+
+	var progressFiles []string
+	for _, event := range strings.Split(strings.TrimSpace(buf.String()), "\n\n") {
+		var progress batchProgress
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(event, "data: ")), &progress); err != nil {
+			t.Fatalf("failed to decode progress event %q: %v", event, err)
+		}
+		progressFiles = append(progressFiles, progress.File)
+	}
+
+	var previous time.Time
+	names := map[string]bool{}
+	for i, path := range summary.OutputFiles {
+		if filepath.Dir(path) != outputDir {
+			t.Fatalf("expected %s to be written to %s", path, outputDir)
+		}
+		names[filepath.Base(path)] = true
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("expected per-file output %s: %v", path, err)
+		}
+		var written batchAnalysisResult
+		if err := json.Unmarshal(data, &written); err != nil {
+			t.Fatalf("failed to decode %s: %v", path, err)
+		}
+		if written.PackagePath != summary.PackageSummaries[i].PackagePath || written.PackagePath != progressFiles[i] {
+			t.Fatalf("expected output files in completion order, file %d holds %s", i, written.PackagePath)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("failed to stat %s: %v", path, err)
+		}
+		if info.ModTime().Before(previous) {
+			t.Fatalf("expected %s to be written after the previous file", path)
+		}
+		previous = info.ModTime()
+	}
+	if !names["Expressions.json"] || !names["Expressions-2.json"] || !names["missing.json"] {
+		t.Fatalf("unexpected output file names: %v", summary.OutputFiles)
+	}
+}
+
+func TestHandleAnalyzeCdcControlTask(t *testing.T) {
+	dir, file := locateTestdata(t, "CdcControl.dtsx")
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"file_path": file,
+			},
+		},
+	}
+	result, err := HandleAnalyzeCdcControlTask(context.Background(), request, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	textContent, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[0])
+	}
+	expected := []string{
+		"Task 1: Mark Initial Load Start",
+		"Operation: MarkInitialLoadStart",
+		"Connection: Sales Source",
+		"State Name: CDC_Sales",
+		"State Variable: User::CDC_State",
+		"Automatic State Persistence: True",
+		"State Connection: CDC State",
+		"Task 2: Get Processing Range",
+		"Operation: GetProcessingRange",
+		"Automatic State Persistence: False",
+	}
+	for _, want := range expected {
+		if !strings.Contains(textContent.Text, want) {
+			t.Fatalf("expected %q in CDC control task analysis, got %q", want, textContent.Text)
+		}
+	}
+}
+
+func TestHandleAnalyzeCdcControlTaskFormatAndOutputFile(t *testing.T) {
+	dir, file := locateTestdata(t, "CdcControl.dtsx")
+	outputPath := filepath.Join(t.TempDir(), "cdc.json")
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"file_path":        file,
+				"format":           "json",
+				"output_file_path": outputPath,
+			},
+		},
+	}
+	result, err := HandleAnalyzeCdcControlTask(context.Background(), request, dir)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected failure: %v %+v", err, result)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &decoded); err != nil {
+		t.Fatalf("expected JSON output, got %q: %v", text, err)
+	}
+	if decoded["tool_name"] != "CDC Control Task Analysis" || !strings.Contains(fmt.Sprint(decoded["data"]), "Operation: GetProcessingRange") {
+		t.Fatalf("unexpected JSON result: %v", decoded)
+	}
+
+	written, err := os.ReadFile(outputPath)
+	if err != nil || string(written) != text {
+		t.Fatalf("expected the formatted result in %s, got %q (err=%v)", outputPath, string(written), err)
+	}
+}
+
+func TestDescribeCdcOperation(t *testing.T) {
+	tests := map[string]string{
+		"0":                  "MarkInitialLoadStart (0)",
+		"3":                  "GetProcessingRange (3)",
+		"5":                  "ResetCdcState (5)",
+		"MarkProcessedRange": "MarkProcessedRange",
+		"":                   "",
+	}
+	for value, want := range tests {
+		if got := describeCdcOperation(value); got != want {
+			t.Errorf("describeCdcOperation(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestHandleAnalyzeMessageQueueTasks(t *testing.T) {
+	dir, file := locateTestdata(t, "MessageQueue.dtsx")
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"file_path": file,
+			},
+		},
+	}
+	result, err := HandleAnalyzeMessageQueueTasks(context.Background(), request, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	textContent, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[0])
+	}
+	expected := []string{
+		"Task 1: Publish Order",
+		"Operation: Send",
+		"Queue Connection: Orders Queue",
+		`Queue Path: .\private$\orders`,
+		"Message Type: String",
+		"Message Content: Order batch ready",
+		"Encryption Algorithm: RC4",
+		"Description: Sends the order batch notification",
+		"Task 2: Read Audit",
+		"Operation: Receive",
+		`Queue Path: mq01\private$\audit`,
+		"Message Type: Data File",
+		"Filter: FromPackage",
+		"Remove From Queue: True",
+		"Use Encryption: True",
+		"Encryption Algorithm: RC2",
+	}
+	for _, want := range expected {
+		if !strings.Contains(textContent.Text, want) {
+			t.Fatalf("expected %q in message queue task analysis, got %q", want, textContent.Text)
+		}
+	}
+}
+
+func TestHandleAnalyzeMessageQueueTasksSecurity(t *testing.T) {
+	analyze := func(name string) string {
+		dir, file := locateTestdata(t, name)
+		request := mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Arguments: map[string]interface{}{
+					"file_path": file,
+				},
+			},
+		}
+		result, err := HandleAnalyzeMessageQueueTasks(context.Background(), request, dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	secure := analyze("MessageQueue.dtsx")
+	if strings.Count(secure, "  Security Findings: none\n") != 2 || strings.Contains(secure, "WARNING") {
+		t.Fatalf("expected no security findings for encrypted queues, got %q", secure)
+	}
+
+	insecure := analyze("MessageQueueInsecure.dtsx")
+	if strings.Count(insecure, "    - WARNING: UseEncryption is False; messages are transmitted over MSMQ in plaintext\n") != 2 {
+		t.Fatalf("expected explicit and default UseEncryption=False to be flagged, got %q", insecure)
+	}
+	if strings.Count(insecure, "    - WARNING: Connection 'Shipping Queue' sets UseWindowsAuthentication to False") != 2 {
+		t.Fatalf("expected UseWindowsAuthentication=False to be flagged, got %q", insecure)
+	}
+	if strings.Contains(insecure, "Security Findings: none") {
+		t.Fatalf("expected every insecure task to have findings, got %q", insecure)
+	}
+}
+
+func TestHandleAnalyzeWmiTask(t *testing.T) {
+	dir, file := locateTestdata(t, "WmiTasks.dtsx")
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"file_path": file,
+			},
+		},
+	}
+	result, err := HandleAnalyzeWmiTask(context.Background(), request, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	textContent, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[0])
+	}
+	expected := []string{
+		"Task 1: Read Disk Space (WMI Data Reader)",
+		"WMI Connection: Local WMI",
+		"WQL Query Source Type: DirectInput",
+		"WQL Query Source: SELECT FreeSpace, Size FROM Win32_LogicalDisk",
+		"Output Type: PropertyNameAndValue",
+		"Destination: Disk Report",
+		"Task 2: Wait For Drop File (WMI Event Watcher)",
+		"Action At Event: LogTheEventAndFireSSISEvent",
+		"After Event: ReturnWithSuccess",
+		"Action At Timeout: LogTheEvent",
+		"After Timeout: ReturnWithFailure",
+		"Timeout: 600 seconds",
+	}
+	for _, want := range expected {
+		if !strings.Contains(textContent.Text, want) {
+			t.Fatalf("expected %q in WMI task analysis, got %q", want, textContent.Text)
+		}
+	}
+}
+
+func TestHandleAnalyzeWmiTaskFormatAndOutputFile(t *testing.T) {
+	dir, file := locateTestdata(t, "WmiTasks.dtsx")
+	outputPath := filepath.Join(t.TempDir(), "wmi.json")
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"file_path":        file,
+				"format":           "json",
+				"output_file_path": outputPath,
+			},
+		},
+	}
+	result, err := HandleAnalyzeWmiTask(context.Background(), request, dir)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected failure: %v %+v", err, result)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &decoded); err != nil {
+		t.Fatalf("expected JSON output, got %q: %v", text, err)
+	}
+	if decoded["tool_name"] != "WMI Task Analysis" || !strings.Contains(fmt.Sprint(decoded["data"]), "WMI Connection: Local WMI") {
+		t.Fatalf("unexpected JSON result: %v", decoded)
+	}
+
+	written, err := os.ReadFile(outputPath)
+	if err != nil || string(written) != text {
+		t.Fatalf("expected the formatted result in %s, got %q (err=%v)", outputPath, string(written), err)
+	}
+}
+
 func TestDescribeTaskType(t *testing.T) {
 	task := types.Task{Properties: []types.Property{{Name: "CreationName", Value: "Microsoft.ExecuteSQLTask"}}}
 	if desc := describeTaskType(task); desc != "Execute SQL Task" {
@@ -208,3 +731,80 @@ func TestExtractPropertyAndSection(t *testing.T) {
 		t.Fatalf("expected full section, got %s", section)
 	}
 }
+
+func TestHandleBatchAnalyzeGlobPattern(t *testing.T) {
+	original := progressOutput
+	t.Cleanup(func() { progressOutput = original })
+	progressOutput = io.Discard
+
+	dir := t.TempDir()
+	sourceDir, source := locateTestdata(t, "Expressions.dtsx")
+	data, err := os.ReadFile(filepath.Join(sourceDir, source))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	for _, name := range []string{"Load.dtsx", "Extract.dtsx", filepath.Join("archive", "Old.dtsx")} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatalf("failed to write package: %v", err)
+		}
+	}
+
+	analyzed := func(arguments map[string]interface{}) []string {
+		t.Helper()
+		arguments["format"] = "json"
+		result, err := HandleBatchAnalyze(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: arguments}}, dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		text := result.Content[0].(mcp.TextContent).Text
+		if result.IsError {
+			t.Fatalf("unexpected tool error: %s", text)
+		}
+		var summary batchSummary
+		if err := json.Unmarshal([]byte(text), &summary); err != nil {
+			t.Fatalf("failed to decode batch summary: %v", err)
+		}
+		var names []string
+		for _, pkg := range summary.PackageSummaries {
+			if !pkg.Success {
+				t.Fatalf("expected %s to be analyzed: %s", pkg.PackagePath, pkg.Error)
+			}
+			names = append(names, filepath.Base(pkg.PackagePath))
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	if got := analyzed(map[string]interface{}{"glob_pattern": "*.dtsx"}); !reflect.DeepEqual(got, []string{"Extract.dtsx", "Load.dtsx"}) {
+		t.Fatalf("expected the glob to match the top-level packages, got %v", got)
+	}
+
+	// Load.dtsx is listed explicitly, by absolute path and matched by the glob; it is analyzed once
+	got := analyzed(map[string]interface{}{
+		"file_paths":   []interface{}{"Load.dtsx", filepath.Join(dir, "Load.dtsx"), "archive/Old.dtsx"},
+		"glob_pattern": "*.dtsx",
+	})
+	if !reflect.DeepEqual(got, []string{"Extract.dtsx", "Load.dtsx", "Old.dtsx"}) {
+		t.Fatalf("expected merged and deduplicated paths, got %v", got)
+	}
+}
+
+func TestHandleBatchAnalyzeRequiresFilePathsOrGlob(t *testing.T) {
+	cases := map[string]map[string]interface{}{
+		"file_paths or glob_pattern parameter is required": {},
+		"invalid glob_pattern":                             {"glob_pattern": "[.dtsx"},
+		"no valid file paths provided":                     {"glob_pattern": "*.missing"},
+	}
+	for want, arguments := range cases {
+		result, err := HandleBatchAnalyze(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: arguments}}, t.TempDir())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if text := result.Content[0].(mcp.TextContent).Text; !result.IsError || !strings.Contains(text, want) {
+			t.Fatalf("expected %q, got %q", want, text)
+		}
+	}
+}
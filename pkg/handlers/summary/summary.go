@@ -0,0 +1,172 @@
+package summary
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/MCPRUNNER/gossisMCP/pkg/formatter"
+	"github.com/MCPRUNNER/gossisMCP/pkg/handlers/analysis"
+	"github.com/MCPRUNNER/gossisMCP/pkg/handlers/extraction"
+	packagehandlers "github.com/MCPRUNNER/gossisMCP/pkg/handlers/packages"
+	"github.com/MCPRUNNER/gossisMCP/pkg/util/output"
+)
+
+type toolHandler func(context.Context, mcp.CallToolRequest, string) (*mcp.CallToolResult, error)
+
+type summarySection struct {
+	Name    string
+	Title   string
+	Handler toolHandler
+}
+
+// summarySections lists the analyses combined by summarize_all, in report order
+var summarySections = []summarySection{
+	{Name: "parse_dtsx", Title: "Package Structure", Handler: extraction.HandleParseDtsx},
+	{Name: "extract_tasks", Title: "Tasks", Handler: extraction.HandleExtractTasks},
+	{Name: "extract_connections", Title: "Connections", Handler: extraction.HandleExtractConnections},
+	{Name: "extract_variables", Title: "Variables", Handler: extraction.HandleExtractVariables},
+	{Name: "extract_parameters", Title: "Parameters", Handler: extraction.HandleExtractParameters},
+	{Name: "analyze_data_flow", Title: "Data Flow", Handler: analysis.HandleAnalyzeDataFlow},
+	{Name: "validate_best_practices", Title: "Best Practices", Handler: packagehandlers.HandleValidateBestPractices},
+	{Name: "detect_hardcoded_values", Title: "Hardcoded Values", Handler: packagehandlers.HandleDetectHardcodedValues},
+	{Name: "scan_credentials", Title: "Credential Scan", Handler: analysis.HandleScanCredentials},
+	{Name: "detect_encryption", Title: "Encryption", Handler: analysis.HandleDetectEncryption},
+	{Name: "analyze_code_quality", Title: "Code Quality", Handler: analysis.HandleAnalyzeCodeQuality},
+}
+
+// HandleSummarizeAll runs the curated analysis tools on a single DTSX file and combines their reports
+func HandleSummarizeAll(ctx context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
+	filePath, err := request.RequireString("file_path")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Get format parameter (default to "text")
+	formatStr := request.GetString("format", "text")
+	format := formatter.OutputFormat(formatStr)
+
+	selected, err := selectSections(request.GetStringSlice("sections", nil))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	sections := make([]formatter.SectionData, len(selected))
+	var wg sync.WaitGroup
+	for i, section := range selected {
+		wg.Add(1)
+		go func(i int, section summarySection) {
+			defer wg.Done()
+			sections[i] = formatter.SectionData{
+				Title:   section.Title,
+				Content: runSection(ctx, section, filePath, packageDirectory),
+				Level:   2,
+			}
+		}(i, section)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return mcp.NewToolResultError("summarize_all cancelled"), nil
+	}
+
+	analysisResult := formatter.CreateAnalysisResult("summarize_all", filePath, sections, nil)
+	report := formatter.FormatAnalysisResult(analysisResult, format)
+
+	if outputPath := request.GetString("output_file_path", ""); outputPath != "" {
+		if err := output.WriteOutput(analysis.ResolveFilePath(outputPath, packageDirectory), report); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	if format == formatter.FormatJSON {
+		jsonResult := map[string]interface{}{
+			"tool_name": analysisResult.ToolName,
+			"file_path": analysisResult.FilePath,
+			"package":   filepath.Base(analysisResult.FilePath),
+			"timestamp": time.Now().Format(time.RFC3339),
+			"status":    analysisResult.Status,
+			"analysis":  sections,
+		}
+		return mcp.NewToolResultStructured(jsonResult, "Package summary"), nil
+	}
+
+	return mcp.NewToolResultText(report), nil
+}
+
+// selectSections filters the curated sections. Plain names include a section and names
+// prefixed with '-' exclude it; with no plain names every section is included.
+func selectSections(names []string) ([]summarySection, error) {
+	known := make(map[string]bool, len(summarySections))
+	for _, section := range summarySections {
+		known[section.Name] = true
+	}
+
+	include := make(map[string]bool)
+	exclude := make(map[string]bool)
+	for _, raw := range names {
+		name := strings.TrimSpace(raw)
+		if name == "" {
+			continue
+		}
+		excluded := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+		if !known[name] {
+			return nil, fmt.Errorf("unknown section: %s", name)
+		}
+		if excluded {
+			exclude[name] = true
+		} else {
+			include[name] = true
+		}
+	}
+
+	var selected []summarySection
+	for _, section := range summarySections {
+		if len(include) > 0 && !include[section.Name] {
+			continue
+		}
+		if exclude[section.Name] {
+			continue
+		}
+		selected = append(selected, section)
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no sections selected")
+	}
+	return selected, nil
+}
+
+// runSection invokes a section handler with text output and returns its report
+func runSection(ctx context.Context, section summarySection, filePath, packageDirectory string) string {
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: section.Name,
+			Arguments: map[string]interface{}{
+				"file_path": filePath,
+				"format":    "text",
+			},
+		},
+	}
+
+	result, err := section.Handler(ctx, request, packageDirectory)
+	if err != nil {
+		return fmt.Sprintf("Error: %v", err)
+	}
+	if result == nil {
+		return "No output"
+	}
+
+	var text strings.Builder
+	for _, content := range result.Content {
+		if textContent, ok := content.(mcp.TextContent); ok {
+			text.WriteString(textContent.Text)
+		}
+	}
+	return strings.TrimSpace(text.String())
+}
@@ -0,0 +1,94 @@
+package summary
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func createRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: args,
+		},
+	}
+}
+
+func repoRoot(t *testing.T) string {
+	t.Helper()
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	t.Fatal("could not locate repository root")
+	return ""
+}
+
+func TestSelectSections(t *testing.T) {
+	all, err := selectSections(nil)
+	if err != nil || len(all) != len(summarySections) {
+		t.Fatalf("expected all sections, got %d (err=%v)", len(all), err)
+	}
+
+	included, err := selectSections([]string{"scan_credentials", "detect_encryption"})
+	if err != nil || len(included) != 2 || included[0].Name != "scan_credentials" {
+		t.Fatalf("expected included sections in report order, got %+v (err=%v)", included, err)
+	}
+
+	excluded, err := selectSections([]string{"-analyze_code_quality"})
+	if err != nil || len(excluded) != len(summarySections)-1 {
+		t.Fatalf("expected one section excluded, got %d (err=%v)", len(excluded), err)
+	}
+
+	if _, err := selectSections([]string{"not_a_tool"}); err == nil {
+		t.Fatal("expected unknown section to be rejected")
+	}
+}
+
+func TestHandleSummarizeAll(t *testing.T) {
+	dir := filepath.Join(repoRoot(t), "testdata")
+	outputPath := filepath.Join(t.TempDir(), "reports", "summary.md")
+	request := createRequest(map[string]interface{}{
+		"file_path":        "Package1.dtsx",
+		"sections":         []interface{}{"parse_dtsx", "detect_encryption"},
+		"format":           "markdown",
+		"output_file_path": outputPath,
+	})
+	result, err := HandleSummarizeAll(context.Background(), request, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	textContent, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[0])
+	}
+	for _, want := range []string{"Package Structure", "Encryption", "Protection Level"} {
+		if !strings.Contains(textContent.Text, want) {
+			t.Fatalf("expected %q in summary, got %q", want, textContent.Text)
+		}
+	}
+	if strings.Contains(textContent.Text, "Code Quality") {
+		t.Fatalf("expected unselected sections to be omitted, got %q", textContent.Text)
+	}
+	written, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("expected output file to be written: %v", err)
+	}
+	if string(written) != textContent.Text {
+		t.Fatal("expected output file to match returned report")
+	}
+}
@@ -13,13 +13,19 @@ import (
 	projecttemplates "github.com/MCPRUNNER/gossisMCP/pkg/templates"
 )
 
+// Options controls optional render_template features
+type Options struct {
+	// EnablePlugins permits functions_plugin to load custom template functions from a compiled Go plugin
+	EnablePlugins bool
+}
+
 type ReportPage struct {
 	Title string                   `json:"title"`
 	Data  []map[string]interface{} `json:"data"`
 }
 
 // HandleRenderTemplate renders an HTML template using JSON data and writes the output file.
-func HandleRenderTemplate(_ context.Context, request mcp.CallToolRequest, packageDirectory string) (*mcp.CallToolResult, error) {
+func HandleRenderTemplate(_ context.Context, request mcp.CallToolRequest, packageDirectory string, options Options) (*mcp.CallToolResult, error) {
 	args, _ := request.Params.Arguments.(map[string]interface{})
 
 	templatePath := getFirstString(args, "template_file_path", "templateFilePath")
@@ -35,6 +41,21 @@ func HandleRenderTemplate(_ context.Context, request mcp.CallToolRequest, packag
 	templatePath = resolveAgainstPackage(templatePath, packageDirectory)
 	outputPath = resolveAgainstPackage(outputPath, packageDirectory)
 
+	var renderOptions projecttemplates.RenderOptions
+	if basePath := getFirstString(args, "base_template_path", "baseTemplatePath"); basePath != "" {
+		renderOptions.BaseTemplatePath = resolveAgainstPackage(basePath, packageDirectory)
+	}
+	if pluginPath := getFirstString(args, "functions_plugin", "functionsPlugin"); pluginPath != "" {
+		if !options.EnablePlugins {
+			return mcp.NewToolResultError("functions_plugin is disabled; set server.enable_template_plugins to enable it"), nil
+		}
+		loaded, err := projecttemplates.LoadFuncMapPlugin(resolveAgainstPackage(pluginPath, packageDirectory))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		renderOptions.Funcs = loaded
+	}
+
 	jsonData, err := extractJSONPayload(args, packageDirectory)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
@@ -144,7 +165,7 @@ func HandleRenderTemplate(_ context.Context, request mcp.CallToolRequest, packag
 		return mcp.NewToolResultError(fmt.Sprintf("failed to create output directory: %v", err)), nil
 	}
 
-	if err := projecttemplates.RenderTemplateFromJSON(jsonData, templatePath, outputPath); err != nil {
+	if err := projecttemplates.RenderTemplateFromJSONWithOptions(jsonData, templatePath, outputPath, renderOptions); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to render template: %v", err)), nil
 	}
 
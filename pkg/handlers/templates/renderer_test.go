@@ -28,7 +28,7 @@ func TestHandleRenderTemplateWithInlineJSON(t *testing.T) {
 		},
 	}
 
-	result, err := HandleRenderTemplate(context.Background(), request, baseDir)
+	result, err := HandleRenderTemplate(context.Background(), request, baseDir, Options{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -72,7 +72,7 @@ func TestHandleRenderTemplateWithJSONFile(t *testing.T) {
 		},
 	}
 
-	result, err := HandleRenderTemplate(context.Background(), request, "")
+	result, err := HandleRenderTemplate(context.Background(), request, "", Options{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -122,7 +122,7 @@ func TestHandleRenderTemplateWithDataArray(t *testing.T) {
 		},
 	}
 
-	result, err := HandleRenderTemplate(context.Background(), request, "")
+	result, err := HandleRenderTemplate(context.Background(), request, "", Options{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -142,3 +142,87 @@ func TestHandleRenderTemplateWithDataArray(t *testing.T) {
 		t.Fatalf("expected output to contain 'test2: Analysis 2', got: %q", output)
 	}
 }
+
+func TestHandleRenderTemplateFunctionsPlugin(t *testing.T) {
+	baseDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(baseDir, "report.tmpl"), []byte("{{titleCase .name}}"), 0o644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"template_file_path": "report.tmpl",
+				"output_file_path":   "report.txt",
+				"json_data":          `{"name":"world"}`,
+				"functions_plugin":   "missing.so",
+			},
+		},
+	}
+
+	result, err := HandleRenderTemplate(context.Background(), request, baseDir, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected functions_plugin to be rejected when template plugins are disabled")
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "enable_template_plugins") {
+		t.Fatalf("expected config hint in error, got %q", text)
+	}
+
+	result, err = HandleRenderTemplate(context.Background(), request, baseDir, Options{EnablePlugins: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected error for a plugin that cannot be opened")
+	}
+	if _, err := os.Stat(filepath.Join(baseDir, "report.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected no output when the plugin fails to load, got %v", err)
+	}
+}
+
+func TestHandleRenderTemplateWithBaseTemplate(t *testing.T) {
+	baseDir := t.TempDir()
+
+	layouts := filepath.Join(baseDir, "layouts")
+	if err := os.MkdirAll(layouts, 0o755); err != nil {
+		t.Fatalf("failed to create layouts directory: %v", err)
+	}
+	base := `<main>{{block "content" .}}default content{{end}}</main>`
+	if err := os.WriteFile(filepath.Join(layouts, "base.tmpl"), []byte(base), 0o644); err != nil {
+		t.Fatalf("failed to write base template: %v", err)
+	}
+	content := `{{define "content"}}Hello {{.name}}!{{end}}`
+	if err := os.WriteFile(filepath.Join(baseDir, "report.tmpl"), []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write content template: %v", err)
+	}
+
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"template_file_path": "report.tmpl",
+				"base_template_path": "layouts/base.tmpl",
+				"output_file_path":   "report.html",
+				"json_data":          `{"name":"World"}`,
+			},
+		},
+	}
+
+	result, err := HandleRenderTemplate(context.Background(), request, baseDir, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error result: %+v", result)
+	}
+
+	data, err := os.ReadFile(filepath.Join(baseDir, "report.html"))
+	if err != nil {
+		t.Fatalf("failed to read rendered output: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "<main>Hello World!</main>" {
+		t.Fatalf("unexpected rendered output: %q", got)
+	}
+}
@@ -0,0 +1,16 @@
+// Package gossismcp defines the API that gossisMCP plugins implement. It has no dependencies on the
+// server so third-party plugins can import it on its own.
+package gossismcp
+
+// MetadataSymbol is the name of the function every plugin binary must export, with the signature
+// func PluginMetadata() gossismcp.PluginInfo
+const MetadataSymbol = "PluginMetadata"
+
+// PluginInfo describes a plugin and the tools it provides
+type PluginInfo struct {
+	Name           string   `json:"name"`
+	Version        string   `json:"version"`
+	Author         string   `json:"author"`
+	Description    string   `json:"description"`
+	SupportedTools []string `json:"supported_tools"`
+}
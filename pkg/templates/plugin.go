@@ -0,0 +1,30 @@
+package templates
+
+import (
+	"fmt"
+	"html/template"
+	"plugin"
+)
+
+// FuncMapSymbol is the name of the function a template functions plugin must export, with the signature
+// func FuncMap() template.FuncMap
+const FuncMapSymbol = "FuncMap"
+
+// LoadFuncMapPlugin opens a compiled Go plugin and returns the template functions it exports
+func LoadFuncMapPlugin(path string) (template.FuncMap, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open template functions plugin: %w", err)
+	}
+
+	sym, err := p.Lookup(FuncMapSymbol)
+	if err != nil {
+		return nil, fmt.Errorf("template functions plugin does not export %s: %w", FuncMapSymbol, err)
+	}
+
+	funcMap, ok := sym.(func() template.FuncMap)
+	if !ok {
+		return nil, fmt.Errorf("invalid template functions plugin: %s must be func() template.FuncMap", FuncMapSymbol)
+	}
+	return funcMap(), nil
+}
@@ -13,6 +13,21 @@ import (
 //
 // jsonData can be a raw JSON string or []byte; typically you'll pass []byte.
 func RenderTemplateFromJSON(jsonData []byte, templatePath, outputPath string) error {
+	return RenderTemplateFromJSONWithOptions(jsonData, templatePath, outputPath, RenderOptions{})
+}
+
+// RenderOptions customises how RenderTemplateFromJSONWithOptions parses a template
+type RenderOptions struct {
+	// Funcs are added to the built-in helper functions, replacing any helper of the same name
+	Funcs template.FuncMap
+	// BaseTemplatePath is a layout parsed before the template. When set, the base is executed and the
+	// template's {{define}} blocks override the base's {{block}} defaults
+	BaseTemplatePath string
+}
+
+// RenderTemplateFromJSONWithOptions behaves like RenderTemplateFromJSON, applying options when
+// parsing the template.
+func RenderTemplateFromJSONWithOptions(jsonData []byte, templatePath, outputPath string, options RenderOptions) error {
 	// 1) Decode JSON into a flexible container (map[string]any)
 	var data map[string]any
 	if err := json.Unmarshal(jsonData, &data); err != nil {
@@ -22,6 +37,12 @@ func RenderTemplateFromJSON(jsonData []byte, templatePath, outputPath string) er
 	// 2) Parse the template
 	// Use template.New with a name derived from the file for better error messages
 	tmplName := filepath.Base(templatePath)
+	templateFiles := []string{templatePath}
+	if options.BaseTemplatePath != "" {
+		// The first file parsed names the template set, so the base layout is the one executed
+		tmplName = filepath.Base(options.BaseTemplatePath)
+		templateFiles = []string{options.BaseTemplatePath, templatePath}
+	}
 	tmpl, err := template.New(tmplName).Funcs(template.FuncMap{
 		// Add any template helper functions here
 		"upper": func(s string) string { return strings.ToUpper(s) },
@@ -41,7 +62,7 @@ func RenderTemplateFromJSON(jsonData []byte, templatePath, outputPath string) er
 		"list": func(v ...interface{}) []interface{} {
 			return v
 		},
-	}).ParseFiles(templatePath)
+	}).Funcs(options.Funcs).ParseFiles(templateFiles...)
 	if err != nil {
 		return err
 	}
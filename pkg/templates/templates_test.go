@@ -1,8 +1,10 @@
 package templates
 
 import (
+	"html/template"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -97,3 +99,67 @@ func TestRenderTemplateFromJSON_TemplateFunctions(t *testing.T) {
 		t.Fatalf("expected %q, got %q", expected, string(outputContent))
 	}
 }
+
+func TestRenderTemplateFromJSONWithOptionsFuncs(t *testing.T) {
+	tempDir := t.TempDir()
+
+	templateContent := `<p>{{titleCase .Name}}</p><p>{{upper .Name}}</p>`
+	templatePath := filepath.Join(tempDir, "test.tmpl")
+	if err := os.WriteFile(templatePath, []byte(templateContent), 0644); err != nil {
+		t.Fatalf("failed to create template file: %v", err)
+	}
+
+	funcs := template.FuncMap{
+		"titleCase": func(s string) string { return strings.ToUpper(s[:1]) + s[1:] },
+		// Custom functions replace built-in helpers of the same name
+		"upper": func(s string) string { return "custom-" + s },
+	}
+	outputPath := filepath.Join(tempDir, "output.html")
+	if err := RenderTemplateFromJSONWithOptions([]byte(`{"Name": "report"}`), templatePath, outputPath, RenderOptions{Funcs: funcs}); err != nil {
+		t.Fatalf("RenderTemplateFromJSONWithOptions failed: %v", err)
+	}
+
+	outputContent, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	expected := `<p>Report</p><p>custom-report</p>`
+	if string(outputContent) != expected {
+		t.Fatalf("expected %q, got %q", expected, string(outputContent))
+	}
+}
+
+func TestLoadFuncMapPluginMissingFile(t *testing.T) {
+	if _, err := LoadFuncMapPlugin(filepath.Join(t.TempDir(), "missing.so")); err == nil {
+		t.Fatal("expected error for missing plugin")
+	}
+}
+
+func TestRenderTemplateFromJSONWithOptionsBaseTemplate(t *testing.T) {
+	tempDir := t.TempDir()
+
+	basePath := filepath.Join(tempDir, "base.tmpl")
+	baseContent := `<html><h1>{{.Title}}</h1>{{block "content" .}}<p>default</p>{{end}}<footer>{{block "footer" .}}base footer{{end}}</footer></html>`
+	if err := os.WriteFile(basePath, []byte(baseContent), 0644); err != nil {
+		t.Fatalf("failed to create base template: %v", err)
+	}
+	templatePath := filepath.Join(tempDir, "report.tmpl")
+	if err := os.WriteFile(templatePath, []byte(`{{define "content"}}<p>{{.Body}}</p>{{end}}`), 0644); err != nil {
+		t.Fatalf("failed to create content template: %v", err)
+	}
+
+	outputPath := filepath.Join(tempDir, "output.html")
+	jsonData := `{"Title": "Report", "Body": "content body"}`
+	if err := RenderTemplateFromJSONWithOptions([]byte(jsonData), templatePath, outputPath, RenderOptions{BaseTemplatePath: basePath}); err != nil {
+		t.Fatalf("RenderTemplateFromJSONWithOptions failed: %v", err)
+	}
+
+	outputContent, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	expected := `<html><h1>Report</h1><p>content body</p><footer>base footer</footer></html>`
+	if string(outputContent) != expected {
+		t.Fatalf("expected %q, got %q", expected, string(outputContent))
+	}
+}
@@ -2,53 +2,118 @@ package types
 
 import (
 	"encoding/xml"
+	"strings"
 )
 
 // SSISPackage represents the root of a DTSX file
 type SSISPackage struct {
-	XMLName               xml.Name              `xml:"Executable"`
-	RefID                 string                `xml:"refId,attr"`
-	ObjectName            string                `xml:"ObjectName,attr"`
-	CreationName          string                `xml:"CreationName,attr"`
-	Properties            []Property            `xml:"Property"`
-	ConnectionMgr         ConnectionMgr         `xml:"ConnectionManagers"`
-	Variables             Variables             `xml:"Variables"`
-	Executables           Executables           `xml:"Executables"`
-	PrecedenceConstraints PrecedenceConstraints `xml:"PrecedenceConstraints"`
-	EventHandlers         EventHandlers         `xml:"EventHandlers"`
-	Parameters            Parameters            `xml:"Parameters"`
-	Configurations        Configurations        `xml:"Configurations"`
+	XMLName               xml.Name              `xml:"Executable" json:"-"`
+	RefID                 string                `xml:"refId,attr" json:"ref_id"`
+	ObjectName            string                `xml:"ObjectName,attr" json:"object_name"`
+	CreationName          string                `xml:"CreationName,attr" json:"creation_name"`
+	Description           string                `xml:"Description,attr" json:"description"`
+	ProtectionLevel       string                `xml:"ProtectionLevel,attr" json:"protection_level"`
+	Properties            []Property            `xml:"Property" json:"properties"`
+	ConnectionMgr         ConnectionMgr         `xml:"ConnectionManagers" json:"connection_mgr"`
+	Variables             Variables             `xml:"Variables" json:"variables"`
+	Executables           Executables           `xml:"Executables" json:"executables"`
+	PrecedenceConstraints PrecedenceConstraints `xml:"PrecedenceConstraints" json:"precedence_constraints"`
+	EventHandlers         EventHandlers         `xml:"EventHandlers" json:"event_handlers"`
+	Parameters            Parameters            `xml:"Parameters" json:"parameters"`
+	Configurations        Configurations        `xml:"Configurations" json:"configurations"`
+	LoggingOptions        *LoggingOptions       `xml:"LoggingOptions" json:"logging_options,omitempty"`
+	LogProviders          []LogProvider         `xml:"LogProviders>LogProvider" json:"log_providers"`
+	PackageVersion        SSISPackageVersion    `xml:"-" json:"package_version"`
+	Checkpoint            Checkpoint            `xml:"-" json:"checkpoint"`
+}
+
+// SSISPackageVersion holds the version metadata stored as attributes on the package root element
+type SSISPackageVersion struct {
+	VersionMajor    string `xml:"VersionMajor,attr" json:"version_major"`
+	VersionMinor    string `xml:"VersionMinor,attr" json:"version_minor"`
+	VersionBuild    string `xml:"VersionBuild,attr" json:"version_build"`
+	VersionComments string `xml:"VersionComments,attr" json:"version_comments"`
+	CreationDate    string `xml:"CreationDate,attr" json:"creation_date"`
+}
+
+// Checkpoint holds the package checkpoint settings used to restart a failed execution
+type Checkpoint struct {
+	SaveCheckpoints    string `xml:"SaveCheckpoints,attr" json:"save_checkpoints"`
+	CheckpointFileName string `xml:"CheckpointFileName,attr" json:"checkpoint_file_name"`
+	CheckpointUsage    string `xml:"CheckpointUsage,attr" json:"checkpoint_usage"`
+}
+
+// UnmarshalXML decodes the package and collects the version and checkpoint attributes of its root element
+func (p *SSISPackage) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plain SSISPackage
+	if err := d.DecodeElement((*plain)(p), &start); err != nil {
+		return err
+	}
+	p.PackageVersion = SSISPackageVersion{}
+	p.Checkpoint = Checkpoint{}
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "VersionMajor":
+			p.PackageVersion.VersionMajor = attr.Value
+		case "VersionMinor":
+			p.PackageVersion.VersionMinor = attr.Value
+		case "VersionBuild":
+			p.PackageVersion.VersionBuild = attr.Value
+		case "VersionComments":
+			p.PackageVersion.VersionComments = attr.Value
+		case "CreationDate":
+			p.PackageVersion.CreationDate = attr.Value
+		case "SaveCheckpoints":
+			p.Checkpoint.SaveCheckpoints = attr.Value
+		case "CheckpointFileName":
+			p.Checkpoint.CheckpointFileName = attr.Value
+		case "CheckpointUsage":
+			p.Checkpoint.CheckpointUsage = attr.Value
+		}
+	}
+	return nil
 }
 
 type Property struct {
-	Name  string `xml:"Name,attr"`
-	Value string `xml:",innerxml"`
+	Name  string `xml:"Name,attr" json:"name"`
+	Value string `xml:",innerxml" json:"value"`
 }
 
 type ConnectionMgr struct {
-	Connections []Connection `xml:"ConnectionManager"`
+	Connections []Connection `xml:"ConnectionManager" json:"connections"`
 }
 
 type Connection struct {
-	Name       string     `xml:"ObjectName,attr"`
-	ObjectData ObjectData `xml:"ObjectData"`
+	Name                string               `xml:"ObjectName,attr" json:"name"`
+	CreationName        string               `xml:"CreationName,attr" json:"creation_name"`
+	DTSID               string               `xml:"DTSID,attr" json:"dtsid"`
+	Description         string               `xml:"Description,attr" json:"description"`
+	ObjectData          ObjectData           `xml:"ObjectData" json:"object_data"`
+	PropertyExpressions []PropertyExpression `xml:"PropertyExpression" json:"property_expressions"`
+}
+
+// PropertyExpression is an SSIS expression that sets a property at run time
+type PropertyExpression struct {
+	Name  string `xml:"Name,attr" json:"name"`
+	Value string `xml:",chardata" json:"value"`
 }
 
 type ObjectData struct {
-	ConnectionMgr InnerConnection `xml:"ConnectionManager"`
-	MsmqConnMgr   MsmqConnection  `xml:"MsmqConnectionManager"`
+	ConnectionMgr InnerConnection `xml:"ConnectionManager" json:"connection_mgr"`
+	MsmqConnMgr   MsmqConnection  `xml:"MsmqConnectionManager" json:"msmq_conn_mgr"`
 }
 
 type InnerConnection struct {
-	ConnectionString string `xml:"ConnectionString,attr"`
+	ConnectionString string `xml:"ConnectionString,attr" json:"connection_string"`
 }
 
 type MsmqConnection struct {
-	ConnectionString string `xml:"ConnectionString,attr"`
+	ConnectionString         string `xml:"ConnectionString,attr" json:"connection_string"`
+	UseWindowsAuthentication string `xml:"UseWindowsAuthentication,attr" json:"use_windows_authentication"`
 }
 
 type Executables struct {
-	Tasks []Task `xml:"Executable"`
+	Tasks []Task `xml:"Executable" json:"tasks"`
 }
 
 // GetAllExecutables returns all tasks and containers as a unified slice
@@ -57,26 +122,26 @@ func (e *Executables) GetAllExecutables() []Task {
 }
 
 type Executable struct {
-	Name         string               `xml:"ObjectName,attr"`
-	CreationName string               `xml:"CreationName,attr"`
-	Description  string               `xml:"Description,attr"`
-	RefId        string               `xml:"refId,attr"`
-	Properties   []Property           `xml:"Property"`
-	ObjectData   ExecutableObjectData `xml:"ObjectData"`
-	Executables  *Executables         `xml:"Executables"` // For containers
-	Variables    Variables            `xml:"Variables"`   // For containers
+	Name         string               `xml:"ObjectName,attr" json:"name"`
+	CreationName string               `xml:"CreationName,attr" json:"creation_name"`
+	Description  string               `xml:"Description,attr" json:"description"`
+	RefId        string               `xml:"refId,attr" json:"ref_id"`
+	Properties   []Property           `xml:"Property" json:"properties"`
+	ObjectData   ExecutableObjectData `xml:"ObjectData" json:"object_data"`
+	Executables  *Executables         `xml:"Executables" json:"executables"` // For containers
+	Variables    Variables            `xml:"Variables" json:"variables"`     // For containers
 }
 
 type ExecutableObjectData struct {
 	// Task-specific data
-	Task       TaskDetails       `xml:"Task"`
-	ScriptTask ScriptTaskDetails `xml:"ScriptTask"`
-	DataFlow   DataFlowDetails   `xml:"pipeline"`
+	Task       TaskDetails       `xml:"Task" json:"task"`
+	ScriptTask ScriptTaskDetails `xml:"ScriptTask" json:"script_task"`
+	DataFlow   DataFlowDetails   `xml:"pipeline" json:"data_flow"`
 
 	// Container-specific data
-	SequenceContainer    SequenceContainerDetails    `xml:"SequenceContainer"`
-	ForLoopContainer     ForLoopContainerDetails     `xml:"ForLoopContainer"`
-	ForeachLoopContainer ForeachLoopContainerDetails `xml:"ForeachLoopContainer"`
+	SequenceContainer    SequenceContainerDetails    `xml:"SequenceContainer" json:"sequence_container"`
+	ForLoopContainer     ForLoopContainerDetails     `xml:"ForLoopContainer" json:"for_loop_container"`
+	ForeachLoopContainer ForeachLoopContainerDetails `xml:"ForeachLoopContainer" json:"foreach_loop_container"`
 }
 
 type SequenceContainerDetails struct {
@@ -84,274 +149,514 @@ type SequenceContainerDetails struct {
 }
 
 type ForLoopContainerDetails struct {
-	ForLoop ForLoopDetails `xml:"ForLoop"`
+	ForLoop ForLoopDetails `xml:"ForLoop" json:"for_loop"`
 }
 
 type ForLoopDetails struct {
-	InitExpression   string `xml:"InitExpression"`
-	EvalExpression   string `xml:"EvalExpression"`
-	AssignExpression string `xml:"AssignExpression"`
+	InitExpression   string `xml:"InitExpression" json:"init_expression"`
+	EvalExpression   string `xml:"EvalExpression" json:"eval_expression"`
+	AssignExpression string `xml:"AssignExpression" json:"assign_expression"`
 }
 
 type ForeachLoopContainerDetails struct {
-	ForeachLoop ForeachLoopDetails `xml:"ForeachLoop"`
+	ForeachLoop ForeachLoopDetails `xml:"ForeachLoop" json:"foreach_loop"`
 }
 
 type ForeachLoopDetails struct {
-	Enumerator           string                      `xml:"Enumerator,attr"`
-	CollectionEnumerator CollectionEnumeratorDetails `xml:"CollectionEnumerator"`
-	ItemEnumerator       ItemEnumeratorDetails       `xml:"ItemEnumerator"`
-	FileEnumerator       FileEnumeratorDetails       `xml:"FileEnumerator"`
-	VariableMappings     VariableMappings            `xml:"VariableMappings"`
+	Enumerator           string                      `xml:"Enumerator,attr" json:"enumerator"`
+	CollectionEnumerator CollectionEnumeratorDetails `xml:"CollectionEnumerator" json:"collection_enumerator"`
+	ItemEnumerator       ItemEnumeratorDetails       `xml:"ItemEnumerator" json:"item_enumerator"`
+	FileEnumerator       FileEnumeratorDetails       `xml:"FileEnumerator" json:"file_enumerator"`
+	VariableMappings     VariableMappings            `xml:"VariableMappings" json:"variable_mappings"`
 }
 
 type CollectionEnumeratorDetails struct {
-	Items []CollectionItem `xml:"Items>Item"`
+	Items []CollectionItem `xml:"Items>Item" json:"items"`
 }
 
 type CollectionItem struct {
-	Value string `xml:",innerxml"`
+	Value string `xml:",innerxml" json:"value"`
 }
 
 type ItemEnumeratorDetails struct {
-	Items []CollectionItem `xml:"Items>Item"`
+	Items []CollectionItem `xml:"Items>Item" json:"items"`
 }
 
 type FileEnumeratorDetails struct {
-	Folder   string `xml:"Folder"`
-	FileSpec string `xml:"FileSpec"`
-	Recurse  bool   `xml:"Recurse"`
+	Folder   string `xml:"Folder" json:"folder"`
+	FileSpec string `xml:"FileSpec" json:"file_spec"`
+	Recurse  bool   `xml:"Recurse" json:"recurse"`
 }
 
 type VariableMappings struct {
-	Mappings []VariableMapping `xml:"VariableMapping"`
+	Mappings []VariableMapping `xml:"VariableMapping" json:"mappings"`
 }
 
 type VariableMapping struct {
-	VariableName string `xml:"VariableName,attr"`
-	Index        int    `xml:"Index,attr"`
+	VariableName string `xml:"VariableName,attr" json:"variable_name"`
+	Index        int    `xml:"Index,attr" json:"index"`
+}
+
+// ForEachEnumerator is the enumerator of a Foreach Loop container, e.g. Microsoft.ForEachFileEnumerator
+type ForEachEnumerator struct {
+	CreationName string                      `xml:"CreationName,attr" json:"creation_name"`
+	DTSID        string                      `xml:"DTSID,attr" json:"dtsid"`
+	ObjectName   string                      `xml:"ObjectName,attr" json:"object_name"`
+	Description  string                      `xml:"Description,attr" json:"description"`
+	ObjectData   ForEachEnumeratorObjectData `xml:"ObjectData" json:"object_data"`
+}
+
+// ForEachEnumeratorObjectData holds the enumerator-specific configuration
+type ForEachEnumeratorObjectData struct {
+	FileEnumerator ForEachFileEnumeratorProperties `xml:"ForEachFileEnumeratorProperties" json:"file_enumerator"`
+	ADOEnumerator  ForEachADOEnumeratorProperties  `xml:"FEEADO" json:"ado_enumerator"`
+}
+
+// ForEachFileEnumeratorProperties lists the FEFEProperty elements of a Foreach File Enumerator, each carrying one setting as an attribute
+type ForEachFileEnumeratorProperties struct {
+	Properties []ForEachEnumeratorProperty `xml:"FEFEProperty" json:"properties"`
+}
+
+type ForEachEnumeratorProperty struct {
+	Attributes []xml.Attr `xml:",any,attr" json:"attributes"`
+}
+
+// ForEachADOEnumeratorProperties holds the settings of a Foreach ADO Enumerator
+type ForEachADOEnumeratorProperties struct {
+	EnumType string `xml:"EnumType,attr" json:"enum_type"`
+	VarName  string `xml:"VarName,attr" json:"var_name"`
+}
+
+// EnumeratorSetting is a named configuration value of a Foreach Loop enumerator
+type EnumeratorSetting struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Type returns the display name of the enumerator, falling back to its CreationName
+func (e ForEachEnumerator) Type() string {
+	enumeratorTypes := []struct{ marker, name string }{
+		{"ForEachFileEnumerator", "Foreach File Enumerator"},
+		{"ForEachADOEnumerator", "Foreach ADO Enumerator"},
+		{"ForEachADONETSchemaRowsetEnumerator", "Foreach ADO.NET Schema Rowset Enumerator"},
+		{"ForEachItemEnumerator", "Foreach Item Enumerator"},
+		{"ForEachFromVarEnumerator", "Foreach From Variable Enumerator"},
+		{"ForEachNodeListEnumerator", "Foreach NodeList Enumerator"},
+		{"ForEachSMOEnumerator", "Foreach SMO Enumerator"},
+		{"ForEachBlobEnumerator", "Foreach Azure Blob Enumerator"},
+	}
+	for _, t := range enumeratorTypes {
+		if strings.Contains(e.CreationName, t.marker) {
+			return t.name
+		}
+	}
+	return e.CreationName
+}
+
+// Settings returns the configuration of the enumerator; file enumerator settings use the names
+// shown by the designer (Directory, Filter, FileNameRetrievalType, Recurse) and ADO enumerator
+// settings are reported as ADOObjectName and EnumerationMode
+func (e ForEachEnumerator) Settings() []EnumeratorSetting {
+	fileSettingNames := map[string]string{"Folder": "Directory", "FileSpec": "Filter"}
+	var settings []EnumeratorSetting
+	for _, prop := range e.ObjectData.FileEnumerator.Properties {
+		for _, attr := range prop.Attributes {
+			name := attr.Name.Local
+			if mapped, ok := fileSettingNames[name]; ok {
+				name = mapped
+			}
+			settings = append(settings, EnumeratorSetting{Name: name, Value: attr.Value})
+		}
+	}
+	if ado := e.ObjectData.ADOEnumerator; ado.VarName != "" || ado.EnumType != "" {
+		settings = append(settings,
+			EnumeratorSetting{Name: "ADOObjectName", Value: ado.VarName},
+			EnumeratorSetting{Name: "EnumerationMode", Value: ado.EnumType})
+	}
+	return settings
 }
 
 type Task struct {
-	Name         string         `xml:"ObjectName,attr"`
-	CreationName string         `xml:"CreationName,attr"`
-	Description  string         `xml:"Description,attr"`
-	RefId        string         `xml:"refId,attr"`
-	Properties   []Property     `xml:"Property"`
-	ObjectData   TaskObjectData `xml:"ObjectData"`
+	Name                  string                `xml:"ObjectName,attr" json:"name"`
+	CreationName          string                `xml:"CreationName,attr" json:"creation_name"`
+	Description           string                `xml:"Description,attr" json:"description"`
+	RefId                 string                `xml:"refId,attr" json:"ref_id"`
+	TransactionOption     string                `xml:"TransactionOption,attr" json:"transaction_option"`
+	InitExpression        string                `xml:"InitExpression,attr" json:"init_expression"`     // For Loop containers
+	EvalExpression        string                `xml:"EvalExpression,attr" json:"eval_expression"`     // For Loop containers
+	AssignExpression      string                `xml:"AssignExpression,attr" json:"assign_expression"` // For Loop containers
+	Properties            []Property            `xml:"Property" json:"properties"`
+	ObjectData            TaskObjectData        `xml:"ObjectData" json:"object_data"`
+	Executables           *Executables          `xml:"Executables" json:"executables"`                      // For containers
+	PrecedenceConstraints PrecedenceConstraints `xml:"PrecedenceConstraints" json:"precedence_constraints"` // For containers
+	EventHandlers         EventHandlers         `xml:"EventHandlers" json:"event_handlers"`
+	Variables             Variables             `xml:"Variables" json:"variables"`
+	LoggingOptions        *LoggingOptions       `xml:"LoggingOptions" json:"logging_options,omitempty"`
+	ForEachEnumerator     *ForEachEnumerator    `xml:"ForEachEnumerator" json:"foreach_enumerator,omitempty"` // For Foreach Loop containers
 }
 
 type TaskObjectData struct {
-	Task       TaskDetails       `xml:"Task"`
-	ScriptTask ScriptTaskDetails `xml:"ScriptTask"`
-	DataFlow   DataFlowDetails   `xml:"pipeline"`
+	Task               TaskDetails                `xml:"Task" json:"task"`
+	ScriptTask         ScriptTaskDetails          `xml:"ScriptTask" json:"script_task"`
+	ScriptProject      ScriptProject              `xml:"ScriptProject" json:"script_project"` // SSIS 2012 and later
+	DataFlow           DataFlowDetails            `xml:"pipeline" json:"data_flow"`
+	ExecutePackageTask ExecutePackageTaskDetails  `xml:"ExecutePackageTask" json:"execute_package_task"`
+	CdcControlTask     CdcControlTaskDetails      `xml:"CDCControlTask" json:"cdc_control_task"`
+	MessageQueueTask   MessageQueueTaskProperties `xml:"MessageQueueTaskData" json:"message_queue_task_data"`
+	WmiDataReaderTask  WmiTaskProperties          `xml:"WMIDRTaskData" json:"wmi_data_reader_task"`
+	WmiEventWatcher    WmiTaskProperties          `xml:"WMIEWTaskData" json:"wmi_event_watcher_task"`
+}
+
+// CdcControlTaskDetails holds the attributes of a CDC Control Task, whose names vary between SSIS versions
+type CdcControlTaskDetails struct {
+	Attributes []xml.Attr `xml:",any,attr" json:"attributes"`
+}
+
+// Attribute returns the first non-empty value among the named attributes
+func (d CdcControlTaskDetails) Attribute(names ...string) string {
+	return attributeValue(d.Attributes, names...)
+}
+
+// MessageQueueTaskProperties holds the attributes of a Message Queue Task, which differ between send and receive tasks
+type MessageQueueTaskProperties struct {
+	Attributes []xml.Attr `xml:",any,attr" json:"attributes"`
+}
+
+// Attribute returns the first non-empty value among the named attributes
+func (d MessageQueueTaskProperties) Attribute(names ...string) string {
+	return attributeValue(d.Attributes, names...)
+}
+
+// WmiTaskProperties holds the attributes of a WMI Data Reader or WMI Event Watcher task
+type WmiTaskProperties struct {
+	Attributes []xml.Attr `xml:",any,attr" json:"attributes"`
+}
+
+// Attribute returns the first non-empty value among the named attributes
+func (d WmiTaskProperties) Attribute(names ...string) string {
+	return attributeValue(d.Attributes, names...)
+}
+
+func attributeValue(attrs []xml.Attr, names ...string) string {
+	for _, name := range names {
+		for _, attr := range attrs {
+			if strings.EqualFold(attr.Name.Local, name) && strings.TrimSpace(attr.Value) != "" {
+				return strings.TrimSpace(attr.Value)
+			}
+		}
+	}
+	return ""
+}
+
+// ExecutePackageTaskDetails identifies the child package run by an Execute Package Task
+type ExecutePackageTaskDetails struct {
+	UseProjectReference bool   `xml:"UseProjectReference" json:"use_project_reference"`
+	PackageName         string `xml:"PackageName" json:"package_name"`
+	Connection          string `xml:"Connection" json:"connection"`
 }
 
 type TaskDetails struct {
-	MessageQueueTask MessageQueueTaskDetails `xml:"MessageQueueTask"`
+	MessageQueueTask MessageQueueTaskDetails `xml:"MessageQueueTask" json:"message_queue_task"`
 }
 
 type MessageQueueTaskDetails struct {
-	MessageQueueTaskData MessageQueueTaskData `xml:"MessageQueueTaskData"`
+	MessageQueueTaskData MessageQueueTaskData `xml:"MessageQueueTaskData" json:"message_queue_task_data"`
 }
 
 type MessageQueueTaskData struct {
-	MessageType string `xml:"MessageType,attr"`
-	Message     string `xml:"Message"`
+	MessageType string `xml:"MessageType,attr" json:"message_type"`
+	Message     string `xml:"Message" json:"message"`
 }
 
 type ScriptTaskDetails struct {
-	ScriptTaskData ScriptTaskData `xml:"ScriptTaskData"`
+	ScriptTaskData ScriptTaskData `xml:"ScriptTaskData" json:"script_task_data"`
 }
 
 type ScriptTaskData struct {
-	ScriptProject ScriptProject `xml:"ScriptProject"`
+	ScriptProject ScriptProject `xml:"ScriptProject" json:"script_project"`
 }
 
 type ScriptProject struct {
-	ScriptCode string `xml:",innerxml"`
+	ScriptCode   string              `xml:",innerxml" json:"script_code"`
+	ProjectItems []ScriptProjectItem `xml:"ProjectItem" json:"project_items"`
+	BinaryItems  []ScriptProjectItem `xml:"BinaryItem" json:"binary_items"`
+}
+
+// ScriptProjectItem is a file stored in a script project; binary item content is base64 encoded
+type ScriptProjectItem struct {
+	Name  string `xml:"Name,attr" json:"name"`
+	Value string `xml:",chardata" json:"value"`
 }
 
 type DataFlowDetails struct {
-	Components DataFlowComponents `xml:"components"`
-	Paths      DataFlowPaths      `xml:"paths"`
+	Components DataFlowComponents `xml:"components" json:"components"`
+	Paths      DataFlowPaths      `xml:"paths" json:"paths"`
 }
 
 type DataFlowComponents struct {
-	Components []DataFlowComponent `xml:"component"`
+	Components []DataFlowComponent `xml:"component" json:"components"`
 }
 
 type DataFlowComponent struct {
-	Name                     string              `xml:"name,attr"`
-	ComponentClassID         string              `xml:"componentClassID,attr"`
-	Description              string              `xml:"description,attr"`
-	LocaleID                 string              `xml:"localeId,attr"`
-	UsesDispositions         bool                `xml:"usesDispositions,attr"`
-	ValidateExternalMetadata bool                `xml:"validateExternalMetadata,attr"`
-	Version                  int                 `xml:"version,attr"`
-	ObjectData               ComponentObjectData `xml:"objectData"`
-	Inputs                   ComponentInputs     `xml:"inputs"`
-	Outputs                  ComponentOutputs    `xml:"outputs"`
+	Name                     string               `xml:"name,attr" json:"name"`
+	ComponentClassID         string               `xml:"componentClassID,attr" json:"component_class_id"`
+	Description              string               `xml:"description,attr" json:"description"`
+	LocaleID                 string               `xml:"localeId,attr" json:"locale_id"`
+	UsesDispositions         bool                 `xml:"usesDispositions,attr" json:"uses_dispositions"`
+	ValidateExternalMetadata bool                 `xml:"validateExternalMetadata,attr" json:"validate_external_metadata"`
+	Version                  int                  `xml:"version,attr" json:"version"`
+	ObjectData               ComponentObjectData  `xml:"objectData" json:"object_data"`
+	Properties               ComponentProperties  `xml:"properties" json:"properties"`
+	Inputs                   ComponentInputs      `xml:"inputs" json:"inputs"`
+	Outputs                  ComponentOutputs     `xml:"outputs" json:"outputs"`
+	Connections              ComponentConnections `xml:"connections" json:"connections"`
+}
+
+type ComponentConnections struct {
+	Connections []ComponentConnection `xml:"connection" json:"connections"`
+}
+
+// ComponentConnection links a data flow component to a package connection manager
+type ComponentConnection struct {
+	Name                   string `xml:"name,attr" json:"name"`
+	ConnectionManagerRefID string `xml:"connectionManagerRefId,attr" json:"connection_manager_ref_id"`
 }
 
 type ComponentObjectData struct {
-	PipelineComponent PipelineComponent `xml:"pipelineComponent"`
+	PipelineComponent PipelineComponent `xml:"pipelineComponent" json:"pipeline_component"`
 }
 
 type PipelineComponent struct {
-	Properties ComponentProperties `xml:"properties"`
+	Properties ComponentProperties `xml:"properties" json:"properties"`
 }
 
 type ComponentProperties struct {
-	Properties []ComponentProperty `xml:"property"`
+	Properties []ComponentProperty `xml:"property" json:"properties"`
 }
 
 type ComponentProperty struct {
-	Name  string `xml:"Name,attr"`
-	Value string `xml:",innerxml"`
+	Name  string `xml:"name,attr" json:"name"`
+	Value string `xml:",innerxml" json:"value"`
 }
 
 type ComponentInputs struct {
-	Inputs []ComponentInput `xml:"input"`
+	Inputs []ComponentInput `xml:"input" json:"inputs"`
 }
 
 type ComponentInput struct {
-	Name           string       `xml:"name,attr"`
-	HasSideEffects bool         `xml:"hasSideEffects,attr"`
-	IsSorted       bool         `xml:"isSorted,attr"`
-	InputColumns   InputColumns `xml:"inputColumns"`
+	Name             string           `xml:"name,attr" json:"name"`
+	HasSideEffects   bool             `xml:"hasSideEffects,attr" json:"has_side_effects"`
+	IsSorted         bool             `xml:"isSorted,attr" json:"is_sorted"`
+	InputColumns     InputColumns     `xml:"inputColumns" json:"input_columns"`
+	ExternalMetadata ExternalMetadata `xml:"externalMetadata" json:"external_metadata"`
+}
+
+type ExternalMetadata struct {
+	Columns []ExternalMetadataColumn `xml:"externalMetadataColumn" json:"columns"`
+}
+
+// ExternalMetadataColumn is a column of the external table or file a source or destination reads or writes
+type ExternalMetadataColumn struct {
+	RefID    string `xml:"refId,attr" json:"ref_id"`
+	Name     string `xml:"name,attr" json:"name"`
+	DataType string `xml:"dataType,attr" json:"data_type"`
+	Length   int    `xml:"length,attr" json:"length"`
 }
 
 type InputColumns struct {
-	Columns []InputColumn `xml:"inputColumn"`
+	Columns []InputColumn `xml:"inputColumn" json:"columns"`
 }
 
 type InputColumn struct {
-	Name      string `xml:"name,attr"`
-	DataType  string `xml:"dataType,attr"`
-	Length    int    `xml:"length,attr"`
-	Precision int    `xml:"precision,attr"`
-	Scale     int    `xml:"scale,attr"`
-	CodePage  int    `xml:"codePage,attr"`
+	Name                     string `xml:"name,attr" json:"name"`
+	CachedName               string `xml:"cachedName,attr" json:"cached_name"`
+	ExternalMetadataColumnID string `xml:"externalMetadataColumnId,attr" json:"external_metadata_column_id"`
+	DataType                 string `xml:"dataType,attr" json:"data_type"`
+	Length                   int    `xml:"length,attr" json:"length"`
+	Precision                int    `xml:"precision,attr" json:"precision"`
+	Scale                    int    `xml:"scale,attr" json:"scale"`
+	CodePage                 int    `xml:"codePage,attr" json:"code_page"`
 }
 
 type ComponentOutputs struct {
-	Outputs []ComponentOutput `xml:"output"`
+	Outputs []ComponentOutput `xml:"output" json:"outputs"`
 }
 
 type ComponentOutput struct {
-	Name           string        `xml:"name,attr"`
-	HasSideEffects bool          `xml:"hasSideEffects,attr"`
-	IsErrorOut     bool          `xml:"isErrorOut,attr"`
-	Synchronous    bool          `xml:"synchronous,attr"`
-	OutputColumns  OutputColumns `xml:"outputColumns"`
+	RefID          string              `xml:"refId,attr" json:"ref_id"`
+	Name           string              `xml:"name,attr" json:"name"`
+	HasSideEffects bool                `xml:"hasSideEffects,attr" json:"has_side_effects"`
+	IsErrorOut     bool                `xml:"isErrorOut,attr" json:"is_error_out"`
+	IsDefaultOut   bool                `xml:"isDefaultOut,attr" json:"is_default_out"`
+	Synchronous    bool                `xml:"synchronous,attr" json:"synchronous"`
+	OutputColumns  OutputColumns       `xml:"outputColumns" json:"output_columns"`
+	Properties     ComponentProperties `xml:"properties" json:"properties"`
 }
 
 type OutputColumns struct {
-	Columns []OutputColumn `xml:"outputColumn"`
+	Columns []OutputColumn `xml:"outputColumn" json:"columns"`
 }
 
 type OutputColumn struct {
-	Name      string `xml:"name,attr"`
-	DataType  string `xml:"dataType,attr"`
-	Length    int    `xml:"length,attr"`
-	Precision int    `xml:"precision,attr"`
-	Scale     int    `xml:"scale,attr"`
-	CodePage  int    `xml:"codePage,attr"`
+	Name       string              `xml:"name,attr" json:"name"`
+	DataType   string              `xml:"dataType,attr" json:"data_type"`
+	Length     int                 `xml:"length,attr" json:"length"`
+	Precision  int                 `xml:"precision,attr" json:"precision"`
+	Scale      int                 `xml:"scale,attr" json:"scale"`
+	CodePage   int                 `xml:"codePage,attr" json:"code_page"`
+	Properties ComponentProperties `xml:"properties" json:"properties"` // Expression and FriendlyExpression of derived columns
 }
 
 type DataFlowPaths struct {
-	Paths []DataFlowPath `xml:"Path"`
+	Paths []DataFlowPath `xml:"path" json:"paths"`
 }
 
 type DataFlowPath struct {
-	Name    string `xml:"name,attr"`
-	StartID string `xml:"startId,attr"`
-	EndID   string `xml:"endId,attr"`
+	Name    string `xml:"name,attr" json:"name"`
+	StartID string `xml:"startId,attr" json:"start_id"`
+	EndID   string `xml:"endId,attr" json:"end_id"`
+}
+
+// LoggingOptions holds the logging settings of a package or container; LoggingMode is 0 (use parent setting), 1 (enabled) or 2 (disabled)
+type LoggingOptions struct {
+	LoggingMode       string                `xml:"LoggingMode,attr" json:"logging_mode"`
+	FilterKind        string                `xml:"FilterKind,attr" json:"filter_kind"`
+	Properties        []Property            `xml:"Property" json:"properties"`
+	SelectedProviders []SelectedLogProvider `xml:"SelectedLogProviders>SelectedLogProvider" json:"selected_providers"`
+}
+
+type SelectedLogProvider struct {
+	InstanceID string `xml:"InstanceID,attr" json:"instance_id"`
+}
+
+// LogProvider is a log provider defined by a package; LoggingOptions select it by DTSID
+type LogProvider struct {
+	ObjectName   string `xml:"ObjectName,attr" json:"object_name"`
+	CreationName string `xml:"CreationName,attr" json:"creation_name"`
+	DTSID        string `xml:"DTSID,attr" json:"dtsid"`
+	Description  string `xml:"Description,attr" json:"description"`
+	ConfigString string `xml:"ConfigString,attr" json:"config_string"`
 }
 
 type Variables struct {
-	Vars []Variable `xml:"Variable"`
+	Vars []Variable `xml:"Variable" json:"vars"`
 }
 
 type Variable struct {
-	Name       string `xml:"ObjectName,attr"`
-	Value      string `xml:"VariableValue"`
-	Expression string `xml:"Expression,attr"`
+	Name       string `xml:"ObjectName,attr" json:"name"`
+	Value      string `xml:"VariableValue" json:"value"`
+	Expression string `xml:"Expression,attr" json:"expression"`
 }
 
 type PrecedenceConstraints struct {
-	Constraints []PrecedenceConstraint `xml:"PrecedenceConstraint"`
+	Constraints []PrecedenceConstraint `xml:"PrecedenceConstraint" json:"constraints"`
 }
 
 type PrecedenceConstraint struct {
-	Name       string `xml:"ObjectName,attr"`
-	From       string `xml:"From,attr"`
-	To         string `xml:"To,attr"`
-	Expression string `xml:"Expression,attr"`
-	EvalOp     string `xml:"EvalOp,attr"`
+	Name       string `xml:"ObjectName,attr" json:"name"`
+	From       string `xml:"From,attr" json:"from"`
+	To         string `xml:"To,attr" json:"to"`
+	Expression string `xml:"Expression,attr" json:"expression"`
+	EvalOp     string `xml:"EvalOp,attr" json:"eval_op"`
+	Value      string `xml:"Value,attr" json:"value"`
 }
 
 type EventHandlers struct {
-	EventHandlers []EventHandler `xml:"EventHandler"`
+	EventHandlers []EventHandler `xml:"EventHandler" json:"event_handlers"`
 }
 
 type EventHandler struct {
-	EventHandlerType      string                `xml:"EventHandlerType,attr"`
-	ContainerID           string                `xml:"ContainerID,attr"`
-	ObjectName            string                `xml:"ObjectName,attr"`
-	Executables           Executables           `xml:"Executables"`
-	Variables             Variables             `xml:"Variables"`
-	PrecedenceConstraints PrecedenceConstraints `xml:"PrecedenceConstraints"`
+	EventHandlerType      string                `xml:"EventHandlerType,attr" json:"event_handler_type"`
+	EventName             string                `xml:"EventName,attr" json:"event_name"`
+	ContainerID           string                `xml:"ContainerID,attr" json:"container_id"`
+	ObjectName            string                `xml:"ObjectName,attr" json:"object_name"`
+	Executables           Executables           `xml:"Executables" json:"executables"`
+	Variables             Variables             `xml:"Variables" json:"variables"`
+	PrecedenceConstraints PrecedenceConstraints `xml:"PrecedenceConstraints" json:"precedence_constraints"`
 }
 
 type Parameters struct {
-	Params []Parameter `xml:"Parameter"`
+	Params []Parameter `xml:"Parameter" json:"params"`
 }
 
 type Parameter struct {
-	Name        string `xml:"ObjectName,attr"`
-	DataType    string `xml:"DataType,attr"`
-	Value       string `xml:"ParameterValue"`
-	Description string `xml:"Description,attr"`
-	Required    bool   `xml:"Required,attr"`
-	Sensitive   bool   `xml:"Sensitive,attr"`
+	Name        string `xml:"ObjectName,attr" json:"name"`
+	DataType    string `xml:"DataType,attr" json:"data_type"`
+	Value       string `xml:"ParameterValue" json:"value"`
+	Description string `xml:"Description,attr" json:"description"`
+	Required    bool   `xml:"Required,attr" json:"required"`
+	Sensitive   bool   `xml:"Sensitive,attr" json:"sensitive"`
 }
 
 type Configurations struct {
-	Configs []Configuration `xml:"Configuration"`
+	Configs []Configuration `xml:"Configuration" json:"configs"`
 }
 
 type Configuration struct {
-	Name                string `xml:"ObjectName,attr"`
-	Type                int    `xml:"ConfigurationType,attr"`
-	Description         string `xml:"Description,attr"`
-	ConfigurationString string `xml:"ConfigurationString"`
-	ConfiguredType      string `xml:"ConfiguredType,attr"`
-	ConfiguredValue     string `xml:"ConfiguredValue"`
+	Name                   string `xml:"ObjectName,attr" json:"name"`
+	DTSID                  string `xml:"DTSID,attr" json:"dtsid"`
+	Type                   int    `xml:"ConfigurationType,attr" json:"type"`
+	Description            string `xml:"Description,attr" json:"description"`
+	ConfigurationString    string `xml:"ConfigurationString,attr" json:"configuration_string"`
+	ConfigurationVariable  string `xml:"ConfigurationVariable,attr" json:"configuration_variable"`
+	ConfigurationFilter    string `xml:"ConfigurationFilter,attr" json:"configuration_filter"`
+	ConfiguredObjectPath   string `xml:"ConfiguredObjectPath,attr" json:"configured_object_path"`
+	ConfiguredPropertyPath string `xml:"ConfiguredPropertyPath,attr" json:"configured_property_path"`
+	ConfiguredType         string `xml:"ConfiguredType,attr" json:"configured_type"`
+	ConfiguredValue        string `xml:"ConfiguredValue" json:"configured_value"`
+}
+
+// Filter returns the configuration filter, falling back to the third part of a SQL Server
+// configuration string such as "Conn";"[dbo].[SSIS Configurations]";"Filter";
+func (c Configuration) Filter() string {
+	if c.ConfigurationFilter != "" {
+		return c.ConfigurationFilter
+	}
+	if !strings.HasPrefix(strings.TrimSpace(c.ConfigurationString), `"`) {
+		return ""
+	}
+	parts := strings.Split(c.ConfigurationString, ";")
+	if len(parts) < 3 {
+		return ""
+	}
+	return strings.Trim(strings.TrimSpace(parts[2]), `"`)
+}
+
+// DTSConfigurationFile is an XML configuration file (.dtsConfig) holding package property overrides
+type DTSConfigurationFile struct {
+	XMLName        xml.Name                `xml:"DTSConfiguration" json:"-"`
+	Configurations []DTSConfigurationEntry `xml:"Configuration" json:"configurations"`
+}
+
+// DTSConfigurationEntry is a single property override in a .dtsConfig file
+type DTSConfigurationEntry struct {
+	ConfiguredType  string `xml:"ConfiguredType,attr" json:"configured_type"`
+	Path            string `xml:"Path,attr" json:"path"`
+	ValueType       string `xml:"ValueType,attr" json:"value_type"`
+	ConfiguredValue string `xml:"ConfiguredValue" json:"configured_value"`
 }
 
 type PerformanceMetrics struct {
-	PackageLevel   []PerformanceProperty
-	DataFlowLevel  []DataFlowPerformance
-	ComponentLevel []ComponentPerformance
+	PackageLevel   []PerformanceProperty  `json:"package_level"`
+	DataFlowLevel  []DataFlowPerformance  `json:"data_flow_level"`
+	ComponentLevel []ComponentPerformance `json:"component_level"`
 }
 
 type PerformanceProperty struct {
-	Name           string
-	Value          string
-	Category       string
-	Recommendation string
+	Name           string `json:"name"`
+	Value          string `json:"value"`
+	Category       string `json:"category"`
+	Recommendation string `json:"recommendation"`
 }
 
 type DataFlowPerformance struct {
-	TaskName   string
-	Properties []PerformanceProperty
-	Components []ComponentPerformance
+	TaskName   string                 `json:"task_name"`
+	Properties []PerformanceProperty  `json:"properties"`
+	Components []ComponentPerformance `json:"components"`
 }
 
 type ComponentPerformance struct {
-	ComponentName string
-	ComponentType string
-	Properties    []PerformanceProperty
+	ComponentName string                `json:"component_name"`
+	ComponentType string                `json:"component_type"`
+	Properties    []PerformanceProperty `json:"properties"`
 }
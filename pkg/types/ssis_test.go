@@ -1,6 +1,16 @@
 package types
 
-import "testing"
+import (
+	"encoding/xml"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
 
 func TestGetAllExecutables(t *testing.T) {
 	execs := Executables{Tasks: []Task{{Name: "TaskA"}, {Name: "TaskB"}}}
@@ -12,3 +22,236 @@ func TestGetAllExecutables(t *testing.T) {
 		t.Fatalf("unexpected task order: %+v", all)
 	}
 }
+
+// checkXMLTags walks a struct type and the package types it references, reporting exported fields
+// that lack an xml tag
+func checkXMLTags(t *testing.T, typ reflect.Type, seen map[reflect.Type]bool) {
+	t.Helper()
+	for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array || typ.Kind() == reflect.Map {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || typ.PkgPath() != reflect.TypeOf(SSISPackage{}).PkgPath() || seen[typ] {
+		return
+	}
+	seen[typ] = true
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if _, ok := field.Tag.Lookup("xml"); !ok {
+			t.Errorf("%s.%s is missing an xml tag", typ.Name(), field.Name)
+		}
+		checkXMLTags(t, field.Type, seen)
+	}
+}
+
+// checkJSONTags reports the exported fields of a struct declaration, and of any anonymous structs inside it,
+// that lack a json tag
+func checkJSONTags(t *testing.T, fset *token.FileSet, typeSpec *ast.TypeSpec) {
+	t.Helper()
+	ast.Inspect(typeSpec.Type, func(node ast.Node) bool {
+		structType, ok := node.(*ast.StructType)
+		if !ok {
+			return true
+		}
+		for _, field := range structType.Fields.List {
+			tag := ""
+			if field.Tag != nil {
+				tag, _ = strconv.Unquote(field.Tag.Value)
+			}
+			for _, name := range field.Names {
+				if _, ok := reflect.StructTag(tag).Lookup("json"); name.IsExported() && !ok {
+					t.Errorf("%s: %s.%s is missing a json tag", fset.Position(name.Pos()), typeSpec.Name.Name, name.Name)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// TestAllTypeFieldsHaveJSONTags parses the package source so that every exported struct is checked,
+// including those no DTSX type references
+func TestAllTypeFieldsHaveJSONTags(t *testing.T) {
+	sources, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("failed to list package sources: %v", err)
+	}
+
+	fset := token.NewFileSet()
+	structs := 0
+	for _, source := range sources {
+		if strings.HasSuffix(source, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, source, nil, 0)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", source, err)
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if _, ok := typeSpec.Type.(*ast.StructType); !ok || !typeSpec.Name.IsExported() {
+					continue
+				}
+				structs++
+				checkJSONTags(t, fset, typeSpec)
+			}
+		}
+	}
+	if structs == 0 {
+		t.Fatal("expected to find exported structs in the package")
+	}
+}
+
+func TestPackageTypeFieldsHaveXMLTags(t *testing.T) {
+	seen := make(map[reflect.Type]bool)
+	for _, typ := range []reflect.Type{reflect.TypeOf(SSISPackage{}), reflect.TypeOf(Executable{})} {
+		checkXMLTags(t, typ, seen)
+	}
+}
+
+func TestUnmarshalLogProviders(t *testing.T) {
+	const dtsx = `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:ObjectName="Logging">
+  <DTS:LogProviders>
+    <DTS:LogProvider DTS:ConfigString="LogDB" DTS:CreationName="Microsoft.LogProviderSQLServer" DTS:Description="Writes log entries for events to a SQL Server database" DTS:DTSID="{AAAAAAAA-0000-0000-0000-000000000001}" DTS:ObjectName="SSIS log provider for SQL Server">
+      <DTS:ObjectData><InnerObject /></DTS:ObjectData>
+    </DTS:LogProvider>
+    <DTS:LogProvider DTS:ConfigString="LogFile" DTS:CreationName="Microsoft.LogProviderTextFile" DTS:Description="Writes log entries for events to a CSV file" DTS:DTSID="{AAAAAAAA-0000-0000-0000-000000000002}" DTS:ObjectName="SSIS log provider for Text files">
+      <DTS:ObjectData><InnerObject /></DTS:ObjectData>
+    </DTS:LogProvider>
+  </DTS:LogProviders>
+</DTS:Executable>`
+
+	var pkg SSISPackage
+	if err := xml.Unmarshal([]byte(strings.ReplaceAll(dtsx, "DTS:", "")), &pkg); err != nil {
+		t.Fatalf("failed to unmarshal package: %v", err)
+	}
+	expected := []LogProvider{
+		{
+			ObjectName:   "SSIS log provider for SQL Server",
+			CreationName: "Microsoft.LogProviderSQLServer",
+			DTSID:        "{AAAAAAAA-0000-0000-0000-000000000001}",
+			Description:  "Writes log entries for events to a SQL Server database",
+			ConfigString: "LogDB",
+		},
+		{
+			ObjectName:   "SSIS log provider for Text files",
+			CreationName: "Microsoft.LogProviderTextFile",
+			DTSID:        "{AAAAAAAA-0000-0000-0000-000000000002}",
+			Description:  "Writes log entries for events to a CSV file",
+			ConfigString: "LogFile",
+		},
+	}
+	if !reflect.DeepEqual(pkg.LogProviders, expected) {
+		t.Fatalf("unexpected log providers: %+v", pkg.LogProviders)
+	}
+}
+
+func TestConfigurationFilter(t *testing.T) {
+	cases := []struct {
+		config   Configuration
+		expected string
+	}{
+		{Configuration{ConfigurationFilter: "Explicit", ConfigurationString: `"Conn";"[dbo].[SSIS Configurations]";"Other";`}, "Explicit"},
+		{Configuration{ConfigurationString: `"Conn";"[dbo].[SSIS Configurations]";"WarehouseLoad";`}, "WarehouseLoad"},
+		{Configuration{ConfigurationString: `C:\Config\Warehouse.dtsConfig`}, ""},
+	}
+	for _, c := range cases {
+		if got := c.config.Filter(); got != c.expected {
+			t.Fatalf("Filter() for %+v = %q, expected %q", c.config, got, c.expected)
+		}
+	}
+}
+
+func TestUnmarshalForEachEnumerator(t *testing.T) {
+	const dtsx = `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:ObjectName="Loops">
+  <DTS:Executables>
+    <DTS:Executable DTS:CreationName="Microsoft.ForEachLoop" DTS:ObjectName="Load Files">
+      <DTS:ForEachEnumerator DTS:CreationName="Microsoft.ForEachFileEnumerator" DTS:ObjectName="{A1}">
+        <DTS:ObjectData>
+          <ForEachFileEnumeratorProperties>
+            <FEFEProperty Folder="C:\Incoming" />
+            <FEFEProperty FileSpec="*.csv" />
+            <FEFEProperty FileNameRetrievalType="0" />
+            <FEFEProperty Recurse="-1" />
+          </ForEachFileEnumeratorProperties>
+        </DTS:ObjectData>
+      </DTS:ForEachEnumerator>
+    </DTS:Executable>
+    <DTS:Executable DTS:CreationName="Microsoft.ForEachLoop" DTS:ObjectName="Process Rows">
+      <DTS:ForEachEnumerator DTS:CreationName="Microsoft.ForEachADOEnumerator" DTS:ObjectName="{A2}">
+        <DTS:ObjectData>
+          <FEEADO EnumType="EnumerateRowsInFirstTable" VarName="User::Customers" />
+        </DTS:ObjectData>
+      </DTS:ForEachEnumerator>
+    </DTS:Executable>
+  </DTS:Executables>
+</DTS:Executable>`
+
+	var pkg SSISPackage
+	if err := xml.Unmarshal([]byte(strings.ReplaceAll(dtsx, "DTS:", "")), &pkg); err != nil {
+		t.Fatalf("failed to unmarshal package: %v", err)
+	}
+
+	files := pkg.Executables.Tasks[0].ForEachEnumerator
+	if files == nil || files.Type() != "Foreach File Enumerator" {
+		t.Fatalf("unexpected file enumerator: %+v", files)
+	}
+	expectedFiles := []EnumeratorSetting{
+		{Name: "Directory", Value: `C:\Incoming`},
+		{Name: "Filter", Value: "*.csv"},
+		{Name: "FileNameRetrievalType", Value: "0"},
+		{Name: "Recurse", Value: "-1"},
+	}
+	if settings := files.Settings(); !reflect.DeepEqual(settings, expectedFiles) {
+		t.Fatalf("unexpected file enumerator settings: %+v", settings)
+	}
+
+	rows := pkg.Executables.Tasks[1].ForEachEnumerator
+	if rows == nil || rows.Type() != "Foreach ADO Enumerator" {
+		t.Fatalf("unexpected ADO enumerator: %+v", rows)
+	}
+	expectedRows := []EnumeratorSetting{
+		{Name: "ADOObjectName", Value: "User::Customers"},
+		{Name: "EnumerationMode", Value: "EnumerateRowsInFirstTable"},
+	}
+	if settings := rows.Settings(); !reflect.DeepEqual(settings, expectedRows) {
+		t.Fatalf("unexpected ADO enumerator settings: %+v", settings)
+	}
+}
+
+func TestUnmarshalPackageVersion(t *testing.T) {
+	const dtsx = `<?xml version="1.0"?>
+<DTS:Executable xmlns:DTS="www.microsoft.com/SqlServer/Dts" DTS:ObjectName="Versioned"
+  DTS:CreationDate="10/25/2024 5:22:36 PM" DTS:VersionBuild="24" DTS:VersionMajor="2" DTS:VersionMinor="3" DTS:VersionComments="Added audit columns">
+  <DTS:Variables />
+</DTS:Executable>`
+
+	for name, content := range map[string]string{"stripped": strings.ReplaceAll(dtsx, "DTS:", ""), "prefixed": dtsx} {
+		var pkg SSISPackage
+		if err := xml.Unmarshal([]byte(content), &pkg); err != nil {
+			t.Fatalf("%s: failed to unmarshal package: %v", name, err)
+		}
+		expected := SSISPackageVersion{
+			VersionMajor:    "2",
+			VersionMinor:    "3",
+			VersionBuild:    "24",
+			VersionComments: "Added audit columns",
+			CreationDate:    "10/25/2024 5:22:36 PM",
+		}
+		if pkg.PackageVersion != expected {
+			t.Fatalf("%s: unexpected package version: %+v", name, pkg.PackageVersion)
+		}
+		if pkg.ObjectName != "Versioned" {
+			t.Fatalf("%s: expected root attributes to still be decoded, got %q", name, pkg.ObjectName)
+		}
+	}
+}
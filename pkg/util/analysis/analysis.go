@@ -191,3 +191,25 @@ func AnalyzeGenericTextFile(content string, isLineNumberNeeded bool, result *str
 		result.WriteString("- Contains IP addresses\n")
 	}
 }
+
+// MaskSensitiveValue masks sensitive values for display
+func MaskSensitiveValue(value string) string {
+	if len(value) <= 4 {
+		return strings.Repeat("*", len(value))
+	}
+	return value[:2] + strings.Repeat("*", len(value)-4) + value[len(value)-2:]
+}
+
+var connectionPasswordPattern = regexp.MustCompile(`(?i)(\b(?:password|pwd)\s*=\s*)("[^"]*"|'[^']*'|[^;]*)`)
+
+// MaskConnectionStringPasswords masks the values of Password and Pwd keys in a connection string
+func MaskConnectionStringPasswords(connectionString string) string {
+	return connectionPasswordPattern.ReplaceAllStringFunc(connectionString, func(match string) string {
+		parts := connectionPasswordPattern.FindStringSubmatch(match)
+		value := parts[2]
+		if strings.TrimSpace(value) == "" {
+			return match
+		}
+		return parts[1] + MaskSensitiveValue(value)
+	})
+}
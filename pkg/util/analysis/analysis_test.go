@@ -157,3 +157,18 @@ func TestAnalyzeGenericTextFile(t *testing.T) {
 		})
 	}
 }
+
+func TestMaskConnectionStringPasswords(t *testing.T) {
+	cases := map[string]string{
+		"Data Source=srv;User ID=etl;Password=Secret123;Provider=SQLNCLI11.1;": "Data Source=srv;User ID=etl;Password=Se*****23;Provider=SQLNCLI11.1;",
+		"Server=srv;pwd = hunter2":                    "Server=srv;pwd = hu***r2",
+		"Server=srv;Password=\"a;b;c;d\";Database=db": "Server=srv;Password=\"a*****d\";Database=db",
+		"Server=srv;Integrated Security=SSPI;":        "Server=srv;Integrated Security=SSPI;",
+		"Server=srv;Password=;":                       "Server=srv;Password=;",
+	}
+	for input, expected := range cases {
+		if masked := MaskConnectionStringPasswords(input); masked != expected {
+			t.Fatalf("MaskConnectionStringPasswords(%q) = %q, want %q", input, masked, expected)
+		}
+	}
+}
@@ -1,10 +1,17 @@
 package file
 
 import (
+	"bytes"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
 )
 
 // ResolveFilePath resolves a file path against the package directory if it's relative
@@ -39,6 +46,81 @@ func IsFileBinary(filePath string) (bool, error) {
 	return false, nil
 }
 
+// DetectContentType sniffs a file's MIME type from its first 512 bytes and returns it with the file size
+func DetectContentType(filePath string) (string, int64, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+
+	buffer := make([]byte, 512)
+	n, err := file.Read(buffer)
+	if err != nil && n == 0 && info.Size() > 0 {
+		return "", 0, err
+	}
+
+	return http.DetectContentType(buffer[:n]), info.Size(), nil
+}
+
+// DecodeText converts file bytes to a UTF-8 string and reports the encoding used.
+// An empty or "auto" encoding detects UTF-8 and UTF-16 byte order marks, keeps valid
+// UTF-8 as is and falls back to Windows-1252 for anything else.
+func DecodeText(data []byte, encodingName string) (string, string, error) {
+	name := strings.ToLower(strings.TrimSpace(encodingName))
+	if name == "" || name == "auto" {
+		name = detectEncoding(data)
+	}
+
+	var decoder *encoding.Decoder
+	switch name {
+	case "utf-8", "utf8":
+		return string(bytes.TrimPrefix(data, utf8BOM)), "utf-8", nil
+	case "utf-8-bom":
+		return string(bytes.TrimPrefix(data, utf8BOM)), "utf-8-bom", nil
+	case "utf-16le", "utf-16-le":
+		name = "utf-16le"
+		decoder = unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewDecoder()
+	case "utf-16be", "utf-16-be":
+		name = "utf-16be"
+		decoder = unicode.UTF16(unicode.BigEndian, unicode.UseBOM).NewDecoder()
+	case "windows-1252", "cp1252":
+		name = "windows-1252"
+		decoder = charmap.Windows1252.NewDecoder()
+	default:
+		return "", "", fmt.Errorf("unsupported encoding: %s (supported: auto, utf-8, utf-16le, utf-16be, windows-1252)", encodingName)
+	}
+
+	decoded, err := decoder.Bytes(data)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to decode %s content: %w", name, err)
+	}
+	return string(decoded), name, nil
+}
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// detectEncoding identifies the text encoding from a byte order mark or UTF-8 validity
+func detectEncoding(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, utf8BOM):
+		return "utf-8-bom"
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return "utf-16le"
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return "utf-16be"
+	case utf8.Valid(data):
+		return "utf-8"
+	default:
+		return "windows-1252"
+	}
+}
+
 // ConvertToLines converts content to lines with optional line numbers
 func ConvertToLines(content string, isLineNumberNeeded bool) []string {
 	lines := strings.Split(content, "\n")
@@ -160,3 +160,56 @@ func TestConvertToLines(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectContentType(t *testing.T) {
+	dir := t.TempDir()
+	textPath := filepath.Join(dir, "script.sql")
+	if err := os.WriteFile(textPath, []byte("SELECT 1;\n"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	contentType, size, err := DetectContentType(textPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if contentType != "text/plain; charset=utf-8" || size != 10 {
+		t.Fatalf("unexpected detection result: %s (%d bytes)", contentType, size)
+	}
+
+	if _, _, err := DetectContentType(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
+
+func TestDecodeText(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         []byte
+		encoding     string
+		expected     string
+		expectedName string
+	}{
+		{"utf-8", []byte("Café"), "", "Café", "utf-8"},
+		{"utf-8 bom", []byte("\xEF\xBB\xBFCafé"), "auto", "Café", "utf-8-bom"},
+		{"utf-16le bom", []byte{0xFF, 0xFE, 'C', 0, 'a', 0, 'f', 0, 0xE9, 0}, "", "Café", "utf-16le"},
+		{"utf-16be bom", []byte{0xFE, 0xFF, 0, 'C', 0, 'a', 0, 'f', 0, 0xE9}, "", "Café", "utf-16be"},
+		{"windows-1252 fallback", []byte{'C', 'a', 'f', 0xE9, ' ', 0x80}, "", "Café €", "windows-1252"},
+		{"utf-16le override without bom", []byte{'O', 0, 'K', 0}, "utf-16le", "OK", "utf-16le"},
+		{"windows-1252 override", []byte{0x93, 'x', 0x94}, "cp1252", "“x”", "windows-1252"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, name, err := DecodeText(tt.data, tt.encoding)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if decoded != tt.expected || name != tt.expectedName {
+				t.Fatalf("DecodeText() = %q (%s), want %q (%s)", decoded, name, tt.expected, tt.expectedName)
+			}
+		})
+	}
+
+	if _, _, err := DecodeText([]byte("x"), "ebcdic"); err == nil {
+		t.Fatal("expected error for unsupported encoding")
+	}
+}
@@ -0,0 +1,32 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+)
+
+// WriteOutput writes content to path, creating any missing parent directories
+func WriteOutput(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to write output file %s: %w", path, err)
+	}
+	return nil
+}
+
+// TruncateText shortens content to at most limit bytes, cutting on a UTF-8 boundary and
+// appending a note that points to output_file_path. A limit of zero or less disables truncation.
+func TruncateText(content string, limit int) (string, bool) {
+	if limit <= 0 || len(content) <= limit {
+		return content, false
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(content[cut]) {
+		cut--
+	}
+	return content[:cut] + fmt.Sprintf("\n[... truncated at %d bytes, use output_file_path to get full output]", limit), true
+}
@@ -0,0 +1,65 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteOutputCreatesParentDirectories(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "nested", "report.txt")
+
+	if err := WriteOutput(path, "report"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected output file to be created: %v", err)
+	}
+	if string(data) != "report" {
+		t.Fatalf("expected file content %q, got %q", "report", string(data))
+	}
+}
+
+func TestWriteOutputFailsWhenParentIsFile(t *testing.T) {
+	parent := filepath.Join(t.TempDir(), "file.txt")
+	if err := os.WriteFile(parent, []byte("x"), 0o644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	if err := WriteOutput(filepath.Join(parent, "report.txt"), "report"); err == nil {
+		t.Fatal("expected error when parent path is a file")
+	}
+}
+
+func TestTruncateTextBoundaryAndSuffix(t *testing.T) {
+	content := "0123456789"
+
+	if text, truncated := TruncateText(content, 10); truncated || text != content {
+		t.Fatalf("expected content at the limit to be unchanged, got %q", text)
+	}
+	if text, truncated := TruncateText(content, 0); truncated || text != content {
+		t.Fatalf("expected zero limit to disable truncation, got %q", text)
+	}
+
+	text, truncated := TruncateText(content, 4)
+	if !truncated {
+		t.Fatal("expected content over the limit to be truncated")
+	}
+	expected := "0123\n[... truncated at 4 bytes, use output_file_path to get full output]"
+	if text != expected {
+		t.Fatalf("expected %q, got %q", expected, text)
+	}
+}
+
+func TestTruncateTextKeepsUTF8Valid(t *testing.T) {
+	text, truncated := TruncateText("ab✓cd", 3)
+	if !truncated {
+		t.Fatal("expected truncation")
+	}
+	if !strings.HasPrefix(text, "ab\n[... truncated at 3 bytes") {
+		t.Fatalf("expected cut before the multi-byte rune, got %q", text)
+	}
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ToolLogger returns the default logger annotated with the tool name and, when present, the file_path argument
+func ToolLogger(request mcp.CallToolRequest) *slog.Logger {
+	return slog.With("tool", request.Params.Name, "file", request.GetString("file_path", ""))
+}
+
+// LoggingMiddleware logs every tool invocation with its tool name, file path, duration and outcome
+func LoggingMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		logger := ToolLogger(request)
+		logger.Debug("tool invoked")
+
+		start := time.Now()
+		result, err := next(ctx, request)
+		duration := time.Since(start)
+
+		switch {
+		case err != nil:
+			logger.Error("tool failed", "duration", duration, "error", err)
+		case result != nil && result.IsError:
+			logger.Warn("tool returned an error result", "duration", duration)
+		default:
+			logger.Info("tool completed", "duration", duration)
+		}
+		return result, err
+	}
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestLoggingMiddlewareAddsToolAndFileFields(t *testing.T) {
+	original := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(original) })
+
+	var buf bytes.Buffer
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	handler := LoggingMiddleware(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{
+		Name:      "parse_dtsx",
+		Arguments: map[string]interface{}{"file_path": "Package1.dtsx"},
+	}}
+	if _, err := handler(context.Background(), request); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected one JSON log entry, got %q: %v", buf.String(), err)
+	}
+	if entry["msg"] != "tool completed" || entry["tool"] != "parse_dtsx" || entry["file"] != "Package1.dtsx" {
+		t.Fatalf("expected tool and file fields, got %v", entry)
+	}
+	if _, ok := entry["duration"]; !ok {
+		t.Fatalf("expected a duration field, got %v", entry)
+	}
+}
+
+func TestLoggingMiddlewareLogsErrorResults(t *testing.T) {
+	original := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(original) })
+
+	var buf bytes.Buffer
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	handler := LoggingMiddleware(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("boom"), nil
+	})
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "extract_tasks"}}
+	if _, err := handler(context.Background(), request); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected one JSON log entry, got %q: %v", buf.String(), err)
+	}
+	if entry["level"] != "WARN" || entry["tool"] != "extract_tasks" {
+		t.Fatalf("expected a warning for the error result, got %v", entry)
+	}
+}
@@ -1,22 +1,73 @@
 package server
 
 import (
-	"log"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/mark3labs/mcp-go/server"
 )
 
-// RunHTTPServer starts an HTTP server with streaming capabilities
+// shutdownTimeout bounds how long in-flight requests may run once shutdown begins
+const shutdownTimeout = 30 * time.Second
+
+// RunHTTPServer starts an HTTP server with streaming capabilities and shuts it down gracefully on SIGTERM or SIGINT
 func RunHTTPServer(s *server.MCPServer, port string) {
 	// Use the official MCP StreamableHTTPServer for proper MCP HTTP transport
 	streamableServer := server.NewStreamableHTTPServer(s)
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", streamableServer)
+	httpServer := &http.Server{Addr: ":" + port, Handler: mux}
+
+	slog.Info("starting MCP HTTP server", "port", port)
+	slog.Info("MCP endpoints available", "url", "http://localhost:"+port+"/mcp")
+	slog.Info("health check available", "url", "http://localhost:"+port+"/health")
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	listener, err := net.Listen("tcp", httpServer.Addr)
+	if err != nil {
+		slog.Error("HTTP server error", "error", err)
+		os.Exit(1)
+	}
+	if err := serveUntilDone(ctx, httpServer, listener, shutdownTimeout); err != nil {
+		slog.Error("HTTP server error", "error", err)
+		os.Exit(1)
+	}
+}
 
-	log.Printf("Starting MCP HTTP server on port %s", port)
-	log.Printf("MCP endpoints available at: http://localhost:%s/mcp", port)
-	log.Printf("Health check available at: http://localhost:%s/health", port)
+// serveUntilDone serves HTTP requests until ctx is done, then stops accepting connections and
+// waits up to timeout for in-flight requests to complete
+func serveUntilDone(ctx context.Context, srv *http.Server, listener net.Listener, timeout time.Duration) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(listener)
+	}()
 
-	// Start the server
-	if err := streamableServer.Start(":" + port); err != nil {
-		log.Fatalf("HTTP server error: %v", err)
+	select {
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("server shutting down gracefully")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
 	}
+	return nil
 }
@@ -1,7 +1,13 @@
 package server
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -42,3 +48,57 @@ func TestRunHTTPServerParameters(t *testing.T) {
 		t.Error("Server creation returned nil")
 	}
 }
+
+func TestServeUntilDoneCompletesInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		fmt.Fprint(w, "finished")
+	}))
+	url := "http://" + ts.Listener.Addr().String()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- serveUntilDone(ctx, ts.Config, ts.Listener, 5*time.Second)
+	}()
+
+	type response struct {
+		body string
+		err  error
+	}
+	responses := make(chan response, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			responses <- response{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		responses <- response{body: string(body), err: err}
+	}()
+
+	<-started
+	cancel()
+
+	select {
+	case err := <-served:
+		t.Fatalf("expected shutdown to wait for the in-flight request, returned %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	got := <-responses
+	if got.err != nil || got.body != "finished" {
+		t.Fatalf("expected in-flight request to complete, got %q, %v", got.body, got.err)
+	}
+	if err := <-served; err != nil {
+		t.Fatalf("expected graceful shutdown, got %v", err)
+	}
+	if _, err := http.Get(url); err == nil {
+		t.Fatal("expected the server to refuse requests after shutdown")
+	}
+}
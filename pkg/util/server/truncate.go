@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/MCPRUNNER/gossisMCP/pkg/util/output"
+)
+
+// TruncateOutputParameter is the tool argument that limits the size of a tool result in bytes
+const TruncateOutputParameter = "truncate_output"
+
+// TruncateOutputMiddleware limits tool results to the byte count given in the truncate_output argument.
+// Handlers still write their full output to output_file_path before the result is truncated.
+func TruncateOutputMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		if err != nil {
+			return result, err
+		}
+		return TruncateResult(result, request.GetInt(TruncateOutputParameter, 0)), nil
+	}
+}
+
+// TruncateResult shortens the text of a successful tool result to at most limit bytes.
+// Structured content larger than the limit is replaced by its truncated JSON text.
+func TruncateResult(result *mcp.CallToolResult, limit int) *mcp.CallToolResult {
+	if result == nil || result.IsError || limit <= 0 {
+		return result
+	}
+
+	if result.StructuredContent != nil {
+		data, err := json.MarshalIndent(result.StructuredContent, "", "  ")
+		if err != nil {
+			return result
+		}
+		if text, truncated := output.TruncateText(string(data), limit); truncated {
+			return mcp.NewToolResultText(text)
+		}
+		return result
+	}
+
+	var parts []string
+	var other []mcp.Content
+	for _, content := range result.Content {
+		if textContent, ok := mcp.AsTextContent(content); ok {
+			parts = append(parts, textContent.Text)
+		} else {
+			other = append(other, content)
+		}
+	}
+	text, truncated := output.TruncateText(strings.Join(parts, "\n"), limit)
+	if !truncated {
+		return result
+	}
+
+	truncatedResult := *result
+	truncatedResult.Content = append([]mcp.Content{mcp.NewTextContent(text)}, other...)
+	return &truncatedResult
+}
+
+// AddTruncateOutputParameter adds the truncate_output argument to the schema of every registered tool
+func AddTruncateOutputParameter(s *server.MCPServer) {
+	var updated []server.ServerTool
+	for _, tool := range s.ListTools() {
+		if tool.Tool.RawInputSchema != nil {
+			continue
+		}
+		if _, exists := tool.Tool.InputSchema.Properties[TruncateOutputParameter]; exists {
+			continue
+		}
+		properties := make(map[string]any, len(tool.Tool.InputSchema.Properties)+1)
+		for name, property := range tool.Tool.InputSchema.Properties {
+			properties[name] = property
+		}
+		properties[TruncateOutputParameter] = map[string]any{
+			"type":        "number",
+			"description": "Maximum number of bytes of output to return; the full output is still written to output_file_path if specified",
+		}
+		tool.Tool.InputSchema.Properties = properties
+		updated = append(updated, *tool)
+	}
+	if len(updated) > 0 {
+		s.AddTools(updated...)
+	}
+}
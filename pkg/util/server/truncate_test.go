@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestTruncateOutputMiddleware(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "report.txt")
+	fullOutput := strings.Repeat("x", 100)
+	handler := TruncateOutputMiddleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := os.WriteFile(outputPath, []byte(fullOutput), 0o644); err != nil {
+			t.Fatalf("failed to write output: %v", err)
+		}
+		return mcp.NewToolResultText(fullOutput), nil
+	})
+
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"truncate_output": float64(40)}}}
+	result, err := handler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	expected := strings.Repeat("x", 40) + "\n[... truncated at 40 bytes, use output_file_path to get full output]"
+	if text != expected {
+		t.Fatalf("expected truncated output %q, got %q", expected, text)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil || string(data) != fullOutput {
+		t.Fatalf("expected full output in file, got %d bytes (err %v)", len(data), err)
+	}
+
+	request.Params.Arguments = map[string]interface{}{}
+	result, _ = handler(context.Background(), request)
+	if text := result.Content[0].(mcp.TextContent).Text; text != fullOutput {
+		t.Fatalf("expected untruncated output without truncate_output, got %d bytes", len(text))
+	}
+}
+
+func TestTruncateResultStructuredAndErrors(t *testing.T) {
+	structured := mcp.NewToolResultStructured(map[string]interface{}{"rows": strings.Repeat("y", 200)}, "Rows")
+	result := TruncateResult(structured, 50)
+	if result.StructuredContent != nil {
+		t.Fatal("expected oversized structured content to be replaced by text")
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.HasSuffix(text, "[... truncated at 50 bytes, use output_file_path to get full output]") {
+		t.Fatalf("expected truncation suffix, got %q", text)
+	}
+
+	errorResult := mcp.NewToolResultError(strings.Repeat("e", 100))
+	if TruncateResult(errorResult, 10) != errorResult {
+		t.Fatal("expected error results to be left unchanged")
+	}
+}
+
+func TestAddTruncateOutputParameter(t *testing.T) {
+	s := server.NewMCPServer("test-server", "1.0.0")
+	s.AddTool(mcp.NewTool("example", mcp.WithString("file_path")), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	AddTruncateOutputParameter(s)
+	tool := s.GetTool("example")
+	if _, ok := tool.Tool.InputSchema.Properties[TruncateOutputParameter]; !ok {
+		t.Fatalf("expected truncate_output parameter, got %v", tool.Tool.InputSchema.Properties)
+	}
+	if _, ok := tool.Tool.InputSchema.Properties["file_path"]; !ok {
+		t.Fatal("expected existing parameters to be kept")
+	}
+}
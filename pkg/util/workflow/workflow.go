@@ -3,11 +3,11 @@ package workflow
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 
+	"github.com/MCPRUNNER/gossisMCP/pkg/util/output"
 	"github.com/MCPRUNNER/gossisMCP/pkg/workflow"
 )
 
@@ -24,6 +24,8 @@ type WorkflowStepSummary struct {
 	Type    string                         `json:"type"`
 	Enabled bool                           `json:"enabled"`
 	Outputs map[string]workflow.StepResult `json:"outputs,omitempty"`
+	// AssertionFailure is set when the step's assert did not hold
+	AssertionFailure string `json:"assertion_failure,omitempty"`
 }
 
 // CloneArguments creates a deep copy of map[string]interface{} arguments
@@ -130,8 +132,51 @@ func ExtractStringArg(args map[string]interface{}, key string) string {
 	return ""
 }
 
-// ExtractFilePathsFromJSON extracts file paths from JSON text
-func ExtractFilePathsFromJSON(jsonText string) ([]string, error) {
+// ExtractFilePathsFromJSON extracts file paths from JSON text. When jsonPath is set (dot notation,
+// e.g. "packages" or "result.packages") only the array at that path is searched: string elements
+// are taken as paths and other elements are searched for the known path fields.
+func ExtractFilePathsFromJSON(jsonText, jsonPath string) ([]string, error) {
+	if jsonPath == "" {
+		return matchFilePaths(jsonText), nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(jsonText), &value); err != nil {
+		return nil, fmt.Errorf("json_path %q: invalid JSON: %w", jsonPath, err)
+	}
+	for _, key := range strings.Split(jsonPath, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("json_path %q: cannot look up %q in a non-object value", jsonPath, key)
+		}
+		if value, ok = object[key]; !ok {
+			return nil, fmt.Errorf("json_path %q: key %q not found", jsonPath, key)
+		}
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("json_path %q: value is not an array", jsonPath)
+	}
+
+	var filePaths []string
+	for _, item := range items {
+		if path, ok := item.(string); ok {
+			if path != "" {
+				filePaths = append(filePaths, path)
+			}
+			continue
+		}
+		encoded, err := json.Marshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("json_path %q: %w", jsonPath, err)
+		}
+		filePaths = append(filePaths, matchFilePaths(string(encoded))...)
+	}
+	return filePaths, nil
+}
+
+// matchFilePaths returns the values of the known path fields found in JSON text
+func matchFilePaths(jsonText string) []string {
 	var filePaths []string
 
 	// Pattern to match file paths in JSON strings
@@ -157,7 +202,7 @@ func ExtractFilePathsFromJSON(jsonText string) ([]string, error) {
 		}
 	}
 
-	return filePaths, nil
+	return filePaths
 }
 
 // ToInterfaceSlice converts []string to []interface{}
@@ -171,13 +216,7 @@ func ToInterfaceSlice(values []string) []interface{} {
 
 // WriteWorkflowOutput writes content to a workflow output file
 func WriteWorkflowOutput(outputPath, content string) error {
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
-		return fmt.Errorf("failed to create workflow output directory %s: %w", filepath.Dir(outputPath), err)
-	}
-	if err := os.WriteFile(outputPath, []byte(content), 0o644); err != nil {
-		return fmt.Errorf("failed to write workflow output %s: %w", outputPath, err)
-	}
-	return nil
+	return output.WriteOutput(outputPath, content)
 }
 
 // CreateWorkflowExecutionSummary creates a summary of workflow execution
@@ -197,6 +236,11 @@ func CreateWorkflowExecutionSummary(workflowPath string, wf *workflow.Workflow,
 
 		if stepResults, exists := results[step.Name]; exists {
 			stepSummary.Outputs = stepResults
+			for _, result := range stepResults {
+				if result.Failure != "" {
+					stepSummary.AssertionFailure = result.Failure
+				}
+			}
 		}
 
 		summary.Steps = append(summary.Steps, stepSummary)
@@ -205,6 +249,16 @@ func CreateWorkflowExecutionSummary(workflowPath string, wf *workflow.Workflow,
 	return summary
 }
 
+// HasAssertionFailures reports whether any step in the summary failed its assertion
+func (s WorkflowExecutionSummary) HasAssertionFailures() bool {
+	for _, step := range s.Steps {
+		if step.AssertionFailure != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // FormatWorkflowSummaryMarkdown formats workflow execution summary as markdown
 func FormatWorkflowSummaryMarkdown(summary WorkflowExecutionSummary) string {
 	var sb strings.Builder
@@ -217,9 +271,14 @@ func FormatWorkflowSummaryMarkdown(summary WorkflowExecutionSummary) string {
 		status := "✅"
 		if !step.Enabled {
 			status = "⏭️"
+		} else if step.AssertionFailure != "" {
+			status = "❌"
 		}
 
 		sb.WriteString(fmt.Sprintf("%d. %s %s (%s)\n", i+1, status, step.Name, step.Type))
+		if step.AssertionFailure != "" {
+			sb.WriteString(fmt.Sprintf("   - Assertion failed: %s\n", step.AssertionFailure))
+		}
 
 		if len(step.Outputs) > 0 {
 			sb.WriteString("   - Outputs:\n")
@@ -240,6 +299,30 @@ func FormatWorkflowSummaryMarkdown(summary WorkflowExecutionSummary) string {
 	return sb.String()
 }
 
+// FormatDryRunReportMarkdown formats a workflow dry-run report as markdown
+func FormatDryRunReportMarkdown(report workflow.DryRunReport) string {
+	var sb strings.Builder
+
+	sb.WriteString("# Workflow Dry Run\n\n")
+	sb.WriteString(fmt.Sprintf("**Workflow:** %s\n\n", report.WorkflowPath))
+	sb.WriteString(fmt.Sprintf("**Steps checked:** %d\n\n", report.StepsChecked))
+
+	if report.Valid {
+		sb.WriteString("✅ The workflow is valid. No steps were executed.\n")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("## Issues (%d)\n\n", len(report.Issues)))
+	for _, issue := range report.Issues {
+		if issue.Step != "" {
+			sb.WriteString(fmt.Sprintf("- ❌ %s: %s\n", issue.Step, issue.Message))
+		} else {
+			sb.WriteString(fmt.Sprintf("- ❌ %s\n", issue.Message))
+		}
+	}
+	return sb.String()
+}
+
 // ExtractJSONObjects scans input for top-level JSON objects and returns them as raw JSON strings
 func ExtractJSONObjects(s string) []string {
 	var objects []string
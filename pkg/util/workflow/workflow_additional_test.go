@@ -417,6 +417,19 @@ func TestFormatWorkflowSummaryMarkdown(t *testing.T) {
 				"- Outputs:",
 			},
 		},
+		{
+			name: "step with failed assertion",
+			summary: WorkflowExecutionSummary{
+				WorkflowPath: "/workflows/test.yaml",
+				Steps: []WorkflowStepSummary{
+					{Name: "step1", Type: "validate_best_practices", Enabled: true, AssertionFailure: `output does not contain "0 findings"`},
+				},
+			},
+			contains: []string{
+				"1. ❌ step1 (validate_best_practices)",
+				`- Assertion failed: output does not contain "0 findings"`,
+			},
+		},
 		{
 			name: "multiple steps and files",
 			summary: WorkflowExecutionSummary{
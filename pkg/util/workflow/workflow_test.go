@@ -268,7 +268,7 @@ func TestExtractFilePathsFromJSON(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := ExtractFilePathsFromJSON(tt.jsonText)
+			result, err := ExtractFilePathsFromJSON(tt.jsonText, "")
 			if (err != nil) != tt.expectError {
 				t.Errorf("ExtractFilePathsFromJSON() error = %v, expectError %v", err, tt.expectError)
 				return
@@ -286,6 +286,66 @@ func TestExtractFilePathsFromJSON(t *testing.T) {
 	}
 }
 
+func TestExtractFilePathsFromJSONPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		jsonText    string
+		jsonPath    string
+		expected    []string
+		expectError bool
+	}{
+		{
+			name:     "top-level array",
+			jsonText: `[{"file_path": "a.dtsx"}, {"file_path": "b.dtsx"}]`,
+			expected: []string{"a.dtsx", "b.dtsx"},
+		},
+		{
+			name:     "nested array of objects",
+			jsonText: `{"count": 2, "packages": [{"path": "a.dtsx"}, {"path": "b.dtsx"}], "output_file_path": "ignored.json"}`,
+			jsonPath: "packages",
+			expected: []string{"a.dtsx", "b.dtsx"},
+		},
+		{
+			name:     "nested array of strings",
+			jsonText: `{"result": {"packages": ["a.dtsx", "b.dtsx"]}}`,
+			jsonPath: "result.packages",
+			expected: []string{"a.dtsx", "b.dtsx"},
+		},
+		{
+			name:        "missing key",
+			jsonText:    `{"files": ["a.dtsx"]}`,
+			jsonPath:    "packages",
+			expectError: true,
+		},
+		{
+			name:        "not an array",
+			jsonText:    `{"packages": "a.dtsx"}`,
+			jsonPath:    "packages",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ExtractFilePathsFromJSON(tt.jsonText, tt.jsonPath)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("ExtractFilePathsFromJSON() error = %v, expectError %v", err, tt.expectError)
+			}
+			if tt.expectError {
+				return
+			}
+			if len(result) != len(tt.expected) {
+				t.Fatalf("ExtractFilePathsFromJSON() = %v, want %v", result, tt.expected)
+			}
+			for i, expected := range tt.expected {
+				if result[i] != expected {
+					t.Errorf("ExtractFilePathsFromJSON()[%d] = %v, want %v", i, result[i], expected)
+				}
+			}
+		})
+	}
+}
+
 func TestToInterfaceSlice(t *testing.T) {
 	tests := []struct {
 		name     string
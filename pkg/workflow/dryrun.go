@@ -0,0 +1,171 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ToolLookup reports whether the runner can dispatch a tool and, if so, which of
+// its parameters are required for the given step parameters.
+type ToolLookup func(tool string, params map[string]interface{}) (required []string, ok bool)
+
+// DryRunIssue is a problem found while validating a workflow without running it.
+type DryRunIssue struct {
+	Step    string `json:"step,omitempty"`
+	Message string `json:"message"`
+}
+
+// DryRunReport is the result of validating a workflow definition.
+type DryRunReport struct {
+	WorkflowPath string        `json:"workflow_path"`
+	Valid        bool          `json:"valid"`
+	StepsChecked int           `json:"steps_checked"`
+	Issues       []DryRunIssue `json:"issues"`
+}
+
+// DryRunFile loads the workflow at the given path and validates it without
+// executing any step. Load and structural errors are reported as issues.
+func DryRunFile(workflowPath string, lookup ToolLookup) DryRunReport {
+	report := DryRunReport{WorkflowPath: workflowPath, Issues: []DryRunIssue{}}
+
+	wf, err := LoadFromFile(workflowPath)
+	if err != nil {
+		report.Issues = append(report.Issues, DryRunIssue{Message: err.Error()})
+		return report
+	}
+
+	report.Issues = append(report.Issues, wf.DryRun(lookup)...)
+	for _, step := range wf.Steps {
+		if step.Enabled {
+			report.StepsChecked++
+		}
+	}
+	report.Valid = len(report.Issues) == 0
+	return report
+}
+
+// DryRun checks every enabled step: the tool must be known to the runner, all
+// of its required parameters must be set (directly, through output_file_path or
+// piped from the previous step), every {Step.Output} placeholder must refer
+// to an output of an earlier enabled step and every {{.name}} reference must
+// name a variable stored by an earlier enabled step.
+func (wf *Workflow) DryRun(lookup ToolLookup) []DryRunIssue {
+	var issues []DryRunIssue
+	outputs := make(map[string]string)
+	stored := make(map[string]bool)
+	piped := ""
+
+	for _, step := range wf.Steps {
+		if !step.Enabled {
+			continue
+		}
+		incoming := piped
+		piped = step.PipeOutputTo
+
+		provided := make(map[string]interface{}, len(step.Parameters)+2)
+		for key, value := range step.Parameters {
+			provided[key] = value
+		}
+		if step.OutputFilePath != "" {
+			provided["output_file_path"] = step.OutputFilePath
+		}
+		if incoming != "" {
+			provided[incoming] = ""
+		}
+
+		tool := strings.TrimPrefix(step.Type, "#")
+		required, ok := lookup(tool, provided)
+		if !ok {
+			issues = append(issues, DryRunIssue{Step: step.Name, Message: fmt.Sprintf("unknown tool %q", tool)})
+		}
+		for _, name := range required {
+			if _, exists := provided[name]; !exists {
+				issues = append(issues, DryRunIssue{Step: step.Name, Message: fmt.Sprintf("missing required parameter %q for tool %q", name, tool)})
+			}
+		}
+
+		var references []string
+		collectPlaceholders(step.Parameters, &references)
+		if step.Loop != nil {
+			collectPlaceholders(step.Loop.InputData, &references)
+		}
+		for _, reference := range references {
+			if message := checkPlaceholder(reference, outputs); message != "" {
+				issues = append(issues, DryRunIssue{Step: step.Name, Message: message})
+			}
+		}
+
+		var variables []string
+		collectVariables(step.Parameters, &variables)
+		if step.Loop != nil {
+			collectVariables(step.Loop.InputData, &variables)
+		}
+		for _, name := range variables {
+			if !stored[name] {
+				issues = append(issues, DryRunIssue{Step: step.Name, Message: fmt.Sprintf("variable {{.%s}} is not stored by an earlier enabled step", name)})
+			}
+		}
+		if step.StoreAs != "" {
+			stored[step.StoreAs] = true
+		}
+
+		outName := "Result"
+		if step.Output != nil && step.Output.Name != "" {
+			outName = step.Output.Name
+		}
+		outputs[step.Name] = outName
+	}
+	return issues
+}
+
+// collectPlaceholders gathers the {Step.Output} placeholders in a parameter value
+func collectPlaceholders(value interface{}, references *[]string) {
+	walkStrings(value, func(text string) {
+		*references = append(*references, placeholderExpr.FindAllString(text, -1)...)
+	})
+}
+
+// collectVariables gathers the names of the {{.name}} references in a parameter value
+func collectVariables(value interface{}, names *[]string) {
+	walkStrings(value, func(text string) {
+		for _, match := range variableExpr.FindAllStringSubmatch(text, -1) {
+			*names = append(*names, match[1])
+		}
+	})
+}
+
+// walkStrings calls visit for every string in a parameter value, visiting map entries in key order
+func walkStrings(value interface{}, visit func(string)) {
+	switch v := value.(type) {
+	case string:
+		visit(v)
+	case []interface{}:
+		for _, item := range v {
+			walkStrings(item, visit)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			walkStrings(v[key], visit)
+		}
+	}
+}
+
+// checkPlaceholder returns a message when a placeholder does not refer to an output of an earlier step
+func checkPlaceholder(placeholder string, outputs map[string]string) string {
+	match := placeholderExpr.FindStringSubmatch(placeholder)
+	stepName, outputName := match[1], strings.SplitN(match[2], ".", 2)[0]
+	declared, ok := outputs[stepName]
+	if !ok {
+		return fmt.Sprintf("placeholder %s refers to step %q, which is not an earlier enabled step", placeholder, stepName)
+	}
+	if declared != outputName {
+		return fmt.Sprintf("placeholder %s refers to output %q, but step %q outputs %q", placeholder, outputName, stepName, declared)
+	}
+	return ""
+}
@@ -10,9 +10,12 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"gopkg.in/yaml.v3"
+
+	"github.com/MCPRUNNER/gossisMCP/pkg/util/output"
 )
 
 // RunnerFunc describes a function capable of invoking an MCP tool.
@@ -24,8 +27,23 @@ type RunnerFunc func(ctx context.Context, tool string, params map[string]interfa
 // Workflow represents an ordered collection of workflow steps.
 type Workflow struct {
 	Steps []Step `json:"Steps" yaml:"Steps"`
+	// Groups maps a step group name to its combined output file; groups without an entry write <group>.json
+	Groups map[string]string `json:"Groups" yaml:"Groups"`
+	// CombineStrategy controls how outputs of steps sharing an output_file_path are combined (default: concat)
+	CombineStrategy string `json:"combine_strategy" yaml:"combine_strategy"`
+	// FailFast aborts the workflow on the first failed step assertion; when false the failure is recorded
+	// on the step result and later steps still run (default: true)
+	FailFast *bool `json:"fail_fast" yaml:"fail_fast"`
 }
 
+// Combine strategies accepted by Workflow.CombineStrategy
+const (
+	CombineConcat    = "concat"
+	CombineJSONArray = "json_array"
+	CombineJSONMerge = "json_merge"
+	CombineLastOnly  = "last_only"
+)
+
 // Step models a single workflow operation.
 type Step struct {
 	Name           string                 `json:"Name" yaml:"Name"`
@@ -35,6 +53,24 @@ type Step struct {
 	Output         *StepOutput            `json:"Output" yaml:"Output"`
 	Loop           *LoopConfig            `json:"loop" yaml:"loop"`
 	OutputFilePath string                 `json:"output_file_path" yaml:"output_file_path"`
+	// PipeOutputTo names a parameter of the next executed step that receives this step's output.
+	PipeOutputTo string `json:"pipe_output_to" yaml:"pipe_output_to"`
+	// Group names a step group whose outputs are merged into one file once every step in the group has run.
+	Group string `json:"group" yaml:"group"`
+	// TimeoutSeconds cancels the context passed to the runner when the step runs longer; zero means no timeout.
+	TimeoutSeconds int `json:"timeout_seconds" yaml:"timeout_seconds"`
+	// StoreAs names a workflow variable that receives this step's output; later steps reference it as {{.name}}.
+	StoreAs string `json:"store_as" yaml:"store_as"`
+	// Assert checks the step output once it has run and fails the step on a mismatch.
+	Assert *StepAssertion `json:"assert" yaml:"assert"`
+}
+
+// StepAssertion checks a step's output. Contains requires the output to include a substring; JSONPath
+// requires the dot-separated field of the JSON output to equal Equals.
+type StepAssertion struct {
+	Contains string      `json:"contains" yaml:"contains"`
+	JSONPath string      `json:"json_path" yaml:"json_path"`
+	Equals   interface{} `json:"equals" yaml:"equals"`
 }
 
 // StepOutput declares the named output captured from a workflow step.
@@ -55,21 +91,42 @@ type LoopConfig struct {
 type StepResult struct {
 	Value  string
 	Format string
+	// Failure describes the failed assertion when the step's assert did not hold
+	Failure string `json:"Failure,omitempty"`
 }
 
 var placeholderExpr = regexp.MustCompile(`\{([A-Za-z0-9_-]+)\.([A-Za-z0-9_.-]+)\}`)
 
+// variableExpr matches {{.name}} references to outputs stored with store_as.
+var variableExpr = regexp.MustCompile(`\{\{\s*\.([A-Za-z0-9_-]+)\s*\}\}`)
+
+// variableNameExpr matches names accepted by store_as.
+var variableNameExpr = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
 // LoadFromFile loads a workflow definition from JSON or YAML.
 func LoadFromFile(path string) (*Workflow, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
+	return parseWorkflow(data, path)
+}
+
+// LoadFromString loads a workflow definition from JSON or YAML content.
+func LoadFromString(content string) (*Workflow, error) {
+	return parseWorkflow([]byte(content), "")
+}
 
+// parseWorkflow decodes and validates a workflow definition. source names the
+// definition in parse errors and may be empty for inline content.
+func parseWorkflow(data []byte, source string) (*Workflow, error) {
 	wf := &Workflow{}
 	if err := json.Unmarshal(data, wf); err != nil {
 		if yamlErr := yaml.Unmarshal(data, wf); yamlErr != nil {
-			return nil, fmt.Errorf("failed to parse workflow %s: %v, %v", path, err, yamlErr)
+			if source == "" {
+				return nil, fmt.Errorf("failed to parse workflow: %v, %v", err, yamlErr)
+			}
+			return nil, fmt.Errorf("failed to parse workflow %s: %v, %v", source, err, yamlErr)
 		}
 	}
 
@@ -89,6 +146,13 @@ func (wf *Workflow) Validate() error {
 		return errors.New("workflow contains no steps")
 	}
 
+	switch wf.CombineStrategy {
+	case "", CombineConcat, CombineJSONArray, CombineJSONMerge, CombineLastOnly:
+	default:
+		return fmt.Errorf("unknown combine_strategy %q (supported: %s, %s, %s, %s)", wf.CombineStrategy,
+			CombineConcat, CombineJSONArray, CombineJSONMerge, CombineLastOnly)
+	}
+
 	seenNames := make(map[string]struct{})
 	for i, step := range wf.Steps {
 		if strings.TrimSpace(step.Name) == "" {
@@ -103,6 +167,29 @@ func (wf *Workflow) Validate() error {
 			return fmt.Errorf("step %s is missing a Type", step.Name)
 		}
 
+		if step.Group != "" && strings.TrimSpace(step.Group) == "" {
+			return fmt.Errorf("step %s has a blank group name", step.Name)
+		}
+
+		if step.TimeoutSeconds < 0 {
+			return fmt.Errorf("step %s timeout_seconds must not be negative", step.Name)
+		}
+
+		if step.StoreAs != "" && !variableNameExpr.MatchString(step.StoreAs) {
+			return fmt.Errorf("step %s store_as %q may only contain letters, digits, '_' and '-'", step.Name, step.StoreAs)
+		}
+
+		if step.Assert != nil {
+			switch {
+			case step.Assert.Contains != "" && step.Assert.JSONPath != "":
+				return fmt.Errorf("step %s assert must set either contains or json_path, not both", step.Name)
+			case step.Assert.Contains == "" && step.Assert.JSONPath == "":
+				return fmt.Errorf("step %s assert must set contains or json_path", step.Name)
+			case step.Assert.JSONPath != "" && step.Assert.Equals == nil:
+				return fmt.Errorf("step %s assert json_path requires equals", step.Name)
+			}
+		}
+
 		if step.Loop != nil {
 			if strings.TrimSpace(step.Loop.InputData) == "" {
 				return fmt.Errorf("step %s loop is missing input_data", step.Name)
@@ -112,6 +199,16 @@ func (wf *Workflow) Validate() error {
 			}
 		}
 	}
+
+	for group := range wf.Groups {
+		found := false
+		for _, step := range wf.Steps {
+			found = found || step.Group == group
+		}
+		if !found {
+			return fmt.Errorf("group %s has no steps", group)
+		}
+	}
 	return nil
 }
 
@@ -125,14 +222,20 @@ func (wf *Workflow) Execute(ctx context.Context, runner RunnerFunc, workflowPath
 	}
 
 	results := make(map[string]map[string]StepResult)
+	// piped holds outputs forwarded via pipe_output_to; it only applies to the next executed step
+	piped := make(map[string]interface{})
+	// variables holds outputs stored via store_as for {{.name}} references in later steps
+	variables := make(map[string]string)
 
 	for _, step := range wf.Steps {
 		if !step.Enabled {
 			continue
 		}
+		incoming := piped
+		piped = make(map[string]interface{})
 
 		if step.Loop != nil {
-			loopItems, err := resolveLoopItems(step.Loop, results)
+			loopItems, err := resolveLoopItems(step.Loop, results, variables)
 			if err != nil {
 				return nil, fmt.Errorf("step %s loop: %w", step.Name, err)
 			}
@@ -141,7 +244,7 @@ func (wf *Workflow) Execute(ctx context.Context, runner RunnerFunc, workflowPath
 			for idx, item := range loopItems {
 				resolvedParams := make(map[string]interface{}, len(step.Parameters))
 				for key, value := range step.Parameters {
-					resolved, err := resolveParameterValue(value, results)
+					resolved, err := resolveParameterValue(value, results, variables)
 					if err != nil {
 						return nil, fmt.Errorf("step %s parameter %s (loop %d): %w", step.Name, key, idx, err)
 					}
@@ -157,6 +260,7 @@ func (wf *Workflow) Execute(ctx context.Context, runner RunnerFunc, workflowPath
 					}
 					resolvedParams[key] = applyLoopItem(resolved, step.Loop.ItemName, item)
 				}
+				injectPipedParams(resolvedParams, incoming)
 				if step.OutputFilePath != "" {
 					if _, exists := resolvedParams["output_file_path"]; !exists {
 						resolvedParams["output_file_path"] = step.OutputFilePath
@@ -164,7 +268,7 @@ func (wf *Workflow) Execute(ctx context.Context, runner RunnerFunc, workflowPath
 				}
 
 				toolName := strings.TrimPrefix(step.Type, "#")
-				outputValue, err := runner(ctx, toolName, resolvedParams)
+				outputValue, err := runStep(ctx, runner, step, toolName, resolvedParams)
 				if err != nil {
 					return nil, fmt.Errorf("step %s (loop %d): %w", step.Name, idx, err)
 				}
@@ -176,15 +280,27 @@ func (wf *Workflow) Execute(ctx context.Context, runner RunnerFunc, workflowPath
 			}
 
 			joined := strings.Join(aggregated, "\n")
+			if step.PipeOutputTo != "" {
+				piped[step.PipeOutputTo] = joined
+			}
+			if step.StoreAs != "" {
+				variables[step.StoreAs] = joined
+			}
+			failure := checkAssertion(step.Assert, joined)
 			if step.Output != nil && step.Output.Name != "" {
-				results[step.Name][step.Output.Name] = StepResult{Value: joined, Format: step.Output.Format}
+				results[step.Name][step.Output.Name] = StepResult{Value: joined, Format: step.Output.Format, Failure: failure}
 			} else {
-				results[step.Name]["Result"] = StepResult{Value: joined}
+				results[step.Name]["Result"] = StepResult{Value: joined, Failure: failure}
+			}
+			if failure != "" && wf.failFast() {
+				return nil, fmt.Errorf("step %s assertion failed: %s", step.Name, failure)
 			}
 
 			// Write combined outputs immediately after loop step completes if output_file_path is set
 			if step.OutputFilePath != "" && workflowPath != "" {
-				_, _ = WriteCombinedStepOutputs(workflowPath, wf, results)
+				if _, err := WriteCombinedStepOutputs(workflowPath, wf, results); err != nil {
+					return nil, fmt.Errorf("step %s: %w", step.Name, err)
+				}
 			}
 
 			continue
@@ -192,18 +308,19 @@ func (wf *Workflow) Execute(ctx context.Context, runner RunnerFunc, workflowPath
 
 		resolvedParams := make(map[string]interface{}, len(step.Parameters))
 		for key, value := range step.Parameters {
-			resolved, err := resolveParameterValue(value, results)
+			resolved, err := resolveParameterValue(value, results, variables)
 			if err != nil {
 				return nil, fmt.Errorf("step %s parameter %s: %w", step.Name, key, err)
 			}
 			resolvedParams[key] = resolved
 		}
+		injectPipedParams(resolvedParams, incoming)
 		if step.OutputFilePath != "" {
 			resolvedParams["output_file_path"] = step.OutputFilePath
 		}
 
 		toolName := strings.TrimPrefix(step.Type, "#")
-		outputValue, err := runner(ctx, toolName, resolvedParams)
+		outputValue, err := runStep(ctx, runner, step, toolName, resolvedParams)
 		if err != nil {
 			return nil, fmt.Errorf("step %s: %w", step.Name, err)
 		}
@@ -211,31 +328,88 @@ func (wf *Workflow) Execute(ctx context.Context, runner RunnerFunc, workflowPath
 		if results[step.Name] == nil {
 			results[step.Name] = make(map[string]StepResult)
 		}
+		if step.PipeOutputTo != "" {
+			piped[step.PipeOutputTo] = outputValue
+		}
+		if step.StoreAs != "" {
+			variables[step.StoreAs] = outputValue
+		}
 
+		failure := checkAssertion(step.Assert, outputValue)
 		if step.Output != nil && step.Output.Name != "" {
-			results[step.Name][step.Output.Name] = StepResult{Value: outputValue, Format: step.Output.Format}
+			results[step.Name][step.Output.Name] = StepResult{Value: outputValue, Format: step.Output.Format, Failure: failure}
 		} else {
 			// Default output name when none is specified
-			results[step.Name]["Result"] = StepResult{Value: outputValue}
+			results[step.Name]["Result"] = StepResult{Value: outputValue, Failure: failure}
+		}
+		if failure != "" && wf.failFast() {
+			return nil, fmt.Errorf("step %s assertion failed: %s", step.Name, failure)
 		}
 
 		// Write combined outputs immediately after step completes if output_file_path is set
 		if step.OutputFilePath != "" && workflowPath != "" {
-			_, _ = WriteCombinedStepOutputs(workflowPath, wf, results)
+			if _, err := WriteCombinedStepOutputs(workflowPath, wf, results); err != nil {
+				return nil, fmt.Errorf("step %s: %w", step.Name, err)
+			}
 		}
 	}
 
 	return results, nil
 }
 
-func resolveParameterValue(value interface{}, outputs map[string]map[string]StepResult) (interface{}, error) {
+// failFast reports whether a failed assertion aborts the workflow
+func (wf *Workflow) failFast() bool {
+	return wf.FailFast == nil || *wf.FailFast
+}
+
+// checkAssertion returns a description of why the output does not satisfy the assertion, or "" when it does
+func checkAssertion(assert *StepAssertion, output string) string {
+	if assert == nil {
+		return ""
+	}
+	if assert.Contains != "" {
+		if !strings.Contains(output, assert.Contains) {
+			return fmt.Sprintf("output does not contain %q", assert.Contains)
+		}
+		return ""
+	}
+
+	actual, err := lookupJSONField(output, assert.JSONPath)
+	if err != nil {
+		return fmt.Sprintf("json_path %s: %v", assert.JSONPath, err)
+	}
+	// Compare JSON encodings so numbers decoded from JSON (float64) and YAML (int) are equal
+	got, err := json.Marshal(actual)
+	if err != nil {
+		return fmt.Sprintf("json_path %s: %v", assert.JSONPath, err)
+	}
+	want, err := json.Marshal(assert.Equals)
+	if err != nil {
+		return fmt.Sprintf("json_path %s: equals: %v", assert.JSONPath, err)
+	}
+	if string(got) != string(want) {
+		return fmt.Sprintf("json_path %s is %s, expected %s", assert.JSONPath, got, want)
+	}
+	return ""
+}
+
+// injectPipedParams adds piped outputs to a step's parameters; explicitly set parameters take precedence
+func injectPipedParams(params map[string]interface{}, piped map[string]interface{}) {
+	for key, value := range piped {
+		if _, exists := params[key]; !exists {
+			params[key] = value
+		}
+	}
+}
+
+func resolveParameterValue(value interface{}, outputs map[string]map[string]StepResult, variables map[string]string) (interface{}, error) {
 	switch v := value.(type) {
 	case string:
-		return resolvePlaceholderString(v, outputs)
+		return resolvePlaceholderString(v, outputs, variables)
 	case []interface{}:
 		resolved := make([]interface{}, len(v))
 		for i, item := range v {
-			out, err := resolveParameterValue(item, outputs)
+			out, err := resolveParameterValue(item, outputs, variables)
 			if err != nil {
 				return nil, err
 			}
@@ -245,7 +419,7 @@ func resolveParameterValue(value interface{}, outputs map[string]map[string]Step
 	case map[string]interface{}:
 		resolved := make(map[string]interface{}, len(v))
 		for key, item := range v {
-			out, err := resolveParameterValue(item, outputs)
+			out, err := resolveParameterValue(item, outputs, variables)
 			if err != nil {
 				return nil, err
 			}
@@ -257,10 +431,10 @@ func resolveParameterValue(value interface{}, outputs map[string]map[string]Step
 	}
 }
 
-func resolvePlaceholderString(input string, outputs map[string]map[string]StepResult) (string, error) {
+func resolvePlaceholderString(input string, outputs map[string]map[string]StepResult, variables map[string]string) (string, error) {
 	matches := placeholderExpr.FindAllStringSubmatch(input, -1)
 	if len(matches) == 0 {
-		return input, nil
+		return resolveVariables(input, variables)
 	}
 
 	result := input
@@ -293,15 +467,32 @@ func resolvePlaceholderString(input string, outputs map[string]map[string]StepRe
 		result = strings.ReplaceAll(result, match[0], replacement)
 	}
 
-	return result, nil
+	return resolveVariables(result, variables)
 }
 
-func resolveLoopItems(loop *LoopConfig, outputs map[string]map[string]StepResult) ([]string, error) {
+// resolveVariables replaces {{.name}} references with the outputs stored under that name
+func resolveVariables(input string, variables map[string]string) (string, error) {
+	var missing string
+	resolved := variableExpr.ReplaceAllStringFunc(input, func(match string) string {
+		name := variableExpr.FindStringSubmatch(match)[1]
+		value, ok := variables[name]
+		if !ok && missing == "" {
+			missing = name
+		}
+		return value
+	})
+	if missing != "" {
+		return "", fmt.Errorf("variable %q has not been stored by an earlier step", missing)
+	}
+	return resolved, nil
+}
+
+func resolveLoopItems(loop *LoopConfig, outputs map[string]map[string]StepResult, variables map[string]string) ([]string, error) {
 	if loop == nil {
 		return nil, nil
 	}
 
-	resolvedInput, err := resolvePlaceholderString(loop.InputData, outputs)
+	resolvedInput, err := resolvePlaceholderString(loop.InputData, outputs, variables)
 	if err != nil {
 		return nil, err
 	}
@@ -373,22 +564,9 @@ func applyLoopItem(value interface{}, itemName string, itemValue string) interfa
 }
 
 func extractFieldFromJSON(jsonText, fieldPath string) (string, error) {
-	var data interface{}
-	if err := json.Unmarshal([]byte(jsonText), &data); err != nil {
-		return "", fmt.Errorf("output is not valid JSON: %w", err)
-	}
-
-	current := data
-	for _, part := range strings.Split(fieldPath, ".") {
-		obj, ok := current.(map[string]interface{})
-		if !ok {
-			return "", fmt.Errorf("field %s not found", fieldPath)
-		}
-		next, exists := obj[part]
-		if !exists {
-			return "", fmt.Errorf("field %s not found", fieldPath)
-		}
-		current = next
+	current, err := lookupJSONField(jsonText, fieldPath)
+	if err != nil {
+		return "", err
 	}
 
 	switch v := current.(type) {
@@ -407,6 +585,28 @@ func extractFieldFromJSON(jsonText, fieldPath string) (string, error) {
 	}
 }
 
+// lookupJSONField decodes jsonText and returns the value at a dot-separated field path
+func lookupJSONField(jsonText, fieldPath string) (interface{}, error) {
+	var data interface{}
+	if err := json.Unmarshal([]byte(jsonText), &data); err != nil {
+		return nil, fmt.Errorf("output is not valid JSON: %w", err)
+	}
+
+	current := data
+	for _, part := range strings.Split(fieldPath, ".") {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("field %s not found", fieldPath)
+		}
+		next, exists := obj[part]
+		if !exists {
+			return nil, fmt.Errorf("field %s not found", fieldPath)
+		}
+		current = next
+	}
+	return current, nil
+}
+
 // ResolveRelativePath expands paths relative to the workflow file location.
 func ResolveRelativePath(workflowPath, target string) string {
 	if workflowPath == "" || filepath.IsAbs(target) {
@@ -457,6 +657,28 @@ func ToolResultToString(result *mcp.CallToolResult) (string, error) {
 	return combined, nil
 }
 
+// ToolResultToStructured returns the structured content of an MCP tool result for steps that process the
+// result programmatically. Results without structured content have their text decoded as JSON instead, and an
+// error is returned when that text is not JSON.
+func ToolResultToStructured(result *mcp.CallToolResult) (interface{}, error) {
+	if result == nil {
+		return nil, errors.New("tool result is nil")
+	}
+	if result.StructuredContent != nil && !result.IsError {
+		return result.StructuredContent, nil
+	}
+
+	text, err := ToolResultToString(result)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(text), &value); err != nil {
+		return nil, fmt.Errorf("tool result has no structured content and its text is not JSON: %w", err)
+	}
+	return value, nil
+}
+
 // parseTopLevelJSONValues decodes one or more top-level JSON values from the
 // provided string. It returns a slice with each decoded value. This handles
 // concatenated JSON objects, arrays, and primitive values robustly.
@@ -478,103 +700,287 @@ func parseTopLevelJSONValues(s string) ([]interface{}, error) {
 }
 
 // WriteCombinedStepOutputs writes aggregated outputs for any workflow steps
-// that declare a step-level OutputFilePath. It returns a list of display
-// paths (relative to the workflow when possible) for files written.
+// that declare a step-level OutputFilePath, followed by the merged output of
+// every step group whose steps have all run. It returns a list of display
+// paths (relative to the workflow when possible) for files written, and an
+// error joining every output that could not be combined or written.
 func WriteCombinedStepOutputs(workflowPath string, wf *Workflow, results map[string]map[string]StepResult) ([]string, error) {
 	var written []string
+	var errs []error
 	workflowDir := filepath.Dir(workflowPath)
 
+	write := func(path, content string) {
+		if err := output.WriteOutput(path, content+"\n"); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write combined output %s: %w", path, err))
+			return
+		}
+		display := path
+		if rel, relErr := filepath.Rel(workflowDir, path); relErr == nil && !strings.HasPrefix(rel, "..") {
+			display = rel
+		}
+		written = append(written, display)
+	}
+
+	// Steps sharing an output file are combined with the workflow's combine_strategy
+	var paths []string
+	contents := make(map[string][]string)
 	for _, step := range wf.Steps {
 		if strings.TrimSpace(step.OutputFilePath) == "" {
 			continue
 		}
-
-		outName := "Result"
-		if step.Output != nil && step.Output.Name != "" {
-			outName = step.Output.Name
+		content, ok := combinedStepContent(step, results)
+		if !ok {
+			continue
+		}
+		path := ResolveRelativePath(workflowPath, step.OutputFilePath)
+		if _, seen := contents[path]; !seen {
+			paths = append(paths, path)
+		}
+		contents[path] = append(contents[path], content)
+	}
+	for _, path := range paths {
+		combined, err := combineOutputs(wf.CombineStrategy, contents[path])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to combine output %s: %w", path, err))
+			continue
 		}
-		stepOutputs, ok := results[step.Name]
+		write(path, combined)
+	}
+
+	for _, group := range wf.groupNames() {
+		merged, ok := mergeGroupOutputs(wf, group, results)
 		if !ok {
 			continue
 		}
-		sr, ok := stepOutputs[outName]
-		if !ok || strings.TrimSpace(sr.Value) == "" {
+		data, err := json.MarshalIndent(merged, "", "  ")
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to marshal group %s output: %w", group, err))
 			continue
 		}
+		write(ResolveRelativePath(workflowPath, wf.groupOutputPath(group)), string(data))
+	}
 
-		combinedPath := ResolveRelativePath(workflowPath, step.OutputFilePath)
+	return written, errors.Join(errs...)
+}
 
-		contentToWrite := sr.Value
-		if strings.EqualFold(step.Output.Format, "json") || strings.EqualFold(sr.Format, "json") {
-			vals, perr := parseTopLevelJSONValues(sr.Value)
-			if perr == nil && len(vals) > 0 {
-				// Normalize into a single array of items for the `data` field.
-				var dataArray []interface{}
-				if len(vals) == 1 {
-					if arr, ok := vals[0].([]interface{}); ok {
-						dataArray = arr
-					} else {
-						dataArray = []interface{}{vals[0]}
-					}
+// combineOutputs joins the outputs written to one file using a combine strategy:
+// concat joins them with newlines, json_array wraps each output as an array
+// element, json_merge deep merges JSON outputs and last_only keeps the final one
+func combineOutputs(strategy string, outputs []string) (string, error) {
+	if len(outputs) == 0 {
+		return "", errors.New("no outputs to combine")
+	}
+
+	switch strategy {
+	case "", CombineConcat:
+		return strings.Join(outputs, "\n"), nil
+	case CombineLastOnly:
+		return outputs[len(outputs)-1], nil
+	case CombineJSONArray:
+		values := make([]interface{}, 0, len(outputs))
+		for _, out := range outputs {
+			var value interface{}
+			if err := json.Unmarshal([]byte(out), &value); err != nil {
+				value = out
+			}
+			values = append(values, value)
+		}
+		data, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case CombineJSONMerge:
+		var merged interface{}
+		for i, out := range outputs {
+			var value interface{}
+			if err := json.Unmarshal([]byte(out), &value); err != nil {
+				return "", fmt.Errorf("json_merge: output %d is not valid JSON: %w", i+1, err)
+			}
+			merged = deepMergeJSON(merged, value)
+		}
+		data, err := json.MarshalIndent(merged, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	default:
+		return "", fmt.Errorf("unknown combine_strategy %q", strategy)
+	}
+}
+
+// deepMergeJSON merges src into dst: objects merge key by key, arrays are
+// concatenated and any other value in src replaces the one in dst
+func deepMergeJSON(dst, src interface{}) interface{} {
+	switch srcValue := src.(type) {
+	case map[string]interface{}:
+		dstMap, ok := dst.(map[string]interface{})
+		if !ok {
+			return srcValue
+		}
+		for key, value := range srcValue {
+			dstMap[key] = deepMergeJSON(dstMap[key], value)
+		}
+		return dstMap
+	case []interface{}:
+		if dstArray, ok := dst.([]interface{}); ok {
+			return append(dstArray, srcValue...)
+		}
+		return srcValue
+	default:
+		return src
+	}
+}
+
+// combinedStepContent returns the content written for a step's output. JSON
+// outputs are normalized into a {"data": [...]} wrapper so looped tool results
+// share one shape.
+func combinedStepContent(step Step, results map[string]map[string]StepResult) (string, bool) {
+	outName := "Result"
+	if step.Output != nil && step.Output.Name != "" {
+		outName = step.Output.Name
+	}
+	stepOutputs, ok := results[step.Name]
+	if !ok {
+		return "", false
+	}
+	sr, ok := stepOutputs[outName]
+	if !ok || strings.TrimSpace(sr.Value) == "" {
+		return "", false
+	}
+
+	contentToWrite := sr.Value
+	if (step.Output != nil && strings.EqualFold(step.Output.Format, "json")) || strings.EqualFold(sr.Format, "json") {
+		vals, perr := parseTopLevelJSONValues(sr.Value)
+		if perr == nil && len(vals) > 0 {
+			// Normalize into a single array of items for the `data` field.
+			var dataArray []interface{}
+			if len(vals) == 1 {
+				if arr, ok := vals[0].([]interface{}); ok {
+					dataArray = arr
 				} else {
-					dataArray = vals
+					dataArray = []interface{}{vals[0]}
 				}
+			} else {
+				dataArray = vals
+			}
 
-				// Inject a computed `package` field (basename without extension)
-				for i := range dataArray {
-					if obj, ok := dataArray[i].(map[string]interface{}); ok {
-						if f, ok := obj["file"].(string); ok && f != "" {
-							base := filepath.Base(f)
-							name := strings.TrimSuffix(base, filepath.Ext(base))
-							obj["package"] = name
-						}
-
-						// Normalize tool-specific fields into a generic "results" key so
-						// templates can render any looped tool output without knowing
-						// field names like "analysis". Always set results; drop legacy keys.
-						switch {
-						case obj["results"] != nil:
-							// keep existing
-						case obj["data"] != nil:
-							obj["results"] = obj["data"]
-						case obj["analysis"] != nil:
-							obj["results"] = obj["analysis"]
-						case obj["message"] != nil:
-							obj["results"] = obj["message"]
-						default:
-							obj["results"] = obj
-						}
-						// Remove legacy keys so the combined JSON is tool-agnostic
-						delete(obj, "analysis")
-						delete(obj, "message")
-						delete(obj, "data")
+			// Inject a computed `package` field (basename without extension)
+			for i := range dataArray {
+				if obj, ok := dataArray[i].(map[string]interface{}); ok {
+					if f, ok := obj["file"].(string); ok && f != "" {
+						base := filepath.Base(f)
+						name := strings.TrimSuffix(base, filepath.Ext(base))
+						obj["package"] = name
+					}
 
-						dataArray[i] = obj
+					// Normalize tool-specific fields into a generic "results" key so
+					// templates can render any looped tool output without knowing
+					// field names like "analysis". Always set results; drop legacy keys.
+					switch {
+					case obj["results"] != nil:
+						// keep existing
+					case obj["data"] != nil:
+						obj["results"] = obj["data"]
+					case obj["analysis"] != nil:
+						obj["results"] = obj["analysis"]
+					case obj["message"] != nil:
+						obj["results"] = obj["message"]
+					default:
+						obj["results"] = obj
 					}
+					// Remove legacy keys so the combined JSON is tool-agnostic
+					delete(obj, "analysis")
+					delete(obj, "message")
+					delete(obj, "data")
+
+					dataArray[i] = obj
 				}
-				wrapper := map[string]interface{}{"data": dataArray}
-				if data, err := json.MarshalIndent(wrapper, "", "  "); err == nil {
-					contentToWrite = string(data)
-				}
+			}
+			wrapper := map[string]interface{}{"data": dataArray}
+			if data, err := json.MarshalIndent(wrapper, "", "  "); err == nil {
+				contentToWrite = string(data)
 			}
 		}
+	}
+	return contentToWrite, true
+}
+
+// groupNames returns the step group names in the order they first appear
+func (wf *Workflow) groupNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, step := range wf.Steps {
+		if step.Group != "" && !seen[step.Group] {
+			seen[step.Group] = true
+			names = append(names, step.Group)
+		}
+	}
+	return names
+}
 
-		if err := os.MkdirAll(filepath.Dir(combinedPath), 0o755); err != nil {
-			fmt.Fprintf(os.Stderr, "failed to create combined output dir %s: %v\n", filepath.Dir(combinedPath), err)
+// groupOutputPath returns the combined output file of a step group
+func (wf *Workflow) groupOutputPath(group string) string {
+	if path := strings.TrimSpace(wf.Groups[group]); path != "" {
+		return path
+	}
+	return group + ".json"
+}
+
+// mergeGroupOutputs merges the outputs of a step group in the same shape as the
+// merge_json tool: {"root": {<name>: <output>}}, keyed by each step's output
+// file base name (or the step name when it has none). It reports false until
+// every enabled step in the group has produced a result.
+func mergeGroupOutputs(wf *Workflow, group string, results map[string]map[string]StepResult) (map[string]interface{}, bool) {
+	merged := make(map[string]interface{})
+	for _, step := range wf.Steps {
+		if step.Group != group || !step.Enabled {
 			continue
 		}
-		if err := os.WriteFile(combinedPath, []byte(contentToWrite+"\n"), 0o644); err != nil {
-			fmt.Fprintf(os.Stderr, "failed to write combined output %s: %v\n", combinedPath, err)
+		if _, ran := results[step.Name]; !ran {
+			return nil, false
+		}
+		content, ok := combinedStepContent(step, results)
+		if !ok {
 			continue
 		}
-		display := combinedPath
-		if rel, relErr := filepath.Rel(workflowDir, combinedPath); relErr == nil && !strings.HasPrefix(rel, "..") {
-			display = rel
+
+		key := step.Name
+		if step.OutputFilePath != "" {
+			base := filepath.Base(step.OutputFilePath)
+			if name := strings.TrimSuffix(base, filepath.Ext(base)); merged[name] == nil {
+				key = name
+			}
 		}
-		written = append(written, display)
+
+		var value interface{}
+		if err := json.Unmarshal([]byte(content), &value); err != nil {
+			value = content
+		}
+		merged[key] = value
 	}
+	if len(merged) == 0 {
+		return nil, false
+	}
+	return map[string]interface{}{"root": merged}, true
+}
+
+// runStep invokes the runner with a context that is cancelled once the step's timeout_seconds elapse,
+// so handlers watching ctx.Done() stop instead of running on in the background
+func runStep(ctx context.Context, runner RunnerFunc, step Step, tool string, params map[string]interface{}) (string, error) {
+	if step.TimeoutSeconds <= 0 {
+		return runner(ctx, tool, params)
+	}
+
+	timeout := time.Duration(step.TimeoutSeconds) * time.Second
+	stepCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	return written, nil
+	output, err := runner(stepCtx, tool, params)
+	if ctx.Err() == nil && errors.Is(stepCtx.Err(), context.DeadlineExceeded) {
+		return "", fmt.Errorf("timed out after %s: %w", timeout, context.DeadlineExceeded)
+	}
+	return output, err
 }
 
 // RunFile loads the workflow at the given path and executes it using the
@@ -585,7 +991,29 @@ func RunFile(ctx context.Context, workflowPath string, runner RunnerFunc) (*Work
 		return nil, nil, errors.New("runner cannot be nil")
 	}
 
-	wf, err := LoadFromFile(workflowPath)
+	data, err := os.ReadFile(workflowPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return run(ctx, data, workflowPath, runner)
+}
+
+// RunString parses a JSON or YAML workflow definition from content and
+// executes it using the provided RunnerFunc. Without a workflow file there is
+// no base directory, so step OutputFilePath values are not written.
+func RunString(ctx context.Context, content string, runner RunnerFunc) (*Workflow, map[string]map[string]StepResult, error) {
+	if runner == nil {
+		return nil, nil, errors.New("runner cannot be nil")
+	}
+
+	return run(ctx, []byte(content), "", runner)
+}
+
+// run parses and executes a workflow definition; workflowPath is empty for
+// inline definitions.
+func run(ctx context.Context, data []byte, workflowPath string, runner RunnerFunc) (*Workflow, map[string]map[string]StepResult, error) {
+	wf, err := parseWorkflow(data, workflowPath)
 	if err != nil {
 		return nil, nil, err
 	}
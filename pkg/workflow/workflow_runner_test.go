@@ -3,10 +3,14 @@ package workflow
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
 )
 
 func TestRunFile_InvokesRunnerAndReturnsResults(t *testing.T) {
@@ -45,6 +49,46 @@ func TestRunFile_InvokesRunnerAndReturnsResults(t *testing.T) {
 	}
 }
 
+func TestRunString_ParsesJSONAndYAMLDefinitions(t *testing.T) {
+	definitions := map[string]string{
+		"json": `{"Steps":[{"Name":"S1","Type":"#dummy","Parameters":{"file_path":"a.dtsx"},"Enabled":true}]}`,
+		"yaml": "Steps:\n  - Name: S1\n    Type: \"#dummy\"\n    Parameters:\n      file_path: a.dtsx\n    Enabled: true\n",
+	}
+	for format, content := range definitions {
+		runner := func(ctx context.Context, tool string, params map[string]interface{}) (string, error) {
+			if tool != "dummy" || params["file_path"] != "a.dtsx" {
+				t.Fatalf("%s: unexpected invocation %s %v", format, tool, params)
+			}
+			return "hello", nil
+		}
+
+		wf, results, err := RunString(context.Background(), content, runner)
+		if err != nil {
+			t.Fatalf("%s: RunString failed: %v", format, err)
+		}
+		if wf == nil || len(wf.Steps) != 1 {
+			t.Fatalf("%s: expected workflow with one step, got %+v", format, wf)
+		}
+		if out := results["S1"]["Result"]; out.Value != "hello" {
+			t.Fatalf("%s: unexpected result value: %s", format, out.Value)
+		}
+	}
+}
+
+func TestRunString_ReportsInvalidDefinition(t *testing.T) {
+	runner := func(ctx context.Context, tool string, params map[string]interface{}) (string, error) {
+		t.Fatalf("runner should not be invoked")
+		return "", nil
+	}
+
+	if _, _, err := RunString(context.Background(), `{"Steps": [`, runner); err == nil || !strings.Contains(err.Error(), "failed to parse workflow") {
+		t.Fatalf("expected parse error, got %v", err)
+	}
+	if _, _, err := RunString(context.Background(), `{"Steps": []}`, runner); err == nil {
+		t.Fatalf("expected validation error for workflow without steps")
+	}
+}
+
 func TestWriteCombinedStepOutputs_WritesJSONArray(t *testing.T) {
 	dir, err := os.MkdirTemp("", "wfout")
 	if err != nil {
@@ -212,3 +256,557 @@ func TestWriteCombinedStepOutputs_WritesPlainTextUnchanged(t *testing.T) {
 		t.Fatalf("expected newline suffix in written file")
 	}
 }
+
+// writeSharedOutput writes two text steps that share one output file and returns the file content
+func writeSharedOutput(t *testing.T, strategy, first, second string) string {
+	t.Helper()
+	dir := t.TempDir()
+	wf := &Workflow{
+		CombineStrategy: strategy,
+		Steps: []Step{
+			{Name: "First", OutputFilePath: "out/shared.txt"},
+			{Name: "Second", OutputFilePath: "out/shared.txt"},
+		},
+	}
+	results := map[string]map[string]StepResult{
+		"First":  {"Result": {Value: first}},
+		"Second": {"Result": {Value: second}},
+	}
+
+	written, err := WriteCombinedStepOutputs(filepath.Join(dir, "wf.json"), wf, results)
+	if err != nil {
+		t.Fatalf("WriteCombinedStepOutputs failed: %v", err)
+	}
+	if len(written) != 1 {
+		t.Fatalf("expected one written file, got %v", written)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "out", "shared.txt"))
+	if err != nil {
+		t.Fatalf("failed to read combined file: %v", err)
+	}
+	return string(data)
+}
+
+func TestWriteCombinedStepOutputs_ConcatStrategy(t *testing.T) {
+	for _, strategy := range []string{"", CombineConcat} {
+		if got := writeSharedOutput(t, strategy, "first output", "second output"); got != "first output\nsecond output\n" {
+			t.Fatalf("strategy %q: expected concatenated outputs, got %q", strategy, got)
+		}
+	}
+}
+
+func TestWriteCombinedStepOutputs_JSONArrayStrategy(t *testing.T) {
+	got := writeSharedOutput(t, CombineJSONArray, `{"tasks": 3}`, "plain text")
+	var values []interface{}
+	if err := json.Unmarshal([]byte(got), &values); err != nil {
+		t.Fatalf("expected a JSON array, got %q: %v", got, err)
+	}
+	if len(values) != 2 {
+		t.Fatalf("expected one element per step, got %v", values)
+	}
+	if obj, ok := values[0].(map[string]interface{}); !ok || obj["tasks"] != float64(3) {
+		t.Fatalf("expected JSON output to be embedded as an object, got %v", values[0])
+	}
+	if values[1] != "plain text" {
+		t.Fatalf("expected non-JSON output to be embedded as a string, got %v", values[1])
+	}
+}
+
+func TestWriteCombinedStepOutputs_JSONMergeStrategy(t *testing.T) {
+	got := writeSharedOutput(t, CombineJSONMerge,
+		`{"package": "Load", "stats": {"tasks": 3, "warnings": 1}, "files": ["a.dtsx"]}`,
+		`{"stats": {"warnings": 2, "errors": 0}, "files": ["b.dtsx"]}`)
+	var merged map[string]interface{}
+	if err := json.Unmarshal([]byte(got), &merged); err != nil {
+		t.Fatalf("expected a JSON object, got %q: %v", got, err)
+	}
+	stats, _ := merged["stats"].(map[string]interface{})
+	if merged["package"] != "Load" || stats["tasks"] != float64(3) || stats["warnings"] != float64(2) || stats["errors"] != float64(0) {
+		t.Fatalf("expected nested objects to be deep merged, got %v", merged)
+	}
+	if files, _ := merged["files"].([]interface{}); len(files) != 2 {
+		t.Fatalf("expected arrays to be concatenated, got %v", merged["files"])
+	}
+}
+
+func TestWriteCombinedStepOutputs_JSONMergeRejectsNonJSON(t *testing.T) {
+	dir := t.TempDir()
+	wfPath := filepath.Join(dir, "wf.json")
+	wf := &Workflow{
+		CombineStrategy: CombineJSONMerge,
+		Steps: []Step{
+			{Name: "Stats", Type: "#stats", Enabled: true, OutputFilePath: "out/merged.json"},
+			{Name: "Notes", Type: "#notes", Enabled: true, OutputFilePath: "out/merged.json"},
+		},
+	}
+	runner := func(_ context.Context, tool string, _ map[string]interface{}) (string, error) {
+		if tool == "notes" {
+			return "plain text", nil
+		}
+		return `{"tasks": 3}`, nil
+	}
+
+	_, err := wf.Execute(context.Background(), runner, wfPath)
+	if err == nil || !strings.Contains(err.Error(), "step Notes") || !strings.Contains(err.Error(), "json_merge: output 2 is not valid JSON") {
+		t.Fatalf("expected the workflow to fail on the non-JSON output, got %v", err)
+	}
+
+	results := map[string]map[string]StepResult{"Notes": {"Result": {Value: "not json"}}}
+	written, err := WriteCombinedStepOutputs(wfPath, wf, results)
+	if err == nil || len(written) != 0 {
+		t.Fatalf("expected a combine error and no file for non-JSON output, got %v (err=%v)", written, err)
+	}
+}
+
+func TestWriteCombinedStepOutputs_LastOnlyStrategy(t *testing.T) {
+	if got := writeSharedOutput(t, CombineLastOnly, "first output", "second output"); got != "second output\n" {
+		t.Fatalf("expected only the last output, got %q", got)
+	}
+}
+
+func TestValidateRejectsUnknownCombineStrategy(t *testing.T) {
+	wf := &Workflow{CombineStrategy: "zip", Steps: []Step{{Name: "S1", Type: "#dummy", Enabled: true}}}
+	if err := wf.Validate(); err == nil || !strings.Contains(err.Error(), "combine_strategy") {
+		t.Fatalf("expected a combine_strategy validation error, got %v", err)
+	}
+}
+
+func TestExecute_PipesStringOutputToNextStep(t *testing.T) {
+	wf := &Workflow{
+		Steps: []Step{
+			{Name: "Read", Type: "#read", Enabled: true, PipeOutputTo: "content"},
+			{Name: "Write", Type: "#write", Enabled: true, Parameters: map[string]interface{}{"file_path": "out.txt"}},
+			{Name: "Report", Type: "#report", Enabled: true},
+		},
+	}
+
+	received := make(map[string]map[string]interface{})
+	runner := func(ctx context.Context, tool string, params map[string]interface{}) (string, error) {
+		received[tool] = params
+		if tool == "read" {
+			return "plain text output", nil
+		}
+		return "ok", nil
+	}
+
+	if _, err := wf.Execute(context.Background(), runner, ""); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if received["write"]["content"] != "plain text output" {
+		t.Fatalf("expected piped content, got %v", received["write"]["content"])
+	}
+	if received["write"]["file_path"] != "out.txt" {
+		t.Fatalf("expected explicit parameters to be kept, got %v", received["write"])
+	}
+	if _, exists := received["report"]["content"]; exists {
+		t.Fatalf("expected piped output to apply only to the next step, got %v", received["report"])
+	}
+}
+
+func TestExecute_StepTimeoutCancelsRunnerContext(t *testing.T) {
+	wf := &Workflow{
+		Steps: []Step{
+			{Name: "Slow", Type: "#slow", Enabled: true, TimeoutSeconds: 1},
+			{Name: "Next", Type: "#next", Enabled: true},
+		},
+	}
+
+	var handlerErr error
+	ran := make(map[string]bool)
+	runner := func(ctx context.Context, tool string, params map[string]interface{}) (string, error) {
+		ran[tool] = true
+		if tool == "slow" {
+			// A handler that honours cancellation returns as soon as the step context is done
+			<-ctx.Done()
+			handlerErr = ctx.Err()
+			return "", handlerErr
+		}
+		return "ok", nil
+	}
+
+	_, err := wf.Execute(context.Background(), runner, "")
+	if err == nil || !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "step Slow: timed out after 1s") {
+		t.Fatalf("expected a step timeout error, got %v", err)
+	}
+	if !errors.Is(handlerErr, context.DeadlineExceeded) {
+		t.Fatalf("expected the runner context to be cancelled, got %v", handlerErr)
+	}
+	if ran["next"] {
+		t.Fatal("expected the workflow to stop after the timed out step")
+	}
+}
+
+func TestValidateRejectsNegativeTimeout(t *testing.T) {
+	wf := &Workflow{Steps: []Step{{Name: "S1", Type: "#dummy", Enabled: true, TimeoutSeconds: -1}}}
+	if err := wf.Validate(); err == nil || !strings.Contains(err.Error(), "timeout_seconds") {
+		t.Fatalf("expected a timeout_seconds validation error, got %v", err)
+	}
+}
+
+func TestExecute_PipesJSONOutputAndSkipsDisabledSteps(t *testing.T) {
+	wf := &Workflow{
+		Steps: []Step{
+			{Name: "Extract", Type: "#extract", Enabled: true, PipeOutputTo: "json_data"},
+			{Name: "Skipped", Type: "#skipped", Enabled: false},
+			{Name: "Convert", Type: "#convert", Enabled: true, Parameters: map[string]interface{}{"format": "html"}},
+		},
+	}
+
+	jsonOutput := `{"tasks":[{"name":"Load"},{"name":"Archive"}]}`
+	var convertParams map[string]interface{}
+	runner := func(ctx context.Context, tool string, params map[string]interface{}) (string, error) {
+		switch tool {
+		case "extract":
+			return jsonOutput, nil
+		case "convert":
+			convertParams = params
+		}
+		return "ok", nil
+	}
+
+	if _, err := wf.Execute(context.Background(), runner, ""); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	data, ok := convertParams["json_data"].(string)
+	if !ok {
+		t.Fatalf("expected json_data to be piped as a string, got %v", convertParams)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &decoded); err != nil {
+		t.Fatalf("expected piped JSON to remain valid: %v", err)
+	}
+	if tasks, _ := decoded["tasks"].([]interface{}); len(tasks) != 2 {
+		t.Fatalf("unexpected piped JSON content: %s", data)
+	}
+}
+
+func TestExecuteMergesStepGroupOutputs(t *testing.T) {
+	dir := t.TempDir()
+	wfPath := filepath.Join(dir, "wf.json")
+	wf := &Workflow{
+		Groups: map[string]string{"review": "reports/review.json"},
+		Steps: []Step{
+			{Name: "Logging", Type: "analyze_logging_configuration", Enabled: true, Group: "review", OutputFilePath: "out/logging.json", Output: &StepOutput{Name: "Result", Format: "json"}},
+			{Name: "Summary", Type: "ask_about_dtsx", Enabled: true, Group: "review"},
+			{Name: "Other", Type: "list_packages", Enabled: true},
+		},
+	}
+
+	runner := func(_ context.Context, tool string, _ map[string]interface{}) (string, error) {
+		switch tool {
+		case "analyze_logging_configuration":
+			return `{"file":"Package1.dtsx","analysis":"ok"}`, nil
+		case "ask_about_dtsx":
+			return "Package Summary", nil
+		default:
+			return "[]", nil
+		}
+	}
+
+	results, err := wf.Execute(context.Background(), runner, wfPath)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	written, err := WriteCombinedStepOutputs(wfPath, wf, results)
+	if err != nil {
+		t.Fatalf("WriteCombinedStepOutputs failed: %v", err)
+	}
+	if len(written) != 2 || written[1] != filepath.Join("reports", "review.json") {
+		t.Fatalf("expected the step output and the group output to be written, got %v", written)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "reports", "review.json"))
+	if err != nil {
+		t.Fatalf("failed to read group output: %v", err)
+	}
+	var merged struct {
+		Root map[string]interface{} `json:"root"`
+	}
+	if err := json.Unmarshal(data, &merged); err != nil {
+		t.Fatalf("group output is not valid JSON: %v", err)
+	}
+	if len(merged.Root) != 2 || merged.Root["Summary"] != "Package Summary" {
+		t.Fatalf("unexpected group output: %s", data)
+	}
+	logging, ok := merged.Root["logging"].(map[string]interface{})
+	if !ok || logging["data"] == nil {
+		t.Fatalf("expected the logging step's combined output under its file name, got %s", data)
+	}
+}
+
+func TestWriteCombinedStepOutputsWaitsForWholeGroup(t *testing.T) {
+	dir := t.TempDir()
+	wfPath := filepath.Join(dir, "wf.json")
+	wf := &Workflow{
+		Steps: []Step{
+			{Name: "A", Type: "x", Enabled: true, Group: "batch"},
+			{Name: "B", Type: "x", Enabled: true, Group: "batch"},
+		},
+	}
+	results := map[string]map[string]StepResult{"A": {"Result": {Value: `{"a":1}`}}}
+
+	written, err := WriteCombinedStepOutputs(wfPath, wf, results)
+	if err != nil {
+		t.Fatalf("WriteCombinedStepOutputs failed: %v", err)
+	}
+	if len(written) != 0 {
+		t.Fatalf("expected no group output before every step has run, got %v", written)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "batch.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected batch.json not to exist yet, got %v", err)
+	}
+}
+
+func TestValidateRejectsGroupWithoutSteps(t *testing.T) {
+	wf := &Workflow{
+		Groups: map[string]string{"missing": "missing.json"},
+		Steps:  []Step{{Name: "A", Type: "x"}},
+	}
+	if err := wf.Validate(); err == nil || !strings.Contains(err.Error(), "group missing has no steps") {
+		t.Fatalf("expected an error for a group without steps, got %v", err)
+	}
+}
+
+func dryRunLookup(tool string, _ map[string]interface{}) ([]string, bool) {
+	switch tool {
+	case "list_packages":
+		return nil, true
+	case "extract_tasks":
+		return []string{"file_path"}, true
+	case "merge_json":
+		return []string{"file_paths"}, true
+	default:
+		return nil, false
+	}
+}
+
+func TestDryRunReportsMissingRequiredParameter(t *testing.T) {
+	wf := &Workflow{Steps: []Step{
+		{Name: "List", Type: "#list_packages", Enabled: true, Output: &StepOutput{Name: "Content", Format: "json"}},
+		{Name: "Tasks", Type: "#extract_tasks", Enabled: true, Parameters: map[string]interface{}{"format": "json"}},
+	}}
+
+	issues := wf.DryRun(dryRunLookup)
+	if len(issues) != 1 || issues[0].Step != "Tasks" || !strings.Contains(issues[0].Message, `missing required parameter "file_path"`) {
+		t.Fatalf("expected a missing file_path issue, got %+v", issues)
+	}
+}
+
+func TestDryRunReportsUnknownTool(t *testing.T) {
+	wf := &Workflow{Steps: []Step{
+		{Name: "Mystery", Type: "#does_not_exist", Enabled: true},
+		{Name: "Skipped", Type: "#also_unknown", Enabled: false},
+	}}
+
+	issues := wf.DryRun(dryRunLookup)
+	if len(issues) != 1 || issues[0].Step != "Mystery" || !strings.Contains(issues[0].Message, `unknown tool "does_not_exist"`) {
+		t.Fatalf("expected an unknown tool issue for the enabled step only, got %+v", issues)
+	}
+}
+
+func TestDryRunAcceptsPipedAndReferencedParameters(t *testing.T) {
+	wf := &Workflow{Steps: []Step{
+		{Name: "List", Type: "#list_packages", Enabled: true, Output: &StepOutput{Name: "Content", Format: "json"}, PipeOutputTo: "file_path"},
+		{Name: "Tasks", Type: "#extract_tasks", Enabled: true},
+		{Name: "Merge", Type: "#merge_json", Enabled: true, Parameters: map[string]interface{}{"file_paths": []interface{}{"{List.Content.packages}", "{Tasks.Missing}", "{Later.Result}"}}},
+		{Name: "Later", Type: "#list_packages", Enabled: true},
+	}}
+
+	issues := wf.DryRun(dryRunLookup)
+	if len(issues) != 2 {
+		t.Fatalf("expected two placeholder issues, got %+v", issues)
+	}
+	if !strings.Contains(issues[0].Message, `refers to output "Missing", but step "Tasks" outputs "Result"`) ||
+		!strings.Contains(issues[1].Message, `refers to step "Later", which is not an earlier enabled step`) {
+		t.Fatalf("unexpected placeholder issues: %+v", issues)
+	}
+}
+
+func TestDryRunFileReportsLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	wfPath := filepath.Join(dir, "wf.json")
+	if err := os.WriteFile(wfPath, []byte(`{"Steps": [{"Name": "A"}]}`), 0o644); err != nil {
+		t.Fatalf("failed to write workflow: %v", err)
+	}
+
+	report := DryRunFile(wfPath, dryRunLookup)
+	if report.Valid || len(report.Issues) != 1 || !strings.Contains(report.Issues[0].Message, "missing a Type") {
+		t.Fatalf("expected a load error issue, got %+v", report)
+	}
+}
+
+func TestRunString_StoresStepOutputForLaterSteps(t *testing.T) {
+	content := `{"Steps":[
+		{"Name":"List","Type":"#list_packages","Parameters":{"directory":"pkgs"},"Enabled":true,"store_as":"packages_list"},
+		{"Name":"Log","Type":"#log","Parameters":{"message":"done"},"Enabled":true},
+		{"Name":"Batch","Type":"#batch_analyze","Parameters":{"file_paths":"{{.packages_list}}","note":"found {{ .packages_list }}"},"Enabled":true}
+	]}`
+
+	received := make(map[string]map[string]interface{})
+	runner := func(ctx context.Context, tool string, params map[string]interface{}) (string, error) {
+		received[tool] = params
+		if tool == "list_packages" {
+			return `["a.dtsx","b.dtsx"]`, nil
+		}
+		return "ok", nil
+	}
+
+	if _, _, err := RunString(context.Background(), content, runner); err != nil {
+		t.Fatalf("RunString failed: %v", err)
+	}
+	if received["batch_analyze"]["file_paths"] != `["a.dtsx","b.dtsx"]` {
+		t.Fatalf("expected stored output to be interpolated, got %v", received["batch_analyze"]["file_paths"])
+	}
+	if received["batch_analyze"]["note"] != `found ["a.dtsx","b.dtsx"]` {
+		t.Fatalf("expected stored output to be interpolated inside text, got %v", received["batch_analyze"]["note"])
+	}
+}
+
+func TestRunString_RejectsUnknownVariable(t *testing.T) {
+	content := `{"Steps":[{"Name":"Batch","Type":"#batch_analyze","Parameters":{"file_paths":"{{.packages_list}}"},"Enabled":true}]}`
+	runner := func(ctx context.Context, tool string, params map[string]interface{}) (string, error) {
+		t.Fatalf("runner should not be invoked")
+		return "", nil
+	}
+
+	if _, _, err := RunString(context.Background(), content, runner); err == nil || !strings.Contains(err.Error(), `variable "packages_list" has not been stored`) {
+		t.Fatalf("expected unknown variable error, got %v", err)
+	}
+}
+
+func TestDryRunReportsVariableNotStoredEarlier(t *testing.T) {
+	wf := &Workflow{Steps: []Step{
+		{Name: "Merge", Type: "#merge_json", Enabled: true, Parameters: map[string]interface{}{"file_paths": "{{.packages_list}}"}},
+		{Name: "List", Type: "#list_packages", Enabled: true, StoreAs: "packages_list"},
+		{Name: "MergeAgain", Type: "#merge_json", Enabled: true, Parameters: map[string]interface{}{"file_paths": "{{.packages_list}}"}},
+	}}
+
+	issues := wf.DryRun(dryRunLookup)
+	if len(issues) != 1 || issues[0].Step != "Merge" || !strings.Contains(issues[0].Message, "variable {{.packages_list}} is not stored") {
+		t.Fatalf("expected a single unstored variable issue, got %+v", issues)
+	}
+}
+
+func TestRunString_ContainsAssertionFailsWorkflow(t *testing.T) {
+	content := `{"Steps":[
+		{"Name":"Validate","Type":"#validate_best_practices","Parameters":{},"Enabled":true,"assert":{"contains":"0 findings"}},
+		{"Name":"Log","Type":"#log","Parameters":{},"Enabled":true}
+	]}`
+
+	calls := 0
+	runner := func(ctx context.Context, tool string, params map[string]interface{}) (string, error) {
+		calls++
+		return "3 findings", nil
+	}
+
+	if _, _, err := RunString(context.Background(), content, runner); err == nil || !strings.Contains(err.Error(), `step Validate assertion failed: output does not contain "0 findings"`) {
+		t.Fatalf("expected contains assertion failure, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected the workflow to abort after the failed step, got %d runner calls", calls)
+	}
+}
+
+func TestRunString_ContainsAssertionPasses(t *testing.T) {
+	content := `{"Steps":[{"Name":"Validate","Type":"#validate_best_practices","Parameters":{},"Enabled":true,"assert":{"contains":"0 findings"}}]}`
+	runner := func(ctx context.Context, tool string, params map[string]interface{}) (string, error) {
+		return "Completed with 0 findings", nil
+	}
+
+	_, results, err := RunString(context.Background(), content, runner)
+	if err != nil {
+		t.Fatalf("RunString failed: %v", err)
+	}
+	if results["Validate"]["Result"].Failure != "" {
+		t.Fatalf("expected no assertion failure, got %q", results["Validate"]["Result"].Failure)
+	}
+}
+
+func TestRunString_JSONPathAssertion(t *testing.T) {
+	runner := func(ctx context.Context, tool string, params map[string]interface{}) (string, error) {
+		return `{"summary":{"total_count":2}}`, nil
+	}
+
+	passing := `{"Steps":[{"Name":"Scan","Type":"#scan_credentials","Parameters":{},"Enabled":true,"assert":{"json_path":"summary.total_count","equals":2}}]}`
+	if _, _, err := RunString(context.Background(), passing, runner); err != nil {
+		t.Fatalf("expected json_path assertion to pass, got %v", err)
+	}
+
+	failing := `{"Steps":[{"Name":"Scan","Type":"#scan_credentials","Parameters":{},"Enabled":true,"assert":{"json_path":"summary.total_count","equals":0}}]}`
+	if _, _, err := RunString(context.Background(), failing, runner); err == nil || !strings.Contains(err.Error(), "json_path summary.total_count is 2, expected 0") {
+		t.Fatalf("expected json_path assertion failure, got %v", err)
+	}
+
+	missing := `{"Steps":[{"Name":"Scan","Type":"#scan_credentials","Parameters":{},"Enabled":true,"assert":{"json_path":"summary.missing","equals":0}}]}`
+	if _, _, err := RunString(context.Background(), missing, runner); err == nil || !strings.Contains(err.Error(), "field summary.missing not found") {
+		t.Fatalf("expected missing field failure, got %v", err)
+	}
+}
+
+func TestRunString_AssertionFailureContinuesWithoutFailFast(t *testing.T) {
+	content := `{"fail_fast":false,"Steps":[
+		{"Name":"Scan","Type":"#scan_credentials","Parameters":{},"Enabled":true,"assert":{"json_path":"total_count","equals":0}},
+		{"Name":"Log","Type":"#log","Parameters":{},"Enabled":true}
+	]}`
+	runner := func(ctx context.Context, tool string, params map[string]interface{}) (string, error) {
+		if tool == "scan_credentials" {
+			return `{"total_count":1}`, nil
+		}
+		return "logged", nil
+	}
+
+	_, results, err := RunString(context.Background(), content, runner)
+	if err != nil {
+		t.Fatalf("expected the workflow to continue, got %v", err)
+	}
+	if got := results["Scan"]["Result"].Failure; got != "json_path total_count is 1, expected 0" {
+		t.Fatalf("expected the failure to be recorded on the step, got %q", got)
+	}
+	if results["Log"]["Result"].Value != "logged" {
+		t.Fatalf("expected later steps to run, got %+v", results["Log"])
+	}
+}
+
+func TestValidate_RejectsIncompleteAssertions(t *testing.T) {
+	cases := map[string]*StepAssertion{
+		"must set contains or json_path":         {},
+		"either contains or json_path, not both": {Contains: "x", JSONPath: "y", Equals: 1},
+		"json_path requires equals":              {JSONPath: "total_count"},
+	}
+	for want, assert := range cases {
+		wf := &Workflow{Steps: []Step{{Name: "Check", Type: "#log", Enabled: true, Assert: assert}}}
+		if err := wf.Validate(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q, got %v", want, err)
+		}
+	}
+}
+
+func TestToolResultToStructured(t *testing.T) {
+	content := map[string]interface{}{"total_count": 2, "items": []string{"a", "b"}}
+	value, err := ToolResultToStructured(mcp.NewToolResultStructured(content, "2 items"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(value, content) {
+		t.Fatalf("expected the structured content itself, got %#v", value)
+	}
+
+	value, err = ToolResultToStructured(mcp.NewToolResultText(`["a.dtsx","b.dtsx"]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if items, ok := value.([]interface{}); !ok || len(items) != 2 || items[0] != "a.dtsx" {
+		t.Fatalf("expected JSON text to be decoded, got %#v", value)
+	}
+
+	if value, err := ToolResultToStructured(mcp.NewToolResultText("3 findings")); err == nil {
+		t.Fatalf("expected an error for plain text, got %#v", value)
+	}
+
+	if _, err := ToolResultToStructured(mcp.NewToolResultError("boom")); err == nil || err.Error() != "boom" {
+		t.Fatalf("expected tool error, got %v", err)
+	}
+	if _, err := ToolResultToStructured(nil); err == nil {
+		t.Fatalf("expected error for nil result")
+	}
+}
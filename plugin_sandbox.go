@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// sandboxedPathParameters are the tool arguments a plugin may use to read or write files
+var sandboxedPathParameters = []string{"file_path", "output_file_path"}
+
+// PluginSandbox wraps a plugin tool so its path arguments must resolve inside the allowed directories
+type PluginSandbox struct {
+	pluginID    string
+	executor    ToolExecutor
+	allowedDirs []string
+}
+
+// NewPluginSandbox restricts executor to paths within allowedDirs; relative paths resolve against the first directory
+func NewPluginSandbox(pluginID string, executor ToolExecutor, allowedDirs ...string) *PluginSandbox {
+	var dirs []string
+	for _, dir := range allowedDirs {
+		if dir == "" {
+			continue
+		}
+		if abs, err := filepath.Abs(dir); err == nil {
+			dirs = append(dirs, abs)
+		}
+	}
+	return &PluginSandbox{pluginID: pluginID, executor: executor, allowedDirs: dirs}
+}
+
+// Execute rejects the call with an error result when a path argument escapes the sandbox
+func (s *PluginSandbox) Execute(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	for _, name := range sandboxedPathParameters {
+		value, ok := args[name].(string)
+		if !ok || value == "" {
+			continue
+		}
+		if err := s.checkPath(value); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("sandboxed: plugin %s cannot use %s: %v", s.pluginID, name, err)), nil
+		}
+	}
+	return s.executor.Execute(ctx, request)
+}
+
+// checkPath returns an error unless path is inside one of the allowed directories
+func (s *PluginSandbox) checkPath(path string) error {
+	for _, dir := range s.allowedDirs {
+		target := path
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(s.allowedDirs[0], target)
+		}
+		rel, err := filepath.Rel(dir, filepath.Clean(target))
+		if err != nil {
+			continue
+		}
+		if rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("path %q is outside the allowed directories", path)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func callSandboxed(t *testing.T, sandbox *PluginSandbox, args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+	result, err := sandbox.Execute(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return result
+}
+
+func TestPluginSandboxRejectsPathTraversal(t *testing.T) {
+	packageDir := t.TempDir()
+	executor := &stubToolExecutor{}
+	sandbox := NewPluginSandbox("mock-plugin", executor, packageDir)
+
+	for _, args := range []map[string]interface{}{
+		{"file_path": "../../etc/passwd"},
+		{"file_path": "Package.dtsx", "output_file_path": filepath.Join(filepath.Dir(packageDir), "report.txt")},
+		{"file_path": "nested/../../outside.dtsx"},
+	} {
+		result := callSandboxed(t, sandbox, args)
+		if result == nil || !result.IsError {
+			t.Fatalf("expected a sandboxed error for %v, got %+v", args, result)
+		}
+		text := result.Content[0].(mcp.TextContent).Text
+		if !strings.HasPrefix(text, "sandboxed: plugin mock-plugin") {
+			t.Fatalf("expected sandboxed error message, got %q", text)
+		}
+	}
+	if executor.executed {
+		t.Fatal("expected plugin tool not to run for paths outside the sandbox")
+	}
+}
+
+func TestPluginSandboxAllowsPackageAndPluginDirectories(t *testing.T) {
+	packageDir := t.TempDir()
+	pluginDir := t.TempDir()
+
+	manager := NewPluginManager()
+	manager.packageDirectory = packageDir
+	manager.allowedDirectories = map[string]string{"mock-plugin": pluginDir}
+
+	for _, args := range []map[string]interface{}{
+		{"file_path": "Package.dtsx"},
+		{"file_path": filepath.Join(packageDir, "sub", "Package.dtsx")},
+		{"file_path": "Package.dtsx", "output_file_path": filepath.Join(pluginDir, "report.txt")},
+	} {
+		executor := &stubToolExecutor{}
+		sandbox := NewPluginSandbox("mock-plugin", executor, manager.sandboxDirectories("mock-plugin")...)
+		if result := callSandboxed(t, sandbox, args); result != nil && result.IsError {
+			t.Fatalf("expected %v to be allowed, got %+v", args, result)
+		}
+		if !executor.executed {
+			t.Fatalf("expected plugin tool to run for %v", args)
+		}
+	}
+
+	other := NewPluginSandbox("other-plugin", &stubToolExecutor{}, manager.sandboxDirectories("other-plugin")...)
+	if result := callSandboxed(t, other, map[string]interface{}{"file_path": filepath.Join(pluginDir, "x.dtsx")}); result == nil || !result.IsError {
+		t.Fatal("expected another plugin's allowed directory to be rejected")
+	}
+}
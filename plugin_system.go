@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/MCPRUNNER/gossisMCP/pkg/config"
+	gossismcp "github.com/MCPRUNNER/gossisMCP/pkg/plugin"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -85,9 +86,11 @@ type PluginResource struct {
 
 // PluginManager handles loading and executing plugins
 type PluginManager struct {
-	loadedPlugins map[string]*LoadedPlugin
-	registry      *PluginRegistry
-	mu            sync.RWMutex
+	loadedPlugins      map[string]*LoadedPlugin
+	registry           *PluginRegistry
+	packageDirectory   string
+	allowedDirectories map[string]string
+	mu                 sync.RWMutex
 }
 
 // LoadedPlugin represents a loaded plugin instance
@@ -119,11 +122,16 @@ type PluginMarketplace struct {
 	mu          sync.RWMutex
 }
 
-// NewPluginSystem creates a new plugin system instance
-func NewPluginSystem(config config.PluginConfig) *PluginSystem {
+// NewPluginSystem creates a new plugin system instance whose plugin tools are sandboxed to packageDirectory
+func NewPluginSystem(config config.PluginConfig, packageDirectory string) *PluginSystem {
+	registry := NewPluginRegistry()
+	manager := NewPluginManager()
+	manager.registry = registry
+	manager.packageDirectory = packageDirectory
+	manager.allowedDirectories = config.Security.AllowedDirectories
 	return &PluginSystem{
-		registry:    NewPluginRegistry(),
-		manager:     NewPluginManager(),
+		registry:    registry,
+		manager:     manager,
 		marketplace: NewPluginMarketplace(config.CommunityRegistry),
 		config:      config,
 	}
@@ -223,7 +231,7 @@ func (ps *PluginSystem) loadInstalledPlugins() error {
 
 	for _, file := range files {
 		if err := ps.manager.LoadPlugin(file); err != nil {
-			log.Printf("Failed to load plugin %s: %v", file, err)
+			slog.Warn("failed to load plugin", "plugin", file, "error", err)
 			continue
 		}
 	}
@@ -238,7 +246,7 @@ func (ps *PluginSystem) autoUpdatePlugins() {
 
 	for range ticker.C {
 		if err := ps.marketplace.UpdateCache(); err != nil {
-			log.Printf("Failed to update plugin cache: %v", err)
+			slog.Warn("failed to update plugin cache", "error", err)
 		}
 
 		// Check for updates to installed plugins
@@ -254,8 +262,8 @@ func (ps *PluginSystem) checkForUpdates() {
 	for _, loaded := range ps.manager.loadedPlugins {
 		if latest, err := ps.marketplace.GetLatestVersion(loaded.Metadata.ID); err == nil {
 			if latest.Version != loaded.Metadata.Version {
-				log.Printf("Update available for plugin %s: %s -> %s",
-					loaded.Metadata.ID, loaded.Metadata.Version, latest.Version)
+				slog.Info("plugin update available", "plugin", loaded.Metadata.ID,
+					"installed", loaded.Metadata.Version, "latest", latest.Version)
 			}
 		}
 	}
@@ -302,15 +310,17 @@ func (pm *PluginManager) LoadPlugin(path string) error {
 	}
 
 	// Get plugin metadata
-	sym, err := p.Lookup("PluginMetadata")
+	sym, err := p.Lookup(gossismcp.MetadataSymbol)
 	if err != nil {
 		return fmt.Errorf("plugin metadata not found: %w", err)
 	}
 
-	metadata, ok := sym.(*PluginMetadata)
+	metadataFunc, ok := sym.(func() gossismcp.PluginInfo)
 	if !ok {
-		return fmt.Errorf("invalid plugin metadata type")
+		return fmt.Errorf("invalid plugin metadata type: %s must be func() gossismcp.PluginInfo", gossismcp.MetadataSymbol)
 	}
+	id := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	metadata := newPluginMetadata(id, metadataFunc())
 
 	loaded := &LoadedPlugin{
 		Metadata:  metadata,
@@ -324,30 +334,30 @@ func (pm *PluginManager) LoadPlugin(path string) error {
 	for _, tool := range metadata.Tools {
 		sym, err := p.Lookup(strings.Title(tool.Name) + "Tool")
 		if err != nil {
-			log.Printf("Tool %s not found in plugin %s", tool.Name, metadata.ID)
+			slog.Warn("plugin tool not found", "tool", tool.Name, "plugin", metadata.ID)
 			continue
 		}
 
 		executor, ok := sym.(ToolExecutor)
 		if !ok {
-			log.Printf("Invalid tool executor type for %s", tool.Name)
+			slog.Warn("invalid plugin tool executor type", "tool", tool.Name, "plugin", metadata.ID)
 			continue
 		}
 
-		loaded.Tools[tool.Name] = executor
+		loaded.Tools[tool.Name] = NewPluginSandbox(metadata.ID, executor, pm.sandboxDirectories(metadata.ID)...)
 	}
 
 	// Load resources
 	for _, resource := range metadata.Resources {
 		sym, err := p.Lookup(strings.Title(resource.Name) + "Resource")
 		if err != nil {
-			log.Printf("Resource %s not found in plugin %s", resource.Name, metadata.ID)
+			slog.Warn("plugin resource not found", "resource", resource.Name, "plugin", metadata.ID)
 			continue
 		}
 
 		handler, ok := sym.(ResourceHandler)
 		if !ok {
-			log.Printf("Invalid resource handler type for %s", resource.Name)
+			slog.Warn("invalid plugin resource handler type", "resource", resource.Name, "plugin", metadata.ID)
 			continue
 		}
 
@@ -358,9 +368,55 @@ func (pm *PluginManager) LoadPlugin(path string) error {
 	pm.loadedPlugins[metadata.ID] = loaded
 	pm.mu.Unlock()
 
+	if pm.registry != nil {
+		pm.registry.Register(metadata)
+	}
+
 	return nil
 }
 
+// sandboxDirectories returns the directories a plugin's tools may access: the package directory and any configured for the plugin
+func (pm *PluginManager) sandboxDirectories(pluginID string) []string {
+	dirs := []string{pm.packageDirectory}
+	if dir, ok := pm.allowedDirectories[pluginID]; ok {
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// newPluginMetadata converts the info exported by a plugin binary into registry metadata
+func newPluginMetadata(id string, info gossismcp.PluginInfo) *PluginMetadata {
+	metadata := &PluginMetadata{
+		ID:          id,
+		Name:        info.Name,
+		Version:     info.Version,
+		Author:      info.Author,
+		Description: info.Description,
+	}
+	if metadata.Name == "" {
+		metadata.Name = id
+	}
+	for _, tool := range info.SupportedTools {
+		metadata.Tools = append(metadata.Tools, PluginTool{Name: tool})
+	}
+	return metadata
+}
+
+// Info returns the plugin API view of the metadata
+func (m *PluginMetadata) Info() gossismcp.PluginInfo {
+	info := gossismcp.PluginInfo{
+		Name:           m.Name,
+		Version:        m.Version,
+		Author:         m.Author,
+		Description:    m.Description,
+		SupportedTools: []string{},
+	}
+	for _, tool := range m.Tools {
+		info.SupportedTools = append(info.SupportedTools, tool.Name)
+	}
+	return info
+}
+
 // GetTool retrieves a tool executor
 func (pm *PluginManager) GetTool(pluginID, toolName string) (ToolExecutor, bool) {
 	pm.mu.RLock()
@@ -535,6 +591,7 @@ func DefaultPluginConfig() config.PluginConfig {
 		Security: config.PluginSecurity{
 			AllowNetworkAccess: false,
 			AllowedDomains:     []string{},
+			AllowedDirectories: map[string]string{},
 			SignatureRequired:  false,
 			TrustedPublishers:  []string{"gossisMCP"},
 		},
@@ -2,10 +2,13 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/MCPRUNNER/gossisMCP/pkg/config"
+	gossismcp "github.com/MCPRUNNER/gossisMCP/pkg/plugin"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -104,7 +107,7 @@ func TestPluginSystemInitializeRegistersBuiltins(t *testing.T) {
 	cfg.PluginDir = filepath.Join(t.TempDir(), "plugins")
 	cfg.AutoUpdate = false
 
-	system := NewPluginSystem(cfg)
+	system := NewPluginSystem(cfg, t.TempDir())
 	if err := system.Initialize(); err != nil {
 		t.Fatalf("unexpected initialize error: %v", err)
 	}
@@ -113,3 +116,56 @@ func TestPluginSystemInitializeRegistersBuiltins(t *testing.T) {
 		t.Fatal("expected builtin plugin ssis-core-analysis to be registered")
 	}
 }
+
+func TestNewPluginMetadataRoundTripsPluginInfo(t *testing.T) {
+	info := gossismcp.PluginInfo{
+		Name:           "Naming Rules",
+		Version:        "2.1.0",
+		Author:         "Data Platform",
+		Description:    "Checks task naming conventions",
+		SupportedTools: []string{"check_task_names", "check_variable_names"},
+	}
+
+	metadata := newPluginMetadata("naming-rules", info)
+	if metadata.ID != "naming-rules" || len(metadata.Tools) != 2 || metadata.Tools[1].Name != "check_variable_names" {
+		t.Fatalf("unexpected metadata: %+v", metadata)
+	}
+	if got := metadata.Info(); !reflect.DeepEqual(got, info) {
+		t.Fatalf("expected %+v, got %+v", info, got)
+	}
+}
+
+func TestHandleGetPluginInfo(t *testing.T) {
+	system := NewPluginSystem(config.DefaultPluginConfig(), t.TempDir())
+	system.manager.loadedPlugins["naming-rules"] = &LoadedPlugin{
+		Metadata: newPluginMetadata("naming-rules", gossismcp.PluginInfo{
+			Name:           "Naming Rules",
+			Version:        "2.1.0",
+			Author:         "Data Platform",
+			SupportedTools: []string{"check_task_names"},
+		}),
+		Enabled: true,
+	}
+
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{
+		Arguments: map[string]interface{}{"name": "naming rules", "format": "json"},
+	}}
+	result, err := system.handleGetPluginInfo(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	var info gossismcp.PluginInfo
+	if err := json.Unmarshal([]byte(text), &info); err != nil {
+		t.Fatalf("expected plugin info JSON, got %q: %v", text, err)
+	}
+	if info.Version != "2.1.0" || info.Author != "Data Platform" || !reflect.DeepEqual(info.SupportedTools, []string{"check_task_names"}) {
+		t.Fatalf("unexpected plugin info: %+v", info)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"name": "missing"}
+	result, err = system.handleGetPluginInfo(context.Background(), request)
+	if err != nil || !result.IsError {
+		t.Fatalf("expected an error result for an unknown plugin, got %+v, %v", result, err)
+	}
+}
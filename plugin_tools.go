@@ -6,7 +6,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"strings"
 	"time"
@@ -97,6 +97,19 @@ func (ps *PluginSystem) createPluginManagementTools(s *server.MCPServer) {
 	)
 	s.AddTool(getPluginDetailsTool, ps.handleGetPluginDetails)
 
+	// Tool to get the metadata a plugin exports
+	getPluginInfoTool := mcp.NewTool("get_plugin_info",
+		mcp.WithDescription("Get the name, version, author, description and supported tools of a single plugin"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Plugin name or ID"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format: text, json (default: text)"),
+		),
+	)
+	s.AddTool(getPluginInfoTool, ps.handleGetPluginInfo)
+
 	// Tool to update plugins
 	updatePluginsTool := mcp.NewTool("update_plugins",
 		mcp.WithDescription("Update installed plugins to latest versions"),
@@ -196,8 +209,8 @@ func (ps *PluginSystem) handleListPlugins(ctx context.Context, request mcp.CallT
 		}
 
 		var sb strings.Builder
-		sb.WriteString("| ID | Name | Version | Author | Status | Description |\n")
-		sb.WriteString("|----|------|---------|--------|--------|-------------|\n")
+		sb.WriteString("| ID | Name | Version | Author | Status | Supported Tools | Description |\n")
+		sb.WriteString("|----|------|---------|--------|--------|-----------------|-------------|\n")
 
 		for _, plugin := range plugins {
 			status := "Not Loaded"
@@ -217,8 +230,9 @@ func (ps *PluginSystem) handleListPlugins(ctx context.Context, request mcp.CallT
 				desc = desc[:47] + "..."
 			}
 
-			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
-				plugin.ID, plugin.Name, plugin.Version, plugin.Author, status, desc))
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n",
+				plugin.ID, plugin.Name, plugin.Version, plugin.Author, status,
+				strings.Join(plugin.Info().SupportedTools, ", "), desc))
 		}
 
 		return mcp.NewToolResultText(sb.String()), nil
@@ -254,7 +268,7 @@ func (ps *PluginSystem) handleListPlugins(ctx context.Context, request mcp.CallT
 			}
 
 			if len(plugin.Tools) > 0 {
-				sb.WriteString(fmt.Sprintf("   Tools: %d\n", len(plugin.Tools)))
+				sb.WriteString(fmt.Sprintf("   Supported Tools: %s\n", strings.Join(plugin.Info().SupportedTools, ", ")))
 			}
 
 			sb.WriteString("\n")
@@ -373,7 +387,7 @@ func (ps *PluginSystem) handleSearchMarketplace(ctx context.Context, request mcp
 	// Update cache if needed
 	if time.Since(ps.marketplace.cacheTime) > time.Hour {
 		if err := ps.marketplace.UpdateCache(); err != nil {
-			log.Printf("Failed to update marketplace cache: %v", err)
+			slog.Warn("failed to update marketplace cache", "error", err)
 		}
 	}
 
@@ -529,6 +543,68 @@ func (ps *PluginSystem) handleGetPluginDetails(ctx context.Context, request mcp.
 	return mcp.NewToolResultText(sb.String()), nil
 }
 
+// handleGetPluginInfo handles the get_plugin_info tool
+func (ps *PluginSystem) handleGetPluginInfo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name := request.GetString("name", "")
+	format := request.GetString("format", "text")
+
+	if name == "" {
+		return mcp.NewToolResultError("Plugin name is required"), nil
+	}
+
+	plugin, exists := ps.findPlugin(name)
+	if !exists {
+		return mcp.NewToolResultError(fmt.Sprintf("Plugin not found: %s", name)), nil
+	}
+	info := plugin.Info()
+
+	if format == "json" {
+		data, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal plugin info: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📦 %s\n", info.Name))
+	sb.WriteString(fmt.Sprintf("   Version: %s\n", info.Version))
+	sb.WriteString(fmt.Sprintf("   Author: %s\n", info.Author))
+	sb.WriteString(fmt.Sprintf("   Description: %s\n", info.Description))
+	if len(info.SupportedTools) > 0 {
+		sb.WriteString(fmt.Sprintf("   Supported Tools: %s\n", strings.Join(info.SupportedTools, ", ")))
+	} else {
+		sb.WriteString("   Supported Tools: none\n")
+	}
+
+	return mcp.NewToolResultText(sb.String()), nil
+}
+
+// findPlugin looks up a registered or loaded plugin by ID or case-insensitive name
+func (ps *PluginSystem) findPlugin(name string) (*PluginMetadata, bool) {
+	if plugin, exists := ps.registry.Get(name); exists {
+		return plugin, true
+	}
+
+	ps.manager.mu.RLock()
+	defer ps.manager.mu.RUnlock()
+	if loaded, exists := ps.manager.loadedPlugins[name]; exists {
+		return loaded.Metadata, true
+	}
+
+	for _, plugin := range ps.registry.List() {
+		if strings.EqualFold(plugin.Name, name) {
+			return plugin, true
+		}
+	}
+	for _, loaded := range ps.manager.loadedPlugins {
+		if strings.EqualFold(loaded.Metadata.Name, name) {
+			return loaded.Metadata, true
+		}
+	}
+	return nil, false
+}
+
 // handleUpdatePlugins handles the update_plugins tool
 func (ps *PluginSystem) handleUpdatePlugins(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	pluginIDsStr := request.GetString("plugin_ids", "")
@@ -827,6 +903,6 @@ func (ps *PluginSystem) buildPlugin(sourceFile string) error {
 		strings.TrimSuffix(sourceFile, ".go"), sourceFile)
 
 	// For now, return success (actual implementation would run the command)
-	log.Printf("Building plugin: %s", cmd)
+	slog.Info("building plugin", "command", cmd)
 	return nil
 }
@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
+
+	gossismcp "github.com/MCPRUNNER/gossisMCP/pkg/plugin"
 )
 
 // ExamplePlugin demonstrates a basic gossisMCP plugin
@@ -61,6 +63,20 @@ var PluginInfo = map[string]interface{}{
 	},
 }
 
+// PluginMetadata is the exported entry point the server looks up when loading the plugin
+func PluginMetadata() gossismcp.PluginInfo {
+	info := gossismcp.PluginInfo{
+		Name:        PluginInfo["name"].(string),
+		Version:     PluginInfo["version"].(string),
+		Author:      PluginInfo["author"].(string),
+		Description: PluginInfo["description"].(string),
+	}
+	for _, tool := range PluginInfo["tools"].([]map[string]interface{}) {
+		info.SupportedTools = append(info.SupportedTools, tool["name"].(string))
+	}
+	return info
+}
+
 // Metadata returns plugin metadata
 func (p *ExamplePlugin) Metadata() map[string]interface{} {
 	return PluginInfo
@@ -44,6 +44,16 @@ func TestExamplePluginMetadata(t *testing.T) {
 	}
 }
 
+func TestExamplePluginExportsPluginInfo(t *testing.T) {
+	info := PluginMetadata()
+	if info.Name != "Example SSIS Plugin" || info.Version != "1.0.0" || info.Author != "gossisMCP Team" {
+		t.Fatalf("unexpected plugin info: %+v", info)
+	}
+	if strings.Join(info.SupportedTools, ",") != "detect_hardcoded_connections,analyze_variable_usage" {
+		t.Fatalf("unexpected supported tools: %v", info.SupportedTools)
+	}
+}
+
 func TestExamplePluginToolsMirrorMetadata(t *testing.T) {
 	plugin := &ExamplePlugin{}
 	metadataTools := PluginInfo["tools"].([]map[string]interface{})
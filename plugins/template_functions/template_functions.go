@@ -0,0 +1,50 @@
+// Command template_functions is an example render_template functions plugin. Build it with
+//
+//	go build -buildmode=plugin -o template_functions.so ./plugins/template_functions
+//
+// and pass the .so path as functions_plugin with server.enable_template_plugins set to true.
+package main
+
+import (
+	"html/template"
+	"strings"
+	"time"
+)
+
+// FuncMap is looked up by render_template and its functions are added to the template
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"titleCase":  titleCase,
+		"formatDate": formatDate,
+		"maskEmail":  maskEmail,
+	}
+}
+
+// titleCase capitalises the first letter of each word
+func titleCase(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
+	}
+	return strings.Join(words, " ")
+}
+
+// formatDate reformats an RFC 3339 timestamp using a Go time layout, returning the input unchanged if it cannot be parsed
+func formatDate(value, layout string) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	return t.Format(layout)
+}
+
+// maskEmail keeps the first character of the local part and the domain of an email address
+func maskEmail(email string) string {
+	at := strings.Index(email, "@")
+	if at <= 0 {
+		return email
+	}
+	return email[:1] + strings.Repeat("*", at-1) + email[at:]
+}
+
+func main() {}